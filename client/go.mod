@@ -0,0 +1,16 @@
+module github.com/mosuka/cete/client
+
+go 1.14
+
+require (
+	github.com/golang/protobuf v1.3.5
+	github.com/mosuka/cete/errors v0.0.0-00010101000000-000000000000
+	github.com/mosuka/cete/protobuf v0.0.0-00010101000000-000000000000
+	golang.org/x/sys v0.0.0-20200122134326-e047566fdf82 // indirect
+	google.golang.org/grpc v1.28.0
+)
+
+replace (
+	github.com/mosuka/cete/errors => ../errors
+	github.com/mosuka/cete/protobuf => ../protobuf
+)
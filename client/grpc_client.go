@@ -4,16 +4,11 @@ import (
 	"context"
 	"log"
 	"math"
-	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
-	"github.com/mosuka/cete/errors"
 	"github.com/mosuka/cete/protobuf"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/keepalive"
-	"google.golang.org/grpc/status"
 )
 
 type GRPCClient struct {
@@ -22,6 +17,8 @@ type GRPCClient struct {
 	conn   *grpc.ClientConn
 	client protobuf.KVSClient
 
+	retrier *leaderRetrier
+
 	logger *log.Logger
 }
 
@@ -34,48 +31,86 @@ func NewGRPCClientWithContext(grpc_address string, baseCtx context.Context) (*GR
 }
 
 func NewGRPCClientWithContextTLS(grpcAddress string, baseCtx context.Context, certificateFile string, commonName string) (*GRPCClient, error) {
+	return NewGRPCClientWithOptions(grpcAddress, baseCtx, WithTLS(certificateFile, commonName))
+}
+
+func NewGRPCClientWithOptions(grpcAddress string, baseCtx context.Context, opts ...Option) (*GRPCClient, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	dialOpts := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallSendMsgSize(math.MaxInt64),
 			grpc.MaxCallRecvMsgSize(math.MaxInt64),
 		),
-		grpc.WithKeepaliveParams(
-			keepalive.ClientParameters{
-				Time:                1 * time.Second,
-				Timeout:             5 * time.Second,
-				PermitWithoutStream: true,
-			},
-		),
+		grpc.WithKeepaliveParams(o.keepalive),
 	}
 
-	ctx, cancel := context.WithCancel(baseCtx)
-
-	if certificateFile == "" {
-		dialOpts = append(dialOpts, grpc.WithInsecure())
-	} else {
-		creds, err := credentials.NewClientTLSFromFile(certificateFile, commonName)
+	switch {
+	case o.transportCredentials != nil:
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(o.transportCredentials))
+	case o.certificateFile != "":
+		creds, err := credentials.NewClientTLSFromFile(o.certificateFile, o.commonName)
 		if err != nil {
 			return nil, err
 		}
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+	default:
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+
+	if o.authority != "" {
+		dialOpts = append(dialOpts, grpc.WithAuthority(o.authority))
+	}
+
+	if o.userAgent != "" {
+		dialOpts = append(dialOpts, grpc.WithUserAgent(o.userAgent))
+	}
+
+	if o.authToken != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bearerToken(o.authToken)))
+	}
+
+	// connections to the leader only convert the errors, they never retry
+	retrier := newLeaderRetrier(o.leaderRetries, o.leaderRetryBackoff, append(dialOpts, grpc.WithChainUnaryInterceptor(errorUnaryClientInterceptor)))
+	interceptors := []grpc.UnaryClientInterceptor{metadataUnaryClientInterceptor}
+	if o.checksums {
+		interceptors = append(interceptors, checksumUnaryClientInterceptor)
 	}
+	interceptors = append(interceptors, retrier.intercept, errorUnaryClientInterceptor)
+	dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(interceptors...))
 
-	conn, err := grpc.DialContext(ctx, grpcAddress, dialOpts...)
+	ctx, cancel := context.WithCancel(baseCtx)
+
+	dialCtx := ctx
+	if o.dialTimeout > 0 {
+		// block until the connection is up, or fail after the dial timeout
+		var dialCancel context.CancelFunc
+		dialCtx, dialCancel = context.WithTimeout(ctx, o.dialTimeout)
+		defer dialCancel()
+		dialOpts = append(dialOpts, grpc.WithBlock())
+	}
+
+	conn, err := grpc.DialContext(dialCtx, grpcAddress, dialOpts...)
 	if err != nil {
 		cancel()
 		return nil, err
 	}
 
 	return &GRPCClient{
-		ctx:    ctx,
-		cancel: cancel,
-		conn:   conn,
-		client: protobuf.NewKVSClient(conn),
+		ctx:     ctx,
+		cancel:  cancel,
+		conn:    conn,
+		client:  protobuf.NewKVSClient(conn),
+		retrier: retrier,
 	}, nil
 }
 
 func (c *GRPCClient) Close() error {
 	c.cancel()
+	c.retrier.close()
 	if c.conn != nil {
 		return c.conn.Close()
 	}
@@ -119,6 +154,78 @@ func (c *GRPCClient) Leave(req *protobuf.LeaveRequest, opts ...grpc.CallOption)
 	return nil
 }
 
+func (c *GRPCClient) BatchJoin(req *protobuf.BatchJoinRequest, opts ...grpc.CallOption) (*protobuf.BatchJoinResponse, error) {
+	if resp, err := c.client.BatchJoin(c.ctx, req, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) Annotate(req *protobuf.AnnotateRequest, opts ...grpc.CallOption) error {
+	if _, err := c.client.Annotate(c.ctx, req, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *GRPCClient) UpdateNodeStatus(req *protobuf.UpdateNodeStatusRequest, opts ...grpc.CallOption) error {
+	if _, err := c.client.UpdateNodeStatus(c.ctx, req, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *GRPCClient) CreateAPIKey(req *protobuf.CreateAPIKeyRequest, opts ...grpc.CallOption) (*protobuf.CreateAPIKeyResponse, error) {
+	if resp, err := c.client.CreateAPIKey(c.ctx, req, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) RevokeAPIKey(req *protobuf.RevokeAPIKeyRequest, opts ...grpc.CallOption) error {
+	if _, err := c.client.RevokeAPIKey(c.ctx, req, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *GRPCClient) APIKeys(opts ...grpc.CallOption) (*protobuf.APIKeysResponse, error) {
+	if resp, err := c.client.APIKeys(c.ctx, &empty.Empty{}, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) MembershipSpec(opts ...grpc.CallOption) (*protobuf.MembershipSpecResponse, error) {
+	if resp, err := c.client.MembershipSpec(c.ctx, &empty.Empty{}, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) SetMembershipSpec(req *protobuf.SetMembershipSpecRequest, opts ...grpc.CallOption) error {
+	if _, err := c.client.SetMembershipSpec(c.ctx, req, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *GRPCClient) DeleteMembershipSpec(opts ...grpc.CallOption) error {
+	if _, err := c.client.DeleteMembershipSpec(c.ctx, &empty.Empty{}, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c *GRPCClient) Node(opts ...grpc.CallOption) (*protobuf.NodeResponse, error) {
 	if resp, err := c.client.Node(c.ctx, &empty.Empty{}, opts...); err != nil {
 		return nil, err
@@ -143,15 +250,89 @@ func (c *GRPCClient) Snapshot(opts ...grpc.CallOption) error {
 	return nil
 }
 
+func (c *GRPCClient) Hash(opts ...grpc.CallOption) (*protobuf.HashResponse, error) {
+	if resp, err := c.client.Hash(c.ctx, &empty.Empty{}, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) StorageLevels(req *protobuf.StorageLevelsRequest, opts ...grpc.CallOption) (*protobuf.StorageLevelsResponse, error) {
+	if resp, err := c.client.StorageLevels(c.ctx, req, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) Dump(opts ...grpc.CallOption) (*protobuf.DumpResponse, error) {
+	if resp, err := c.client.Dump(c.ctx, &empty.Empty{}, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) DecommissionStatus(req *protobuf.DecommissionStatusRequest, opts ...grpc.CallOption) (*protobuf.DecommissionStatusResponse, error) {
+	if resp, err := c.client.DecommissionStatus(c.ctx, req, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) Role(opts ...grpc.CallOption) (*protobuf.RoleResponse, error) {
+	if resp, err := c.client.Role(c.ctx, &empty.Empty{}, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) SetRole(req *protobuf.SetRoleRequest, opts ...grpc.CallOption) error {
+	if _, err := c.client.SetRole(c.ctx, req, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *GRPCClient) ClusterConfig(opts ...grpc.CallOption) (*protobuf.ClusterConfigResponse, error) {
+	if resp, err := c.client.ClusterConfig(c.ctx, &empty.Empty{}, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) SetClusterConfig(req *protobuf.SetClusterConfigRequest, opts ...grpc.CallOption) error {
+	if _, err := c.client.SetClusterConfig(c.ctx, req, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c *GRPCClient) Get(req *protobuf.GetRequest, opts ...grpc.CallOption) (*protobuf.GetResponse, error) {
 	if resp, err := c.client.Get(c.ctx, req, opts...); err != nil {
-		st, _ := status.FromError(err)
-		switch st.Code() {
-		case codes.NotFound:
-			return nil, errors.ErrNotFound
-		default:
-			return nil, err
-		}
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) Scan(req *protobuf.ScanRequest, opts ...grpc.CallOption) (*protobuf.ScanResponse, error) {
+	if resp, err := c.client.Scan(c.ctx, req, opts...); err != nil {
+		return nil, err
+	} else {
+		return resp, nil
+	}
+}
+
+func (c *GRPCClient) Export(req *protobuf.ExportRequest, opts ...grpc.CallOption) (*protobuf.ExportResponse, error) {
+	if resp, err := c.client.Export(c.ctx, req, opts...); err != nil {
+		return nil, err
 	} else {
 		return resp, nil
 	}
@@ -173,7 +354,23 @@ func (c *GRPCClient) Delete(req *protobuf.DeleteRequest, opts ...grpc.CallOption
 	return nil
 }
 
-func (c *GRPCClient) Watch(req *empty.Empty, opts ...grpc.CallOption) (protobuf.KVS_WatchClient, error) {
+func (c *GRPCClient) Copy(req *protobuf.CopyRequest, opts ...grpc.CallOption) error {
+	if _, err := c.client.Copy(c.ctx, req, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *GRPCClient) Move(req *protobuf.MoveRequest, opts ...grpc.CallOption) error {
+	if _, err := c.client.Move(c.ctx, req, opts...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *GRPCClient) Watch(req *protobuf.WatchRequest, opts ...grpc.CallOption) (protobuf.KVS_WatchClient, error) {
 	return c.client.Watch(c.ctx, req, opts...)
 }
 
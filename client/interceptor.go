@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errorUnaryClientInterceptor converts the status errors returned by the server
+// into *errors.Error, so that callers can use errors.Is and errors.As.
+func errorUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err == nil {
+		return nil
+	}
+
+	if st, ok := status.FromError(err); ok {
+		return errors.FromStatus(st)
+	}
+
+	return err
+}
+
+type outgoingMetadataCallOption struct {
+	grpc.EmptyCallOption
+	md metadata.MD
+}
+
+// OutgoingMetadata returns a call option that sends the metadata with the request,
+// e.g. the client ID attributed to a write.
+func OutgoingMetadata(md metadata.MD) grpc.CallOption {
+	return outgoingMetadataCallOption{md: md}
+}
+
+// metadataUnaryClientInterceptor adds the metadata of the OutgoingMetadata call options to the request.
+func metadataUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	for _, opt := range opts {
+		if o, ok := opt.(outgoingMetadataCallOption); ok {
+			for key, values := range o.md {
+				for _, value := range values {
+					ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+				}
+			}
+		}
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// checksumUnaryClientInterceptor sets the checksum of the values written, unless already set.
+func checksumUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if r, ok := req.(*protobuf.SetRequest); ok && r.Checksum == nil {
+		r.Checksum = &wrappers.UInt32Value{Value: protobuf.Checksum(r.Value)}
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// leaderRetrier retries requests rejected because the node is not the leader on
+// the leader. The leader is taken from the error, or asked for if the error does
+// not carry it, e.g. while an election is in progress.
+type leaderRetrier struct {
+	maxRetries int
+	backoff    time.Duration
+	dialOpts   []grpc.DialOption
+
+	mutex sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+func newLeaderRetrier(maxRetries int, backoff time.Duration, dialOpts []grpc.DialOption) *leaderRetrier {
+	return &leaderRetrier{
+		maxRetries: maxRetries,
+		backoff:    backoff,
+		dialOpts:   dialOpts,
+		conns:      make(map[string]*grpc.ClientConn, 0),
+	}
+}
+
+func (r *leaderRetrier) intercept(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	for attempt := 1; attempt <= r.maxRetries && errors.Is(err, errors.ErrNotLeader); attempt++ {
+		timer := time.NewTimer(time.Duration(attempt) * r.backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		address, leaderErr := r.leaderAddress(ctx, cc, err)
+		if leaderErr != nil {
+			continue
+		}
+
+		if address == cc.Target() {
+			err = invoker(ctx, method, req, reply, cc, opts...)
+			continue
+		}
+
+		conn, leaderErr := r.conn(address)
+		if leaderErr != nil {
+			return err
+		}
+		err = conn.Invoke(ctx, method, req, reply, opts...)
+	}
+
+	return err
+}
+
+// leaderAddress returns the gRPC address of the leader.
+func (r *leaderRetrier) leaderAddress(ctx context.Context, cc *grpc.ClientConn, err error) (string, error) {
+	var node *protobuf.Node
+	if hint := errors.LeaderHint(err); hint != nil && hint.Node != nil {
+		node = hint.Node
+	} else {
+		resp, err := protobuf.NewKVSClient(cc).Cluster(ctx, &empty.Empty{})
+		if err != nil {
+			return "", err
+		}
+		node = resp.Cluster.Nodes[resp.Cluster.Leader]
+	}
+
+	if node == nil || node.Metadata == nil || node.Metadata.GrpcAddress == "" {
+		return "", errors.ErrNotFoundLeader
+	}
+
+	host, port, err := net.SplitHostPort(node.Metadata.GrpcAddress)
+	if err != nil {
+		return "", err
+	}
+	if host == "" {
+		// the leader listens on all interfaces, assume it is reachable by the same host name
+		if h, _, err := net.SplitHostPort(cc.Target()); err == nil {
+			host = h
+		}
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+func (r *leaderRetrier) conn(address string) (*grpc.ClientConn, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if conn, ok := r.conns[address]; ok {
+		return conn, nil
+	}
+
+	conn, err := grpc.Dial(address, r.dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	r.conns[address] = conn
+
+	return conn, nil
+}
+
+func (r *leaderRetrier) close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for address, conn := range r.conns {
+		_ = conn.Close()
+		delete(r.conns, address)
+	}
+}
+
+// bearerToken sends the token in the authorization metadata of the requests.
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
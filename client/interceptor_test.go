@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func TestLeaderRetrier(t *testing.T) {
+	// the connection is never used, the leader hint points back at it
+	cc, err := grpc.Dial("127.0.0.1:7", grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer cc.Close()
+	hint := &protobuf.LeaderHint{Id: "node1", Node: &protobuf.Node{Metadata: &protobuf.Metadata{GrpcAddress: "127.0.0.1:7"}}}
+
+	for _, test := range []struct {
+		name     string
+		errs     []error
+		expected error
+		calls    int
+	}{
+		{"refused before being proposed", []error{errors.NotLeader(hint), nil}, nil, 2},
+		{"leadership lost while being applied", []error{errors.Wrap(errors.ErrOutcomeUnknown, "leadership lost while committing log"), nil}, errors.ErrOutcomeUnknown, 1},
+		{"retries exhausted", []error{errors.NotLeader(hint), errors.NotLeader(hint), errors.NotLeader(hint), errors.NotLeader(hint)}, errors.ErrNotLeader, 4},
+	} {
+		r := newLeaderRetrier(3, time.Millisecond, nil)
+		calls := 0
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			err := test.errs[calls]
+			calls++
+			if err == nil {
+				return nil
+			}
+			// as received from the server
+			return errors.FromStatus(err.(interface{ GRPCStatus() *status.Status }).GRPCStatus())
+		}
+
+		err := r.intercept(context.Background(), "/kvs.KVS/Move", &protobuf.MoveRequest{Source: "/a", Destination: "/b"}, nil, cc, invoker)
+		if (test.expected == nil && err != nil) || (test.expected != nil && !errors.Is(err, test.expected)) {
+			t.Errorf("%s: expected %v, saw %v", test.name, test.expected, err)
+		}
+		if calls != test.calls {
+			t.Errorf("%s: expected %d calls, saw %d", test.name, test.calls, calls)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+)
+
+type options struct {
+	dialTimeout          time.Duration
+	certificateFile      string
+	commonName           string
+	transportCredentials credentials.TransportCredentials
+	authority            string
+	keepalive            keepalive.ClientParameters
+	userAgent            string
+	authToken            string
+	leaderRetries        int
+	leaderRetryBackoff   time.Duration
+	checksums            bool
+}
+
+func defaultOptions() *options {
+	return &options{
+		keepalive: keepalive.ClientParameters{
+			Time:                1 * time.Second,
+			Timeout:             5 * time.Second,
+			PermitWithoutStream: true,
+		},
+		leaderRetries:      3,
+		leaderRetryBackoff: 200 * time.Millisecond,
+	}
+}
+
+// Option configures how the client connects to the server.
+type Option func(*options)
+
+// WithDialTimeout makes the client wait until the connection is established.
+// If the connection can not be established within the timeout, creating the client fails.
+// A zero timeout means that the connection is established in the background.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.dialTimeout = timeout
+	}
+}
+
+// WithTLS enables TLS using the certificate file and the certificate common name.
+// TLS is disabled if the certificate file is empty.
+func WithTLS(certificateFile string, commonName string) Option {
+	return func(o *options) {
+		o.certificateFile = certificateFile
+		o.commonName = commonName
+	}
+}
+
+// WithTransportCredentials sets the transport credentials.
+// It takes precedence over WithTLS.
+func WithTransportCredentials(creds credentials.TransportCredentials) Option {
+	return func(o *options) {
+		o.transportCredentials = creds
+	}
+}
+
+// WithAuthority overrides the :authority pseudo-header sent to the server.
+func WithAuthority(authority string) Option {
+	return func(o *options) {
+		o.authority = authority
+	}
+}
+
+// WithKeepalive sets the keepalive parameters of the connection.
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return func(o *options) {
+		o.keepalive = params
+	}
+}
+
+// WithUserAgent sets the user agent sent to the server.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithAuthToken presents the token, or the API key, as a bearer token with every request.
+// The token is sent in the clear unless TLS is enabled.
+func WithAuthToken(token string) Option {
+	return func(o *options) {
+		o.authToken = token
+	}
+}
+
+// WithLeaderRetry sets how many times a request rejected because the node is not
+// the leader is retried on the leader, and how long to wait before each retry.
+// Retries are disabled if maxRetries is zero.
+func WithLeaderRetry(maxRetries int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.leaderRetries = maxRetries
+		o.leaderRetryBackoff = backoff
+	}
+}
+
+// WithChecksums makes the client set the checksum of the values it writes, unless already
+// set, so that the nodes refuse to write a value corrupted on its way with a DATA_LOSS error.
+func WithChecksums(enabled bool) Option {
+	return func(o *options) {
+		o.checksums = enabled
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	annotateCmd = &cobra.Command{
+		Use:   "annotate ID KEY=VALUE...",
+		Args:  cobra.MinimumNArgs(2),
+		Short: "Annotate a node of the cluster",
+		Long:  "Attach annotations to a node of the cluster, e.g. reason=\"draining for kernel upgrade\". KEY= removes the annotation. The annotations are shown by the cluster and node commands",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			id := args[0]
+
+			annotations := make(map[string]string, 0)
+			for _, arg := range args[1:] {
+				kv := strings.SplitN(arg, "=", 2)
+				if len(kv) != 2 {
+					return fmt.Errorf("annotation %q is not in KEY=VALUE format", arg)
+				}
+				annotations[kv[0]] = kv[1]
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			req := &protobuf.AnnotateRequest{
+				Id:          id,
+				Annotations: annotations,
+			}
+
+			if err := c.Annotate(req); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(annotateCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	annotateCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	annotateCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	annotateCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	annotateCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", annotateCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("certificate_file", annotateCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", annotateCmd.PersistentFlags().Lookup("common-name"))
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	apiKeyCmd = &cobra.Command{
+		Use:   "api-key",
+		Short: "Manage the API keys",
+		Long:  "Create, list and revoke the API keys, which allow short-lived access to the keys with a prefix",
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(apiKeyCmd)
+}
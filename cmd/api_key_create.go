@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	apiKeyCreateCmd = &cobra.Command{
+		Use:   "create",
+		Args:  cobra.NoArgs,
+		Short: "Create an API key",
+		Long:  "Create an API key allowed to read, and optionally to write, the keys with a prefix until it expires. The key is shown once",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+			apiKeyPrefix = viper.GetString("api_key_prefix")
+			apiKeyWrite = viper.GetBool("api_key_write")
+			apiKeyTTL = viper.GetDuration("api_key_ttl")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			req := &protobuf.CreateAPIKeyRequest{
+				Prefix: apiKeyPrefix,
+				Write:  apiKeyWrite,
+				Ttl:    ptypes.DurationProto(apiKeyTTL),
+			}
+
+			resp, err := c.CreateAPIKey(req)
+			if err != nil {
+				return err
+			}
+
+			respBytes, err := json.Marshal(resp)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(respBytes))
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	apiKeyCmd.AddCommand(apiKeyCreateCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	apiKeyCreateCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	apiKeyCreateCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	apiKeyCreateCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	apiKeyCreateCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+	apiKeyCreateCmd.PersistentFlags().StringVar(&apiKeyPrefix, "prefix", "", "prefix of the keys the API key may access. if omitted, every key")
+	apiKeyCreateCmd.PersistentFlags().BoolVar(&apiKeyWrite, "write", false, "allow the API key to write the keys, not only to read them")
+	apiKeyCreateCmd.PersistentFlags().DurationVar(&apiKeyTTL, "ttl", time.Hour, "time after which the API key expires")
+
+	_ = viper.BindPFlag("grpc_address", apiKeyCreateCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("certificate_file", apiKeyCreateCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", apiKeyCreateCmd.PersistentFlags().Lookup("common-name"))
+	_ = viper.BindPFlag("api_key_prefix", apiKeyCreateCmd.PersistentFlags().Lookup("prefix"))
+	_ = viper.BindPFlag("api_key_write", apiKeyCreateCmd.PersistentFlags().Lookup("write"))
+	_ = viper.BindPFlag("api_key_ttl", apiKeyCreateCmd.PersistentFlags().Lookup("ttl"))
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	apiKeyListCmd = &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Short: "List the API keys",
+		Long:  "List the API keys with their prefix, permission and expiration, the expired ones included until they are revoked",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			resp, err := c.APIKeys()
+			if err != nil {
+				return err
+			}
+
+			respBytes, err := json.Marshal(resp)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(respBytes))
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	apiKeyCmd.AddCommand(apiKeyListCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	apiKeyListCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	apiKeyListCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	apiKeyListCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	apiKeyListCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", apiKeyListCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("certificate_file", apiKeyListCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", apiKeyListCmd.PersistentFlags().Lookup("common-name"))
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	apiKeyRevokeCmd = &cobra.Command{
+		Use:   "revoke ID",
+		Args:  cobra.ExactArgs(1),
+		Short: "Revoke an API key",
+		Long:  "Revoke an API key, the requests presenting it are refused from then on",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			id := args[0]
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			req := &protobuf.RevokeAPIKeyRequest{
+				Id: id,
+			}
+
+			if err := c.RevokeAPIKey(req); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	apiKeyCmd.AddCommand(apiKeyRevokeCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	apiKeyRevokeCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	apiKeyRevokeCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	apiKeyRevokeCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	apiKeyRevokeCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", apiKeyRevokeCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("certificate_file", apiKeyRevokeCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", apiKeyRevokeCmd.PersistentFlags().Lookup("common-name"))
+}
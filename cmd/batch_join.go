@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	batchJoinCmd = &cobra.Command{
+		Use:   "batch-join FILE",
+		Args:  cobra.ExactArgs(1),
+		Short: "Converge the cluster membership to the nodes in the file",
+		Long:  "Converge the cluster membership to the nodes in the file. Nodes missing from the cluster join it, nodes not in the file leave it. Use - to read the nodes from the standard input.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			var nodesBytes []byte
+			var err error
+			if args[0] == "-" {
+				nodesBytes, err = ioutil.ReadAll(os.Stdin)
+			} else {
+				nodesBytes, err = ioutil.ReadFile(args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			req := &protobuf.BatchJoinRequest{}
+			if err := json.Unmarshal(nodesBytes, req); err != nil {
+				return err
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			resp, err := c.BatchJoin(req)
+			if err != nil {
+				return err
+			}
+
+			respBytes, err := json.Marshal(resp)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(respBytes))
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(batchJoinCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	batchJoinCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	batchJoinCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	batchJoinCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	batchJoinCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", batchJoinCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("certificate_file", batchJoinCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", batchJoinCmd.PersistentFlags().Lookup("common-name"))
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/log"
+	"github.com/mosuka/cete/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	bootstrapCmd = &cobra.Command{
+		Use:   "bootstrap",
+		Args:  cobra.NoArgs,
+		Short: "Turn the data of a standalone node into a single node cluster",
+		Long:  "Turn the Badger data of a standalone node into the data of a single node cluster in place, without dumping and loading it. Start the node with the same flags afterwards, other nodes can then join it. The node must be stopped",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id = viper.GetString("id")
+			raftAddress = viper.GetString("raft_address")
+			dataDirectory = viper.GetString("data_directory")
+			kvsDirectory = viper.GetString("kvs_directory")
+			raftDirectory = viper.GetString("raft_directory")
+			snapshotDirectory = viper.GetString("snapshot_directory")
+
+			logLevel = viper.GetString("log_level")
+
+			logger := log.NewLogger(logLevel, "", 500, 3, 30, false)
+
+			return server.BootstrapDataDirectory(id, raftAddress, dataDirectory, logger, server.WithStorageDirectories(kvsDirectory, raftDirectory, snapshotDirectory))
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(bootstrapCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	bootstrapCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	bootstrapCmd.PersistentFlags().StringVar(&id, "id", "", "node ID")
+	_ = bootstrapCmd.MarkPersistentFlagRequired("id")
+	bootstrapCmd.PersistentFlags().StringVar(&raftAddress, "raft-address", ":7000", "Raft server listen address the node will be started with")
+	bootstrapCmd.PersistentFlags().StringVar(&dataDirectory, "data-directory", "/tmp/cete/data", "data directory which store the key-value store data and Raft logs")
+	bootstrapCmd.PersistentFlags().StringVar(&kvsDirectory, "kvs-directory", "", "directory of the Badger data of the standalone node. if omitted, kvs in the data directory is used")
+	bootstrapCmd.PersistentFlags().StringVar(&raftDirectory, "raft-directory", "", "directory of the Raft logs. if omitted, raft in the data directory is used")
+	bootstrapCmd.PersistentFlags().StringVar(&snapshotDirectory, "snapshot-directory", "", "directory under which the Raft snapshots are stored. if omitted, the data directory is used")
+	bootstrapCmd.PersistentFlags().StringVar(&logLevel, "log-level", "INFO", "log level")
+
+	_ = viper.BindPFlag("id", bootstrapCmd.PersistentFlags().Lookup("id"))
+	_ = viper.BindPFlag("raft_address", bootstrapCmd.PersistentFlags().Lookup("raft-address"))
+	_ = viper.BindPFlag("data_directory", bootstrapCmd.PersistentFlags().Lookup("data-directory"))
+	_ = viper.BindPFlag("kvs_directory", bootstrapCmd.PersistentFlags().Lookup("kvs-directory"))
+	_ = viper.BindPFlag("raft_directory", bootstrapCmd.PersistentFlags().Lookup("raft-directory"))
+	_ = viper.BindPFlag("snapshot_directory", bootstrapCmd.PersistentFlags().Lookup("snapshot-directory"))
+	_ = viper.BindPFlag("log_level", bootstrapCmd.PersistentFlags().Lookup("log-level"))
+}
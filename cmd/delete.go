@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/mitchellh/go-homedir"
 	"github.com/mosuka/cete/client"
 	"github.com/mosuka/cete/protobuf"
@@ -21,12 +22,22 @@ var (
 		RunE: func(cmd *cobra.Command, args []string) error {
 			grpcAddress = viper.GetString("grpc_address")
 
+			requestMetadata = viper.GetStringSlice("metadata")
+
+			expectedIndex = viper.GetInt64("expected_index")
+
 			certificateFile = viper.GetString("certificate_file")
 			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
 
 			key := args[0]
 
-			c, err := client.NewGRPCClientWithContextTLS(grpcAddress, context.Background(), certificateFile, commonName)
+			md, err := parseMetadata(requestMetadata)
+			if err != nil {
+				return err
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
 			if err != nil {
 				return err
 			}
@@ -37,8 +48,11 @@ var (
 			req := &protobuf.DeleteRequest{
 				Key: key,
 			}
+			if expectedIndex >= 0 {
+				req.ExpectedIndex = &wrappers.UInt64Value{Value: uint64(expectedIndex)}
+			}
 
-			if err := c.Delete(req); err != nil {
+			if err := c.Delete(req, client.OutgoingMetadata(md)); err != nil {
 				return err
 			}
 
@@ -82,10 +96,14 @@ func init() {
 
 	deleteCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
 	deleteCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	deleteCmd.PersistentFlags().Int64Var(&expectedIndex, "expected-index", -1, "abort the delete unless the key was last modified at the index. -1 disables the check")
+	deleteCmd.PersistentFlags().StringSliceVar(&requestMetadata, "metadata", []string{}, "gRPC metadata sent with the request, e.g. x-client-id=batch-loader")
 	deleteCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
 	deleteCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
 
 	_ = viper.BindPFlag("grpc_address", deleteCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("expected_index", deleteCmd.PersistentFlags().Lookup("expected-index"))
+	_ = viper.BindPFlag("metadata", deleteCmd.PersistentFlags().Lookup("metadata"))
 	_ = viper.BindPFlag("certificate_file", deleteCmd.PersistentFlags().Lookup("certificate-file"))
 	_ = viper.BindPFlag("common_name", deleteCmd.PersistentFlags().Lookup("common-name"))
 }
@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/mosuka/cete/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	doctorPass = "PASS"
+	doctorWarn = "WARN"
+	doctorFail = "FAIL"
+)
+
+type doctorResult struct {
+	status string
+	check  string
+	detail string
+}
+
+// doctorNode is a node of the cluster and its answer to the Node request.
+type doctorNode struct {
+	id     string
+	client *client.GRPCClient
+	resp   *protobuf.NodeResponse
+	err    error
+	// the local time halfway through the Node request
+	midpoint time.Time
+	rtt      time.Duration
+}
+
+var (
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Args:  cobra.NoArgs,
+		Short: "Check a running cluster",
+		Long:  "Run live checks against a running cluster: a write, read and delete roundtrip, the ability to keep writing after losing a node, clock skew, disk headroom and version skew. The checks write and delete scratch keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			doctorScratchPrefix = viper.GetString("scratch_prefix")
+			doctorMaxClockSkew = viper.GetDuration("max_clock_skew")
+			doctorMinDiskFree = viper.GetFloat64("min_disk_free")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			// the report tells what failed, the usage does not help
+			cmd.SilenceUsage = true
+
+			clusterResp, err := c.Cluster()
+			if err != nil {
+				return err
+			}
+			nodes := doctorConnect(clusterResp.Cluster)
+			defer func() {
+				for _, node := range nodes {
+					if node.client != nil {
+						_ = node.client.Close()
+					}
+				}
+			}()
+
+			results := make([]doctorResult, 0)
+			results = append(results, doctorRoundtrip(c))
+			results = append(results, doctorFailover(clusterResp.Cluster, nodes))
+			results = append(results, doctorClockSkew(nodes))
+			results = append(results, doctorDiskHeadroom(nodes))
+			results = append(results, doctorVersionSkew(nodes))
+
+			failed := 0
+			for _, result := range results {
+				fmt.Printf("%s  %-13s %s\n", result.status, result.check, result.detail)
+				if result.status == doctorFail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d checks failed", failed, len(results))
+			}
+
+			return nil
+		},
+	}
+)
+
+func doctorScratchKey() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return doctorScratchPrefix + hex.EncodeToString(b)
+}
+
+func doctorConnect(cluster *protobuf.Cluster) []*doctorNode {
+	nodes := make([]*doctorNode, 0, len(cluster.Nodes))
+	for id, n := range cluster.Nodes {
+		node := &doctorNode{id: id}
+		nodes = append(nodes, node)
+		if n.Metadata == nil || n.Metadata.GrpcAddress == "" {
+			node.err = fmt.Errorf("no gRPC address")
+			continue
+		}
+		node.client, node.err = client.NewGRPCClientWithOptions(n.Metadata.GrpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+		if node.err != nil {
+			continue
+		}
+		start := time.Now()
+		node.resp, node.err = node.client.Node()
+		node.rtt = time.Since(start)
+		node.midpoint = start.Add(node.rtt / 2)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].id < nodes[j].id
+	})
+
+	return nodes
+}
+
+func doctorRoundtrip(c *client.GRPCClient) doctorResult {
+	result := doctorResult{check: "roundtrip", status: doctorFail}
+
+	start := time.Now()
+	key := doctorScratchKey()
+	value := []byte(time.Now().UTC().Format(time.RFC3339Nano))
+	if err := c.Set(&protobuf.SetRequest{Key: key, Value: value}); err != nil {
+		result.detail = fmt.Sprintf("failed to set %s: %v", key, err)
+		return result
+	}
+	getResp, err := c.Get(&protobuf.GetRequest{Key: key, Consistency: protobuf.GetRequest_Strong})
+	if err != nil {
+		result.detail = fmt.Sprintf("failed to get %s: %v", key, err)
+		return result
+	}
+	if !bytes.Equal(getResp.Value, value) {
+		result.detail = fmt.Sprintf("read %q from %s, expected %q", getResp.Value, key, value)
+		return result
+	}
+	if err := c.Delete(&protobuf.DeleteRequest{Key: key}); err != nil {
+		result.detail = fmt.Sprintf("failed to delete %s: %v", key, err)
+		return result
+	}
+	if _, err := c.Get(&protobuf.GetRequest{Key: key, Consistency: protobuf.GetRequest_Strong}); errors.Code(err) != codes.NotFound {
+		result.detail = fmt.Sprintf("expected %s to be deleted, saw %v", key, err)
+		return result
+	}
+
+	result.status = doctorPass
+	result.detail = fmt.Sprintf("set, got and deleted %s in %s", key, time.Since(start).Round(time.Millisecond))
+	return result
+}
+
+// doctorFailover writes a scratch key through every node, as clients do when they fail
+// over to another node, and checks that the cluster keeps a quorum after losing a node.
+func doctorFailover(cluster *protobuf.Cluster, nodes []*doctorNode) doctorResult {
+	result := doctorResult{check: "failover", status: doctorFail}
+
+	if cluster.Leader == "" {
+		result.detail = "no leader"
+		return result
+	}
+
+	healthy := 0
+	unhealthy := make([]string, 0)
+	for _, node := range nodes {
+		if node.err != nil {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s is unreachable: %v", node.id, node.err))
+			continue
+		}
+		key := doctorScratchKey()
+		if err := node.client.Set(&protobuf.SetRequest{Key: key, Value: []byte(node.id)}); err != nil {
+			unhealthy = append(unhealthy, fmt.Sprintf("failed to write %s through %s: %v", key, node.id, err))
+			continue
+		}
+		_ = node.client.Delete(&protobuf.DeleteRequest{Key: key})
+		healthy++
+	}
+
+	quorum := len(cluster.Nodes)/2 + 1
+	tolerated := healthy - quorum
+	switch {
+	case tolerated < 0:
+		result.detail = fmt.Sprintf("%d of %d nodes are healthy, the quorum is lost: %s", healthy, len(cluster.Nodes), strings.Join(unhealthy, "; "))
+	case len(unhealthy) > 0:
+		result.detail = fmt.Sprintf("%d of %d nodes are healthy, tolerating %d more failures: %s", healthy, len(cluster.Nodes), tolerated, strings.Join(unhealthy, "; "))
+	case len(cluster.Nodes) == 1:
+		result.status = doctorWarn
+		result.detail = fmt.Sprintf("%s is the only node, the cluster cannot fail over", cluster.Leader)
+	case tolerated < 1:
+		result.detail = fmt.Sprintf("the cluster of %d nodes cannot lose a node without losing the quorum", len(cluster.Nodes))
+	default:
+		result.status = doctorPass
+		result.detail = fmt.Sprintf("leader %s, writes are accepted through all %d nodes, tolerating %d failures", cluster.Leader, healthy, tolerated)
+	}
+
+	return result
+}
+
+func doctorClockSkew(nodes []*doctorNode) doctorResult {
+	result := doctorResult{check: "clock skew", status: doctorPass}
+
+	var maxSkew time.Duration
+	var maxNode string
+	for _, node := range nodes {
+		if node.resp == nil || node.resp.Time == nil {
+			continue
+		}
+		t, err := ptypes.Timestamp(node.resp.Time)
+		if err != nil {
+			continue
+		}
+		skew := t.Sub(node.midpoint)
+		if skew < 0 {
+			skew = -skew
+		}
+		// the node read its clock somewhere within the round trip
+		skew -= node.rtt / 2
+		if skew > maxSkew {
+			maxSkew = skew
+			maxNode = node.id
+		}
+	}
+
+	if maxSkew > doctorMaxClockSkew {
+		result.status = doctorFail
+		result.detail = fmt.Sprintf("the clock of %s is off by %s, more than %s", maxNode, maxSkew.Round(time.Millisecond), doctorMaxClockSkew)
+		return result
+	}
+	result.detail = fmt.Sprintf("the clocks are within %s of this host", doctorMaxClockSkew)
+
+	return result
+}
+
+func doctorDiskHeadroom(nodes []*doctorNode) doctorResult {
+	result := doctorResult{check: "disk headroom", status: doctorPass}
+
+	lowest := 100.0
+	lowestNode := ""
+	for _, node := range nodes {
+		if node.resp == nil || node.resp.DiskTotalBytes == 0 {
+			continue
+		}
+		free := float64(node.resp.DiskFreeBytes) / float64(node.resp.DiskTotalBytes) * 100
+		if free < lowest {
+			lowest = free
+			lowestNode = node.id
+		}
+	}
+
+	switch {
+	case lowestNode == "":
+		result.status = doctorWarn
+		result.detail = "no node reported its disk usage"
+	case lowest < doctorMinDiskFree:
+		result.status = doctorFail
+		result.detail = fmt.Sprintf("%s has %.1f%% of its disk free, less than %.1f%%", lowestNode, lowest, doctorMinDiskFree)
+	default:
+		result.detail = fmt.Sprintf("the fullest disk, on %s, has %.1f%% free", lowestNode, lowest)
+	}
+
+	return result
+}
+
+func doctorVersionSkew(nodes []*doctorNode) doctorResult {
+	result := doctorResult{check: "version skew", status: doctorPass}
+
+	versions := make(map[string][]string, 0)
+	for _, node := range nodes {
+		if node.resp == nil {
+			continue
+		}
+		versions[node.resp.Version] = append(versions[node.resp.Version], node.id)
+	}
+
+	switch {
+	case len(versions) > 1:
+		result.status = doctorFail
+		result.detail = fmt.Sprintf("the nodes run different versions: %v", versions)
+	case len(versions) == 1:
+		for v := range versions {
+			result.detail = fmt.Sprintf("all nodes run %s", v)
+			if v != version.Version {
+				result.status = doctorWarn
+				result.detail += fmt.Sprintf(", this command is %s", version.Version)
+			}
+		}
+	default:
+		result.status = doctorFail
+		result.detail = "no node reported its version"
+	}
+
+	return result
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	doctorCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	doctorCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	doctorCmd.PersistentFlags().StringVar(&doctorScratchPrefix, "scratch-prefix", "/cete-doctor/", "prefix of the scratch keys written and deleted by the checks")
+	doctorCmd.PersistentFlags().DurationVar(&doctorMaxClockSkew, "max-clock-skew", 500*time.Millisecond, "maximum difference between the clocks of the nodes and of this host")
+	doctorCmd.PersistentFlags().Float64Var(&doctorMinDiskFree, "min-disk-free", 10, "minimum percentage of free disk space on each node")
+	doctorCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	doctorCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", doctorCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("scratch_prefix", doctorCmd.PersistentFlags().Lookup("scratch-prefix"))
+	_ = viper.BindPFlag("max_clock_skew", doctorCmd.PersistentFlags().Lookup("max-clock-skew"))
+	_ = viper.BindPFlag("min_disk_free", doctorCmd.PersistentFlags().Lookup("min-disk-free"))
+	_ = viper.BindPFlag("certificate_file", doctorCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", doctorCmd.PersistentFlags().Lookup("common-name"))
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	dumpCmd = &cobra.Command{
+		Use:   "dump",
+		Args:  cobra.NoArgs,
+		Short: "Dump the diagnostics of the node",
+		Long:  "Dump the goroutine stacks, Raft stats, storage levels, config and recent slow requests of the node to a file in its data directory, to attach to bug reports. The node also dumps them on SIGQUIT",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			dumpOutput = viper.GetString("output")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			resp, err := c.Dump()
+			if err != nil {
+				return err
+			}
+
+			if dumpOutput != "" {
+				if err := ioutil.WriteFile(dumpOutput, resp.Bundle, 0600); err != nil {
+					return err
+				}
+				fmt.Println(dumpOutput)
+				return nil
+			}
+
+			fmt.Println(resp.Path)
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(dumpCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	dumpCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	dumpCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	dumpCmd.PersistentFlags().StringVar(&dumpOutput, "output", "", "also write the diagnostics to this local file. if omitted, only the path of the file written by the node is printed")
+	dumpCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	dumpCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", dumpCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("output", dumpCmd.PersistentFlags().Lookup("output"))
+	_ = viper.BindPFlag("certificate_file", dumpCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", dumpCmd.PersistentFlags().Lookup("common-name"))
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	exportCmd = &cobra.Command{
+		Use:   "export DESTINATION [PREFIX]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "Export key-values",
+		Long:  "Scan key-values on the node and write them as JSON lines to a file:// destination on the node or upload them to an http(s):// destination, e.g. a presigned object storage URL",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			keyRegexp = viper.GetString("key_regexp")
+			keyGlob = viper.GetString("key_glob")
+			minValueSize = viper.GetUint64("min_value_size")
+			maxValueSize = viper.GetUint64("max_value_size")
+			modifiedSince = viper.GetUint64("modified_since")
+			exportFormat = viper.GetString("format")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			destination := args[0]
+			prefix := ""
+			if len(args) > 1 {
+				prefix = args[1]
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			req := &protobuf.ExportRequest{
+				Scan: &protobuf.ScanRequest{
+					Prefix:        prefix,
+					KeyRegexp:     keyRegexp,
+					KeyGlob:       keyGlob,
+					MinValueSize:  minValueSize,
+					MaxValueSize:  maxValueSize,
+					ModifiedSince: modifiedSince,
+				},
+				Destination: destination,
+				Format:      exportFormat,
+			}
+
+			resp, err := c.Export(req)
+			if err != nil {
+				return err
+			}
+
+			respBytes, err := json.Marshal(resp)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(respBytes))
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	exportCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	exportCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	exportCmd.PersistentFlags().StringVar(&keyRegexp, "key-regexp", "", "export only the keys matching the regular expression")
+	exportCmd.PersistentFlags().StringVar(&keyGlob, "key-glob", "", "export only the keys matching the glob pattern")
+	exportCmd.PersistentFlags().Uint64Var(&minValueSize, "min-value-size", 0, "export only the values of at least this many bytes")
+	exportCmd.PersistentFlags().Uint64Var(&maxValueSize, "max-value-size", 0, "export only the values of at most this many bytes. 0 means no limit")
+	exportCmd.PersistentFlags().Uint64Var(&modifiedSince, "modified-since", 0, "export only the keys modified at or after this Raft log index")
+	exportCmd.PersistentFlags().StringVar(&exportFormat, "format", "jsonl", "export format. only jsonl is supported")
+	exportCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	exportCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", exportCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("key_regexp", exportCmd.PersistentFlags().Lookup("key-regexp"))
+	_ = viper.BindPFlag("key_glob", exportCmd.PersistentFlags().Lookup("key-glob"))
+	_ = viper.BindPFlag("min_value_size", exportCmd.PersistentFlags().Lookup("min-value-size"))
+	_ = viper.BindPFlag("max_value_size", exportCmd.PersistentFlags().Lookup("max-value-size"))
+	_ = viper.BindPFlag("modified_since", exportCmd.PersistentFlags().Lookup("modified-since"))
+	_ = viper.BindPFlag("format", exportCmd.PersistentFlags().Lookup("format"))
+	_ = viper.BindPFlag("certificate_file", exportCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", exportCmd.PersistentFlags().Lookup("common-name"))
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	failoverCmd = &cobra.Command{
+		Use:   "failover",
+		Args:  cobra.NoArgs,
+		Short: "Promote a standby cluster to primary",
+		Long:  "Freeze the source cluster of the standby given by --grpc-address, wait for the standby to replicate the writes up to the freeze, then promote the standby to primary. The source stays frozen. With --force, the standby is promoted even if the source can not be frozen or the standby does not catch up, and the writes it has not replicated are lost",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+			failoverSource = viper.GetString("failover_source")
+			failoverReason = viper.GetString("failover_reason")
+			failoverTimeout = viper.GetDuration("failover_timeout")
+			forceFailover = viper.GetBool("failover_force")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			resp, err := c.Role()
+			if err != nil {
+				return err
+			}
+			if resp.Role.Mode != protobuf.Role_Standby {
+				return fmt.Errorf("the cluster of %s is not a standby but a %s", grpcAddress, strings.ToLower(resp.Role.Mode.String()))
+			}
+			if failoverSource == "" {
+				failoverSource = resp.Role.Source
+			}
+
+			frozenIndex, err := freezeSource(failoverSource, failoverReason, failoverTimeout)
+			switch {
+			case err != nil && !forceFailover:
+				return fmt.Errorf("failed to freeze the source cluster %s, promote the standby anyway with --force: %w", failoverSource, err)
+			case err != nil:
+				fmt.Printf("failed to freeze the source cluster %s, the writes it took may not be replicated: %v\n", failoverSource, err)
+			default:
+				fmt.Printf("froze the source cluster %s at index %d\n", failoverSource, frozenIndex)
+				err := waitReplicated(c, frozenIndex, failoverTimeout)
+				switch {
+				case err != nil && !forceFailover:
+					return fmt.Errorf("%w, the source cluster stays frozen until it is set back to primary, or promote the standby anyway with --force", err)
+				case err != nil:
+					fmt.Printf("%v, the writes not replicated are lost\n", err)
+				}
+			}
+
+			req := &protobuf.SetRoleRequest{
+				Mode:   protobuf.Role_Primary,
+				Reason: failoverReason,
+			}
+			if err := c.SetRole(req); err != nil {
+				return err
+			}
+
+			resp, err = c.Role()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("promoted the standby to primary at index %d, replicated up to index %d of the source cluster\n", resp.Role.Index, resp.ReplicatedIndex)
+
+			return nil
+		},
+	}
+)
+
+// freezeSource freezes the source cluster and returns the index of the freeze in its log.
+func freezeSource(source string, reason string, timeout time.Duration) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	c, err := client.NewGRPCClientWithOptions(source, ctx, client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	if err := c.SetRole(&protobuf.SetRoleRequest{Mode: protobuf.Role_Frozen, Reason: reason}); err != nil {
+		return 0, err
+	}
+
+	// a follower may not have applied the freeze yet
+	for {
+		resp, err := c.Role()
+		if err != nil {
+			return 0, err
+		}
+		if resp.Role.Mode == protobuf.Role_Frozen {
+			return resp.Role.Index, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// waitReplicated waits for the standby to replicate the source up to the index.
+func waitReplicated(c *client.GRPCClient, index uint64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := c.Role()
+		if err != nil {
+			return err
+		}
+		if resp.ReplicatedIndex >= index {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the standby replicated up to index %d of the source cluster, not %d", resp.ReplicatedIndex, index)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(failoverCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	failoverCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	failoverCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	failoverCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	failoverCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+	failoverCmd.PersistentFlags().StringVar(&failoverSource, "source", "", "gRPC address of a node of the source cluster. defaults to the source of the standby")
+	failoverCmd.PersistentFlags().StringVar(&failoverReason, "reason", "", "why the failover happens, e.g. its ticket. recorded in the roles of both clusters")
+	failoverCmd.PersistentFlags().DurationVar(&failoverTimeout, "timeout", time.Minute, "how long to wait for the source to be frozen and for the standby to catch up")
+	failoverCmd.PersistentFlags().BoolVar(&forceFailover, "force", false, "promote the standby even if the source can not be frozen or the standby does not catch up")
+
+	_ = viper.BindPFlag("grpc_address", failoverCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("certificate_file", failoverCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", failoverCmd.PersistentFlags().Lookup("common-name"))
+	_ = viper.BindPFlag("failover_source", failoverCmd.PersistentFlags().Lookup("source"))
+	_ = viper.BindPFlag("failover_reason", failoverCmd.PersistentFlags().Lookup("reason"))
+	_ = viper.BindPFlag("failover_timeout", failoverCmd.PersistentFlags().Lookup("timeout"))
+	_ = viper.BindPFlag("failover_force", failoverCmd.PersistentFlags().Lookup("force"))
+}
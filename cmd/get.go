@@ -23,10 +23,20 @@ var (
 
 			certificateFile = viper.GetString("certificate_file")
 			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			showIndex = viper.GetBool("show_index")
+			getOffset = viper.GetUint64("offset")
+			getLength = viper.GetUint64("length")
 
 			key := args[0]
 
-			c, err := client.NewGRPCClientWithContextTLS(grpcAddress, context.Background(), certificateFile, commonName)
+			consistency, err := protobuf.ParseConsistency(viper.GetString("consistency"))
+			if err != nil {
+				return err
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
 			if err != nil {
 				return err
 			}
@@ -35,7 +45,10 @@ var (
 			}()
 
 			req := &protobuf.GetRequest{
-				Key: key,
+				Key:         key,
+				Consistency: consistency,
+				Offset:      getOffset,
+				Length:      getLength,
 			}
 
 			resp, err := c.Get(req)
@@ -43,7 +56,11 @@ var (
 				return err
 			}
 
-			fmt.Println(string(resp.Value))
+			if showIndex {
+				fmt.Printf("%d\t%s\n", resp.ModifiedIndex, string(resp.Value))
+			} else {
+				fmt.Println(string(resp.Value))
+			}
 
 			return nil
 		},
@@ -85,10 +102,18 @@ func init() {
 
 	getCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
 	getCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	getCmd.PersistentFlags().StringVar(&readConsistency, "consistency", "stale", "read consistency. stale reads the node, strong reads the leader, leader_preferred reads the leader if it can be reached")
+	getCmd.PersistentFlags().Uint64Var(&getOffset, "offset", 0, "offset of the range of the value to read")
+	getCmd.PersistentFlags().Uint64Var(&getLength, "length", 0, "maximum length of the range of the value to read. up to the end of the value if omitted")
+	getCmd.PersistentFlags().BoolVar(&showIndex, "show-index", false, "print the index of the log entry that last modified the key before the value")
 	getCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
 	getCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
 
 	_ = viper.BindPFlag("grpc_address", getCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("consistency", getCmd.PersistentFlags().Lookup("consistency"))
+	_ = viper.BindPFlag("offset", getCmd.PersistentFlags().Lookup("offset"))
+	_ = viper.BindPFlag("length", getCmd.PersistentFlags().Lookup("length"))
+	_ = viper.BindPFlag("show_index", getCmd.PersistentFlags().Lookup("show-index"))
 	_ = viper.BindPFlag("certificate_file", getCmd.PersistentFlags().Lookup("certificate-file"))
 	_ = viper.BindPFlag("common_name", getCmd.PersistentFlags().Lookup("common-name"))
 }
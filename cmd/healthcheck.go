@@ -22,8 +22,9 @@ var (
 
 			certificateFile = viper.GetString("certificate_file")
 			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
 
-			c, err := client.NewGRPCClientWithContextTLS(grpcAddress, context.Background(), certificateFile, commonName)
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
 			if err != nil {
 				return err
 			}
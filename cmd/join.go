@@ -23,11 +23,12 @@ var (
 
 			certificateFile = viper.GetString("certificate_file")
 			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
 
 			id := args[0]
 			targetGrpcAddress := args[1]
 
-			t, err := client.NewGRPCClientWithContextTLS(targetGrpcAddress, context.Background(), certificateFile, commonName)
+			t, err := client.NewGRPCClientWithOptions(targetGrpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
 			if err != nil {
 				return err
 			}
@@ -40,7 +41,7 @@ var (
 				return err
 			}
 
-			c, err := client.NewGRPCClientWithContextTLS(grpcAddress, context.Background(), certificateFile, commonName)
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// parseMetadata parses key=value pairs into gRPC metadata.
+func parseMetadata(pairs []string) (metadata.MD, error) {
+	md := metadata.MD{}
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("metadata %q is not in key=value format", pair)
+		}
+		md.Append(kv[0], kv[1])
+	}
+
+	return md, nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	moveCmd = &cobra.Command{
+		Use:   "move SOURCE DESTINATION",
+		Args:  cobra.ExactArgs(2),
+		Short: "Move a key-value, or the key-values with a prefix, to another key or prefix",
+		Long:  "Move a key-value, or the key-values with a prefix, to another key or prefix. With --prefix, the source prefix of the keys is replaced by the destination",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			requestMetadata = viper.GetStringSlice("metadata")
+
+			copyPrefix = viper.GetBool("prefix")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			source := args[0]
+			destination := args[1]
+
+			md, err := parseMetadata(requestMetadata)
+			if err != nil {
+				return err
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			req := &protobuf.MoveRequest{
+				Source:      source,
+				Destination: destination,
+				Prefix:      copyPrefix,
+			}
+
+			if err := c.Move(req, client.OutgoingMetadata(md)); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	moveCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	moveCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	moveCmd.PersistentFlags().BoolVar(&copyPrefix, "prefix", false, "move the key-values with the source prefix")
+	moveCmd.PersistentFlags().StringSliceVar(&requestMetadata, "metadata", []string{}, "gRPC metadata sent with the request, e.g. x-client-id=batch-loader")
+	moveCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	moveCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", moveCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("prefix", moveCmd.PersistentFlags().Lookup("prefix"))
+	_ = viper.BindPFlag("metadata", moveCmd.PersistentFlags().Lookup("metadata"))
+	_ = viper.BindPFlag("certificate_file", moveCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", moveCmd.PersistentFlags().Lookup("common-name"))
+}
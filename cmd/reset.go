@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/log"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/mosuka/cete/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	resetCmd = &cobra.Command{
+		Use:   "reset",
+		Args:  cobra.NoArgs,
+		Short: "Wipe the data of a node that left the cluster",
+		Long:  "Verify that the node has been removed from the cluster and that its data is no longer needed, then wipe its data directory so that it can join the cluster again with a fresh ID. The node must be stopped",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id = viper.GetString("id")
+			dataDirectory = viper.GetString("data_directory")
+			kvsDirectory = viper.GetString("kvs_directory")
+			raftDirectory = viper.GetString("raft_directory")
+			snapshotDirectory = viper.GetString("snapshot_directory")
+			peerGrpcAddress = viper.GetString("peer_grpc_address")
+			forceReset = viper.GetBool("force")
+			resetTimeout = viper.GetDuration("timeout")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			logLevel = viper.GetString("log_level")
+
+			if peerGrpcAddress == "" {
+				return fmt.Errorf("--peer-grpc-address is required to verify that the node left the cluster")
+			}
+
+			c, err := client.NewGRPCClientWithOptions(peerGrpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			resp, err := c.Cluster()
+			if err != nil {
+				return err
+			}
+			if _, member := resp.Cluster.Nodes[id]; member {
+				if !forceReset {
+					return fmt.Errorf("node %s is still a member of the cluster, remove it first or use --force", id)
+				}
+				if err := c.Leave(&protobuf.LeaveRequest{Id: id}); err != nil {
+					return err
+				}
+			}
+
+			if err := waitSafeToWipe(c, id, resetTimeout); err != nil {
+				return err
+			}
+
+			logger := log.NewLogger(logLevel, "", 500, 3, 30, false)
+			if err := server.ResetDataDirectory(dataDirectory, logger, server.WithStorageDirectories(kvsDirectory, raftDirectory, snapshotDirectory)); err != nil {
+				return err
+			}
+
+			// the old ID is still known to the cluster, the node rejoins under a new one
+			newID := make([]byte, 4)
+			if _, err := rand.Read(newID); err != nil {
+				return err
+			}
+			fmt.Printf("%s-%s\n", id, hex.EncodeToString(newID))
+
+			return nil
+		},
+	}
+)
+
+// waitSafeToWipe waits until every remaining voter has applied the removal of the node.
+// Nodes without a decommission status left the cluster before it was tracked.
+func waitSafeToWipe(c *client.GRPCClient, id string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := c.DecommissionStatus(&protobuf.DecommissionStatusRequest{Id: id})
+		switch {
+		case errors.Is(err, errors.ErrNotFound):
+			return nil
+		case err != nil:
+			return err
+		case resp.Status.Phase == protobuf.DecommissionStatus_SafeToWipe:
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node %s is not safe to wipe yet, decommission phase is %s", id, resp.Status.Phase.String())
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(resetCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	resetCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	resetCmd.PersistentFlags().StringVar(&id, "id", "", "node ID")
+	_ = resetCmd.MarkPersistentFlagRequired("id")
+	resetCmd.PersistentFlags().StringVar(&dataDirectory, "data-directory", "/tmp/cete/data", "data directory which store the key-value store data and Raft logs")
+	resetCmd.PersistentFlags().StringVar(&kvsDirectory, "kvs-directory", "", "directory of the key-value store. if omitted, kvs in the data directory is used")
+	resetCmd.PersistentFlags().StringVar(&raftDirectory, "raft-directory", "", "directory of the Raft logs. if omitted, raft in the data directory is used")
+	resetCmd.PersistentFlags().StringVar(&snapshotDirectory, "snapshot-directory", "", "directory under which the Raft snapshots are stored. if omitted, the data directory is used")
+	resetCmd.PersistentFlags().StringVar(&peerGrpcAddress, "peer-grpc-address", "", "listen address of a gRPC server in the cluster the node left")
+	resetCmd.PersistentFlags().BoolVar(&forceReset, "force", false, "remove the node from the cluster if it is still a member")
+	resetCmd.PersistentFlags().DurationVar(&resetTimeout, "timeout", 30*time.Second, "how long to wait for the node data to become safe to wipe")
+	resetCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	resetCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+	resetCmd.PersistentFlags().StringVar(&logLevel, "log-level", "INFO", "log level")
+
+	_ = viper.BindPFlag("id", resetCmd.PersistentFlags().Lookup("id"))
+	_ = viper.BindPFlag("data_directory", resetCmd.PersistentFlags().Lookup("data-directory"))
+	_ = viper.BindPFlag("kvs_directory", resetCmd.PersistentFlags().Lookup("kvs-directory"))
+	_ = viper.BindPFlag("raft_directory", resetCmd.PersistentFlags().Lookup("raft-directory"))
+	_ = viper.BindPFlag("snapshot_directory", resetCmd.PersistentFlags().Lookup("snapshot-directory"))
+	_ = viper.BindPFlag("peer_grpc_address", resetCmd.PersistentFlags().Lookup("peer-grpc-address"))
+	_ = viper.BindPFlag("force", resetCmd.PersistentFlags().Lookup("force"))
+	_ = viper.BindPFlag("timeout", resetCmd.PersistentFlags().Lookup("timeout"))
+	_ = viper.BindPFlag("certificate_file", resetCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", resetCmd.PersistentFlags().Lookup("common-name"))
+	_ = viper.BindPFlag("log_level", resetCmd.PersistentFlags().Lookup("log-level"))
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mosuka/cete/server"
+)
+
+// parseRetention parses prefix=max-age pairs into the compaction filters deleting the keys
+// with the prefix not modified for longer than the maximum age, e.g. /logs/=720h.
+func parseRetention(pairs []string) ([]server.RaftServerOption, error) {
+	opts := make([]server.RaftServerOption, 0, len(pairs))
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("retention %q is not in prefix=max-age format", pair)
+		}
+		maxAge, err := time.ParseDuration(kv[1])
+		if err != nil || maxAge <= 0 {
+			return nil, fmt.Errorf("retention %q has an invalid max age", pair)
+		}
+		opts = append(opts, server.WithCompactionFilter("retention:"+kv[0], kv[0], server.RetentionFilter(maxAge)))
+	}
+
+	return opts, nil
+}
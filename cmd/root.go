@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -15,3 +16,9 @@ var (
 func Execute() error {
 	return rootCmd.Execute()
 }
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&authToken, "auth-token", "", "token required by the node on every request but the health checks, or presented by the client, which may present an API key instead. the node also accepts a secret reference such as vault://secret/data/cete#token")
+
+	_ = viper.BindPFlag("auth_token", rootCmd.PersistentFlags().Lookup("auth-token"))
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	scanCmd = &cobra.Command{
+		Use:   "scan [PREFIX]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Scan key-values",
+		Long:  "Scan key-values with a prefix, optionally filtered on the server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+
+			keyRegexp = viper.GetString("key_regexp")
+			keyGlob = viper.GetString("key_glob")
+			minValueSize = viper.GetUint64("min_value_size")
+			maxValueSize = viper.GetUint64("max_value_size")
+			modifiedSince = viper.GetUint64("modified_since")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			prefix := ""
+			if len(args) > 0 {
+				prefix = args[0]
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			req := &protobuf.ScanRequest{
+				Prefix:        prefix,
+				KeyRegexp:     keyRegexp,
+				KeyGlob:       keyGlob,
+				MinValueSize:  minValueSize,
+				MaxValueSize:  maxValueSize,
+				ModifiedSince: modifiedSince,
+			}
+
+			resp, err := c.Scan(req)
+			if err != nil {
+				return err
+			}
+
+			for _, value := range resp.Values {
+				fmt.Println(string(value))
+			}
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	scanCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	scanCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	scanCmd.PersistentFlags().StringVar(&keyRegexp, "key-regexp", "", "return only the keys matching the regular expression")
+	scanCmd.PersistentFlags().StringVar(&keyGlob, "key-glob", "", "return only the keys matching the glob pattern")
+	scanCmd.PersistentFlags().Uint64Var(&minValueSize, "min-value-size", 0, "return only the values of at least this many bytes")
+	scanCmd.PersistentFlags().Uint64Var(&maxValueSize, "max-value-size", 0, "return only the values of at most this many bytes. 0 means no limit")
+	scanCmd.PersistentFlags().Uint64Var(&modifiedSince, "modified-since", 0, "return only the keys modified at or after this Raft log index")
+	scanCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	scanCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+
+	_ = viper.BindPFlag("grpc_address", scanCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("key_regexp", scanCmd.PersistentFlags().Lookup("key-regexp"))
+	_ = viper.BindPFlag("key_glob", scanCmd.PersistentFlags().Lookup("key-glob"))
+	_ = viper.BindPFlag("min_value_size", scanCmd.PersistentFlags().Lookup("min-value-size"))
+	_ = viper.BindPFlag("max_value_size", scanCmd.PersistentFlags().Lookup("max-value-size"))
+	_ = viper.BindPFlag("modified_since", scanCmd.PersistentFlags().Lookup("modified-since"))
+	_ = viper.BindPFlag("certificate_file", scanCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", scanCmd.PersistentFlags().Lookup("common-name"))
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/mitchellh/go-homedir"
 	"github.com/mosuka/cete/client"
 	"github.com/mosuka/cete/protobuf"
@@ -21,13 +23,27 @@ var (
 		RunE: func(cmd *cobra.Command, args []string) error {
 			grpcAddress = viper.GetString("grpc_address")
 
+			requestMetadata = viper.GetStringSlice("metadata")
+
+			expectedIndex = viper.GetInt64("expected_index")
+
+			ttl = viper.GetDuration("ttl")
+
+			checksum = viper.GetBool("checksum")
+
 			certificateFile = viper.GetString("certificate_file")
 			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
 
 			key := args[0]
 			value := args[1]
 
-			c, err := client.NewGRPCClientWithContextTLS(grpcAddress, context.Background(), certificateFile, commonName)
+			md, err := parseMetadata(requestMetadata)
+			if err != nil {
+				return err
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken), client.WithChecksums(checksum))
 			if err != nil {
 				return err
 			}
@@ -39,8 +55,14 @@ var (
 				Key:   key,
 				Value: []byte(value),
 			}
+			if ttl > 0 {
+				req.Ttl = ptypes.DurationProto(ttl)
+			}
+			if expectedIndex >= 0 {
+				req.ExpectedIndex = &wrappers.UInt64Value{Value: uint64(expectedIndex)}
+			}
 
-			if err := c.Set(req); err != nil {
+			if err := c.Set(req, client.OutgoingMetadata(md)); err != nil {
 				return err
 			}
 
@@ -84,10 +106,18 @@ func init() {
 
 	setCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
 	setCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	setCmd.PersistentFlags().DurationVar(&ttl, "ttl", 0, "time after which the key-value expires. if omitted, it never expires")
+	setCmd.PersistentFlags().Int64Var(&expectedIndex, "expected-index", -1, "abort the write unless the key was last modified at the index, or does not exist if 0. -1 disables the check")
+	setCmd.PersistentFlags().BoolVar(&checksum, "checksum", false, "send the checksum of the value, so that the nodes refuse to write it if it was corrupted on its way")
+	setCmd.PersistentFlags().StringSliceVar(&requestMetadata, "metadata", []string{}, "gRPC metadata sent with the request, e.g. x-client-id=batch-loader")
 	setCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
 	setCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
 
 	_ = viper.BindPFlag("grpc_address", setCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("ttl", setCmd.PersistentFlags().Lookup("ttl"))
+	_ = viper.BindPFlag("expected_index", setCmd.PersistentFlags().Lookup("expected-index"))
+	_ = viper.BindPFlag("checksum", setCmd.PersistentFlags().Lookup("checksum"))
+	_ = viper.BindPFlag("metadata", setCmd.PersistentFlags().Lookup("metadata"))
 	_ = viper.BindPFlag("certificate_file", setCmd.PersistentFlags().Lookup("certificate-file"))
 	_ = viper.BindPFlag("common_name", setCmd.PersistentFlags().Lookup("common-name"))
 }
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	setRoleCmd = &cobra.Command{
+		Use:   "set-role MODE",
+		Args:  cobra.ExactArgs(1),
+		Short: "Set the role of the cluster",
+		Long:  "Set the role of the cluster to primary, standby or frozen. A standby refuses the writes of the clients and replicates those of the cluster given by --source, after --replicated-index. A frozen cluster refuses the writes, so that its standby catches up before it is promoted, see the failover command",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+			roleSource = viper.GetString("role_source")
+			roleReason = viper.GetString("role_reason")
+			replicatedIndex = viper.GetUint64("replicated_index")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			mode, err := parseRoleMode(args[0])
+			if err != nil {
+				return err
+			}
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			req := &protobuf.SetRoleRequest{
+				Mode:            mode,
+				Source:          roleSource,
+				Reason:          roleReason,
+				ReplicatedIndex: replicatedIndex,
+			}
+
+			if err := c.SetRole(req); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+)
+
+func parseRoleMode(s string) (protobuf.Role_Mode, error) {
+	for name, value := range protobuf.Role_Mode_value {
+		if strings.EqualFold(name, s) {
+			return protobuf.Role_Mode(value), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown role %q, expected primary, standby or frozen", s)
+}
+
+func init() {
+	rootCmd.AddCommand(setRoleCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	setRoleCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	setRoleCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	setRoleCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	setRoleCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+	setRoleCmd.PersistentFlags().StringVar(&roleSource, "source", "", "gRPC address of a node of the cluster a standby replicates")
+	setRoleCmd.PersistentFlags().StringVar(&roleReason, "reason", "", "why the role is set, e.g. the ticket of a failover")
+	setRoleCmd.PersistentFlags().Uint64Var(&replicatedIndex, "replicated-index", 0, "index in the log of the source after which a standby replicates, e.g. the applied index of a copy of its data. 0 replicates the whole log, or keeps the replicated index of a standby")
+
+	_ = viper.BindPFlag("grpc_address", setRoleCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("certificate_file", setRoleCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", setRoleCmd.PersistentFlags().Lookup("common-name"))
+	_ = viper.BindPFlag("role_source", setRoleCmd.PersistentFlags().Lookup("source"))
+	_ = viper.BindPFlag("role_reason", setRoleCmd.PersistentFlags().Lookup("reason"))
+	_ = viper.BindPFlag("replicated_index", setRoleCmd.PersistentFlags().Lookup("replicated-index"))
+}
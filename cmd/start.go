@@ -5,16 +5,20 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/mosuka/cete/client"
 	"github.com/mosuka/cete/log"
+	"github.com/mosuka/cete/marshaler"
 	"github.com/mosuka/cete/protobuf"
+	"github.com/mosuka/cete/secret"
 	"github.com/mosuka/cete/server"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 var (
@@ -23,16 +27,70 @@ var (
 		Short: "Start the key value store server",
 		Long:  "Start the key value store server",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// the profile sets the defaults of the other flags
+			profileName = viper.GetString("profile")
+			profile, err := server.ParseProfile(profileName)
+			if err != nil {
+				return err
+			}
+			viper.SetDefault("snapshot_rate_limit", profile.SnapshotRateLimit)
+			viper.SetDefault("catch_up_as_nonvoter", profile.CatchUpAsNonvoter)
+			viper.SetDefault("transport_max_pool", profile.TransportMaxPool)
+			viper.SetDefault("transport_timeout", profile.TransportTimeout)
+			viper.SetDefault("transport_timeout_scale", profile.TransportTimeoutScale/1024)
+
 			id = viper.GetString("id")
+			idGenerator = viper.GetString("id_generator")
 			raftAddress = viper.GetString("raft_address")
 			grpcAddress = viper.GetString("grpc_address")
 			httpAddress = viper.GetString("http_address")
+			readOnlyGrpcAddress = viper.GetString("read_only_grpc_address")
 			dataDirectory = viper.GetString("data_directory")
+			kvsDirectory = viper.GetString("kvs_directory")
+			raftDirectory = viper.GetString("raft_directory")
+			snapshotDirectory = viper.GetString("snapshot_directory")
 			peerGrpcAddress = viper.GetString("peer_grpc_address")
+			disableForwarding = viper.GetBool("disable_forwarding")
+			reconcileInterval = viper.GetDuration("reconcile_membership_interval")
+			sweepInterval = viper.GetDuration("expiration_sweep_interval")
+			retention = viper.GetStringSlice("retention")
+			snapshotLogSize = viper.GetInt64("snapshot_log_size")
+			snapshotMaxInterval = viper.GetDuration("snapshot_max_interval")
+			catchUpAsNonvoter = viper.GetBool("catch_up_as_nonvoter")
+			peerAllowlistIDs = viper.GetStringSlice("peer_allowlist_ids")
+			peerAllowlistCIDRs = viper.GetStringSlice("peer_allowlist_cidrs")
+			exportDirectory = viper.GetString("export_directory")
+			exportURLPrefixes = viper.GetStringSlice("export_url_prefixes")
+			snapshotRateLimit = viper.GetInt64("snapshot_rate_limit")
+			transportMaxPool = viper.GetInt("transport_max_pool")
+			transportTimeout = viper.GetDuration("transport_timeout")
+			transportTimeoutScale = viper.GetInt64("transport_timeout_scale")
+			disableFastRestart = viper.GetBool("disable_fast_restart")
+			quorumLossTimeout = viper.GetDuration("quorum_loss_timeout")
+			nodeStatusInterval = viper.GetDuration("node_status_interval")
+			nodeStatusJitter = viper.GetDuration("node_status_jitter")
+			warmupTimeout = viper.GetDuration("warmup_timeout")
+			warmupPrefixes = viper.GetStringSlice("warmup_prefixes")
+			disableBackpressure = viper.GetBool("disable_write_backpressure")
+			disableWriteFencing = viper.GetBool("disable_write_fencing")
+			propagateMetadata = viper.GetStringSlice("propagate_metadata")
+			priorityPrefixes = viper.GetStringSlice("priority_prefixes")
+			watchBufferSize = viper.GetInt("watch_buffer_size")
+			watchOverflow = viper.GetString("watch_overflow_policy")
+			slowRequestThreshold = viper.GetDuration("slow_request_threshold")
+			injectMethods = viper.GetStringSlice("inject_methods")
+			injectLatency = viper.GetDuration("inject_latency")
+			injectJitter = viper.GetDuration("inject_jitter")
+			injectErrorRate = viper.GetFloat64("inject_error_rate")
+			payloadMethods = viper.GetStringSlice("log_payload_methods")
+			redactKeys = viper.GetStringSlice("log_payload_redact_keys")
+			redactValues = viper.GetStringSlice("log_payload_redact_values")
 
 			certificateFile = viper.GetString("certificate_file")
 			keyFile = viper.GetString("key_file")
 			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+			secretRefreshInterval = viper.GetDuration("secret_refresh_interval")
 
 			logLevel = viper.GetString("log_level")
 			logFile = viper.GetString("log_file")
@@ -49,15 +107,86 @@ var (
 				logMaxAge,
 				logCompress,
 			)
+			marshaler.SetLogger(logger.Named("marshaler"))
+
+			var generate server.IDGenerator
+			switch idGenerator {
+			case "uuid":
+				generate = server.UUIDGenerator
+			case "hostname":
+				generate = server.HostnameGenerator
+			default:
+				return fmt.Errorf("unknown ID generator %q", idGenerator)
+			}
+			if id, err = server.NodeIdentity(dataDirectory, id, generate, server.WithStorageDirectories(kvsDirectory, raftDirectory, snapshotDirectory)); err != nil {
+				return err
+			}
+			logger.Info("node identity", zap.String("id", id), zap.String("data_directory", dataDirectory))
+
+			// fetch the TLS keys from their secret providers
+			secrets, err := secret.NewFiles(logger)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = secrets.Close()
+			}()
+			if certificateFile, err = secrets.Path(context.Background(), certificateFile); err != nil {
+				return err
+			}
+			if keyFile, err = secrets.Path(context.Background(), keyFile); err != nil {
+				return err
+			}
+			secrets.Start(secretRefreshInterval)
+			if secret.IsReference(authToken) {
+				token, err := secret.Fetch(context.Background(), authToken)
+				if err != nil {
+					return err
+				}
+				authToken = strings.TrimSpace(string(token))
+			}
 
 			bootstrap := peerGrpcAddress == "" || peerGrpcAddress == grpcAddress
 
-			raftServer, err := server.NewRaftServer(id, raftAddress, dataDirectory, bootstrap, logger)
+			profile.TransportMaxPool = transportMaxPool
+			profile.TransportTimeout = transportTimeout
+			profile.TransportTimeoutScale = int(transportTimeoutScale * 1024)
+			raftOpts, err := parseRetention(retention)
+			if err != nil {
+				return err
+			}
+			if len(peerAllowlistIDs) > 0 || len(peerAllowlistCIDRs) > 0 {
+				allowlist, err := server.NewPeerAllowlist(peerAllowlistIDs, peerAllowlistCIDRs)
+				if err != nil {
+					return err
+				}
+				raftOpts = append(raftOpts, server.WithPeerAllowlist(allowlist))
+			}
+			raftServer, err := server.NewRaftServer(id, raftAddress, dataDirectory, bootstrap, logger, append([]server.RaftServerOption{server.WithMembershipReconciliation(reconcileInterval), server.WithExpirationSweep(sweepInterval), server.WithAdaptiveSnapshots(uint64(snapshotLogSize)*1024*1024, snapshotMaxInterval), server.WithSnapshotRateLimit(snapshotRateLimit * 1024 * 1024), server.WithStorageDirectories(kvsDirectory, raftDirectory, snapshotDirectory), server.WithPropagatedMetadata(propagateMetadata...), server.WithPriorityPrefixes(priorityPrefixes...), server.WithProfile(profile), server.WithFastRestart(!disableFastRestart), server.WithQuorumLossTimeout(quorumLossTimeout), server.WithWriteBackpressure(!disableBackpressure), server.WithWriteFencing(!disableWriteFencing), server.WithExportDestinations(exportDirectory, exportURLPrefixes...)}, raftOpts...)...)
 			if err != nil {
 				return err
 			}
 
-			grpcServer, err := server.NewGRPCServer(grpcAddress, raftServer, certificateFile, keyFile, commonName, logger)
+			if injectErrorRate < 0 || injectErrorRate > 1 {
+				return fmt.Errorf("--inject-error-rate must be between 0 and 1, saw %v", injectErrorRate)
+			}
+
+			watchOverflowPolicy, err := server.ParseWatchOverflowPolicy(watchOverflow)
+			if err != nil {
+				return err
+			}
+
+			metadata := &protobuf.Metadata{
+				GrpcAddress:         grpcAddress,
+				HttpAddress:         httpAddress,
+				ReadOnlyGrpcAddress: readOnlyGrpcAddress,
+			}
+			// the token must not end up in the diagnostic dumps
+			diagnosticsConfig := viper.AllSettings()
+			if authToken != "" {
+				diagnosticsConfig["auth_token"] = "REDACTED"
+			}
+			grpcServer, err := server.NewGRPCServer(grpcAddress, raftServer, certificateFile, keyFile, commonName, logger, server.WithForwarding(!disableForwarding), server.WithWatchBuffer(watchBufferSize, watchOverflowPolicy), server.WithSlowRequestThreshold(slowRequestThreshold), server.WithDiagnosticsConfig(diagnosticsConfig), server.WithFaultInjection(server.FaultInjection{Methods: injectMethods, Latency: injectLatency, Jitter: injectJitter, ErrorRate: injectErrorRate}), server.WithPayloadLogging(server.PayloadLogging{Methods: payloadMethods, RedactKeys: redactKeys, RedactValues: redactValues}), server.WithReadOnlyAddress(readOnlyGrpcAddress), server.WithNodeStatusPropagation(nodeStatusInterval, nodeStatusJitter, metadata), server.WithWarmup(warmupTimeout, warmupPrefixes), server.WithAuthToken(authToken))
 			if err != nil {
 				return err
 			}
@@ -68,7 +197,20 @@ var (
 			}
 
 			quitCh := make(chan os.Signal, 1)
-			signal.Notify(quitCh, os.Kill, os.Interrupt, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+			signal.Notify(quitCh, os.Kill, os.Interrupt, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+
+			// dump the diagnostics on SIGQUIT instead of exiting
+			dumpCh := make(chan os.Signal, 1)
+			signal.Notify(dumpCh, syscall.SIGQUIT)
+			go func() {
+				for range dumpCh {
+					if path, err := grpcServer.Dump(); err != nil {
+						logger.Error("failed to dump diagnostics", zap.Error(err))
+					} else {
+						logger.Info("diagnostics dumped on SIGQUIT", zap.String("path", path))
+					}
+				}
+			}()
 
 			if err := raftServer.Start(); err != nil {
 				return err
@@ -98,7 +240,7 @@ var (
 				joinGrpcAddress = peerGrpcAddress
 			}
 
-			c, err := client.NewGRPCClientWithContextTLS(joinGrpcAddress, context.Background(), certificateFile, commonName)
+			c, err := client.NewGRPCClientWithOptions(joinGrpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
 			if err != nil {
 				return err
 			}
@@ -111,11 +253,9 @@ var (
 				Id: id,
 				Node: &protobuf.Node{
 					RaftAddress: raftAddress,
-					Metadata: &protobuf.Metadata{
-						GrpcAddress: grpcAddress,
-						HttpAddress: httpAddress,
-					},
+					Metadata:    metadata,
 				},
+				CatchUpAsNonvoter: catchUpAsNonvoter && !bootstrap,
 			}
 			if err = c.Join(joinRequest); err != nil {
 				return err
@@ -167,15 +307,57 @@ func init() {
 	})
 
 	startCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
-	startCmd.PersistentFlags().StringVar(&id, "id", "node1", "node ID")
+	startCmd.PersistentFlags().StringVar(&id, "id", "", "node ID. if omitted, the ID recorded in the data directory is used, or a new one is generated")
+	startCmd.PersistentFlags().StringVar(&idGenerator, "id-generator", "uuid", "how the ID of a new node is generated when --id is omitted. uuid for a random UUID, hostname for the host name")
 	startCmd.PersistentFlags().StringVar(&raftAddress, "raft-address", ":7000", "Raft server listen address")
 	startCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
 	startCmd.PersistentFlags().StringVar(&httpAddress, "http-address", ":8000", "HTTP server listen address")
+	startCmd.PersistentFlags().StringVar(&readOnlyGrpcAddress, "read-only-grpc-address", "", "gRPC listen address serving only reads. writes are refused with the leader. disabled if omitted")
 	startCmd.PersistentFlags().StringVar(&dataDirectory, "data-directory", "/tmp/cete/data", "data directory which store the key-value store data and Raft logs")
+	startCmd.PersistentFlags().StringVar(&kvsDirectory, "kvs-directory", "", "directory of the key-value store. if omitted, kvs in the data directory is used")
+	startCmd.PersistentFlags().StringVar(&raftDirectory, "raft-directory", "", "directory of the Raft logs. if omitted, raft in the data directory is used")
+	startCmd.PersistentFlags().StringVar(&snapshotDirectory, "snapshot-directory", "", "directory under which the Raft snapshots are stored. if omitted, the data directory is used")
 	startCmd.PersistentFlags().StringVar(&peerGrpcAddress, "peer-grpc-address", "", "listen address of the existing gRPC server in the joining cluster")
-	startCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
-	startCmd.PersistentFlags().StringVar(&keyFile, "key-file", "", "path to the client server TLS key file")
+	startCmd.PersistentFlags().BoolVar(&disableForwarding, "disable-forwarding", false, "reject write requests on a follower with the leader address instead of forwarding them to the leader")
+	startCmd.PersistentFlags().DurationVar(&reconcileInterval, "reconcile-membership-interval", 0, "interval at which the leader reconciles the cluster membership against the membership spec. 0 disables the reconciliation")
+	startCmd.PersistentFlags().DurationVar(&sweepInterval, "expiration-sweep-interval", time.Minute, "interval at which the leader deletes the expired keys. 0 disables the sweep")
+	startCmd.PersistentFlags().StringSliceVar(&retention, "retention", []string{}, "prefix=max-age pairs, the keys with the prefix not modified for longer than the maximum age are deleted at every expiration sweep, e.g. /logs/=720h")
+	startCmd.PersistentFlags().StringVar(&profileName, "profile", server.LANProfile.Name, "settings suited to the network between the nodes. lan for the nodes of a data center, wan for nodes spread over data centers")
+	startCmd.PersistentFlags().BoolVar(&catchUpAsNonvoter, "catch-up-as-nonvoter", false, "join the cluster as a non-voter, also when restarting, and become a voter once caught up with the leader. defaults to true with the wan profile")
+	startCmd.PersistentFlags().StringSliceVar(&peerAllowlistIDs, "peer-allowlist-ids", []string{}, "patterns of the node IDs that may join the cluster, e.g. node*. any ID if omitted")
+	startCmd.PersistentFlags().StringSliceVar(&peerAllowlistCIDRs, "peer-allowlist-cidrs", []string{}, "networks of the Raft addresses that may join the cluster and connect to the Raft transport, e.g. 10.0.0.0/8. any address if omitted")
+	startCmd.PersistentFlags().StringVar(&exportDirectory, "export-directory", "", "directory of the files the exports may be written to. file exports are refused if omitted")
+	startCmd.PersistentFlags().StringSliceVar(&exportURLPrefixes, "export-url-prefixes", []string{}, "prefixes of the http(s) URLs the exports may be uploaded to, e.g. https://bucket.s3.amazonaws.com/backups/. uploads are refused if omitted")
+	startCmd.PersistentFlags().Int64Var(&snapshotLogSize, "snapshot-log-size", 0, "MB of log entries applied since the last snapshot above which a snapshot is taken. 0 disables the trigger")
+	startCmd.PersistentFlags().DurationVar(&snapshotMaxInterval, "snapshot-max-interval", 0, "maximum time between snapshots while log entries are applied. 0 disables the trigger")
+	startCmd.PersistentFlags().Int64Var(&snapshotRateLimit, "snapshot-rate-limit", 0, "maximum MB per second written when persisting a snapshot. 0 disables the limit. defaults to 32 with the wan profile")
+	startCmd.PersistentFlags().IntVar(&transportMaxPool, "transport-max-pool", server.LANProfile.TransportMaxPool, "number of Raft connections pooled to each node. defaults to 8 with the wan profile")
+	startCmd.PersistentFlags().DurationVar(&transportTimeout, "transport-timeout", server.LANProfile.TransportTimeout, "I/O timeout of the Raft connections. defaults to 30s with the wan profile")
+	startCmd.PersistentFlags().Int64Var(&transportTimeoutScale, "transport-timeout-scale", int64(server.LANProfile.TransportTimeoutScale/1024), "KB of a snapshot sent to a node per transport timeout, the timeout of installing a snapshot grows with its size. lower it for slow links. defaults to 64 with the wan profile")
+	startCmd.PersistentFlags().BoolVar(&disableFastRestart, "disable-fast-restart", false, "restore the latest snapshot on startup even if the key value store was left intact by a clean shutdown")
+	startCmd.PersistentFlags().BoolVar(&disableWriteFencing, "disable-write-fencing", false, "acknowledge the writes without confirming the leader lease, and even if the leadership was lost while applying them")
+	startCmd.PersistentFlags().BoolVar(&disableBackpressure, "disable-write-backpressure", false, "let Badger block the writes while the compactions are behind instead of holding them back")
+	startCmd.PersistentFlags().DurationVar(&nodeStatusInterval, "node-status-interval", 10*time.Second, "interval at which the node replicates its state, addresses and readiness, so that the cluster state is served without asking the other nodes. 0 disables the propagation")
+	startCmd.PersistentFlags().DurationVar(&nodeStatusJitter, "node-status-jitter", 2*time.Second, "maximum random delay added to the node status interval, so that the nodes do not report at once")
+	startCmd.PersistentFlags().DurationVar(&warmupTimeout, "warmup-timeout", 0, "maximum time the node waits after it starts to catch up with the leader and read the keys with --warmup-prefixes before it reports ready. 0 disables the warmup")
+	startCmd.PersistentFlags().StringSliceVar(&warmupPrefixes, "warmup-prefixes", []string{}, "prefixes of the keys read to warm the caches up before the node reports ready, e.g. /sessions/")
+	startCmd.PersistentFlags().DurationVar(&quorumLossTimeout, "quorum-loss-timeout", 30*time.Second, "time without a leader after which the quorum is considered lost, the node is no longer ready and logs how to recover. 0 disables the detection")
+	startCmd.PersistentFlags().StringSliceVar(&propagateMetadata, "propagate-metadata", []string{"x-client-id", "x-origin-service"}, "gRPC metadata keys of write requests recorded in the replicated events")
+	startCmd.PersistentFlags().StringSliceVar(&priorityPrefixes, "priority-prefixes", []string{}, "prefixes of the keys whose writes go ahead of the other writes and whose events are fanned out to their watchers by their own queue, e.g. /config/")
+	startCmd.PersistentFlags().IntVar(&watchBufferSize, "watch-buffer-size", 1024, "number of events buffered for each watch")
+	startCmd.PersistentFlags().StringVar(&watchOverflow, "watch-overflow-policy", "cancel", "what happens to a watch that falls behind by more events than its buffer holds. cancel cancels the watch with a resume token, drop_oldest drops the oldest events and sends a gap")
+	startCmd.PersistentFlags().DurationVar(&slowRequestThreshold, "slow-request-threshold", time.Second, "duration above which a request is listed in the diagnostic dumps. 0 disables the list")
+	startCmd.PersistentFlags().StringSliceVar(&injectMethods, "inject-methods", []string{}, "gRPC methods to inject faults into, e.g. Get,Set. all methods if omitted. for staging environments only")
+	startCmd.PersistentFlags().DurationVar(&injectLatency, "inject-latency", 0, "latency injected into each request. for staging environments only")
+	startCmd.PersistentFlags().DurationVar(&injectJitter, "inject-jitter", 0, "maximum random latency injected into each request in addition to --inject-latency. for staging environments only")
+	startCmd.PersistentFlags().Float64Var(&injectErrorRate, "inject-error-rate", 0, "ratio of requests failed with UNAVAILABLE, from 0 to 1. for staging environments only")
+	startCmd.PersistentFlags().StringSliceVar(&payloadMethods, "log-payload-methods", []string{}, "gRPC methods whose request and response payloads are logged, e.g. Get,Set. for debugging only")
+	startCmd.PersistentFlags().StringSliceVar(&redactKeys, "log-payload-redact-keys", []string{}, "regular expressions matched against the keys, the values of the keys matching are redacted from the logged payloads")
+	startCmd.PersistentFlags().StringSliceVar(&redactValues, "log-payload-redact-values", []string{}, "regular expressions matched against the values, the values matching are redacted from the logged payloads")
+	startCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file, or a secret reference such as vault://secret/data/cete#certificate or exec://command")
+	startCmd.PersistentFlags().StringVar(&keyFile, "key-file", "", "path to the client server TLS key file, or a secret reference such as vault://secret/data/cete#key or exec://command")
 	startCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+	startCmd.PersistentFlags().DurationVar(&secretRefreshInterval, "secret-refresh-interval", 5*time.Minute, "interval at which the secrets fetched from Vault or commands are fetched again to pick up rotations. 0 disables the refresh")
 	startCmd.PersistentFlags().StringVar(&logLevel, "log-level", "INFO", "log level")
 	startCmd.PersistentFlags().StringVar(&logFile, "log-file", os.Stderr.Name(), "log file")
 	startCmd.PersistentFlags().IntVar(&logMaxSize, "log-max-size", 500, "max size of a log file in megabytes")
@@ -184,13 +366,55 @@ func init() {
 	startCmd.PersistentFlags().BoolVar(&logCompress, "log-compress", false, "compress a log file")
 
 	_ = viper.BindPFlag("id", startCmd.PersistentFlags().Lookup("id"))
+	_ = viper.BindPFlag("id_generator", startCmd.PersistentFlags().Lookup("id-generator"))
 	_ = viper.BindPFlag("raft_address", startCmd.PersistentFlags().Lookup("raft-address"))
 	_ = viper.BindPFlag("grpc_address", startCmd.PersistentFlags().Lookup("grpc-address"))
 	_ = viper.BindPFlag("http_address", startCmd.PersistentFlags().Lookup("http-address"))
+	_ = viper.BindPFlag("read_only_grpc_address", startCmd.PersistentFlags().Lookup("read-only-grpc-address"))
 	_ = viper.BindPFlag("data_directory", startCmd.PersistentFlags().Lookup("data-directory"))
+	_ = viper.BindPFlag("kvs_directory", startCmd.PersistentFlags().Lookup("kvs-directory"))
+	_ = viper.BindPFlag("raft_directory", startCmd.PersistentFlags().Lookup("raft-directory"))
+	_ = viper.BindPFlag("snapshot_directory", startCmd.PersistentFlags().Lookup("snapshot-directory"))
 	_ = viper.BindPFlag("peer_grpc_address", startCmd.PersistentFlags().Lookup("peer-grpc-address"))
+	_ = viper.BindPFlag("disable_forwarding", startCmd.PersistentFlags().Lookup("disable-forwarding"))
+	_ = viper.BindPFlag("reconcile_membership_interval", startCmd.PersistentFlags().Lookup("reconcile-membership-interval"))
+	_ = viper.BindPFlag("expiration_sweep_interval", startCmd.PersistentFlags().Lookup("expiration-sweep-interval"))
+	_ = viper.BindPFlag("retention", startCmd.PersistentFlags().Lookup("retention"))
+	_ = viper.BindPFlag("profile", startCmd.PersistentFlags().Lookup("profile"))
+	_ = viper.BindPFlag("catch_up_as_nonvoter", startCmd.PersistentFlags().Lookup("catch-up-as-nonvoter"))
+	_ = viper.BindPFlag("peer_allowlist_ids", startCmd.PersistentFlags().Lookup("peer-allowlist-ids"))
+	_ = viper.BindPFlag("peer_allowlist_cidrs", startCmd.PersistentFlags().Lookup("peer-allowlist-cidrs"))
+	_ = viper.BindPFlag("export_directory", startCmd.PersistentFlags().Lookup("export-directory"))
+	_ = viper.BindPFlag("export_url_prefixes", startCmd.PersistentFlags().Lookup("export-url-prefixes"))
+	_ = viper.BindPFlag("snapshot_log_size", startCmd.PersistentFlags().Lookup("snapshot-log-size"))
+	_ = viper.BindPFlag("snapshot_max_interval", startCmd.PersistentFlags().Lookup("snapshot-max-interval"))
+	_ = viper.BindPFlag("snapshot_rate_limit", startCmd.PersistentFlags().Lookup("snapshot-rate-limit"))
+	_ = viper.BindPFlag("transport_max_pool", startCmd.PersistentFlags().Lookup("transport-max-pool"))
+	_ = viper.BindPFlag("transport_timeout", startCmd.PersistentFlags().Lookup("transport-timeout"))
+	_ = viper.BindPFlag("transport_timeout_scale", startCmd.PersistentFlags().Lookup("transport-timeout-scale"))
+	_ = viper.BindPFlag("disable_fast_restart", startCmd.PersistentFlags().Lookup("disable-fast-restart"))
+	_ = viper.BindPFlag("quorum_loss_timeout", startCmd.PersistentFlags().Lookup("quorum-loss-timeout"))
+	_ = viper.BindPFlag("node_status_interval", startCmd.PersistentFlags().Lookup("node-status-interval"))
+	_ = viper.BindPFlag("node_status_jitter", startCmd.PersistentFlags().Lookup("node-status-jitter"))
+	_ = viper.BindPFlag("warmup_timeout", startCmd.PersistentFlags().Lookup("warmup-timeout"))
+	_ = viper.BindPFlag("warmup_prefixes", startCmd.PersistentFlags().Lookup("warmup-prefixes"))
+	_ = viper.BindPFlag("disable_write_fencing", startCmd.PersistentFlags().Lookup("disable-write-fencing"))
+	_ = viper.BindPFlag("disable_write_backpressure", startCmd.PersistentFlags().Lookup("disable-write-backpressure"))
+	_ = viper.BindPFlag("propagate_metadata", startCmd.PersistentFlags().Lookup("propagate-metadata"))
+	_ = viper.BindPFlag("priority_prefixes", startCmd.PersistentFlags().Lookup("priority-prefixes"))
+	_ = viper.BindPFlag("watch_buffer_size", startCmd.PersistentFlags().Lookup("watch-buffer-size"))
+	_ = viper.BindPFlag("watch_overflow_policy", startCmd.PersistentFlags().Lookup("watch-overflow-policy"))
+	_ = viper.BindPFlag("slow_request_threshold", startCmd.PersistentFlags().Lookup("slow-request-threshold"))
+	_ = viper.BindPFlag("inject_methods", startCmd.PersistentFlags().Lookup("inject-methods"))
+	_ = viper.BindPFlag("inject_latency", startCmd.PersistentFlags().Lookup("inject-latency"))
+	_ = viper.BindPFlag("inject_jitter", startCmd.PersistentFlags().Lookup("inject-jitter"))
+	_ = viper.BindPFlag("inject_error_rate", startCmd.PersistentFlags().Lookup("inject-error-rate"))
+	_ = viper.BindPFlag("log_payload_methods", startCmd.PersistentFlags().Lookup("log-payload-methods"))
+	_ = viper.BindPFlag("log_payload_redact_keys", startCmd.PersistentFlags().Lookup("log-payload-redact-keys"))
+	_ = viper.BindPFlag("log_payload_redact_values", startCmd.PersistentFlags().Lookup("log-payload-redact-values"))
 	_ = viper.BindPFlag("certificate_file", startCmd.PersistentFlags().Lookup("certificate-file"))
 	_ = viper.BindPFlag("key_file", startCmd.PersistentFlags().Lookup("key-file"))
+	_ = viper.BindPFlag("secret_refresh_interval", startCmd.PersistentFlags().Lookup("secret-refresh-interval"))
 	_ = viper.BindPFlag("common_name", startCmd.PersistentFlags().Lookup("common-name"))
 	_ = viper.BindPFlag("log_level", startCmd.PersistentFlags().Lookup("log-level"))
 	_ = viper.BindPFlag("log_max_size", startCmd.PersistentFlags().Lookup("log-max-size"))
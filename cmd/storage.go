@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	storageCmd = &cobra.Command{
+		Use:   "storage",
+		Short: "Inspect the storage of a node",
+		Long:  "Inspect the Badger storage of a node",
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(storageCmd)
+}
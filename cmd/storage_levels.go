@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	storageLevelsCmd = &cobra.Command{
+		Use:   "levels",
+		Args:  cobra.NoArgs,
+		Short: "Show the levels of the LSM tree",
+		Long:  "Show the number of tables, the entries and the size of each level of the LSM tree of the node, and optionally its tables and the share of stale data",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddress = viper.GetString("grpc_address")
+			storageTables = viper.GetBool("tables")
+			countLiveKeys = viper.GetBool("count_live_keys")
+
+			certificateFile = viper.GetString("certificate_file")
+			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
+
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = c.Close()
+			}()
+
+			req := &protobuf.StorageLevelsRequest{
+				Tables:        storageTables,
+				CountLiveKeys: countLiveKeys,
+			}
+
+			resp, err := c.StorageLevels(req)
+			if err != nil {
+				return err
+			}
+
+			respBytes, err := json.Marshal(resp)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(respBytes))
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	storageCmd.AddCommand(storageLevelsCmd)
+
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			viper.SetConfigFile(configFile)
+		} else {
+			home, err := homedir.Dir()
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			viper.AddConfigPath("/etc")
+			viper.AddConfigPath(home)
+			viper.SetConfigName("cete")
+
+		}
+
+		viper.SetEnvPrefix("CETE")
+		viper.AutomaticEnv()
+
+		if err := viper.ReadInConfig(); err != nil {
+			switch err.(type) {
+			case viper.ConfigFileNotFoundError:
+				// cete.yaml does not found in config search path
+			default:
+				_, _ = fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	})
+
+	storageLevelsCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
+	storageLevelsCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	storageLevelsCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
+	storageLevelsCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
+	storageLevelsCmd.PersistentFlags().BoolVar(&storageTables, "tables", false, "also list the tables of each level")
+	storageLevelsCmd.PersistentFlags().BoolVar(&countLiveKeys, "count-live-keys", false, "count the live keys to estimate the share of stale data, which iterates over all the keys")
+
+	_ = viper.BindPFlag("grpc_address", storageLevelsCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("certificate_file", storageLevelsCmd.PersistentFlags().Lookup("certificate-file"))
+	_ = viper.BindPFlag("common_name", storageLevelsCmd.PersistentFlags().Lookup("common-name"))
+	_ = viper.BindPFlag("tables", storageLevelsCmd.PersistentFlags().Lookup("tables"))
+	_ = viper.BindPFlag("count_live_keys", storageLevelsCmd.PersistentFlags().Lookup("count-live-keys"))
+}
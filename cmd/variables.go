@@ -1,20 +1,103 @@
 package cmd
 
+import (
+	"time"
+)
+
 var (
-	configFile      string
-	id              string
-	raftAddress     string
-	grpcAddress     string
-	httpAddress     string
-	dataDirectory   string
-	peerGrpcAddress string
-	certificateFile string
-	keyFile         string
-	commonName      string
-	logLevel        string
-	logFile         string
-	logMaxSize      int
-	logMaxBackups   int
-	logMaxAge       int
-	logCompress     bool
+	configFile            string
+	id                    string
+	idGenerator           string
+	raftAddress           string
+	grpcAddress           string
+	httpAddress           string
+	readOnlyGrpcAddress   string
+	dataDirectory         string
+	kvsDirectory          string
+	raftDirectory         string
+	snapshotDirectory     string
+	peerGrpcAddress       string
+	disableForwarding     bool
+	reconcileInterval     time.Duration
+	sweepInterval         time.Duration
+	retention             []string
+	secretRefreshInterval time.Duration
+	profileName           string
+	catchUpAsNonvoter     bool
+	peerAllowlistIDs      []string
+	peerAllowlistCIDRs    []string
+	exportDirectory       string
+	exportURLPrefixes     []string
+	snapshotRateLimit     int64
+	transportMaxPool      int
+	transportTimeout      time.Duration
+	transportTimeoutScale int64
+	snapshotLogSize       int64
+	snapshotMaxInterval   time.Duration
+	disableFastRestart    bool
+	quorumLossTimeout     time.Duration
+	nodeStatusInterval    time.Duration
+	nodeStatusJitter      time.Duration
+	warmupTimeout         time.Duration
+	warmupPrefixes        []string
+	disableBackpressure   bool
+	disableWriteFencing   bool
+	propagateMetadata     []string
+	priorityPrefixes      []string
+	requestMetadata       []string
+	watchBufferSize       int
+	watchOverflow         string
+	resumeToken           string
+	watchCompact          bool
+	watchKeyDelta         bool
+	slowRequestThreshold  time.Duration
+	injectMethods         []string
+	injectLatency         time.Duration
+	injectJitter          time.Duration
+	injectErrorRate       float64
+	payloadMethods        []string
+	redactKeys            []string
+	redactValues          []string
+	dumpOutput            string
+	doctorScratchPrefix   string
+	doctorMaxClockSkew    time.Duration
+	doctorMinDiskFree     float64
+	readConsistency       string
+	keyRegexp             string
+	keyGlob               string
+	minValueSize          uint64
+	maxValueSize          uint64
+	modifiedSince         uint64
+	exportFormat          string
+	copyPrefix            bool
+	ttl                   time.Duration
+	expectedIndex         int64
+	checksum              bool
+	showIndex             bool
+	getOffset             uint64
+	getLength             uint64
+	forceReset            bool
+	resetTimeout          time.Duration
+	storageTables         bool
+	countLiveKeys         bool
+	apiKeyPrefix          string
+	apiKeyWrite           bool
+	apiKeyTTL             time.Duration
+	roleSource            string
+	roleReason            string
+	replicatedIndex       uint64
+	failoverSource        string
+	failoverReason        string
+	failoverTimeout       time.Duration
+	forceFailover         bool
+	certificateFile       string
+	keyFile               string
+	commonName            string
+	authToken             string
+	logLevel              string
+	logFile               string
+	logMaxSize            int
+	logMaxBackups         int
+	logMaxAge             int
+	logCompress           bool
 )
@@ -8,7 +8,6 @@ import (
 	"os/signal"
 	"syscall"
 
-	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/mitchellh/go-homedir"
 	"github.com/mosuka/cete/client"
 	"github.com/mosuka/cete/marshaler"
@@ -19,16 +18,23 @@ import (
 
 var (
 	watchCmd = &cobra.Command{
-		Use:   "watch",
+		Use:   "watch [PREFIX]",
+		Args:  cobra.MaximumNArgs(1),
 		Short: "Watch a node updates",
-		Long:  "Watch a node updates",
+		Long:  "Watch a node updates. If a prefix is given, only the updates of the keys with the prefix are watched",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			grpcAddress = viper.GetString("grpc_address")
 
+			resumeToken = viper.GetString("resume_token")
+
+			watchCompact = viper.GetBool("compact")
+			watchKeyDelta = viper.GetBool("key_delta")
+
 			certificateFile = viper.GetString("certificate_file")
 			commonName = viper.GetString("common_name")
+			authToken = viper.GetString("auth_token")
 
-			c, err := client.NewGRPCClientWithContextTLS(grpcAddress, context.Background(), certificateFile, commonName)
+			c, err := client.NewGRPCClientWithOptions(grpcAddress, context.Background(), client.WithTLS(certificateFile, commonName), client.WithAuthToken(authToken))
 			if err != nil {
 				return err
 			}
@@ -36,85 +42,88 @@ var (
 				_ = c.Close()
 			}()
 
-			req := &empty.Empty{}
+			req := &protobuf.WatchRequest{
+				ResumeToken: resumeToken,
+				KeyDelta:    watchKeyDelta,
+			}
+			if watchCompact {
+				req.Encoding = protobuf.WatchRequest_Compact
+			}
+			if len(args) > 0 {
+				req.Prefix = args[0]
+			}
 			watchClient, err := c.Watch(req)
 			if err != nil {
 				return err
 			}
 
+			decoder := marshaler.NewCompactEventDecoder()
+
+			errCh := make(chan error, 1)
 			go func() {
 				for {
 					resp, err := watchClient.Recv()
 					if err == io.EOF {
-						break
+						errCh <- nil
+						return
 					}
 					if err != nil {
-						break
+						errCh <- err
+						return
 					}
 
-					switch resp.Event.Type {
-					case protobuf.Event_Join:
-						eventReq := &protobuf.SetMetadataRequest{}
-						if eventData, err := marshaler.MarshalAny(resp.Event.Data); err != nil {
-							_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, %v", resp.Event.Type.String(), err))
-						} else {
-							if eventData == nil {
-								_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, nil", resp.Event.Type.String()))
-							} else {
-								eventReq = eventData.(*protobuf.SetMetadataRequest)
-							}
-						}
-						fmt.Printf("%s, %v\n", resp.Event.Type.String(), eventReq)
-					case protobuf.Event_Leave:
-						eventReq := &protobuf.DeleteMetadataRequest{}
-						if eventData, err := marshaler.MarshalAny(resp.Event.Data); err != nil {
-							_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, %v", resp.Event.Type.String(), err))
-						} else {
-							if eventData == nil {
-								_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, nil", resp.Event.Type.String()))
-							} else {
-								eventReq = eventData.(*protobuf.DeleteMetadataRequest)
-							}
+					switch {
+					case resp.ResumeToken != "":
+						_, _ = fmt.Fprintf(os.Stderr, "the watch was cancelled, resume it with --resume-token=%s\n", resp.ResumeToken)
+						continue
+					case resp.Gap != nil:
+						fmt.Printf("Gap, %d events dropped between %d and %d\n", resp.Gap.Dropped, resp.Gap.FromIndex, resp.Gap.ToIndex)
+						continue
+					case len(resp.CompactEvents) > 0:
+						events, err := decoder.Decode(resp.CompactEvents)
+						if err != nil {
+							errCh <- err
+							return
 						}
-						fmt.Printf("%s, %v\n", resp.Event.Type.String(), eventReq)
-					case protobuf.Event_Set:
-						putRequest := &protobuf.SetRequest{}
-						if putRequestInstance, err := marshaler.MarshalAny(resp.Event.Data); err != nil {
-							_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, %v", resp.Event.Type.String(), err))
-						} else {
-							if putRequestInstance == nil {
-								_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, nil", resp.Event.Type.String()))
-							} else {
-								putRequest = putRequestInstance.(*protobuf.SetRequest)
-							}
+						for _, event := range events {
+							printWatchEvent(event.Event)
 						}
-						fmt.Printf("%s, %v\n", resp.Event.Type.String(), putRequest)
-					case protobuf.Event_Delete:
-						deleteRequest := &protobuf.DeleteRequest{}
-						if deleteRequestInstance, err := marshaler.MarshalAny(resp.Event.Data); err != nil {
-							_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, %v", resp.Event.Type.String(), err))
-						} else {
-							if deleteRequestInstance == nil {
-								_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, nil", resp.Event.Type.String()))
-							} else {
-								deleteRequest = deleteRequestInstance.(*protobuf.DeleteRequest)
-							}
-						}
-						fmt.Printf("%s, %v\n", resp.Event.Type.String(), deleteRequest)
+						continue
 					}
+
+					printWatchEvent(resp.Event)
 				}
 			}()
 
 			quitCh := make(chan os.Signal, 1)
 			signal.Notify(quitCh, os.Kill, os.Interrupt, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
-			<-quitCh
-
-			return nil
+			select {
+			case <-quitCh:
+				return nil
+			case err := <-errCh:
+				return err
+			}
 		},
 	}
 )
 
+func printWatchEvent(event *protobuf.Event) {
+	data, err := marshaler.EventData(event)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf("%s, %v", event.Type.String(), err))
+		return
+	}
+	line := fmt.Sprintf("%s, %v", event.Type.String(), data)
+	if len(event.Metadata) > 0 {
+		line = fmt.Sprintf("%s, %v", line, event.Metadata)
+	}
+	if event.MembershipEpoch > 0 {
+		line = fmt.Sprintf("%s, epoch %d", line, event.MembershipEpoch)
+	}
+	fmt.Println(line)
+}
+
 func init() {
 	rootCmd.AddCommand(watchCmd)
 
@@ -150,10 +159,16 @@ func init() {
 
 	watchCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "config file. if omitted, cete.yaml in /etc and home directory will be searched")
 	watchCmd.PersistentFlags().StringVar(&grpcAddress, "grpc-address", ":9000", "gRPC server listen address")
+	watchCmd.PersistentFlags().StringVar(&resumeToken, "resume-token", "", "resume a watch after the events it received. the token is the index of the last event received")
+	watchCmd.PersistentFlags().BoolVar(&watchCompact, "compact", false, "receive the events in the compact encoding, which takes less bandwidth")
+	watchCmd.PersistentFlags().BoolVar(&watchKeyDelta, "key-delta", false, "with the compact encoding, receive the keys as the difference from the previous key")
 	watchCmd.PersistentFlags().StringVar(&certificateFile, "certificate-file", "", "path to the client server TLS certificate file")
 	watchCmd.PersistentFlags().StringVar(&commonName, "common-name", "", "certificate common name")
 
 	_ = viper.BindPFlag("grpc_address", watchCmd.PersistentFlags().Lookup("grpc-address"))
+	_ = viper.BindPFlag("resume_token", watchCmd.PersistentFlags().Lookup("resume-token"))
+	_ = viper.BindPFlag("compact", watchCmd.PersistentFlags().Lookup("compact"))
+	_ = viper.BindPFlag("key_delta", watchCmd.PersistentFlags().Lookup("key-delta"))
 	_ = viper.BindPFlag("certificate_file", watchCmd.PersistentFlags().Lookup("certificate-file"))
 	_ = viper.BindPFlag("common_name", watchCmd.PersistentFlags().Lookup("common-name"))
 }
@@ -1,11 +1,267 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mosuka/cete/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 var (
-	ErrNotFoundLeader    = errors.New("does not found leader")
-	ErrNodeAlreadyExists = errors.New("node already exists")
-	ErrNodeNotReady      = errors.New("node not ready")
-	ErrNotFound          = errors.New("not found")
-	ErrTimeout           = errors.New("timeout")
+	ErrChecksumMismatch  = newSentinel(codes.DataLoss, false, "checksum mismatch")
+	ErrConflict          = newSentinel(codes.Aborted, false, "conflict")
+	ErrEmptyMembership   = newSentinel(codes.InvalidArgument, false, "membership must contain at least one node")
+	ErrNotFoundLeader    = newSentinel(codes.Unavailable, true, "does not found leader")
+	ErrNotLeader         = newSentinel(codes.FailedPrecondition, true, "not leader")
+	ErrNodeAlreadyExists = newSentinel(codes.AlreadyExists, false, "node already exists")
+	ErrNodeNotReady      = newSentinel(codes.Unavailable, true, "node not ready")
+	ErrNotFound          = newSentinel(codes.NotFound, false, "not found")
+	ErrOutOfRange        = newSentinel(codes.OutOfRange, false, "out of range")
+	ErrOutcomeUnknown    = newSentinel(codes.Unknown, false, "the leadership was lost, the write may have been applied")
+	ErrPeerUnavailable   = newSentinel(codes.Unavailable, true, "peer unavailable")
+	ErrPermissionDenied  = newSentinel(codes.PermissionDenied, false, "permission denied")
+	ErrQuorumLost        = newSentinel(codes.Unavailable, true, "quorum lost")
+	ErrReadOnly          = newSentinel(codes.FailedPrecondition, false, "read-only")
+	ErrReservedKey       = newSentinel(codes.InvalidArgument, false, "key uses the reserved system prefix")
+	ErrTimeout           = newSentinel(codes.DeadlineExceeded, true, "timeout")
+	ErrUnauthenticated   = newSentinel(codes.Unauthenticated, false, "unauthenticated")
+	ErrWatcherTooSlow    = newSentinel(codes.ResourceExhausted, true, "watcher fell behind")
+
+	ErrUnknownEventType      = newSentinel(codes.Internal, false, "unknown event type")
+	ErrUnknownPayloadType    = newSentinel(codes.Internal, false, "unknown payload type")
+	ErrUnexpectedPayloadType = newSentinel(codes.Internal, false, "unexpected payload type")
+	ErrEmptyPayload          = newSentinel(codes.Internal, false, "empty payload")
 )
+
+// sentinels are matched against the message of errors received from a server,
+// so that errors.Is works on both sides of a gRPC call.
+var sentinels []*Error
+
+func newSentinel(code codes.Code, retryable bool, message string) *Error {
+	e := &Error{
+		Code:      code,
+		Message:   message,
+		Retryable: retryable,
+	}
+	sentinels = append(sentinels, e)
+
+	return e
+}
+
+// Error is an error carrying a gRPC status code and whether the failed operation
+// can be retried. Errors caused by a node not being the leader carry the leader,
+// so that the operation can be retried on it. Errors caused by a write running out of
+// time carry how the time was spent.
+// Error implements the interface used by the grpc status package, so a server can
+// return it as is.
+type Error struct {
+	Code      codes.Code
+	Message   string
+	Retryable bool
+	Leader    *protobuf.LeaderHint
+	Budget    *protobuf.DeadlineBudget
+	Err       error
+}
+
+func New(code codes.Code, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.Code, e.Error())
+
+	details := make([]proto.Message, 0)
+	if e.Leader != nil {
+		details = append(details, e.Leader)
+	}
+	if e.Budget != nil {
+		details = append(details, e.Budget)
+	}
+	if len(details) > 0 {
+		if withDetails, err := st.WithDetails(details...); err == nil {
+			return withDetails
+		}
+	}
+
+	return st
+}
+
+// Wrap adds context to the error. The code and the retryability of the error are kept.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := &Error{
+		Code:      Code(err),
+		Message:   message,
+		Retryable: IsRetryable(err),
+		Leader:    LeaderHint(err),
+		Budget:    DeadlineBudget(err),
+		Err:       err,
+	}
+
+	return e
+}
+
+func Wrapf(err error, format string, a ...interface{}) error {
+	return Wrap(err, fmt.Sprintf(format, a...))
+}
+
+// Convert returns the error as an *Error. Errors that do not carry a code get the specified code.
+func Convert(err error, code codes.Code) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		if e == err {
+			return e
+		}
+		return Wrap(err, "")
+	}
+
+	if st, ok := status.FromError(err); ok {
+		return FromStatus(st)
+	}
+
+	return &Error{
+		Code:    code,
+		Message: err.Error(),
+	}
+}
+
+// FromStatus converts a status received from a server into an *Error.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	e := &Error{
+		Code:      st.Code(),
+		Message:   st.Message(),
+		Retryable: st.Code() == codes.Unavailable || st.Code() == codes.DeadlineExceeded || st.Code() == codes.Aborted,
+	}
+
+	for _, sentinel := range sentinels {
+		if sentinel.Code != st.Code() {
+			continue
+		}
+		if st.Message() == sentinel.Message {
+			e.Message = ""
+		} else if strings.HasSuffix(st.Message(), ": "+sentinel.Message) {
+			e.Message = strings.TrimSuffix(st.Message(), ": "+sentinel.Message)
+		} else {
+			continue
+		}
+		e.Err = sentinel
+		e.Retryable = sentinel.Retryable
+		break
+	}
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *protobuf.LeaderHint:
+			e.Leader = d
+		case *protobuf.DeadlineBudget:
+			e.Budget = d
+		}
+	}
+
+	return e
+}
+
+// NotLeader returns an error telling the client to retry the operation on the leader.
+func NotLeader(leader *protobuf.LeaderHint) error {
+	return &Error{
+		Code:      ErrNotLeader.Code,
+		Retryable: true,
+		Leader:    leader,
+		Err:       ErrNotLeader,
+	}
+}
+
+// Code returns the code of the error. Errors without a code are Unknown.
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+
+	return codes.Unknown
+}
+
+// IsRetryable reports whether the failed operation can be retried.
+func IsRetryable(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Retryable
+	}
+
+	return false
+}
+
+// LeaderHint returns the leader carried by the error, if any.
+func LeaderHint(err error) *protobuf.LeaderHint {
+	var e *Error
+	for errors.As(err, &e) {
+		if e.Leader != nil {
+			return e.Leader
+		}
+		err = e.Err
+	}
+
+	return nil
+}
+
+// DeadlineBudget returns how the time of a write that ran out of time was spent, if known.
+func DeadlineBudget(err error) *protobuf.DeadlineBudget {
+	var e *Error
+	for errors.As(err, &e) {
+		if e.Budget != nil {
+			return e.Budget
+		}
+		err = e.Err
+	}
+
+	return nil
+}
+
+func Is(err error, target error) bool {
+	return errors.Is(err, target)
+}
+
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
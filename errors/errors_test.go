@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mosuka/cete/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWrap(t *testing.T) {
+	err := Wrapf(ErrTimeout, "no leader detected within %v", "10s")
+
+	if !Is(err, ErrTimeout) {
+		t.Errorf("expected %v to wrap %v", err, ErrTimeout)
+	}
+	if Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected code %v, saw %v", codes.DeadlineExceeded, Code(err))
+	}
+	if !IsRetryable(err) {
+		t.Errorf("expected %v to be retryable", err)
+	}
+	if err.Error() != "no leader detected within 10s: timeout" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	// wrapped by the standard library
+	err = fmt.Errorf("%w: Set", ErrUnknownEventType)
+	if Code(err) != codes.Internal || IsRetryable(err) {
+		t.Errorf("expected a non-retryable internal error, saw %v", err)
+	}
+	if Code(fmt.Errorf("plain")) != codes.Unknown {
+		t.Errorf("expected code %v", codes.Unknown)
+	}
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	hint := &protobuf.LeaderHint{
+		Id: "node1",
+		Node: &protobuf.Node{
+			RaftAddress: ":7000",
+		},
+	}
+
+	tests := []struct {
+		err       error
+		target    error
+		code      codes.Code
+		retryable bool
+		leader    bool
+	}{
+		{ErrNotFound, ErrNotFound, codes.NotFound, false, false},
+		{Wrapf(ErrNotFound, "node %s", "node1"), ErrNotFound, codes.NotFound, false, false},
+		{NotLeader(hint), ErrNotLeader, codes.FailedPrecondition, true, true},
+		{Wrap(ErrNotLeader, "leadership transfer in progress"), ErrNotLeader, codes.FailedPrecondition, true, false},
+		{Wrap(ErrOutcomeUnknown, "leadership lost while committing log"), ErrOutcomeUnknown, codes.Unknown, false, false},
+		{Convert(fmt.Errorf("disk full"), codes.Internal), nil, codes.Internal, false, false},
+	}
+
+	for _, test := range tests {
+		st, ok := status.FromError(test.err)
+		if !ok {
+			t.Fatalf("expected %v to carry a status", test.err)
+		}
+
+		// the error as seen by the client
+		err := FromStatus(st)
+		if test.target != nil && !Is(err, test.target) {
+			t.Errorf("expected %v to wrap %v", err, test.target)
+		}
+		if err.Error() != test.err.Error() {
+			t.Errorf("expected message %q, saw %q", test.err.Error(), err.Error())
+		}
+		if Code(err) != test.code {
+			t.Errorf("%v: expected code %v, saw %v", err, test.code, Code(err))
+		}
+		if IsRetryable(err) != test.retryable {
+			t.Errorf("%v: expected retryable to be %v", err, test.retryable)
+		}
+		if (LeaderHint(err) != nil) != test.leader {
+			t.Errorf("%v: expected leader hint to be present: %v", err, test.leader)
+		}
+	}
+}
@@ -12,6 +12,9 @@ require (
 	github.com/hashicorp/raft v1.1.2
 	github.com/mash/go-accesslog v1.1.0
 	github.com/mitchellh/go-homedir v1.1.0
+	github.com/mosuka/cete/client v0.0.0-00010101000000-000000000000
+	github.com/mosuka/cete/errors v0.0.0-00010101000000-000000000000
+	github.com/mosuka/cete/protobuf v0.0.0-00010101000000-000000000000
 	github.com/natefinch/lumberjack v2.0.0+incompatible
 	github.com/prometheus/client_golang v1.5.1
 	github.com/prometheus/common v0.9.1
@@ -22,3 +25,9 @@ require (
 	google.golang.org/grpc v1.28.0
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0 // indirect
 )
+
+replace (
+	github.com/mosuka/cete/client => ./client
+	github.com/mosuka/cete/errors => ./errors
+	github.com/mosuka/cete/protobuf => ./protobuf
+)
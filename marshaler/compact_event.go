@@ -0,0 +1,143 @@
+package marshaler
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mosuka/cete/protobuf"
+)
+
+// CompactEventEncoder encodes the events of a watch in the compact encoding. The event
+// data is encoded in the binary format instead of JSON, and the indexes and, with the
+// key delta, the keys are encoded as the difference from the previous event, so the
+// events must be decoded in order by a single CompactEventDecoder.
+type CompactEventEncoder struct {
+	keyDelta  bool
+	lastIndex uint64
+	lastKey   string
+}
+
+func NewCompactEventEncoder(keyDelta bool) *CompactEventEncoder {
+	return &CompactEventEncoder{
+		keyDelta: keyDelta,
+	}
+}
+
+// Append appends the event framed by its size to the buffer.
+func (e *CompactEventEncoder) Append(buf []byte, index uint64, event *protobuf.Event) ([]byte, error) {
+	data, err := EventData(event)
+	if err != nil {
+		return buf, err
+	}
+
+	c := &protobuf.CompactEvent{
+		Type:       event.Type,
+		IndexDelta: index - e.lastIndex,
+		Metadata:   event.Metadata,
+
+		MembershipEpoch: event.MembershipEpoch,
+	}
+	e.lastIndex = index
+
+	key, hasKey := "", false
+	switch d := data.(type) {
+	case *protobuf.SetRequest:
+		key, hasKey = d.Key, true
+		c.Value = d.Value
+		d.Key, d.Value = "", nil
+	case *protobuf.DeleteRequest:
+		key, hasKey = d.Key, true
+		d.Key = ""
+	}
+	if hasKey {
+		shared := 0
+		if e.keyDelta {
+			for shared < len(key) && shared < len(e.lastKey) && key[shared] == e.lastKey[shared] {
+				shared++
+			}
+		}
+		c.SharedKeyPrefix = uint32(shared)
+		c.Key = []byte(key[shared:])
+		e.lastKey = key
+	}
+
+	if c.Data, err = proto.Marshal(data); err != nil {
+		return buf, err
+	}
+	b, err := proto.Marshal(c)
+	if err != nil {
+		return buf, err
+	}
+
+	size := make([]byte, binary.MaxVarintLen64)
+	buf = append(buf, size[:binary.PutUvarint(size, uint64(len(b)))]...)
+
+	return append(buf, b...), nil
+}
+
+// CompactEventDecoder decodes the compact events of a watch into the events sent by the
+// verbose encoding.
+type CompactEventDecoder struct {
+	lastIndex uint64
+	lastKey   string
+}
+
+func NewCompactEventDecoder() *CompactEventDecoder {
+	return &CompactEventDecoder{}
+}
+
+// Decode decodes the compact events of a response.
+func (d *CompactEventDecoder) Decode(frames []byte) ([]*protobuf.WatchResponse, error) {
+	responses := make([]*protobuf.WatchResponse, 0)
+	for len(frames) > 0 {
+		size, n := binary.Uvarint(frames)
+		if n <= 0 || uint64(len(frames)-n) < size {
+			return responses, fmt.Errorf("malformed compact event frame")
+		}
+		c := &protobuf.CompactEvent{}
+		if err := proto.Unmarshal(frames[n:n+int(size)], c); err != nil {
+			return responses, err
+		}
+		frames = frames[n+int(size):]
+
+		expected, ok := eventDataTypes[c.Type]
+		if !ok {
+			return responses, fmt.Errorf("unknown event type %s", c.Type.String())
+		}
+		data := reflect.New(reflect.TypeOf(expected).Elem()).Interface().(proto.Message)
+		if err := proto.Unmarshal(c.Data, data); err != nil {
+			return responses, err
+		}
+
+		if c.Type == protobuf.Event_Set || c.Type == protobuf.Event_Delete {
+			if int(c.SharedKeyPrefix) > len(d.lastKey) {
+				return responses, fmt.Errorf("compact event shares %d bytes of a %d bytes key", c.SharedKeyPrefix, len(d.lastKey))
+			}
+			key := d.lastKey[:c.SharedKeyPrefix] + string(c.Key)
+			switch req := data.(type) {
+			case *protobuf.SetRequest:
+				req.Key, req.Value = key, c.Value
+			case *protobuf.DeleteRequest:
+				req.Key = key
+			}
+			d.lastKey = key
+		}
+
+		event, err := NewEvent(c.Type, data)
+		if err != nil {
+			return responses, err
+		}
+		event.Metadata = c.Metadata
+		event.MembershipEpoch = c.MembershipEpoch
+
+		d.lastIndex += c.IndexDelta
+		responses = append(responses, &protobuf.WatchResponse{
+			Event: event,
+			Index: d.lastIndex,
+		})
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,76 @@
+package marshaler
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mosuka/cete/protobuf"
+)
+
+func TestCompactEvent(t *testing.T) {
+	events := []struct {
+		index     uint64
+		eventType protobuf.Event_Type
+		data      proto.Message
+	}{
+		{10, protobuf.Event_Set, &protobuf.SetRequest{Key: "/users/1/name", Value: []byte("a")}},
+		{11, protobuf.Event_Set, &protobuf.SetRequest{Key: "/users/1/mail", Value: []byte("b")}},
+		{12, protobuf.Event_Join, &protobuf.SetMetadataRequest{Id: "node2", Metadata: &protobuf.Metadata{GrpcAddress: ":9001"}}},
+		{15, protobuf.Event_Delete, &protobuf.DeleteRequest{Key: "/users/2"}},
+		{16, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/users/", Destination: "/archive/", Prefix: true}},
+	}
+
+	for _, keyDelta := range []bool{false, true} {
+		encoder := NewCompactEventEncoder(keyDelta)
+		decoder := NewCompactEventDecoder()
+
+		// the events are split into two responses
+		var frames [2][]byte
+		verbose := 0
+		for i, e := range events {
+			event, err := NewEvent(e.eventType, e.data)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if i == 0 {
+				event.Metadata = map[string]string{"x-client-id": "loader"}
+			}
+			b, _ := proto.Marshal(&protobuf.WatchResponse{Event: event, Index: e.index})
+			verbose += len(b)
+			if frames[i/3], err = encoder.Append(frames[i/3], e.index, event); err != nil {
+				t.Fatalf("%v", err)
+			}
+		}
+		if compact := len(frames[0]) + len(frames[1]); compact >= verbose {
+			t.Errorf("expected the compact encoding to be smaller than %d bytes, saw %d", verbose, compact)
+		}
+
+		responses := make([]*protobuf.WatchResponse, 0)
+		for _, frame := range frames {
+			decoded, err := decoder.Decode(frame)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			responses = append(responses, decoded...)
+		}
+		if len(responses) != len(events) {
+			t.Fatalf("expected %d events, saw %d", len(events), len(responses))
+		}
+		for i, resp := range responses {
+			data, err := EventData(resp.Event)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if resp.Index != events[i].index || !proto.Equal(data, events[i].data) {
+				t.Errorf("expected event %d to be %v at %d, saw %v at %d", i, events[i].data, events[i].index, data, resp.Index)
+			}
+		}
+		if responses[0].Event.Metadata["x-client-id"] != "loader" {
+			t.Errorf("expected the metadata to be kept, saw %v", responses[0].Event.Metadata)
+		}
+	}
+
+	if _, err := NewCompactEventDecoder().Decode([]byte{0x05, 0x01}); err == nil {
+		t.Errorf("expected a truncated frame to fail")
+	}
+}
@@ -0,0 +1,87 @@
+package marshaler
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// eventDataTypes maps each event type to the message carried in its data.
+// Every event type must be listed here, otherwise it can neither be proposed nor applied.
+// The compiler does not check the mapping: NewEvent and EventData enforce it at runtime,
+// and TestEventDataTypes fails if an event type of the protobuf enum is missing.
+var eventDataTypes = map[protobuf.Event_Type]proto.Message{
+	protobuf.Event_Join:                 (*protobuf.SetMetadataRequest)(nil),
+	protobuf.Event_Leave:                (*protobuf.DeleteMetadataRequest)(nil),
+	protobuf.Event_Set:                  (*protobuf.SetRequest)(nil),
+	protobuf.Event_Delete:               (*protobuf.DeleteRequest)(nil),
+	protobuf.Event_SetMembershipSpec:    (*protobuf.SetMembershipSpecRequest)(nil),
+	protobuf.Event_DeleteMembershipSpec: (*empty.Empty)(nil),
+	protobuf.Event_Reconcile:            (*protobuf.ReconcileAction)(nil),
+	protobuf.Event_Decommission:         (*protobuf.DecommissionStatus)(nil),
+	protobuf.Event_Copy:                 (*protobuf.CopyRequest)(nil),
+	protobuf.Event_Move:                 (*protobuf.MoveRequest)(nil),
+	protobuf.Event_Expire:               (*protobuf.ExpireRequest)(nil),
+	protobuf.Event_Annotate:             (*protobuf.AnnotateRequest)(nil),
+	protobuf.Event_UpdateNodeStatus:     (*protobuf.UpdateNodeStatusRequest)(nil),
+	protobuf.Event_CreateAPIKey:         (*protobuf.APIKey)(nil),
+	protobuf.Event_RevokeAPIKey:         (*protobuf.RevokeAPIKeyRequest)(nil),
+	protobuf.Event_Purge:                (*protobuf.PurgeRequest)(nil),
+	protobuf.Event_SetRole:              (*protobuf.SetRoleRequest)(nil),
+	protobuf.Event_SetClusterConfig:     (*protobuf.SetClusterConfigRequest)(nil),
+	protobuf.Event_Replicate:            (*protobuf.ReplicateRequest)(nil),
+}
+
+// NewEvent builds an event of the specified type.
+// It returns an error if the data is not the message registered for the event type.
+func NewEvent(eventType protobuf.Event_Type, data proto.Message) (*protobuf.Event, error) {
+	expected, ok := eventDataTypes[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnknownEventType, eventType.String())
+	}
+	if reflect.TypeOf(data) != reflect.TypeOf(expected) {
+		return nil, fmt.Errorf("%w: %s event requires %T, got %T", errors.ErrUnexpectedPayloadType, eventType.String(), expected, data)
+	}
+
+	dataAny := &any.Any{}
+	if err := UnmarshalAny(data, dataAny); err != nil {
+		return nil, err
+	}
+
+	return &protobuf.Event{
+		Type: eventType,
+		Data: dataAny,
+	}, nil
+}
+
+// EventData decodes the data of the event into the message registered for the event type.
+// Events and payloads written by a node with a different set of event types result in
+// ErrUnknownEventType, ErrUnknownPayloadType or ErrUnexpectedPayloadType.
+func EventData(event *protobuf.Event) (proto.Message, error) {
+	expected, ok := eventDataTypes[event.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnknownEventType, event.Type.String())
+	}
+	if event.Data == nil {
+		return nil, fmt.Errorf("%w: %s event", errors.ErrEmptyPayload, event.Type.String())
+	}
+
+	data, err := MarshalAny(event.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	message, ok := data.(proto.Message)
+	if !ok || reflect.TypeOf(message) != reflect.TypeOf(expected) {
+		logger.Debug("unexpected event payload", zap.String("event_type", event.Type.String()), zap.String("type_url", event.Data.TypeUrl))
+		return nil, fmt.Errorf("%w: %s event requires %T, got %q", errors.ErrUnexpectedPayloadType, event.Type.String(), expected, event.Data.TypeUrl)
+	}
+
+	return message, nil
+}
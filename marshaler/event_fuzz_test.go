@@ -0,0 +1,58 @@
+//go:build go1.18
+// +build go1.18
+
+package marshaler
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/mosuka/cete/protobuf"
+)
+
+func FuzzMarshalAny(f *testing.F) {
+	f.Add("protobuf.SetRequest", []byte(`{"key":"a","value":"MQ=="}`))
+	f.Add("protobuf.Node", []byte(`{"raft_address":":7000"}`))
+	f.Add("unknown", []byte(`{}`))
+	f.Add("map[string]interface {}", []byte(`{"a":1}`))
+
+	f.Fuzz(func(t *testing.T, typeUrl string, value []byte) {
+		_, _ = MarshalAny(&any.Any{TypeUrl: typeUrl, Value: value})
+	})
+}
+
+func FuzzEventData(f *testing.F) {
+	for eventType, data := range map[protobuf.Event_Type]proto.Message{
+		protobuf.Event_Set:    &protobuf.SetRequest{Key: "a", Value: []byte("1")},
+		protobuf.Event_Delete: &protobuf.DeleteRequest{Key: "a"},
+		protobuf.Event_Join:   &protobuf.SetMetadataRequest{Id: "node1"},
+	} {
+		event, err := NewEvent(eventType, data)
+		if err != nil {
+			f.Fatalf("%v", err)
+		}
+		b, err := proto.Marshal(event)
+		if err != nil {
+			f.Fatalf("%v", err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		event := &protobuf.Event{}
+		if err := proto.Unmarshal(b, event); err != nil {
+			return
+		}
+
+		data, err := EventData(event)
+		if err != nil {
+			return
+		}
+
+		// a decoded payload is always the message registered for the event type
+		if _, err := NewEvent(event.Type, data); err != nil {
+			t.Fatalf("decoded payload is not accepted for %s: %v", event.Type.String(), err)
+		}
+	})
+}
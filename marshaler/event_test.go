@@ -0,0 +1,86 @@
+package marshaler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	ceteerrors "github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+)
+
+func TestEventDataTypes(t *testing.T) {
+	for value, name := range protobuf.Event_Type_name {
+		eventType := protobuf.Event_Type(value)
+		if eventType == protobuf.Event_Unknown {
+			continue
+		}
+		expected, ok := eventDataTypes[eventType]
+		if !ok {
+			t.Errorf("event type %s has no registered data type", name)
+			continue
+		}
+
+		// the data type must also be known to the type registry
+		data := reflect.New(reflect.TypeOf(expected).Elem()).Interface().(proto.Message)
+		event, err := NewEvent(eventType, data)
+		if err != nil {
+			t.Errorf("failed to create a %s event: %v", name, err)
+			continue
+		}
+		if _, err := EventData(event); err != nil {
+			t.Errorf("failed to decode the data of a %s event: %v", name, err)
+		}
+	}
+}
+
+func TestEvent(t *testing.T) {
+	req := &protobuf.SetRequest{
+		Key:   "a",
+		Value: []byte("1"),
+	}
+
+	event, err := NewEvent(protobuf.Event_Set, req)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	data, err := EventData(event)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !proto.Equal(req, data) {
+		t.Errorf("expected content to see %v, saw %v", req, data)
+	}
+
+	// the data does not match the event type
+	if _, err := NewEvent(protobuf.Event_Delete, req); !errors.Is(err, ceteerrors.ErrUnexpectedPayloadType) {
+		t.Errorf("expected %v, saw %v", ceteerrors.ErrUnexpectedPayloadType, err)
+	}
+	event.Type = protobuf.Event_Delete
+	if _, err := EventData(event); !errors.Is(err, ceteerrors.ErrUnexpectedPayloadType) {
+		t.Errorf("expected %v, saw %v", ceteerrors.ErrUnexpectedPayloadType, err)
+	}
+
+	// an event type added by a newer node
+	event.Type = protobuf.Event_Type(100)
+	if _, err := EventData(event); !errors.Is(err, ceteerrors.ErrUnknownEventType) {
+		t.Errorf("expected %v, saw %v", ceteerrors.ErrUnknownEventType, err)
+	}
+
+	// a payload type added by a newer node
+	event = &protobuf.Event{
+		Type: protobuf.Event_Set,
+		Data: &any.Any{TypeUrl: "protobuf.SetRequestV2", Value: []byte("{}")},
+	}
+	if _, err := EventData(event); !errors.Is(err, ceteerrors.ErrUnknownPayloadType) {
+		t.Errorf("expected %v, saw %v", ceteerrors.ErrUnknownPayloadType, err)
+	}
+
+	event.Data = nil
+	if _, err := EventData(event); !errors.Is(err, ceteerrors.ErrEmptyPayload) {
+		t.Errorf("expected %v, saw %v", ceteerrors.ErrEmptyPayload, err)
+	}
+}
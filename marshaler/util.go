@@ -2,13 +2,49 @@ package marshaler
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/metric"
 	"github.com/mosuka/cete/protobuf"
 	"github.com/mosuka/cete/registry"
+	"go.uber.org/zap"
 )
 
+// the directions of the conversions in the metrics, decode converts an Any to a message
+// and encode a message to an Any
+const (
+	directionDecode = "decode"
+	directionEncode = "encode"
+)
+
+// unknownType labels the conversions of unregistered types, the type URLs of malformed
+// payloads must not grow the number of series.
+const unknownType = "unknown"
+
+var logger = zap.NewNop()
+
+// SetLogger sets the logger of the conversion failures. They are logged at the debug
+// level along with the offending type URL.
+func SetLogger(l *zap.Logger) {
+	logger = l
+}
+
+func observeConversion(direction string, typeUrl string, typeLabel string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+		logger.Debug("failed to convert payload", zap.String("direction", direction), zap.String("type_url", typeUrl), zap.Error(err))
+	}
+
+	metric.MarshalerConversionsMetric.WithLabelValues(direction, typeLabel, result).Inc()
+	metric.MarshalerConversionDurationMetric.WithLabelValues(direction, typeLabel).Observe(time.Since(start).Seconds())
+}
+
 func init() {
 	registry.RegisterType("protobuf.LivenessCheckResponse", reflect.TypeOf(protobuf.LivenessCheckResponse{}))
 	registry.RegisterType("protobuf.ReadinessCheckResponse", reflect.TypeOf(protobuf.ReadinessCheckResponse{}))
@@ -23,12 +59,32 @@ func init() {
 	registry.RegisterType("protobuf.GetResponse", reflect.TypeOf(protobuf.GetResponse{}))
 	registry.RegisterType("protobuf.SetRequest", reflect.TypeOf(protobuf.SetRequest{}))
 	registry.RegisterType("protobuf.DeleteRequest", reflect.TypeOf(protobuf.DeleteRequest{}))
+	registry.RegisterType("protobuf.CopyRequest", reflect.TypeOf(protobuf.CopyRequest{}))
+	registry.RegisterType("protobuf.MoveRequest", reflect.TypeOf(protobuf.MoveRequest{}))
+	registry.RegisterType("protobuf.ExpireRequest", reflect.TypeOf(protobuf.ExpireRequest{}))
 	registry.RegisterType("protobuf.SetMetadataRequest", reflect.TypeOf(protobuf.SetMetadataRequest{}))
 	registry.RegisterType("protobuf.DeleteMetadataRequest", reflect.TypeOf(protobuf.DeleteMetadataRequest{}))
 	registry.RegisterType("protobuf.Event", reflect.TypeOf(protobuf.Event{}))
 	registry.RegisterType("protobuf.WatchResponse", reflect.TypeOf(protobuf.WatchResponse{}))
 	registry.RegisterType("protobuf.MetricsResponse", reflect.TypeOf(protobuf.MetricsResponse{}))
 	registry.RegisterType("protobuf.KeyValuePair", reflect.TypeOf(protobuf.KeyValuePair{}))
+	registry.RegisterType("protobuf.LeaderHint", reflect.TypeOf(protobuf.LeaderHint{}))
+	registry.RegisterType("protobuf.BatchJoinRequest", reflect.TypeOf(protobuf.BatchJoinRequest{}))
+	registry.RegisterType("protobuf.BatchJoinResponse", reflect.TypeOf(protobuf.BatchJoinResponse{}))
+	registry.RegisterType("protobuf.MembershipSpec", reflect.TypeOf(protobuf.MembershipSpec{}))
+	registry.RegisterType("protobuf.MembershipSpecResponse", reflect.TypeOf(protobuf.MembershipSpecResponse{}))
+	registry.RegisterType("protobuf.SetMembershipSpecRequest", reflect.TypeOf(protobuf.SetMembershipSpecRequest{}))
+	registry.RegisterType("protobuf.ReconcileAction", reflect.TypeOf(protobuf.ReconcileAction{}))
+	registry.RegisterType("protobuf.DecommissionStatus", reflect.TypeOf(protobuf.DecommissionStatus{}))
+	registry.RegisterType("protobuf.AnnotateRequest", reflect.TypeOf(protobuf.AnnotateRequest{}))
+	registry.RegisterType("protobuf.UpdateNodeStatusRequest", reflect.TypeOf(protobuf.UpdateNodeStatusRequest{}))
+	registry.RegisterType("protobuf.APIKey", reflect.TypeOf(protobuf.APIKey{}))
+	registry.RegisterType("protobuf.RevokeAPIKeyRequest", reflect.TypeOf(protobuf.RevokeAPIKeyRequest{}))
+	registry.RegisterType("protobuf.PurgeRequest", reflect.TypeOf(protobuf.PurgeRequest{}))
+	registry.RegisterType("protobuf.SetRoleRequest", reflect.TypeOf(protobuf.SetRoleRequest{}))
+	registry.RegisterType("protobuf.ReplicateRequest", reflect.TypeOf(protobuf.ReplicateRequest{}))
+	registry.RegisterType("protobuf.SetClusterConfigRequest", reflect.TypeOf(protobuf.SetClusterConfigRequest{}))
+	registry.RegisterType("empty.Empty", reflect.TypeOf(empty.Empty{}))
 	registry.RegisterType("map[string]interface {}", reflect.TypeOf((map[string]interface{})(nil)))
 }
 
@@ -37,12 +93,21 @@ func MarshalAny(message *any.Any) (interface{}, error) {
 		return nil, nil
 	}
 
+	start := time.Now()
+
 	typeUrl := message.TypeUrl
 	value := message.Value
 
 	instance := registry.TypeInstanceByName(typeUrl)
+	if instance == nil {
+		err := fmt.Errorf("%w: %q", errors.ErrUnknownPayloadType, typeUrl)
+		observeConversion(directionDecode, typeUrl, unknownType, start, err)
+		return nil, err
+	}
 
-	if err := json.Unmarshal(value, instance); err != nil {
+	err := json.Unmarshal(value, instance)
+	observeConversion(directionDecode, typeUrl, typeUrl, start, err)
+	if err != nil {
 		return nil, err
 	} else {
 		return instance, nil
@@ -55,12 +120,17 @@ func UnmarshalAny(instance interface{}, message *any.Any) error {
 		return nil
 	}
 
+	start := time.Now()
+
+	typeUrl := registry.TypeNameByInstance(instance)
+
 	value, err := json.Marshal(instance)
+	observeConversion(directionEncode, typeUrl, typeUrl, start, err)
 	if err != nil {
 		return err
 	}
 
-	message.TypeUrl = registry.TypeNameByInstance(instance)
+	message.TypeUrl = typeUrl
 	message.Value = value
 
 	return nil
@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/ptypes/any"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/metric"
 	"github.com/mosuka/cete/protobuf"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestMarshalAny(t *testing.T) {
@@ -107,3 +110,14 @@ func TestUnmarshalAny(t *testing.T) {
 		t.Errorf("expected content to see %v, saw %v", "Leader", node.State)
 	}
 }
+
+func TestMarshalAnyMetrics(t *testing.T) {
+	failures := testutil.ToFloat64(metric.MarshalerConversionsMetric.WithLabelValues(directionDecode, unknownType, "error"))
+
+	if _, err := MarshalAny(&any.Any{TypeUrl: "protobuf.Missing", Value: []byte("{}")}); !errors.Is(err, errors.ErrUnknownPayloadType) {
+		t.Errorf("expected an unknown payload type, saw %v", err)
+	}
+	if count := testutil.ToFloat64(metric.MarshalerConversionsMetric.WithLabelValues(directionDecode, unknownType, "error")); count != failures+1 {
+		t.Errorf("expected %v failures, saw %v", failures+1, count)
+	}
+}
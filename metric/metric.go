@@ -108,6 +108,27 @@ var (
 		Help:      "Number of nodes.",
 	}, []string{"id"})
 
+	RaftFencedWritesMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "raft",
+		Name:      "fenced_writes_total",
+		Help:      "Number of writes refused because the node was not, or no longer, the leader.",
+	}, []string{"id", "reason"})
+
+	RaftRejectedPeersMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "raft",
+		Name:      "rejected_peers_total",
+		Help:      "Number of joins and Raft connections refused because the peer is not in the peer allowlist.",
+	}, []string{"id", "reason"})
+
+	RaftQuorumLostMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cete",
+		Subsystem: "raft",
+		Name:      "quorum_lost",
+		Help:      "1 if the node has not known a leader for longer than the quorum loss timeout, 0 otherwise.",
+	}, []string{"id"})
+
 	KvsNumReadsMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "cete",
 		Subsystem: "kvs",
@@ -198,6 +219,113 @@ var (
 		Name:      "pending_writes",
 		Help:      "Pending writes.",
 	}, []string{"id", "path"})
+
+	KvsLevel0TablesMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cete",
+		Subsystem: "kvs",
+		Name:      "level0_tables",
+		Help:      "Number of tables in level 0 waiting to be compacted.",
+	}, []string{"id"})
+
+	KvsWriteStalledMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cete",
+		Subsystem: "kvs",
+		Name:      "write_stalled",
+		Help:      "Whether the writes are held back until the compactions catch up.",
+	}, []string{"id"})
+
+	KvsWriteStallsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "kvs",
+		Name:      "write_stalls_total",
+		Help:      "Number of times the writes were held back until the compactions caught up.",
+	}, []string{"id"})
+
+	KvsExpiredKeysMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "kvs",
+		Name:      "expired_keys_total",
+		Help:      "Number of expired keys deleted by the expiration sweeps.",
+	}, []string{"id"})
+
+	KvsExpiredBytesMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "kvs",
+		Name:      "expired_bytes_total",
+		Help:      "Number of bytes of the keys and values deleted by the expiration sweeps.",
+	}, []string{"id"})
+
+	KvsPurgedKeysMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "kvs",
+		Name:      "purged_keys_total",
+		Help:      "Number of keys deleted by the compaction filters.",
+	}, []string{"id", "filter"})
+
+	ReplicationReplicatedIndexMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cete",
+		Subsystem: "replication",
+		Name:      "replicated_index",
+		Help:      "Index in the log of the source cluster of the last event replicated by a standby.",
+	}, []string{"id"})
+
+	RaftWriteStageDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "cete",
+		Subsystem: "raft",
+		Name:      "write_stage_duration_seconds",
+		Help:      "Time spent by write requests in each stage.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 16),
+	}, []string{"id", "stage"})
+
+	MarshalerConversionsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "marshaler",
+		Name:      "conversions_total",
+		Help:      "Number of conversions between the messages and the JSON carried in Any, by direction, type and result.",
+	}, []string{"direction", "type", "result"})
+
+	MarshalerConversionDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "cete",
+		Subsystem: "marshaler",
+		Name:      "conversion_duration_seconds",
+		Help:      "Time spent converting between the messages and the JSON carried in Any.",
+		Buckets:   prometheus.ExponentialBuckets(0.00001, 2, 16),
+	}, []string{"direction", "type"})
+
+	WatchWatchersMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cete",
+		Subsystem: "watch",
+		Name:      "watchers",
+		Help:      "Number of watchers.",
+	}, []string{"id"})
+
+	WatchQueuedEventsMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cete",
+		Subsystem: "watch",
+		Name:      "queued_events",
+		Help:      "Number of events queued for all watchers.",
+	}, []string{"id"})
+
+	WatchDroppedEventsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "watch",
+		Name:      "dropped_events_total",
+		Help:      "Number of events dropped because a watcher fell behind.",
+	}, []string{"id"})
+
+	WatchDroppedPriorityEventsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "watch",
+		Name:      "dropped_priority_events_total",
+		Help:      "Number of events of priority writes dropped because they were not fanned out, e.g. while the node stops.",
+	}, []string{"id"})
+
+	WatchCancelledMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cete",
+		Subsystem: "watch",
+		Name:      "cancelled_total",
+		Help:      "Number of watches cancelled because the watcher fell behind.",
+	}, []string{"id"})
 )
 
 func init() {
@@ -217,6 +345,9 @@ func init() {
 		RaftNumPeersMetric,
 		RaftLastContactMetric,
 		RaftNumNodesMetric,
+		RaftQuorumLostMetric,
+		RaftFencedWritesMetric,
+		RaftRejectedPeersMetric,
 		KvsNumReadsMetric,
 		KvsNumWritesMetric,
 		KvsNumBytesReadMetric,
@@ -230,6 +361,21 @@ func init() {
 		KvsLSMSizeMetric,
 		KvsVlogSizeMetric,
 		KvsPendingWritesMetric,
+		KvsLevel0TablesMetric,
+		KvsWriteStalledMetric,
+		KvsWriteStallsMetric,
+		KvsExpiredKeysMetric,
+		KvsExpiredBytesMetric,
+		KvsPurgedKeysMetric,
+		ReplicationReplicatedIndexMetric,
+		RaftWriteStageDurationMetric,
+		MarshalerConversionsMetric,
+		MarshalerConversionDurationMetric,
+		WatchWatchersMetric,
+		WatchQueuedEventsMetric,
+		WatchDroppedEventsMetric,
+		WatchDroppedPriorityEventsMetric,
+		WatchCancelledMetric,
 	)
 	GrpcMetrics.EnableHandlingTimeHistogram(
 		func(o *prometheus.HistogramOpts) {
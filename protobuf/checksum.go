@@ -0,0 +1,12 @@
+package protobuf
+
+import (
+	"hash/crc32"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Checksum returns the CRC-32C of the value, as set in the checksum of a SetRequest.
+func Checksum(value []byte) uint32 {
+	return crc32.Checksum(value, castagnoli)
+}
@@ -0,0 +1,19 @@
+package protobuf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseConsistency parses a read consistency level, e.g. "strong" or "leader_preferred".
+// The case and the underscores are ignored.
+func ParseConsistency(s string) (GetRequest_Consistency, error) {
+	normalized := strings.ReplaceAll(s, "_", "")
+	for name, value := range GetRequest_Consistency_value {
+		if strings.EqualFold(name, normalized) {
+			return GetRequest_Consistency(value), nil
+		}
+	}
+
+	return GetRequest_Stale, fmt.Errorf("unknown consistency %q", s)
+}
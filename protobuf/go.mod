@@ -0,0 +1,11 @@
+module github.com/mosuka/cete/protobuf
+
+go 1.14
+
+require (
+	github.com/golang/protobuf v1.3.5
+	github.com/grpc-ecosystem/grpc-gateway v1.14.3
+	golang.org/x/sys v0.0.0-20200122134326-e047566fdf82 // indirect
+	google.golang.org/genproto v0.0.0-20190927181202-20e1ac93f88c
+	google.golang.org/grpc v1.28.0
+)
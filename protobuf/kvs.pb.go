@@ -8,7 +8,10 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	any "github.com/golang/protobuf/ptypes/any"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
 	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
@@ -28,30 +31,199 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
-type Event_Type int32
+type ReconcileAction_Type int32
 
 const (
-	Event_Unknown Event_Type = 0
-	Event_Join    Event_Type = 1
-	Event_Leave   Event_Type = 2
-	Event_Set     Event_Type = 3
-	Event_Delete  Event_Type = 4
+	ReconcileAction_Unknown     ReconcileAction_Type = 0
+	ReconcileAction_Join        ReconcileAction_Type = 1
+	ReconcileAction_Unreachable ReconcileAction_Type = 2
+	ReconcileAction_Extra       ReconcileAction_Type = 3
 )
 
-var Event_Type_name = map[int32]string{
+var ReconcileAction_Type_name = map[int32]string{
 	0: "Unknown",
 	1: "Join",
-	2: "Leave",
-	3: "Set",
-	4: "Delete",
+	2: "Unreachable",
+	3: "Extra",
+}
+
+var ReconcileAction_Type_value = map[string]int32{
+	"Unknown":     0,
+	"Join":        1,
+	"Unreachable": 2,
+	"Extra":       3,
+}
+
+func (x ReconcileAction_Type) String() string {
+	return proto.EnumName(ReconcileAction_Type_name, int32(x))
+}
+
+func (ReconcileAction_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{15, 0}
+}
+
+type DecommissionStatus_Phase int32
+
+const (
+	DecommissionStatus_Unknown               DecommissionStatus_Phase = 0
+	DecommissionStatus_Leaving               DecommissionStatus_Phase = 1
+	DecommissionStatus_ConfigChangeCommitted DecommissionStatus_Phase = 2
+	DecommissionStatus_DataNotNeeded         DecommissionStatus_Phase = 3
+	DecommissionStatus_SafeToWipe            DecommissionStatus_Phase = 4
+)
+
+var DecommissionStatus_Phase_name = map[int32]string{
+	0: "Unknown",
+	1: "Leaving",
+	2: "ConfigChangeCommitted",
+	3: "DataNotNeeded",
+	4: "SafeToWipe",
+}
+
+var DecommissionStatus_Phase_value = map[string]int32{
+	"Unknown":               0,
+	"Leaving":               1,
+	"ConfigChangeCommitted": 2,
+	"DataNotNeeded":         3,
+	"SafeToWipe":            4,
+}
+
+func (x DecommissionStatus_Phase) String() string {
+	return proto.EnumName(DecommissionStatus_Phase_name, int32(x))
+}
+
+func (DecommissionStatus_Phase) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{16, 0}
+}
+
+type GetRequest_Consistency int32
+
+const (
+	GetRequest_Stale           GetRequest_Consistency = 0
+	GetRequest_Strong          GetRequest_Consistency = 1
+	GetRequest_LeaderPreferred GetRequest_Consistency = 2
+)
+
+var GetRequest_Consistency_name = map[int32]string{
+	0: "Stale",
+	1: "Strong",
+	2: "LeaderPreferred",
+}
+
+var GetRequest_Consistency_value = map[string]int32{
+	"Stale":           0,
+	"Strong":          1,
+	"LeaderPreferred": 2,
+}
+
+func (x GetRequest_Consistency) String() string {
+	return proto.EnumName(GetRequest_Consistency_name, int32(x))
+}
+
+func (GetRequest_Consistency) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{35, 0}
+}
+
+type Role_Mode int32
+
+const (
+	// the cluster takes the writes
+	Role_Primary Role_Mode = 0
+	// the cluster refuses the writes, and replicates those of the source cluster
+	Role_Standby Role_Mode = 1
+	// the cluster refuses the writes, so that a standby can catch up before it is promoted
+	Role_Frozen Role_Mode = 2
+)
+
+var Role_Mode_name = map[int32]string{
+	0: "Primary",
+	1: "Standby",
+	2: "Frozen",
+}
+
+var Role_Mode_value = map[string]int32{
+	"Primary": 0,
+	"Standby": 1,
+	"Frozen":  2,
+}
+
+func (x Role_Mode) String() string {
+	return proto.EnumName(Role_Mode_name, int32(x))
+}
+
+func (Role_Mode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{47, 0}
+}
+
+type Event_Type int32
+
+const (
+	Event_Unknown              Event_Type = 0
+	Event_Join                 Event_Type = 1
+	Event_Leave                Event_Type = 2
+	Event_Set                  Event_Type = 3
+	Event_Delete               Event_Type = 4
+	Event_SetMembershipSpec    Event_Type = 5
+	Event_DeleteMembershipSpec Event_Type = 6
+	Event_Reconcile            Event_Type = 7
+	Event_Decommission         Event_Type = 8
+	Event_Copy                 Event_Type = 9
+	Event_Move                 Event_Type = 10
+	Event_Expire               Event_Type = 11
+	Event_Annotate             Event_Type = 12
+	Event_UpdateNodeStatus     Event_Type = 13
+	Event_CreateAPIKey         Event_Type = 14
+	Event_RevokeAPIKey         Event_Type = 15
+	Event_Purge                Event_Type = 16
+	Event_SetRole              Event_Type = 17
+	Event_Replicate            Event_Type = 18
+	Event_SetClusterConfig     Event_Type = 19
+)
+
+var Event_Type_name = map[int32]string{
+	0:  "Unknown",
+	1:  "Join",
+	2:  "Leave",
+	3:  "Set",
+	4:  "Delete",
+	5:  "SetMembershipSpec",
+	6:  "DeleteMembershipSpec",
+	7:  "Reconcile",
+	8:  "Decommission",
+	9:  "Copy",
+	10: "Move",
+	11: "Expire",
+	12: "Annotate",
+	13: "UpdateNodeStatus",
+	14: "CreateAPIKey",
+	15: "RevokeAPIKey",
+	16: "Purge",
+	17: "SetRole",
+	18: "Replicate",
+	19: "SetClusterConfig",
 }
 
 var Event_Type_value = map[string]int32{
-	"Unknown": 0,
-	"Join":    1,
-	"Leave":   2,
-	"Set":     3,
-	"Delete":  4,
+	"Unknown":              0,
+	"Join":                 1,
+	"Leave":                2,
+	"Set":                  3,
+	"Delete":               4,
+	"SetMembershipSpec":    5,
+	"DeleteMembershipSpec": 6,
+	"Reconcile":            7,
+	"Decommission":         8,
+	"Copy":                 9,
+	"Move":                 10,
+	"Expire":               11,
+	"Annotate":             12,
+	"UpdateNodeStatus":     13,
+	"CreateAPIKey":         14,
+	"RevokeAPIKey":         15,
+	"Purge":                16,
+	"SetRole":              17,
+	"Replicate":            18,
+	"SetClusterConfig":     19,
 }
 
 func (x Event_Type) String() string {
@@ -59,7 +231,33 @@ func (x Event_Type) String() string {
 }
 
 func (Event_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{17, 0}
+	return fileDescriptor_431078ad7b21f851, []int{56, 0}
+}
+
+type WatchRequest_Encoding int32
+
+const (
+	WatchRequest_Verbose WatchRequest_Encoding = 0
+	// several events are sent in each response, in compact_events
+	WatchRequest_Compact WatchRequest_Encoding = 1
+)
+
+var WatchRequest_Encoding_name = map[int32]string{
+	0: "Verbose",
+	1: "Compact",
+}
+
+var WatchRequest_Encoding_value = map[string]int32{
+	"Verbose": 0,
+	"Compact": 1,
+}
+
+func (x WatchRequest_Encoding) String() string {
+	return proto.EnumName(WatchRequest_Encoding_name, int32(x))
+}
+
+func (WatchRequest_Encoding) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{57, 0}
 }
 
 type LivenessCheckResponse struct {
@@ -143,6 +341,7 @@ func (m *ReadinessCheckResponse) GetReady() bool {
 type Metadata struct {
 	GrpcAddress          string   `protobuf:"bytes,1,opt,name=grpc_address,json=grpcAddress,proto3" json:"grpc_address,omitempty"`
 	HttpAddress          string   `protobuf:"bytes,2,opt,name=http_address,json=httpAddress,proto3" json:"http_address,omitempty"`
+	ReadOnlyGrpcAddress  string   `protobuf:"bytes,3,opt,name=read_only_grpc_address,json=readOnlyGrpcAddress,proto3" json:"read_only_grpc_address,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -187,13 +386,23 @@ func (m *Metadata) GetHttpAddress() string {
 	return ""
 }
 
+func (m *Metadata) GetReadOnlyGrpcAddress() string {
+	if m != nil {
+		return m.ReadOnlyGrpcAddress
+	}
+	return ""
+}
+
 type Node struct {
-	RaftAddress          string    `protobuf:"bytes,1,opt,name=raft_address,json=raftAddress,proto3" json:"raft_address,omitempty"`
-	Metadata             *Metadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	State                string    `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	RaftAddress          string            `protobuf:"bytes,1,opt,name=raft_address,json=raftAddress,proto3" json:"raft_address,omitempty"`
+	Metadata             *Metadata         `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	State                string            `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	AppliedIndex         uint64            `protobuf:"varint,4,opt,name=applied_index,json=appliedIndex,proto3" json:"applied_index,omitempty"`
+	Annotations          map[string]string `protobuf:"bytes,5,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Ready                bool              `protobuf:"varint,6,opt,name=ready,proto3" json:"ready,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Node) Reset()         { *m = Node{} }
@@ -242,12 +451,36 @@ func (m *Node) GetState() string {
 	return ""
 }
 
+func (m *Node) GetAppliedIndex() uint64 {
+	if m != nil {
+		return m.AppliedIndex
+	}
+	return 0
+}
+
+func (m *Node) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *Node) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
 type Cluster struct {
-	Nodes                map[string]*Node `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Leader               string           `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Nodes  map[string]*Node `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Leader string           `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	// the number of membership changes applied, incremented by every node added to or
+	// removed from the cluster
+	MembershipEpoch      uint64   `protobuf:"varint,3,opt,name=membership_epoch,json=membershipEpoch,proto3" json:"membership_epoch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Cluster) Reset()         { *m = Cluster{} }
@@ -289,9 +522,166 @@ func (m *Cluster) GetLeader() string {
 	return ""
 }
 
+func (m *Cluster) GetMembershipEpoch() uint64 {
+	if m != nil {
+		return m.MembershipEpoch
+	}
+	return 0
+}
+
+type LeaderHint struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Node                 *Node    `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaderHint) Reset()         { *m = LeaderHint{} }
+func (m *LeaderHint) String() string { return proto.CompactTextString(m) }
+func (*LeaderHint) ProtoMessage()    {}
+func (*LeaderHint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{5}
+}
+
+func (m *LeaderHint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaderHint.Unmarshal(m, b)
+}
+func (m *LeaderHint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaderHint.Marshal(b, m, deterministic)
+}
+func (m *LeaderHint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaderHint.Merge(m, src)
+}
+func (m *LeaderHint) XXX_Size() int {
+	return xxx_messageInfo_LeaderHint.Size(m)
+}
+func (m *LeaderHint) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaderHint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaderHint proto.InternalMessageInfo
+
+func (m *LeaderHint) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *LeaderHint) GetNode() *Node {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+type StageTiming struct {
+	Stage                string             `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Elapsed              *duration.Duration `protobuf:"bytes,2,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StageTiming) Reset()         { *m = StageTiming{} }
+func (m *StageTiming) String() string { return proto.CompactTextString(m) }
+func (*StageTiming) ProtoMessage()    {}
+func (*StageTiming) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{6}
+}
+
+func (m *StageTiming) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StageTiming.Unmarshal(m, b)
+}
+func (m *StageTiming) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StageTiming.Marshal(b, m, deterministic)
+}
+func (m *StageTiming) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StageTiming.Merge(m, src)
+}
+func (m *StageTiming) XXX_Size() int {
+	return xxx_messageInfo_StageTiming.Size(m)
+}
+func (m *StageTiming) XXX_DiscardUnknown() {
+	xxx_messageInfo_StageTiming.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StageTiming proto.InternalMessageInfo
+
+func (m *StageTiming) GetStage() string {
+	if m != nil {
+		return m.Stage
+	}
+	return ""
+}
+
+func (m *StageTiming) GetElapsed() *duration.Duration {
+	if m != nil {
+		return m.Elapsed
+	}
+	return nil
+}
+
+type DeadlineBudget struct {
+	Budget               *duration.Duration `protobuf:"bytes,1,opt,name=budget,proto3" json:"budget,omitempty"`
+	ExceededStage        string             `protobuf:"bytes,2,opt,name=exceeded_stage,json=exceededStage,proto3" json:"exceeded_stage,omitempty"`
+	Stages               []*StageTiming     `protobuf:"bytes,3,rep,name=stages,proto3" json:"stages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *DeadlineBudget) Reset()         { *m = DeadlineBudget{} }
+func (m *DeadlineBudget) String() string { return proto.CompactTextString(m) }
+func (*DeadlineBudget) ProtoMessage()    {}
+func (*DeadlineBudget) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{7}
+}
+
+func (m *DeadlineBudget) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeadlineBudget.Unmarshal(m, b)
+}
+func (m *DeadlineBudget) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeadlineBudget.Marshal(b, m, deterministic)
+}
+func (m *DeadlineBudget) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeadlineBudget.Merge(m, src)
+}
+func (m *DeadlineBudget) XXX_Size() int {
+	return xxx_messageInfo_DeadlineBudget.Size(m)
+}
+func (m *DeadlineBudget) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeadlineBudget.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeadlineBudget proto.InternalMessageInfo
+
+func (m *DeadlineBudget) GetBudget() *duration.Duration {
+	if m != nil {
+		return m.Budget
+	}
+	return nil
+}
+
+func (m *DeadlineBudget) GetExceededStage() string {
+	if m != nil {
+		return m.ExceededStage
+	}
+	return ""
+}
+
+func (m *DeadlineBudget) GetStages() []*StageTiming {
+	if m != nil {
+		return m.Stages
+	}
+	return nil
+}
+
 type JoinRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Node                 *Node    `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	CatchUpAsNonvoter    bool     `protobuf:"varint,3,opt,name=catch_up_as_nonvoter,json=catchUpAsNonvoter,proto3" json:"catch_up_as_nonvoter,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -301,7 +691,7 @@ func (m *JoinRequest) Reset()         { *m = JoinRequest{} }
 func (m *JoinRequest) String() string { return proto.CompactTextString(m) }
 func (*JoinRequest) ProtoMessage()    {}
 func (*JoinRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{5}
+	return fileDescriptor_431078ad7b21f851, []int{8}
 }
 
 func (m *JoinRequest) XXX_Unmarshal(b []byte) error {
@@ -336,6 +726,13 @@ func (m *JoinRequest) GetNode() *Node {
 	return nil
 }
 
+func (m *JoinRequest) GetCatchUpAsNonvoter() bool {
+	if m != nil {
+		return m.CatchUpAsNonvoter
+	}
+	return false
+}
+
 type LeaveRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -347,7 +744,7 @@ func (m *LeaveRequest) Reset()         { *m = LeaveRequest{} }
 func (m *LeaveRequest) String() string { return proto.CompactTextString(m) }
 func (*LeaveRequest) ProtoMessage()    {}
 func (*LeaveRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{6}
+	return fileDescriptor_431078ad7b21f851, []int{9}
 }
 
 func (m *LeaveRequest) XXX_Unmarshal(b []byte) error {
@@ -375,1044 +772,4237 @@ func (m *LeaveRequest) GetId() string {
 	return ""
 }
 
-type NodeResponse struct {
-	Node                 *Node    `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type BatchJoinRequest struct {
+	Nodes                map[string]*Node `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
-func (m *NodeResponse) Reset()         { *m = NodeResponse{} }
-func (m *NodeResponse) String() string { return proto.CompactTextString(m) }
-func (*NodeResponse) ProtoMessage()    {}
-func (*NodeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{7}
+func (m *BatchJoinRequest) Reset()         { *m = BatchJoinRequest{} }
+func (m *BatchJoinRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchJoinRequest) ProtoMessage()    {}
+func (*BatchJoinRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{10}
 }
 
-func (m *NodeResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_NodeResponse.Unmarshal(m, b)
+func (m *BatchJoinRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchJoinRequest.Unmarshal(m, b)
 }
-func (m *NodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_NodeResponse.Marshal(b, m, deterministic)
+func (m *BatchJoinRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchJoinRequest.Marshal(b, m, deterministic)
 }
-func (m *NodeResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NodeResponse.Merge(m, src)
+func (m *BatchJoinRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchJoinRequest.Merge(m, src)
 }
-func (m *NodeResponse) XXX_Size() int {
-	return xxx_messageInfo_NodeResponse.Size(m)
+func (m *BatchJoinRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchJoinRequest.Size(m)
 }
-func (m *NodeResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_NodeResponse.DiscardUnknown(m)
+func (m *BatchJoinRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchJoinRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_NodeResponse proto.InternalMessageInfo
+var xxx_messageInfo_BatchJoinRequest proto.InternalMessageInfo
 
-func (m *NodeResponse) GetNode() *Node {
+func (m *BatchJoinRequest) GetNodes() map[string]*Node {
 	if m != nil {
-		return m.Node
+		return m.Nodes
 	}
 	return nil
 }
 
-type ClusterResponse struct {
-	Cluster              *Cluster `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+type BatchJoinResponse struct {
+	Joined               []string `protobuf:"bytes,1,rep,name=joined,proto3" json:"joined,omitempty"`
+	Left                 []string `protobuf:"bytes,2,rep,name=left,proto3" json:"left,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ClusterResponse) Reset()         { *m = ClusterResponse{} }
-func (m *ClusterResponse) String() string { return proto.CompactTextString(m) }
-func (*ClusterResponse) ProtoMessage()    {}
-func (*ClusterResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{8}
+func (m *BatchJoinResponse) Reset()         { *m = BatchJoinResponse{} }
+func (m *BatchJoinResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchJoinResponse) ProtoMessage()    {}
+func (*BatchJoinResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{11}
 }
 
-func (m *ClusterResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ClusterResponse.Unmarshal(m, b)
+func (m *BatchJoinResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchJoinResponse.Unmarshal(m, b)
 }
-func (m *ClusterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ClusterResponse.Marshal(b, m, deterministic)
+func (m *BatchJoinResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchJoinResponse.Marshal(b, m, deterministic)
 }
-func (m *ClusterResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ClusterResponse.Merge(m, src)
+func (m *BatchJoinResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchJoinResponse.Merge(m, src)
 }
-func (m *ClusterResponse) XXX_Size() int {
-	return xxx_messageInfo_ClusterResponse.Size(m)
+func (m *BatchJoinResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchJoinResponse.Size(m)
 }
-func (m *ClusterResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ClusterResponse.DiscardUnknown(m)
+func (m *BatchJoinResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchJoinResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ClusterResponse proto.InternalMessageInfo
+var xxx_messageInfo_BatchJoinResponse proto.InternalMessageInfo
 
-func (m *ClusterResponse) GetCluster() *Cluster {
+func (m *BatchJoinResponse) GetJoined() []string {
 	if m != nil {
-		return m.Cluster
+		return m.Joined
 	}
 	return nil
 }
 
-type GetRequest struct {
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *BatchJoinResponse) GetLeft() []string {
+	if m != nil {
+		return m.Left
+	}
+	return nil
 }
 
-func (m *GetRequest) Reset()         { *m = GetRequest{} }
-func (m *GetRequest) String() string { return proto.CompactTextString(m) }
-func (*GetRequest) ProtoMessage()    {}
-func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{9}
+type MembershipSpec struct {
+	Nodes                map[string]*Node `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
-func (m *GetRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
+func (m *MembershipSpec) Reset()         { *m = MembershipSpec{} }
+func (m *MembershipSpec) String() string { return proto.CompactTextString(m) }
+func (*MembershipSpec) ProtoMessage()    {}
+func (*MembershipSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{12}
 }
-func (m *GetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetRequest.Marshal(b, m, deterministic)
+
+func (m *MembershipSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MembershipSpec.Unmarshal(m, b)
 }
-func (m *GetRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetRequest.Merge(m, src)
+func (m *MembershipSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MembershipSpec.Marshal(b, m, deterministic)
 }
-func (m *GetRequest) XXX_Size() int {
-	return xxx_messageInfo_GetRequest.Size(m)
+func (m *MembershipSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MembershipSpec.Merge(m, src)
 }
-func (m *GetRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetRequest.DiscardUnknown(m)
+func (m *MembershipSpec) XXX_Size() int {
+	return xxx_messageInfo_MembershipSpec.Size(m)
+}
+func (m *MembershipSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_MembershipSpec.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetRequest proto.InternalMessageInfo
+var xxx_messageInfo_MembershipSpec proto.InternalMessageInfo
 
-func (m *GetRequest) GetKey() string {
+func (m *MembershipSpec) GetNodes() map[string]*Node {
 	if m != nil {
-		return m.Key
+		return m.Nodes
 	}
-	return ""
+	return nil
 }
 
-type GetResponse struct {
-	Value                []byte   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type MembershipSpecResponse struct {
+	Spec                 *MembershipSpec `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *GetResponse) Reset()         { *m = GetResponse{} }
-func (m *GetResponse) String() string { return proto.CompactTextString(m) }
-func (*GetResponse) ProtoMessage()    {}
-func (*GetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{10}
+func (m *MembershipSpecResponse) Reset()         { *m = MembershipSpecResponse{} }
+func (m *MembershipSpecResponse) String() string { return proto.CompactTextString(m) }
+func (*MembershipSpecResponse) ProtoMessage()    {}
+func (*MembershipSpecResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{13}
 }
 
-func (m *GetResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetResponse.Unmarshal(m, b)
+func (m *MembershipSpecResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MembershipSpecResponse.Unmarshal(m, b)
 }
-func (m *GetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetResponse.Marshal(b, m, deterministic)
+func (m *MembershipSpecResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MembershipSpecResponse.Marshal(b, m, deterministic)
 }
-func (m *GetResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetResponse.Merge(m, src)
+func (m *MembershipSpecResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MembershipSpecResponse.Merge(m, src)
 }
-func (m *GetResponse) XXX_Size() int {
-	return xxx_messageInfo_GetResponse.Size(m)
+func (m *MembershipSpecResponse) XXX_Size() int {
+	return xxx_messageInfo_MembershipSpecResponse.Size(m)
 }
-func (m *GetResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetResponse.DiscardUnknown(m)
+func (m *MembershipSpecResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MembershipSpecResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetResponse proto.InternalMessageInfo
+var xxx_messageInfo_MembershipSpecResponse proto.InternalMessageInfo
 
-func (m *GetResponse) GetValue() []byte {
+func (m *MembershipSpecResponse) GetSpec() *MembershipSpec {
 	if m != nil {
-		return m.Value
+		return m.Spec
 	}
 	return nil
 }
 
-type ScanRequest struct {
-	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type SetMembershipSpecRequest struct {
+	Spec                 *MembershipSpec `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *ScanRequest) Reset()         { *m = ScanRequest{} }
-func (m *ScanRequest) String() string { return proto.CompactTextString(m) }
-func (*ScanRequest) ProtoMessage()    {}
-func (*ScanRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{11}
+func (m *SetMembershipSpecRequest) Reset()         { *m = SetMembershipSpecRequest{} }
+func (m *SetMembershipSpecRequest) String() string { return proto.CompactTextString(m) }
+func (*SetMembershipSpecRequest) ProtoMessage()    {}
+func (*SetMembershipSpecRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{14}
 }
 
-func (m *ScanRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ScanRequest.Unmarshal(m, b)
+func (m *SetMembershipSpecRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMembershipSpecRequest.Unmarshal(m, b)
 }
-func (m *ScanRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ScanRequest.Marshal(b, m, deterministic)
+func (m *SetMembershipSpecRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMembershipSpecRequest.Marshal(b, m, deterministic)
 }
-func (m *ScanRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ScanRequest.Merge(m, src)
+func (m *SetMembershipSpecRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMembershipSpecRequest.Merge(m, src)
 }
-func (m *ScanRequest) XXX_Size() int {
-	return xxx_messageInfo_ScanRequest.Size(m)
+func (m *SetMembershipSpecRequest) XXX_Size() int {
+	return xxx_messageInfo_SetMembershipSpecRequest.Size(m)
 }
-func (m *ScanRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ScanRequest.DiscardUnknown(m)
+func (m *SetMembershipSpecRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMembershipSpecRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ScanRequest proto.InternalMessageInfo
+var xxx_messageInfo_SetMembershipSpecRequest proto.InternalMessageInfo
 
-func (m *ScanRequest) GetPrefix() string {
+func (m *SetMembershipSpecRequest) GetSpec() *MembershipSpec {
 	if m != nil {
-		return m.Prefix
+		return m.Spec
 	}
-	return ""
+	return nil
 }
 
-type ScanResponse struct {
-	Values               [][]byte `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type ReconcileAction struct {
+	Type                 ReconcileAction_Type `protobuf:"varint,1,opt,name=type,proto3,enum=kvs.ReconcileAction_Type" json:"type,omitempty"`
+	Id                   string               `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Node                 *Node                `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *ScanResponse) Reset()         { *m = ScanResponse{} }
-func (m *ScanResponse) String() string { return proto.CompactTextString(m) }
-func (*ScanResponse) ProtoMessage()    {}
-func (*ScanResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{12}
+func (m *ReconcileAction) Reset()         { *m = ReconcileAction{} }
+func (m *ReconcileAction) String() string { return proto.CompactTextString(m) }
+func (*ReconcileAction) ProtoMessage()    {}
+func (*ReconcileAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{15}
 }
 
-func (m *ScanResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ScanResponse.Unmarshal(m, b)
+func (m *ReconcileAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReconcileAction.Unmarshal(m, b)
 }
-func (m *ScanResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ScanResponse.Marshal(b, m, deterministic)
+func (m *ReconcileAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReconcileAction.Marshal(b, m, deterministic)
 }
-func (m *ScanResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ScanResponse.Merge(m, src)
+func (m *ReconcileAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReconcileAction.Merge(m, src)
 }
-func (m *ScanResponse) XXX_Size() int {
-	return xxx_messageInfo_ScanResponse.Size(m)
+func (m *ReconcileAction) XXX_Size() int {
+	return xxx_messageInfo_ReconcileAction.Size(m)
 }
-func (m *ScanResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ScanResponse.DiscardUnknown(m)
+func (m *ReconcileAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReconcileAction.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ScanResponse proto.InternalMessageInfo
+var xxx_messageInfo_ReconcileAction proto.InternalMessageInfo
 
-func (m *ScanResponse) GetValues() [][]byte {
+func (m *ReconcileAction) GetType() ReconcileAction_Type {
 	if m != nil {
-		return m.Values
+		return m.Type
+	}
+	return ReconcileAction_Unknown
+}
+
+func (m *ReconcileAction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReconcileAction) GetNode() *Node {
+	if m != nil {
+		return m.Node
 	}
 	return nil
 }
 
-type SetRequest struct {
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type DecommissionStatus struct {
+	Id                   string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Phase                DecommissionStatus_Phase `protobuf:"varint,2,opt,name=phase,proto3,enum=kvs.DecommissionStatus_Phase" json:"phase,omitempty"`
+	ConfigIndex          uint64                   `protobuf:"varint,3,opt,name=config_index,json=configIndex,proto3" json:"config_index,omitempty"`
+	UpdatedIndex         uint64                   `protobuf:"varint,4,opt,name=updated_index,json=updatedIndex,proto3" json:"updated_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
 }
 
-func (m *SetRequest) Reset()         { *m = SetRequest{} }
-func (m *SetRequest) String() string { return proto.CompactTextString(m) }
-func (*SetRequest) ProtoMessage()    {}
-func (*SetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{13}
+func (m *DecommissionStatus) Reset()         { *m = DecommissionStatus{} }
+func (m *DecommissionStatus) String() string { return proto.CompactTextString(m) }
+func (*DecommissionStatus) ProtoMessage()    {}
+func (*DecommissionStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{16}
 }
 
-func (m *SetRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetRequest.Unmarshal(m, b)
+func (m *DecommissionStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DecommissionStatus.Unmarshal(m, b)
 }
-func (m *SetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetRequest.Marshal(b, m, deterministic)
+func (m *DecommissionStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DecommissionStatus.Marshal(b, m, deterministic)
 }
-func (m *SetRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetRequest.Merge(m, src)
+func (m *DecommissionStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DecommissionStatus.Merge(m, src)
 }
-func (m *SetRequest) XXX_Size() int {
-	return xxx_messageInfo_SetRequest.Size(m)
+func (m *DecommissionStatus) XXX_Size() int {
+	return xxx_messageInfo_DecommissionStatus.Size(m)
 }
-func (m *SetRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetRequest.DiscardUnknown(m)
+func (m *DecommissionStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_DecommissionStatus.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetRequest proto.InternalMessageInfo
+var xxx_messageInfo_DecommissionStatus proto.InternalMessageInfo
 
-func (m *SetRequest) GetKey() string {
+func (m *DecommissionStatus) GetId() string {
 	if m != nil {
-		return m.Key
+		return m.Id
 	}
 	return ""
 }
 
-func (m *SetRequest) GetValue() []byte {
+func (m *DecommissionStatus) GetPhase() DecommissionStatus_Phase {
 	if m != nil {
-		return m.Value
+		return m.Phase
 	}
-	return nil
+	return DecommissionStatus_Unknown
 }
 
-type DeleteRequest struct {
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *DecommissionStatus) GetConfigIndex() uint64 {
+	if m != nil {
+		return m.ConfigIndex
+	}
+	return 0
 }
 
-func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
-func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
-func (*DeleteRequest) ProtoMessage()    {}
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{14}
+func (m *DecommissionStatus) GetUpdatedIndex() uint64 {
+	if m != nil {
+		return m.UpdatedIndex
+	}
+	return 0
 }
 
-func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
+type AnnotateRequest struct {
+	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Annotations          map[string]string `protobuf:"bytes,2,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
-func (m *DeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteRequest.Marshal(b, m, deterministic)
+
+func (m *AnnotateRequest) Reset()         { *m = AnnotateRequest{} }
+func (m *AnnotateRequest) String() string { return proto.CompactTextString(m) }
+func (*AnnotateRequest) ProtoMessage()    {}
+func (*AnnotateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{17}
 }
-func (m *DeleteRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteRequest.Merge(m, src)
+
+func (m *AnnotateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnnotateRequest.Unmarshal(m, b)
 }
-func (m *DeleteRequest) XXX_Size() int {
-	return xxx_messageInfo_DeleteRequest.Size(m)
+func (m *AnnotateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnnotateRequest.Marshal(b, m, deterministic)
 }
-func (m *DeleteRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteRequest.DiscardUnknown(m)
+func (m *AnnotateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnnotateRequest.Merge(m, src)
+}
+func (m *AnnotateRequest) XXX_Size() int {
+	return xxx_messageInfo_AnnotateRequest.Size(m)
+}
+func (m *AnnotateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnnotateRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteRequest proto.InternalMessageInfo
+var xxx_messageInfo_AnnotateRequest proto.InternalMessageInfo
 
-func (m *DeleteRequest) GetKey() string {
+func (m *AnnotateRequest) GetId() string {
 	if m != nil {
-		return m.Key
+		return m.Id
 	}
 	return ""
 }
 
-type SetMetadataRequest struct {
-	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+func (m *AnnotateRequest) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+// NodeStatus is the status of a node as last reported by the node, or by the leader if
+// the node is unreachable.
+type NodeStatus struct {
+	State                string    `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
 	Metadata             *Metadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Ready                bool      `protobuf:"varint,3,opt,name=ready,proto3" json:"ready,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
 	XXX_unrecognized     []byte    `json:"-"`
 	XXX_sizecache        int32     `json:"-"`
 }
 
-func (m *SetMetadataRequest) Reset()         { *m = SetMetadataRequest{} }
-func (m *SetMetadataRequest) String() string { return proto.CompactTextString(m) }
-func (*SetMetadataRequest) ProtoMessage()    {}
-func (*SetMetadataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{15}
+func (m *NodeStatus) Reset()         { *m = NodeStatus{} }
+func (m *NodeStatus) String() string { return proto.CompactTextString(m) }
+func (*NodeStatus) ProtoMessage()    {}
+func (*NodeStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{18}
 }
 
-func (m *SetMetadataRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetMetadataRequest.Unmarshal(m, b)
+func (m *NodeStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStatus.Unmarshal(m, b)
 }
-func (m *SetMetadataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetMetadataRequest.Marshal(b, m, deterministic)
+func (m *NodeStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStatus.Marshal(b, m, deterministic)
 }
-func (m *SetMetadataRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetMetadataRequest.Merge(m, src)
+func (m *NodeStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStatus.Merge(m, src)
 }
-func (m *SetMetadataRequest) XXX_Size() int {
-	return xxx_messageInfo_SetMetadataRequest.Size(m)
+func (m *NodeStatus) XXX_Size() int {
+	return xxx_messageInfo_NodeStatus.Size(m)
 }
-func (m *SetMetadataRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetMetadataRequest.DiscardUnknown(m)
+func (m *NodeStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStatus.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetMetadataRequest proto.InternalMessageInfo
+var xxx_messageInfo_NodeStatus proto.InternalMessageInfo
 
-func (m *SetMetadataRequest) GetId() string {
+func (m *NodeStatus) GetState() string {
 	if m != nil {
-		return m.Id
+		return m.State
 	}
 	return ""
 }
 
-func (m *SetMetadataRequest) GetMetadata() *Metadata {
+func (m *NodeStatus) GetMetadata() *Metadata {
 	if m != nil {
 		return m.Metadata
 	}
 	return nil
 }
 
-type DeleteMetadataRequest struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *NodeStatus) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
 }
 
-func (m *DeleteMetadataRequest) Reset()         { *m = DeleteMetadataRequest{} }
-func (m *DeleteMetadataRequest) String() string { return proto.CompactTextString(m) }
-func (*DeleteMetadataRequest) ProtoMessage()    {}
-func (*DeleteMetadataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{16}
+type UpdateNodeStatusRequest struct {
+	Id                   string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status               *NodeStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
-func (m *DeleteMetadataRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteMetadataRequest.Unmarshal(m, b)
+func (m *UpdateNodeStatusRequest) Reset()         { *m = UpdateNodeStatusRequest{} }
+func (m *UpdateNodeStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateNodeStatusRequest) ProtoMessage()    {}
+func (*UpdateNodeStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{19}
 }
-func (m *DeleteMetadataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteMetadataRequest.Marshal(b, m, deterministic)
+
+func (m *UpdateNodeStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateNodeStatusRequest.Unmarshal(m, b)
 }
-func (m *DeleteMetadataRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteMetadataRequest.Merge(m, src)
+func (m *UpdateNodeStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateNodeStatusRequest.Marshal(b, m, deterministic)
 }
-func (m *DeleteMetadataRequest) XXX_Size() int {
-	return xxx_messageInfo_DeleteMetadataRequest.Size(m)
+func (m *UpdateNodeStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateNodeStatusRequest.Merge(m, src)
 }
-func (m *DeleteMetadataRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteMetadataRequest.DiscardUnknown(m)
+func (m *UpdateNodeStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateNodeStatusRequest.Size(m)
+}
+func (m *UpdateNodeStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateNodeStatusRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteMetadataRequest proto.InternalMessageInfo
+var xxx_messageInfo_UpdateNodeStatusRequest proto.InternalMessageInfo
 
-func (m *DeleteMetadataRequest) GetId() string {
+func (m *UpdateNodeStatusRequest) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-type Event struct {
-	Type                 Event_Type `protobuf:"varint,1,opt,name=type,proto3,enum=kvs.Event_Type" json:"type,omitempty"`
-	Data                 *any.Any   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+func (m *UpdateNodeStatusRequest) GetStatus() *NodeStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
 }
 
-func (m *Event) Reset()         { *m = Event{} }
-func (m *Event) String() string { return proto.CompactTextString(m) }
-func (*Event) ProtoMessage()    {}
-func (*Event) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{17}
+// APIKey is a credential allowed to read, and to write if write is set, the keys with the
+// prefix until it expires. Only the hash of its secret is recorded.
+type APIKey struct {
+	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Prefix               string               `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Write                bool                 `protobuf:"varint,3,opt,name=write,proto3" json:"write,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Hash                 []byte               `protobuf:"bytes,6,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *APIKey) Reset()         { *m = APIKey{} }
+func (m *APIKey) String() string { return proto.CompactTextString(m) }
+func (*APIKey) ProtoMessage()    {}
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{20}
 }
 
-func (m *Event) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Event.Unmarshal(m, b)
+func (m *APIKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APIKey.Unmarshal(m, b)
 }
-func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+func (m *APIKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APIKey.Marshal(b, m, deterministic)
 }
-func (m *Event) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Event.Merge(m, src)
+func (m *APIKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIKey.Merge(m, src)
 }
-func (m *Event) XXX_Size() int {
-	return xxx_messageInfo_Event.Size(m)
+func (m *APIKey) XXX_Size() int {
+	return xxx_messageInfo_APIKey.Size(m)
 }
-func (m *Event) XXX_DiscardUnknown() {
-	xxx_messageInfo_Event.DiscardUnknown(m)
+func (m *APIKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIKey.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Event proto.InternalMessageInfo
+var xxx_messageInfo_APIKey proto.InternalMessageInfo
 
-func (m *Event) GetType() Event_Type {
+func (m *APIKey) GetId() string {
 	if m != nil {
-		return m.Type
+		return m.Id
 	}
-	return Event_Unknown
+	return ""
 }
 
-func (m *Event) GetData() *any.Any {
+func (m *APIKey) GetPrefix() string {
 	if m != nil {
-		return m.Data
+		return m.Prefix
 	}
-	return nil
-}
-
-type WatchResponse struct {
-	Event                *Event   `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	return ""
 }
 
-func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
-func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
-func (*WatchResponse) ProtoMessage()    {}
-func (*WatchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{18}
+func (m *APIKey) GetWrite() bool {
+	if m != nil {
+		return m.Write
+	}
+	return false
 }
 
-func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WatchResponse.Unmarshal(m, b)
+func (m *APIKey) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
 }
-func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+
+func (m *APIKey) GetExpiresAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
 }
-func (m *WatchResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WatchResponse.Merge(m, src)
+
+func (m *APIKey) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
 }
-func (m *WatchResponse) XXX_Size() int {
-	return xxx_messageInfo_WatchResponse.Size(m)
+
+type CreateAPIKeyRequest struct {
+	Prefix               string             `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Write                bool               `protobuf:"varint,2,opt,name=write,proto3" json:"write,omitempty"`
+	Ttl                  *duration.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
-func (m *WatchResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+
+func (m *CreateAPIKeyRequest) Reset()         { *m = CreateAPIKeyRequest{} }
+func (m *CreateAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateAPIKeyRequest) ProtoMessage()    {}
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{21}
 }
 
-var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+func (m *CreateAPIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAPIKeyRequest.Unmarshal(m, b)
+}
+func (m *CreateAPIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAPIKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateAPIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAPIKeyRequest.Merge(m, src)
+}
+func (m *CreateAPIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateAPIKeyRequest.Size(m)
+}
+func (m *CreateAPIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAPIKeyRequest.DiscardUnknown(m)
+}
 
-func (m *WatchResponse) GetEvent() *Event {
+var xxx_messageInfo_CreateAPIKeyRequest proto.InternalMessageInfo
+
+func (m *CreateAPIKeyRequest) GetPrefix() string {
 	if m != nil {
-		return m.Event
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *CreateAPIKeyRequest) GetWrite() bool {
+	if m != nil {
+		return m.Write
+	}
+	return false
+}
+
+func (m *CreateAPIKeyRequest) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
 	}
 	return nil
 }
 
-type MetricsResponse struct {
-	Metrics              []byte   `protobuf:"bytes,1,opt,name=metrics,proto3" json:"metrics,omitempty"`
+type CreateAPIKeyResponse struct {
+	ApiKey *APIKey `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// the key to present, it can not be retrieved afterwards
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *MetricsResponse) Reset()         { *m = MetricsResponse{} }
-func (m *MetricsResponse) String() string { return proto.CompactTextString(m) }
-func (*MetricsResponse) ProtoMessage()    {}
-func (*MetricsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{19}
+func (m *CreateAPIKeyResponse) Reset()         { *m = CreateAPIKeyResponse{} }
+func (m *CreateAPIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateAPIKeyResponse) ProtoMessage()    {}
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{22}
 }
 
-func (m *MetricsResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_MetricsResponse.Unmarshal(m, b)
+func (m *CreateAPIKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAPIKeyResponse.Unmarshal(m, b)
 }
-func (m *MetricsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_MetricsResponse.Marshal(b, m, deterministic)
+func (m *CreateAPIKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAPIKeyResponse.Marshal(b, m, deterministic)
 }
-func (m *MetricsResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MetricsResponse.Merge(m, src)
+func (m *CreateAPIKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAPIKeyResponse.Merge(m, src)
 }
-func (m *MetricsResponse) XXX_Size() int {
-	return xxx_messageInfo_MetricsResponse.Size(m)
+func (m *CreateAPIKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateAPIKeyResponse.Size(m)
 }
-func (m *MetricsResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_MetricsResponse.DiscardUnknown(m)
+func (m *CreateAPIKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAPIKeyResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_MetricsResponse proto.InternalMessageInfo
+var xxx_messageInfo_CreateAPIKeyResponse proto.InternalMessageInfo
 
-func (m *MetricsResponse) GetMetrics() []byte {
+func (m *CreateAPIKeyResponse) GetApiKey() *APIKey {
 	if m != nil {
-		return m.Metrics
+		return m.ApiKey
 	}
 	return nil
 }
 
-type KeyValuePair struct {
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+func (m *CreateAPIKeyResponse) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type RevokeAPIKeyRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *KeyValuePair) Reset()         { *m = KeyValuePair{} }
-func (m *KeyValuePair) String() string { return proto.CompactTextString(m) }
-func (*KeyValuePair) ProtoMessage()    {}
-func (*KeyValuePair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_431078ad7b21f851, []int{20}
+func (m *RevokeAPIKeyRequest) Reset()         { *m = RevokeAPIKeyRequest{} }
+func (m *RevokeAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPIKeyRequest) ProtoMessage()    {}
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{23}
 }
 
-func (m *KeyValuePair) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_KeyValuePair.Unmarshal(m, b)
+func (m *RevokeAPIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Unmarshal(m, b)
 }
-func (m *KeyValuePair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_KeyValuePair.Marshal(b, m, deterministic)
+func (m *RevokeAPIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Marshal(b, m, deterministic)
 }
-func (m *KeyValuePair) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_KeyValuePair.Merge(m, src)
+func (m *RevokeAPIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeAPIKeyRequest.Merge(m, src)
 }
-func (m *KeyValuePair) XXX_Size() int {
-	return xxx_messageInfo_KeyValuePair.Size(m)
+func (m *RevokeAPIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Size(m)
 }
-func (m *KeyValuePair) XXX_DiscardUnknown() {
-	xxx_messageInfo_KeyValuePair.DiscardUnknown(m)
+func (m *RevokeAPIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeAPIKeyRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_KeyValuePair proto.InternalMessageInfo
+var xxx_messageInfo_RevokeAPIKeyRequest proto.InternalMessageInfo
 
-func (m *KeyValuePair) GetKey() string {
+func (m *RevokeAPIKeyRequest) GetId() string {
 	if m != nil {
-		return m.Key
+		return m.Id
 	}
 	return ""
 }
 
-func (m *KeyValuePair) GetValue() []byte {
+type APIKeysResponse struct {
+	ApiKeys              []*APIKey `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *APIKeysResponse) Reset()         { *m = APIKeysResponse{} }
+func (m *APIKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*APIKeysResponse) ProtoMessage()    {}
+func (*APIKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{24}
+}
+
+func (m *APIKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APIKeysResponse.Unmarshal(m, b)
+}
+func (m *APIKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APIKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *APIKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIKeysResponse.Merge(m, src)
+}
+func (m *APIKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_APIKeysResponse.Size(m)
+}
+func (m *APIKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIKeysResponse proto.InternalMessageInfo
+
+func (m *APIKeysResponse) GetApiKeys() []*APIKey {
 	if m != nil {
-		return m.Value
+		return m.ApiKeys
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("kvs.Event_Type", Event_Type_name, Event_Type_value)
-	proto.RegisterType((*LivenessCheckResponse)(nil), "kvs.LivenessCheckResponse")
-	proto.RegisterType((*ReadinessCheckResponse)(nil), "kvs.ReadinessCheckResponse")
-	proto.RegisterType((*Metadata)(nil), "kvs.Metadata")
-	proto.RegisterType((*Node)(nil), "kvs.Node")
-	proto.RegisterType((*Cluster)(nil), "kvs.Cluster")
-	proto.RegisterMapType((map[string]*Node)(nil), "kvs.Cluster.NodesEntry")
-	proto.RegisterType((*JoinRequest)(nil), "kvs.JoinRequest")
-	proto.RegisterType((*LeaveRequest)(nil), "kvs.LeaveRequest")
-	proto.RegisterType((*NodeResponse)(nil), "kvs.NodeResponse")
-	proto.RegisterType((*ClusterResponse)(nil), "kvs.ClusterResponse")
-	proto.RegisterType((*GetRequest)(nil), "kvs.GetRequest")
-	proto.RegisterType((*GetResponse)(nil), "kvs.GetResponse")
-	proto.RegisterType((*ScanRequest)(nil), "kvs.ScanRequest")
-	proto.RegisterType((*ScanResponse)(nil), "kvs.ScanResponse")
-	proto.RegisterType((*SetRequest)(nil), "kvs.SetRequest")
-	proto.RegisterType((*DeleteRequest)(nil), "kvs.DeleteRequest")
-	proto.RegisterType((*SetMetadataRequest)(nil), "kvs.SetMetadataRequest")
-	proto.RegisterType((*DeleteMetadataRequest)(nil), "kvs.DeleteMetadataRequest")
-	proto.RegisterType((*Event)(nil), "kvs.Event")
-	proto.RegisterType((*WatchResponse)(nil), "kvs.WatchResponse")
-	proto.RegisterType((*MetricsResponse)(nil), "kvs.MetricsResponse")
-	proto.RegisterType((*KeyValuePair)(nil), "kvs.KeyValuePair")
+type DecommissionStatusRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() {
-	proto.RegisterFile("protobuf/kvs.proto", fileDescriptor_431078ad7b21f851)
+func (m *DecommissionStatusRequest) Reset()         { *m = DecommissionStatusRequest{} }
+func (m *DecommissionStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*DecommissionStatusRequest) ProtoMessage()    {}
+func (*DecommissionStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{25}
 }
 
-var fileDescriptor_431078ad7b21f851 = []byte{
-	// 1016 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xdd, 0x6e, 0xdb, 0x46,
-	0x13, 0x8d, 0xfe, 0x6c, 0x79, 0x24, 0xdb, 0xcc, 0x58, 0xd6, 0xe7, 0xd0, 0xf9, 0x9c, 0x78, 0x83,
-	0xa6, 0xae, 0x5b, 0x8b, 0x8d, 0x5b, 0xf4, 0xc7, 0x68, 0x2e, 0x52, 0xd7, 0x08, 0xda, 0x38, 0x8d,
-	0x41, 0xb5, 0x29, 0xd0, 0x1b, 0x63, 0x4d, 0x8e, 0x65, 0x56, 0x32, 0xc9, 0x92, 0x2b, 0xa5, 0x42,
-	0x90, 0x9b, 0x02, 0x7d, 0x82, 0xa2, 0x4f, 0xd6, 0x57, 0xe8, 0x83, 0x14, 0xfb, 0x43, 0x51, 0xb2,
-	0xc4, 0x26, 0x57, 0xe2, 0xee, 0x9c, 0x3d, 0x73, 0x76, 0x66, 0xf6, 0x40, 0x80, 0x71, 0x12, 0x89,
-	0xe8, 0x62, 0x78, 0xe9, 0xf4, 0x47, 0x69, 0x47, 0x2d, 0xb0, 0xd2, 0x1f, 0xa5, 0xf6, 0x9d, 0x5e,
-	0x14, 0xf5, 0x06, 0xe4, 0x4c, 0xe2, 0x3c, 0x1c, 0xeb, 0xb8, 0xbd, 0x7d, 0x33, 0x44, 0xd7, 0xb1,
-	0xc8, 0x82, 0x77, 0x4d, 0x90, 0xc7, 0x81, 0xc3, 0xc3, 0x30, 0x12, 0x5c, 0x04, 0x51, 0x68, 0xa8,
-	0xed, 0x8f, 0xd4, 0x8f, 0x77, 0xd0, 0xa3, 0xf0, 0x20, 0x7d, 0xc5, 0x7b, 0x3d, 0x4a, 0x9c, 0x28,
-	0x56, 0x88, 0x79, 0x34, 0x3b, 0x80, 0xcd, 0xd3, 0x60, 0x44, 0x21, 0xa5, 0xe9, 0xf1, 0x15, 0x79,
-	0x7d, 0x97, 0xd2, 0x38, 0x0a, 0x53, 0xc2, 0x16, 0xd4, 0xf8, 0x20, 0x18, 0xd1, 0x56, 0xe9, 0x7e,
-	0x69, 0xaf, 0xee, 0xea, 0x05, 0xeb, 0x40, 0xdb, 0x25, 0xee, 0x07, 0x0b, 0xf1, 0x09, 0x71, 0x7f,
-	0x9c, 0xe1, 0xd5, 0x82, 0x9d, 0x41, 0xfd, 0x39, 0x09, 0xee, 0x73, 0xc1, 0x71, 0x17, 0x9a, 0xbd,
-	0x24, 0xf6, 0xce, 0xb9, 0xef, 0x27, 0x94, 0xa6, 0x0a, 0xb8, 0xe2, 0x36, 0xe4, 0xde, 0x13, 0xbd,
-	0x25, 0x21, 0x57, 0x42, 0xc4, 0x13, 0x48, 0x59, 0x43, 0xe4, 0x9e, 0x81, 0xb0, 0x5f, 0xa0, 0xfa,
-	0x7d, 0xe4, 0x93, 0x84, 0x26, 0xfc, 0x52, 0xdc, 0x64, 0x93, 0x7b, 0x19, 0xdb, 0x07, 0x50, 0xbf,
-	0x36, 0xc9, 0x15, 0x53, 0xe3, 0x70, 0xb5, 0x23, 0x5b, 0x90, 0x29, 0x72, 0x27, 0x61, 0xa9, 0x3e,
-	0x15, 0x5c, 0xd0, 0x56, 0x45, 0xd1, 0xe8, 0x05, 0xfb, 0xab, 0x04, 0xcb, 0xc7, 0x83, 0x61, 0x2a,
-	0x28, 0xc1, 0x03, 0xa8, 0x85, 0x91, 0x4f, 0x32, 0x51, 0x65, 0xaf, 0x71, 0xf8, 0x3f, 0xc5, 0x64,
-	0x82, 0x1d, 0xa9, 0x28, 0x3d, 0x09, 0x45, 0x32, 0x76, 0x35, 0x0a, 0xdb, 0xb0, 0x34, 0x20, 0xee,
-	0x53, 0x62, 0xee, 0x60, 0x56, 0xf6, 0x31, 0x40, 0x0e, 0x46, 0x0b, 0x2a, 0x7d, 0x1a, 0x1b, 0xed,
-	0xf2, 0x13, 0xef, 0x41, 0x6d, 0xc4, 0x07, 0x43, 0x32, 0x82, 0x57, 0x54, 0x1a, 0x79, 0xc2, 0xd5,
-	0xfb, 0x47, 0xe5, 0x2f, 0x4a, 0xec, 0x2b, 0x68, 0x7c, 0x17, 0x05, 0xa1, 0x4b, 0xbf, 0x0e, 0x29,
-	0x15, 0xb8, 0x06, 0xe5, 0xc0, 0x37, 0x24, 0xe5, 0xc0, 0xc7, 0xff, 0x43, 0x55, 0x8a, 0x98, 0xa7,
-	0x50, 0xdb, 0x6c, 0x07, 0x9a, 0xa7, 0xc4, 0x47, 0x54, 0x70, 0x9c, 0x1d, 0x40, 0x53, 0xa1, 0xb3,
-	0xce, 0x66, 0x74, 0xa5, 0xc5, 0x74, 0x5f, 0xc2, 0xba, 0x29, 0xc3, 0xe4, 0xc4, 0x43, 0x58, 0xf6,
-	0xf4, 0x96, 0x39, 0xd4, 0x9c, 0xae, 0x96, 0x9b, 0x05, 0xd9, 0x0e, 0xc0, 0x53, 0x12, 0x99, 0x8e,
-	0xb9, 0x62, 0xb0, 0x07, 0xd0, 0x50, 0xf1, 0x7c, 0xc4, 0x74, 0x6d, 0x24, 0xa4, 0x69, 0x0a, 0xc2,
-	0xde, 0x83, 0x46, 0xd7, 0xe3, 0x93, 0x62, 0xb4, 0x61, 0x29, 0x4e, 0xe8, 0x32, 0xf8, 0xcd, 0x10,
-	0x99, 0x15, 0x7b, 0x08, 0x4d, 0x0d, 0x33, 0x64, 0x6d, 0x58, 0x52, 0xe7, 0x75, 0x43, 0x9b, 0xae,
-	0x59, 0xb1, 0x4f, 0x01, 0xba, 0xff, 0xa1, 0x29, 0x17, 0x51, 0x9e, 0x16, 0xb1, 0x0b, 0xab, 0xdf,
-	0xd0, 0x80, 0x04, 0x15, 0x5f, 0xe6, 0x05, 0x60, 0x97, 0xc4, 0x64, 0xf6, 0x0a, 0x7a, 0xf7, 0xee,
-	0x33, 0xcb, 0xde, 0x87, 0x4d, 0x9d, 0xf3, 0x2d, 0x9c, 0x72, 0x8c, 0x6b, 0x27, 0x23, 0x0a, 0x05,
-	0x3e, 0x80, 0xaa, 0x18, 0xc7, 0xba, 0x80, 0x6b, 0x87, 0xeb, 0x8a, 0x59, 0x45, 0x3a, 0x3f, 0x8c,
-	0x63, 0x72, 0x55, 0x10, 0xf7, 0xa0, 0x3a, 0x95, 0xbe, 0xd5, 0xd1, 0x6e, 0xd3, 0xc9, 0xac, 0xa8,
-	0xf3, 0x24, 0x1c, 0xbb, 0x0a, 0xc1, 0x1e, 0x43, 0x55, 0x9e, 0xc3, 0x06, 0x2c, 0xff, 0x18, 0xf6,
-	0xc3, 0xe8, 0x55, 0x68, 0xdd, 0xc2, 0x3a, 0x54, 0xe5, 0x70, 0x5a, 0x25, 0x5c, 0x81, 0x9a, 0x1a,
-	0x34, 0xab, 0x8c, 0xcb, 0x50, 0xe9, 0x92, 0xb0, 0x2a, 0x08, 0xb0, 0xa4, 0x45, 0x5b, 0x55, 0xf6,
-	0x08, 0x56, 0x7f, 0xe2, 0xc2, 0xbb, 0x9a, 0xf4, 0xe4, 0x3e, 0xd4, 0x48, 0xaa, 0x31, 0x53, 0x03,
-	0xb9, 0x3e, 0x57, 0x07, 0xd8, 0x87, 0xb0, 0xfe, 0x9c, 0x44, 0x12, 0x78, 0xe9, 0xe4, 0xd0, 0x16,
-	0x2c, 0x5f, 0xeb, 0x2d, 0x33, 0x17, 0xd9, 0x92, 0x7d, 0x06, 0xcd, 0x67, 0x34, 0x7e, 0x29, 0x1b,
-	0x74, 0xc6, 0x83, 0xe4, 0x5d, 0x9b, 0x79, 0xf8, 0x47, 0x1d, 0x2a, 0xcf, 0x5e, 0x76, 0xf1, 0x1c,
-	0x56, 0x67, 0xbc, 0x11, 0xdb, 0x73, 0xb5, 0x38, 0x91, 0xb6, 0x6c, 0xdb, 0x4a, 0xe8, 0x42, 0x1f,
-	0x65, 0xf6, 0xef, 0x7f, 0xff, 0xf3, 0x67, 0xb9, 0x85, 0xe8, 0x8c, 0x1e, 0x39, 0x03, 0x03, 0x39,
-	0xf7, 0x14, 0xdf, 0x05, 0xac, 0xcd, 0xba, 0x69, 0x61, 0x86, 0x6d, 0x95, 0x61, 0xb1, 0xf5, 0xb2,
-	0x6d, 0x95, 0x62, 0x13, 0x37, 0x64, 0x8a, 0x24, 0xc3, 0x98, 0x1c, 0xc7, 0xc6, 0x2f, 0x8b, 0x98,
-	0x6f, 0xe7, 0xef, 0x39, 0xe3, 0xb3, 0x14, 0x1f, 0x60, 0x5d, 0xf2, 0xc9, 0x37, 0x8e, 0x67, 0xba,
-	0xa7, 0x68, 0x29, 0xf0, 0x94, 0xf7, 0xd8, 0x05, 0xb4, 0x6c, 0x47, 0x71, 0x6c, 0xd9, 0x96, 0xe4,
-	0x30, 0xef, 0xdd, 0x79, 0x1d, 0xf8, 0x6f, 0x8e, 0x94, 0x6b, 0xe0, 0x69, 0xee, 0xac, 0x45, 0xca,
-	0x5a, 0x33, 0xa6, 0x91, 0x89, 0xdb, 0x50, 0xc4, 0xab, 0xd8, 0x98, 0x22, 0xc6, 0x53, 0x33, 0x69,
-	0xa8, 0x6f, 0x33, 0x6d, 0x6f, 0x85, 0x0a, 0xb7, 0x14, 0x11, 0xee, 0xcf, 0x29, 0xc4, 0x33, 0xa8,
-	0x77, 0x43, 0x1e, 0xa7, 0x57, 0x91, 0x28, 0x14, 0x57, 0xc4, 0xda, 0x52, 0xac, 0x6b, 0xd8, 0x94,
-	0xac, 0x69, 0xc6, 0x72, 0x0c, 0x95, 0xa7, 0x24, 0x50, 0x3f, 0xb8, 0xdc, 0xf2, 0x6c, 0x2b, 0xdf,
-	0x30, 0xd7, 0xbb, 0xa3, 0xce, 0x6f, 0xe0, 0x6d, 0x79, 0x5e, 0x3e, 0x32, 0xe7, 0x75, 0x9f, 0xc6,
-	0x8f, 0xf7, 0xf7, 0xdf, 0xe0, 0xb7, 0x50, 0x95, 0x0e, 0x66, 0x9a, 0x30, 0xe5, 0x79, 0xa6, 0x87,
-	0xd3, 0xf6, 0xc6, 0xee, 0x2a, 0x9e, 0x36, 0xb6, 0x72, 0x1e, 0x6d, 0x84, 0x8a, 0xea, 0x54, 0x3d,
-	0x47, 0xa3, 0x27, 0xb7, 0xbb, 0xc2, 0x5b, 0x19, 0x36, 0x7b, 0x5e, 0xd5, 0x51, 0x69, 0x1f, 0x5f,
-	0x64, 0x6f, 0x1a, 0x51, 0x11, 0xce, 0x38, 0x61, 0x21, 0xa7, 0xb9, 0xe9, 0xfe, 0x82, 0x9b, 0x7e,
-	0x0e, 0x35, 0x65, 0x0c, 0x85, 0xd5, 0xd7, 0x79, 0x66, 0xcc, 0x83, 0xdd, 0xfa, 0xb8, 0x24, 0xa7,
-	0xca, 0xd8, 0xc3, 0x5b, 0xa6, 0xea, 0x86, 0x89, 0xcc, 0x4e, 0x95, 0xf1, 0x8f, 0xaf, 0x77, 0x7f,
-	0xbe, 0xd7, 0x0b, 0xc4, 0xd5, 0xf0, 0xa2, 0xe3, 0x45, 0xd7, 0xce, 0x75, 0x94, 0x0e, 0xfb, 0xdc,
-	0xf1, 0x48, 0xe4, 0x7f, 0xcb, 0x2e, 0x96, 0xd4, 0xd7, 0x27, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff,
-	0x2e, 0xf6, 0xd7, 0x64, 0xe4, 0x09, 0x00, 0x00,
+func (m *DecommissionStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DecommissionStatusRequest.Unmarshal(m, b)
+}
+func (m *DecommissionStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DecommissionStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *DecommissionStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DecommissionStatusRequest.Merge(m, src)
+}
+func (m *DecommissionStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_DecommissionStatusRequest.Size(m)
+}
+func (m *DecommissionStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DecommissionStatusRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+var xxx_messageInfo_DecommissionStatusRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func (m *DecommissionStatusRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
 
-// KVSClient is the client API for KVS service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type KVSClient interface {
-	LivenessCheck(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LivenessCheckResponse, error)
-	ReadinessCheck(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ReadinessCheckResponse, error)
-	Node(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*NodeResponse, error)
-	Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	Cluster(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ClusterResponse, error)
-	Leave(ctx context.Context, in *LeaveRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	Snapshot(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
-	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
-	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error)
-	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	Watch(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (KVS_WatchClient, error)
-	Metrics(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MetricsResponse, error)
+type DecommissionStatusResponse struct {
+	Status               *DecommissionStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
-type kVSClient struct {
-	cc grpc.ClientConnInterface
+func (m *DecommissionStatusResponse) Reset()         { *m = DecommissionStatusResponse{} }
+func (m *DecommissionStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*DecommissionStatusResponse) ProtoMessage()    {}
+func (*DecommissionStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{26}
 }
 
-func NewKVSClient(cc grpc.ClientConnInterface) KVSClient {
-	return &kVSClient{cc}
+func (m *DecommissionStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DecommissionStatusResponse.Unmarshal(m, b)
+}
+func (m *DecommissionStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DecommissionStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *DecommissionStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DecommissionStatusResponse.Merge(m, src)
+}
+func (m *DecommissionStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_DecommissionStatusResponse.Size(m)
+}
+func (m *DecommissionStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DecommissionStatusResponse.DiscardUnknown(m)
 }
 
-func (c *kVSClient) LivenessCheck(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LivenessCheckResponse, error) {
-	out := new(LivenessCheckResponse)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/LivenessCheck", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_DecommissionStatusResponse proto.InternalMessageInfo
+
+func (m *DecommissionStatusResponse) GetStatus() *DecommissionStatus {
+	if m != nil {
+		return m.Status
 	}
-	return out, nil
+	return nil
 }
 
-func (c *kVSClient) ReadinessCheck(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ReadinessCheckResponse, error) {
-	out := new(ReadinessCheckResponse)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/ReadinessCheck", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type NodeResponse struct {
+	Node                 *Node                `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Version              string               `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	DiskFreeBytes        uint64               `protobuf:"varint,4,opt,name=disk_free_bytes,json=diskFreeBytes,proto3" json:"disk_free_bytes,omitempty"`
+	DiskTotalBytes       uint64               `protobuf:"varint,5,opt,name=disk_total_bytes,json=diskTotalBytes,proto3" json:"disk_total_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (c *kVSClient) Node(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*NodeResponse, error) {
-	out := new(NodeResponse)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Node", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *NodeResponse) Reset()         { *m = NodeResponse{} }
+func (m *NodeResponse) String() string { return proto.CompactTextString(m) }
+func (*NodeResponse) ProtoMessage()    {}
+func (*NodeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{27}
 }
 
-func (c *kVSClient) Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Join", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *NodeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeResponse.Unmarshal(m, b)
+}
+func (m *NodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeResponse.Marshal(b, m, deterministic)
+}
+func (m *NodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeResponse.Merge(m, src)
+}
+func (m *NodeResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeResponse.Size(m)
+}
+func (m *NodeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeResponse.DiscardUnknown(m)
 }
 
-func (c *kVSClient) Cluster(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ClusterResponse, error) {
-	out := new(ClusterResponse)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Cluster", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_NodeResponse proto.InternalMessageInfo
+
+func (m *NodeResponse) GetNode() *Node {
+	if m != nil {
+		return m.Node
 	}
-	return out, nil
+	return nil
 }
 
-func (c *kVSClient) Leave(ctx context.Context, in *LeaveRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Leave", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *NodeResponse) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
 	}
-	return out, nil
+	return nil
 }
 
-func (c *kVSClient) Snapshot(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+func (m *NodeResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *NodeResponse) GetDiskFreeBytes() uint64 {
+	if m != nil {
+		return m.DiskFreeBytes
+	}
+	return 0
+}
+
+func (m *NodeResponse) GetDiskTotalBytes() uint64 {
+	if m != nil {
+		return m.DiskTotalBytes
+	}
+	return 0
+}
+
+type ClusterResponse struct {
+	Cluster              *Cluster `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClusterResponse) Reset()         { *m = ClusterResponse{} }
+func (m *ClusterResponse) String() string { return proto.CompactTextString(m) }
+func (*ClusterResponse) ProtoMessage()    {}
+func (*ClusterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{28}
+}
+
+func (m *ClusterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterResponse.Unmarshal(m, b)
+}
+func (m *ClusterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterResponse.Marshal(b, m, deterministic)
+}
+func (m *ClusterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterResponse.Merge(m, src)
+}
+func (m *ClusterResponse) XXX_Size() int {
+	return xxx_messageInfo_ClusterResponse.Size(m)
+}
+func (m *ClusterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterResponse proto.InternalMessageInfo
+
+func (m *ClusterResponse) GetCluster() *Cluster {
+	if m != nil {
+		return m.Cluster
+	}
+	return nil
+}
+
+type HashResponse struct {
+	AppliedIndex         uint64   `protobuf:"varint,1,opt,name=applied_index,json=appliedIndex,proto3" json:"applied_index,omitempty"`
+	Hash                 string   `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HashResponse) Reset()         { *m = HashResponse{} }
+func (m *HashResponse) String() string { return proto.CompactTextString(m) }
+func (*HashResponse) ProtoMessage()    {}
+func (*HashResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{29}
+}
+
+func (m *HashResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HashResponse.Unmarshal(m, b)
+}
+func (m *HashResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HashResponse.Marshal(b, m, deterministic)
+}
+func (m *HashResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HashResponse.Merge(m, src)
+}
+func (m *HashResponse) XXX_Size() int {
+	return xxx_messageInfo_HashResponse.Size(m)
+}
+func (m *HashResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HashResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HashResponse proto.InternalMessageInfo
+
+func (m *HashResponse) GetAppliedIndex() uint64 {
+	if m != nil {
+		return m.AppliedIndex
+	}
+	return 0
+}
+
+func (m *HashResponse) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+type StorageLevelsRequest struct {
+	// also list the tables of each level
+	Tables bool `protobuf:"varint,1,opt,name=tables,proto3" json:"tables,omitempty"`
+	// count the live keys to estimate the share of stale data, which iterates over all the keys
+	CountLiveKeys        bool     `protobuf:"varint,2,opt,name=count_live_keys,json=countLiveKeys,proto3" json:"count_live_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageLevelsRequest) Reset()         { *m = StorageLevelsRequest{} }
+func (m *StorageLevelsRequest) String() string { return proto.CompactTextString(m) }
+func (*StorageLevelsRequest) ProtoMessage()    {}
+func (*StorageLevelsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{30}
+}
+
+func (m *StorageLevelsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageLevelsRequest.Unmarshal(m, b)
+}
+func (m *StorageLevelsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageLevelsRequest.Marshal(b, m, deterministic)
+}
+func (m *StorageLevelsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageLevelsRequest.Merge(m, src)
+}
+func (m *StorageLevelsRequest) XXX_Size() int {
+	return xxx_messageInfo_StorageLevelsRequest.Size(m)
+}
+func (m *StorageLevelsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageLevelsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageLevelsRequest proto.InternalMessageInfo
+
+func (m *StorageLevelsRequest) GetTables() bool {
+	if m != nil {
+		return m.Tables
+	}
+	return false
+}
+
+func (m *StorageLevelsRequest) GetCountLiveKeys() bool {
+	if m != nil {
+		return m.CountLiveKeys
+	}
+	return false
+}
+
+type StorageTable struct {
+	Id    uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Level int32  `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	// the smallest and the largest key of the table
+	Left  string `protobuf:"bytes,3,opt,name=left,proto3" json:"left,omitempty"`
+	Right string `protobuf:"bytes,4,opt,name=right,proto3" json:"right,omitempty"`
+	// the entries of the table, including the old versions and the deletions of the keys
+	Keys                 uint64   `protobuf:"varint,5,opt,name=keys,proto3" json:"keys,omitempty"`
+	Size                 int64    `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageTable) Reset()         { *m = StorageTable{} }
+func (m *StorageTable) String() string { return proto.CompactTextString(m) }
+func (*StorageTable) ProtoMessage()    {}
+func (*StorageTable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{31}
+}
+
+func (m *StorageTable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageTable.Unmarshal(m, b)
+}
+func (m *StorageTable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageTable.Marshal(b, m, deterministic)
+}
+func (m *StorageTable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageTable.Merge(m, src)
+}
+func (m *StorageTable) XXX_Size() int {
+	return xxx_messageInfo_StorageTable.Size(m)
+}
+func (m *StorageTable) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageTable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageTable proto.InternalMessageInfo
+
+func (m *StorageTable) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *StorageTable) GetLevel() int32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *StorageTable) GetLeft() string {
+	if m != nil {
+		return m.Left
+	}
+	return ""
+}
+
+func (m *StorageTable) GetRight() string {
+	if m != nil {
+		return m.Right
+	}
+	return ""
+}
+
+func (m *StorageTable) GetKeys() uint64 {
+	if m != nil {
+		return m.Keys
+	}
+	return 0
+}
+
+func (m *StorageTable) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type StorageLevel struct {
+	Level                int32    `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	Tables               int32    `protobuf:"varint,2,opt,name=tables,proto3" json:"tables,omitempty"`
+	Keys                 uint64   `protobuf:"varint,3,opt,name=keys,proto3" json:"keys,omitempty"`
+	Size                 int64    `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageLevel) Reset()         { *m = StorageLevel{} }
+func (m *StorageLevel) String() string { return proto.CompactTextString(m) }
+func (*StorageLevel) ProtoMessage()    {}
+func (*StorageLevel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{32}
+}
+
+func (m *StorageLevel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageLevel.Unmarshal(m, b)
+}
+func (m *StorageLevel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageLevel.Marshal(b, m, deterministic)
+}
+func (m *StorageLevel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageLevel.Merge(m, src)
+}
+func (m *StorageLevel) XXX_Size() int {
+	return xxx_messageInfo_StorageLevel.Size(m)
+}
+func (m *StorageLevel) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageLevel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageLevel proto.InternalMessageInfo
+
+func (m *StorageLevel) GetLevel() int32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *StorageLevel) GetTables() int32 {
+	if m != nil {
+		return m.Tables
+	}
+	return 0
+}
+
+func (m *StorageLevel) GetKeys() uint64 {
+	if m != nil {
+		return m.Keys
+	}
+	return 0
+}
+
+func (m *StorageLevel) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type StorageLevelsResponse struct {
+	Levels   []*StorageLevel `protobuf:"bytes,1,rep,name=levels,proto3" json:"levels,omitempty"`
+	Tables   []*StorageTable `protobuf:"bytes,2,rep,name=tables,proto3" json:"tables,omitempty"`
+	LsmSize  int64           `protobuf:"varint,3,opt,name=lsm_size,json=lsmSize,proto3" json:"lsm_size,omitempty"`
+	VlogSize int64           `protobuf:"varint,4,opt,name=vlog_size,json=vlogSize,proto3" json:"vlog_size,omitempty"`
+	// only set if the live keys were counted
+	LiveKeys uint64 `protobuf:"varint,5,opt,name=live_keys,json=liveKeys,proto3" json:"live_keys,omitempty"`
+	// the share of the entries of the tables that are old versions or deletions
+	StaleRatio           float64  `protobuf:"fixed64,6,opt,name=stale_ratio,json=staleRatio,proto3" json:"stale_ratio,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageLevelsResponse) Reset()         { *m = StorageLevelsResponse{} }
+func (m *StorageLevelsResponse) String() string { return proto.CompactTextString(m) }
+func (*StorageLevelsResponse) ProtoMessage()    {}
+func (*StorageLevelsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{33}
+}
+
+func (m *StorageLevelsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageLevelsResponse.Unmarshal(m, b)
+}
+func (m *StorageLevelsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageLevelsResponse.Marshal(b, m, deterministic)
+}
+func (m *StorageLevelsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageLevelsResponse.Merge(m, src)
+}
+func (m *StorageLevelsResponse) XXX_Size() int {
+	return xxx_messageInfo_StorageLevelsResponse.Size(m)
+}
+func (m *StorageLevelsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageLevelsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageLevelsResponse proto.InternalMessageInfo
+
+func (m *StorageLevelsResponse) GetLevels() []*StorageLevel {
+	if m != nil {
+		return m.Levels
+	}
+	return nil
+}
+
+func (m *StorageLevelsResponse) GetTables() []*StorageTable {
+	if m != nil {
+		return m.Tables
+	}
+	return nil
+}
+
+func (m *StorageLevelsResponse) GetLsmSize() int64 {
+	if m != nil {
+		return m.LsmSize
+	}
+	return 0
+}
+
+func (m *StorageLevelsResponse) GetVlogSize() int64 {
+	if m != nil {
+		return m.VlogSize
+	}
+	return 0
+}
+
+func (m *StorageLevelsResponse) GetLiveKeys() uint64 {
+	if m != nil {
+		return m.LiveKeys
+	}
+	return 0
+}
+
+func (m *StorageLevelsResponse) GetStaleRatio() float64 {
+	if m != nil {
+		return m.StaleRatio
+	}
+	return 0
+}
+
+type DumpResponse struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Bundle               []byte   `protobuf:"bytes,2,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DumpResponse) Reset()         { *m = DumpResponse{} }
+func (m *DumpResponse) String() string { return proto.CompactTextString(m) }
+func (*DumpResponse) ProtoMessage()    {}
+func (*DumpResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{34}
+}
+
+func (m *DumpResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DumpResponse.Unmarshal(m, b)
+}
+func (m *DumpResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DumpResponse.Marshal(b, m, deterministic)
+}
+func (m *DumpResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DumpResponse.Merge(m, src)
+}
+func (m *DumpResponse) XXX_Size() int {
+	return xxx_messageInfo_DumpResponse.Size(m)
+}
+func (m *DumpResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DumpResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DumpResponse proto.InternalMessageInfo
+
+func (m *DumpResponse) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *DumpResponse) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+type GetRequest struct {
+	Key         string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Consistency GetRequest_Consistency `protobuf:"varint,2,opt,name=consistency,proto3,enum=kvs.GetRequest_Consistency" json:"consistency,omitempty"`
+	// read a range of the value from the offset, of at most length bytes, or up to the end of
+	// the value if the length is 0
+	Offset               uint64   `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length               uint64   `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRequest) Reset()         { *m = GetRequest{} }
+func (m *GetRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRequest) ProtoMessage()    {}
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{35}
+}
+
+func (m *GetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
+}
+func (m *GetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRequest.Merge(m, src)
+}
+func (m *GetRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRequest.Size(m)
+}
+func (m *GetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRequest proto.InternalMessageInfo
+
+func (m *GetRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetRequest) GetConsistency() GetRequest_Consistency {
+	if m != nil {
+		return m.Consistency
+	}
+	return GetRequest_Stale
+}
+
+func (m *GetRequest) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *GetRequest) GetLength() uint64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+type GetResponse struct {
+	Value []byte `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	// the index of the log entry that last modified the key
+	ModifiedIndex uint64 `protobuf:"varint,2,opt,name=modified_index,json=modifiedIndex,proto3" json:"modified_index,omitempty"`
+	// the size of the whole value, to read it in ranges
+	Size                 uint64   `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetResponse) Reset()         { *m = GetResponse{} }
+func (m *GetResponse) String() string { return proto.CompactTextString(m) }
+func (*GetResponse) ProtoMessage()    {}
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{36}
+}
+
+func (m *GetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetResponse.Unmarshal(m, b)
+}
+func (m *GetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetResponse.Marshal(b, m, deterministic)
+}
+func (m *GetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetResponse.Merge(m, src)
+}
+func (m *GetResponse) XXX_Size() int {
+	return xxx_messageInfo_GetResponse.Size(m)
+}
+func (m *GetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetResponse proto.InternalMessageInfo
+
+func (m *GetResponse) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *GetResponse) GetModifiedIndex() uint64 {
+	if m != nil {
+		return m.ModifiedIndex
+	}
+	return 0
+}
+
+func (m *GetResponse) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type ScanRequest struct {
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// filters evaluated by the server, an item must match all of them
+	KeyRegexp            string   `protobuf:"bytes,2,opt,name=key_regexp,json=keyRegexp,proto3" json:"key_regexp,omitempty"`
+	KeyGlob              string   `protobuf:"bytes,3,opt,name=key_glob,json=keyGlob,proto3" json:"key_glob,omitempty"`
+	MinValueSize         uint64   `protobuf:"varint,4,opt,name=min_value_size,json=minValueSize,proto3" json:"min_value_size,omitempty"`
+	MaxValueSize         uint64   `protobuf:"varint,5,opt,name=max_value_size,json=maxValueSize,proto3" json:"max_value_size,omitempty"`
+	ModifiedSince        uint64   `protobuf:"varint,6,opt,name=modified_since,json=modifiedSince,proto3" json:"modified_since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScanRequest) Reset()         { *m = ScanRequest{} }
+func (m *ScanRequest) String() string { return proto.CompactTextString(m) }
+func (*ScanRequest) ProtoMessage()    {}
+func (*ScanRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{37}
+}
+
+func (m *ScanRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScanRequest.Unmarshal(m, b)
+}
+func (m *ScanRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScanRequest.Marshal(b, m, deterministic)
+}
+func (m *ScanRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScanRequest.Merge(m, src)
+}
+func (m *ScanRequest) XXX_Size() int {
+	return xxx_messageInfo_ScanRequest.Size(m)
+}
+func (m *ScanRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScanRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScanRequest proto.InternalMessageInfo
+
+func (m *ScanRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ScanRequest) GetKeyRegexp() string {
+	if m != nil {
+		return m.KeyRegexp
+	}
+	return ""
+}
+
+func (m *ScanRequest) GetKeyGlob() string {
+	if m != nil {
+		return m.KeyGlob
+	}
+	return ""
+}
+
+func (m *ScanRequest) GetMinValueSize() uint64 {
+	if m != nil {
+		return m.MinValueSize
+	}
+	return 0
+}
+
+func (m *ScanRequest) GetMaxValueSize() uint64 {
+	if m != nil {
+		return m.MaxValueSize
+	}
+	return 0
+}
+
+func (m *ScanRequest) GetModifiedSince() uint64 {
+	if m != nil {
+		return m.ModifiedSince
+	}
+	return 0
+}
+
+type ScanResponse struct {
+	Values               [][]byte `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScanResponse) Reset()         { *m = ScanResponse{} }
+func (m *ScanResponse) String() string { return proto.CompactTextString(m) }
+func (*ScanResponse) ProtoMessage()    {}
+func (*ScanResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{38}
+}
+
+func (m *ScanResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScanResponse.Unmarshal(m, b)
+}
+func (m *ScanResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScanResponse.Marshal(b, m, deterministic)
+}
+func (m *ScanResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScanResponse.Merge(m, src)
+}
+func (m *ScanResponse) XXX_Size() int {
+	return xxx_messageInfo_ScanResponse.Size(m)
+}
+func (m *ScanResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScanResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScanResponse proto.InternalMessageInfo
+
+func (m *ScanResponse) GetValues() [][]byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type ExportRequest struct {
+	Scan *ScanRequest `protobuf:"bytes,1,opt,name=scan,proto3" json:"scan,omitempty"`
+	// file:///path/to/file, or an http(s) URL the result is uploaded to with PUT, e.g. a presigned object storage URL
+	Destination string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	// jsonl
+	Format               string   `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportRequest) Reset()         { *m = ExportRequest{} }
+func (m *ExportRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportRequest) ProtoMessage()    {}
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{39}
+}
+
+func (m *ExportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportRequest.Unmarshal(m, b)
+}
+func (m *ExportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportRequest.Merge(m, src)
+}
+func (m *ExportRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportRequest.Size(m)
+}
+func (m *ExportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportRequest proto.InternalMessageInfo
+
+func (m *ExportRequest) GetScan() *ScanRequest {
+	if m != nil {
+		return m.Scan
+	}
+	return nil
+}
+
+func (m *ExportRequest) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *ExportRequest) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+type ExportResponse struct {
+	Count                uint64   `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Bytes                uint64   `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportResponse) Reset()         { *m = ExportResponse{} }
+func (m *ExportResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportResponse) ProtoMessage()    {}
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{40}
+}
+
+func (m *ExportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportResponse.Unmarshal(m, b)
+}
+func (m *ExportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportResponse.Merge(m, src)
+}
+func (m *ExportResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportResponse.Size(m)
+}
+func (m *ExportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportResponse proto.InternalMessageInfo
+
+func (m *ExportResponse) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ExportResponse) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+type SetRequest struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// the key expires after the TTL. It never expires if omitted
+	Ttl *duration.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// the expiration time, set by the leader from the TTL
+	ExpiresAt *timestamp.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// the write is aborted unless the key was last modified by the log entry with the
+	// index, or does not exist if the index is 0. It is not checked if omitted
+	ExpectedIndex *wrappers.UInt64Value `protobuf:"bytes,5,opt,name=expected_index,json=expectedIndex,proto3" json:"expected_index,omitempty"`
+	// the CRC-32C (Castagnoli) of the value. The write is aborted with DATA_LOSS unless
+	// the value applied by the nodes matches it. It is not checked if omitted
+	Checksum             *wrappers.UInt32Value `protobuf:"bytes,6,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *SetRequest) Reset()         { *m = SetRequest{} }
+func (m *SetRequest) String() string { return proto.CompactTextString(m) }
+func (*SetRequest) ProtoMessage()    {}
+func (*SetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{41}
+}
+
+func (m *SetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRequest.Unmarshal(m, b)
+}
+func (m *SetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRequest.Marshal(b, m, deterministic)
+}
+func (m *SetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRequest.Merge(m, src)
+}
+func (m *SetRequest) XXX_Size() int {
+	return xxx_messageInfo_SetRequest.Size(m)
+}
+func (m *SetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRequest proto.InternalMessageInfo
+
+func (m *SetRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *SetRequest) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *SetRequest) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+func (m *SetRequest) GetExpiresAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return nil
+}
+
+func (m *SetRequest) GetExpectedIndex() *wrappers.UInt64Value {
+	if m != nil {
+		return m.ExpectedIndex
+	}
+	return nil
+}
+
+func (m *SetRequest) GetChecksum() *wrappers.UInt32Value {
+	if m != nil {
+		return m.Checksum
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// the delete is aborted unless the key was last modified by the log entry with the index
+	ExpectedIndex        *wrappers.UInt64Value `protobuf:"bytes,2,opt,name=expected_index,json=expectedIndex,proto3" json:"expected_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()    {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{42}
+}
+
+func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
+}
+func (m *DeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRequest.Merge(m, src)
+}
+func (m *DeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteRequest.Size(m)
+}
+func (m *DeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteRequest proto.InternalMessageInfo
+
+func (m *DeleteRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *DeleteRequest) GetExpectedIndex() *wrappers.UInt64Value {
+	if m != nil {
+		return m.ExpectedIndex
+	}
+	return nil
+}
+
+type CopyRequest struct {
+	// the key to copy, or the prefix of the keys to copy if prefix is set
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// the key to copy to, or the prefix replacing the source prefix if prefix is set
+	Destination          string   `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Prefix               bool     `protobuf:"varint,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CopyRequest) Reset()         { *m = CopyRequest{} }
+func (m *CopyRequest) String() string { return proto.CompactTextString(m) }
+func (*CopyRequest) ProtoMessage()    {}
+func (*CopyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{43}
+}
+
+func (m *CopyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CopyRequest.Unmarshal(m, b)
+}
+func (m *CopyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CopyRequest.Marshal(b, m, deterministic)
+}
+func (m *CopyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CopyRequest.Merge(m, src)
+}
+func (m *CopyRequest) XXX_Size() int {
+	return xxx_messageInfo_CopyRequest.Size(m)
+}
+func (m *CopyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CopyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CopyRequest proto.InternalMessageInfo
+
+func (m *CopyRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *CopyRequest) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *CopyRequest) GetPrefix() bool {
+	if m != nil {
+		return m.Prefix
+	}
+	return false
+}
+
+type MoveRequest struct {
+	// the key to move, or the prefix of the keys to move if prefix is set
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// the key to move to, or the prefix replacing the source prefix if prefix is set
+	Destination          string   `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Prefix               bool     `protobuf:"varint,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MoveRequest) Reset()         { *m = MoveRequest{} }
+func (m *MoveRequest) String() string { return proto.CompactTextString(m) }
+func (*MoveRequest) ProtoMessage()    {}
+func (*MoveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{44}
+}
+
+func (m *MoveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveRequest.Unmarshal(m, b)
+}
+func (m *MoveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveRequest.Marshal(b, m, deterministic)
+}
+func (m *MoveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveRequest.Merge(m, src)
+}
+func (m *MoveRequest) XXX_Size() int {
+	return xxx_messageInfo_MoveRequest.Size(m)
+}
+func (m *MoveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MoveRequest proto.InternalMessageInfo
+
+func (m *MoveRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *MoveRequest) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *MoveRequest) GetPrefix() bool {
+	if m != nil {
+		return m.Prefix
+	}
+	return false
+}
+
+// ExpireRequest deletes the keys that expired at the time, as found by the expiration sweep of the leader.
+type ExpireRequest struct {
+	Keys                 []string             `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ExpireRequest) Reset()         { *m = ExpireRequest{} }
+func (m *ExpireRequest) String() string { return proto.CompactTextString(m) }
+func (*ExpireRequest) ProtoMessage()    {}
+func (*ExpireRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{45}
+}
+
+func (m *ExpireRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExpireRequest.Unmarshal(m, b)
+}
+func (m *ExpireRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExpireRequest.Marshal(b, m, deterministic)
+}
+func (m *ExpireRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpireRequest.Merge(m, src)
+}
+func (m *ExpireRequest) XXX_Size() int {
+	return xxx_messageInfo_ExpireRequest.Size(m)
+}
+func (m *ExpireRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpireRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpireRequest proto.InternalMessageInfo
+
+func (m *ExpireRequest) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *ExpireRequest) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+// PurgeRequest deletes the keys dropped by a compaction filter, unless they were modified
+// since the filter was run.
+type PurgeRequest struct {
+	// the name of the compaction filter
+	Filter               string               `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Keys                 []*PurgeRequest_Key  `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *PurgeRequest) Reset()         { *m = PurgeRequest{} }
+func (m *PurgeRequest) String() string { return proto.CompactTextString(m) }
+func (*PurgeRequest) ProtoMessage()    {}
+func (*PurgeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{46}
+}
+
+func (m *PurgeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeRequest.Unmarshal(m, b)
+}
+func (m *PurgeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeRequest.Marshal(b, m, deterministic)
+}
+func (m *PurgeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeRequest.Merge(m, src)
+}
+func (m *PurgeRequest) XXX_Size() int {
+	return xxx_messageInfo_PurgeRequest.Size(m)
+}
+func (m *PurgeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeRequest proto.InternalMessageInfo
+
+func (m *PurgeRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *PurgeRequest) GetKeys() []*PurgeRequest_Key {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *PurgeRequest) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+type PurgeRequest_Key struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// the index of the log entry that last modified the key when the filter was run
+	ModifiedIndex        uint64   `protobuf:"varint,2,opt,name=modified_index,json=modifiedIndex,proto3" json:"modified_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeRequest_Key) Reset()         { *m = PurgeRequest_Key{} }
+func (m *PurgeRequest_Key) String() string { return proto.CompactTextString(m) }
+func (*PurgeRequest_Key) ProtoMessage()    {}
+func (*PurgeRequest_Key) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{46, 0}
+}
+
+func (m *PurgeRequest_Key) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeRequest_Key.Unmarshal(m, b)
+}
+func (m *PurgeRequest_Key) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeRequest_Key.Marshal(b, m, deterministic)
+}
+func (m *PurgeRequest_Key) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeRequest_Key.Merge(m, src)
+}
+func (m *PurgeRequest_Key) XXX_Size() int {
+	return xxx_messageInfo_PurgeRequest_Key.Size(m)
+}
+func (m *PurgeRequest_Key) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeRequest_Key.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeRequest_Key proto.InternalMessageInfo
+
+func (m *PurgeRequest_Key) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *PurgeRequest_Key) GetModifiedIndex() uint64 {
+	if m != nil {
+		return m.ModifiedIndex
+	}
+	return 0
+}
+
+// Role tells whether the cluster takes the writes of the clients, or replicates those of
+// another cluster.
+type Role struct {
+	Mode Role_Mode `protobuf:"varint,1,opt,name=mode,proto3,enum=kvs.Role_Mode" json:"mode,omitempty"`
+	// the gRPC address of a node of the cluster a standby replicates
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// why the role was set, e.g. the ticket of a failover
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// the index of the log entry that set the role
+	Index uint64 `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	// the time the role was set
+	Time                 *timestamp.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Role) Reset()         { *m = Role{} }
+func (m *Role) String() string { return proto.CompactTextString(m) }
+func (*Role) ProtoMessage()    {}
+func (*Role) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{47}
+}
+
+func (m *Role) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Role.Unmarshal(m, b)
+}
+func (m *Role) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Role.Marshal(b, m, deterministic)
+}
+func (m *Role) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Role.Merge(m, src)
+}
+func (m *Role) XXX_Size() int {
+	return xxx_messageInfo_Role.Size(m)
+}
+func (m *Role) XXX_DiscardUnknown() {
+	xxx_messageInfo_Role.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Role proto.InternalMessageInfo
+
+func (m *Role) GetMode() Role_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return Role_Primary
+}
+
+func (m *Role) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *Role) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Role) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Role) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+type RoleResponse struct {
+	Role *Role `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	// the index in the log of the source cluster of the last event replicated by a standby
+	ReplicatedIndex      uint64   `protobuf:"varint,2,opt,name=replicated_index,json=replicatedIndex,proto3" json:"replicated_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoleResponse) Reset()         { *m = RoleResponse{} }
+func (m *RoleResponse) String() string { return proto.CompactTextString(m) }
+func (*RoleResponse) ProtoMessage()    {}
+func (*RoleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{48}
+}
+
+func (m *RoleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleResponse.Unmarshal(m, b)
+}
+func (m *RoleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleResponse.Marshal(b, m, deterministic)
+}
+func (m *RoleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleResponse.Merge(m, src)
+}
+func (m *RoleResponse) XXX_Size() int {
+	return xxx_messageInfo_RoleResponse.Size(m)
+}
+func (m *RoleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleResponse proto.InternalMessageInfo
+
+func (m *RoleResponse) GetRole() *Role {
+	if m != nil {
+		return m.Role
+	}
+	return nil
+}
+
+func (m *RoleResponse) GetReplicatedIndex() uint64 {
+	if m != nil {
+		return m.ReplicatedIndex
+	}
+	return 0
+}
+
+type SetRoleRequest struct {
+	Mode   Role_Mode `protobuf:"varint,1,opt,name=mode,proto3,enum=kvs.Role_Mode" json:"mode,omitempty"`
+	Source string    `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Reason string    `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// the index in the log of the source cluster from which a standby replicates, e.g. the
+	// applied index of a copy of its data. 0 replicates the whole log
+	ReplicatedIndex      uint64   `protobuf:"varint,4,opt,name=replicated_index,json=replicatedIndex,proto3" json:"replicated_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetRoleRequest) Reset()         { *m = SetRoleRequest{} }
+func (m *SetRoleRequest) String() string { return proto.CompactTextString(m) }
+func (*SetRoleRequest) ProtoMessage()    {}
+func (*SetRoleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{49}
+}
+
+func (m *SetRoleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRoleRequest.Unmarshal(m, b)
+}
+func (m *SetRoleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRoleRequest.Marshal(b, m, deterministic)
+}
+func (m *SetRoleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRoleRequest.Merge(m, src)
+}
+func (m *SetRoleRequest) XXX_Size() int {
+	return xxx_messageInfo_SetRoleRequest.Size(m)
+}
+func (m *SetRoleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRoleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRoleRequest proto.InternalMessageInfo
+
+func (m *SetRoleRequest) GetMode() Role_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return Role_Primary
+}
+
+func (m *SetRoleRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *SetRoleRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *SetRoleRequest) GetReplicatedIndex() uint64 {
+	if m != nil {
+		return m.ReplicatedIndex
+	}
+	return 0
+}
+
+// ReplicateRequest applies an event of the source cluster to a standby.
+type ReplicateRequest struct {
+	// the index of the event in the log of the source cluster
+	Index uint64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// missing for the events of the source cluster that only move the replicated index,
+	// e.g. its freeze
+	Event                *Event   `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplicateRequest) Reset()         { *m = ReplicateRequest{} }
+func (m *ReplicateRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplicateRequest) ProtoMessage()    {}
+func (*ReplicateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{50}
+}
+
+func (m *ReplicateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicateRequest.Unmarshal(m, b)
+}
+func (m *ReplicateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicateRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplicateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicateRequest.Merge(m, src)
+}
+func (m *ReplicateRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplicateRequest.Size(m)
+}
+func (m *ReplicateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicateRequest proto.InternalMessageInfo
+
+func (m *ReplicateRequest) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ReplicateRequest) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+// ClusterConfig holds the settings shared by the nodes of the cluster, e.g. the snapshot
+// schedule or feature flags, so that they are changed once for every node.
+type ClusterConfig struct {
+	Settings map[string]string `protobuf:"bytes,1,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// the index of the log entry that last changed the settings
+	Index uint64 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	// the time the settings were last changed
+	Time                 *timestamp.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ClusterConfig) Reset()         { *m = ClusterConfig{} }
+func (m *ClusterConfig) String() string { return proto.CompactTextString(m) }
+func (*ClusterConfig) ProtoMessage()    {}
+func (*ClusterConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{51}
+}
+
+func (m *ClusterConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterConfig.Unmarshal(m, b)
+}
+func (m *ClusterConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterConfig.Marshal(b, m, deterministic)
+}
+func (m *ClusterConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterConfig.Merge(m, src)
+}
+func (m *ClusterConfig) XXX_Size() int {
+	return xxx_messageInfo_ClusterConfig.Size(m)
+}
+func (m *ClusterConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterConfig proto.InternalMessageInfo
+
+func (m *ClusterConfig) GetSettings() map[string]string {
+	if m != nil {
+		return m.Settings
+	}
+	return nil
+}
+
+func (m *ClusterConfig) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ClusterConfig) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+type ClusterConfigResponse struct {
+	Config               *ClusterConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ClusterConfigResponse) Reset()         { *m = ClusterConfigResponse{} }
+func (m *ClusterConfigResponse) String() string { return proto.CompactTextString(m) }
+func (*ClusterConfigResponse) ProtoMessage()    {}
+func (*ClusterConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{52}
+}
+
+func (m *ClusterConfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterConfigResponse.Unmarshal(m, b)
+}
+func (m *ClusterConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterConfigResponse.Marshal(b, m, deterministic)
+}
+func (m *ClusterConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterConfigResponse.Merge(m, src)
+}
+func (m *ClusterConfigResponse) XXX_Size() int {
+	return xxx_messageInfo_ClusterConfigResponse.Size(m)
+}
+func (m *ClusterConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterConfigResponse proto.InternalMessageInfo
+
+func (m *ClusterConfigResponse) GetConfig() *ClusterConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type SetClusterConfigRequest struct {
+	// the settings to change, an empty value removes the setting
+	Settings             map[string]string `protobuf:"bytes,1,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SetClusterConfigRequest) Reset()         { *m = SetClusterConfigRequest{} }
+func (m *SetClusterConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*SetClusterConfigRequest) ProtoMessage()    {}
+func (*SetClusterConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{53}
+}
+
+func (m *SetClusterConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetClusterConfigRequest.Unmarshal(m, b)
+}
+func (m *SetClusterConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetClusterConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *SetClusterConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetClusterConfigRequest.Merge(m, src)
+}
+func (m *SetClusterConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_SetClusterConfigRequest.Size(m)
+}
+func (m *SetClusterConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetClusterConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetClusterConfigRequest proto.InternalMessageInfo
+
+func (m *SetClusterConfigRequest) GetSettings() map[string]string {
+	if m != nil {
+		return m.Settings
+	}
+	return nil
+}
+
+type SetMetadataRequest struct {
+	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Metadata             *Metadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SetMetadataRequest) Reset()         { *m = SetMetadataRequest{} }
+func (m *SetMetadataRequest) String() string { return proto.CompactTextString(m) }
+func (*SetMetadataRequest) ProtoMessage()    {}
+func (*SetMetadataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{54}
+}
+
+func (m *SetMetadataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMetadataRequest.Unmarshal(m, b)
+}
+func (m *SetMetadataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMetadataRequest.Marshal(b, m, deterministic)
+}
+func (m *SetMetadataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMetadataRequest.Merge(m, src)
+}
+func (m *SetMetadataRequest) XXX_Size() int {
+	return xxx_messageInfo_SetMetadataRequest.Size(m)
+}
+func (m *SetMetadataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMetadataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMetadataRequest proto.InternalMessageInfo
+
+func (m *SetMetadataRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SetMetadataRequest) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type DeleteMetadataRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteMetadataRequest) Reset()         { *m = DeleteMetadataRequest{} }
+func (m *DeleteMetadataRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteMetadataRequest) ProtoMessage()    {}
+func (*DeleteMetadataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{55}
+}
+
+func (m *DeleteMetadataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteMetadataRequest.Unmarshal(m, b)
+}
+func (m *DeleteMetadataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteMetadataRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteMetadataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteMetadataRequest.Merge(m, src)
+}
+func (m *DeleteMetadataRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteMetadataRequest.Size(m)
+}
+func (m *DeleteMetadataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteMetadataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteMetadataRequest proto.InternalMessageInfo
+
+func (m *DeleteMetadataRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type Event struct {
+	Type Event_Type `protobuf:"varint,1,opt,name=type,proto3,enum=kvs.Event_Type" json:"type,omitempty"`
+	Data *any.Any   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// request metadata of the write, e.g. the client ID, for auditing
+	Metadata map[string]string `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// the time the leader proposed the event, recorded as the modification time of the
+	// keys written. Missing from the events proposed by older versions
+	Time *timestamp.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	// the membership epoch reached by a Join or Leave event that added or removed a node,
+	// set by the node sending the event to a watcher. 0 for the other events
+	MembershipEpoch      uint64   `protobuf:"varint,5,opt,name=membership_epoch,json=membershipEpoch,proto3" json:"membership_epoch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{56}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetType() Event_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Event_Unknown
+}
+
+func (m *Event) GetData() *any.Any {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Event) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *Event) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *Event) GetMembershipEpoch() uint64 {
+	if m != nil {
+		return m.MembershipEpoch
+	}
+	return 0
+}
+
+type WatchRequest struct {
+	// only the events of the keys with the prefix are sent, or all events, including
+	// the cluster events, if the prefix is empty
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// resume a watch after the events it has received, as told by the resume token of its last response
+	ResumeToken string                `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	Encoding    WatchRequest_Encoding `protobuf:"varint,3,opt,name=encoding,proto3,enum=kvs.WatchRequest_Encoding" json:"encoding,omitempty"`
+	// with the compact encoding, send the keys as the difference from the key of the previous event
+	KeyDelta             bool     `protobuf:"varint,4,opt,name=key_delta,json=keyDelta,proto3" json:"key_delta,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{57}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetEncoding() WatchRequest_Encoding {
+	if m != nil {
+		return m.Encoding
+	}
+	return WatchRequest_Verbose
+}
+
+func (m *WatchRequest) GetKeyDelta() bool {
+	if m != nil {
+		return m.KeyDelta
+	}
+	return false
+}
+
+// CompactEvent is an event in the compact encoding. The events are framed by their size in
+// a varint, and several of them are sent in a response.
+type CompactEvent struct {
+	Type Event_Type `protobuf:"varint,1,opt,name=type,proto3,enum=kvs.Event_Type" json:"type,omitempty"`
+	// the difference from the index of the previous event of the watch
+	IndexDelta uint64 `protobuf:"varint,2,opt,name=index_delta,json=indexDelta,proto3" json:"index_delta,omitempty"`
+	// the number of leading bytes of the key shared with the key of the previous event of
+	// the watch, with the key delta
+	SharedKeyPrefix uint32 `protobuf:"varint,3,opt,name=shared_key_prefix,json=sharedKeyPrefix,proto3" json:"shared_key_prefix,omitempty"`
+	Key             []byte `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	Value           []byte `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	// the data of the event in the binary format, without the key and the value
+	Data                 []byte            `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	MembershipEpoch      uint64            `protobuf:"varint,8,opt,name=membership_epoch,json=membershipEpoch,proto3" json:"membership_epoch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CompactEvent) Reset()         { *m = CompactEvent{} }
+func (m *CompactEvent) String() string { return proto.CompactTextString(m) }
+func (*CompactEvent) ProtoMessage()    {}
+func (*CompactEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{58}
+}
+
+func (m *CompactEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompactEvent.Unmarshal(m, b)
+}
+func (m *CompactEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompactEvent.Marshal(b, m, deterministic)
+}
+func (m *CompactEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompactEvent.Merge(m, src)
+}
+func (m *CompactEvent) XXX_Size() int {
+	return xxx_messageInfo_CompactEvent.Size(m)
+}
+func (m *CompactEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompactEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompactEvent proto.InternalMessageInfo
+
+func (m *CompactEvent) GetType() Event_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Event_Unknown
+}
+
+func (m *CompactEvent) GetIndexDelta() uint64 {
+	if m != nil {
+		return m.IndexDelta
+	}
+	return 0
+}
+
+func (m *CompactEvent) GetSharedKeyPrefix() uint32 {
+	if m != nil {
+		return m.SharedKeyPrefix
+	}
+	return 0
+}
+
+func (m *CompactEvent) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *CompactEvent) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *CompactEvent) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *CompactEvent) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *CompactEvent) GetMembershipEpoch() uint64 {
+	if m != nil {
+		return m.MembershipEpoch
+	}
+	return 0
+}
+
+// WatchGap tells a watcher that events in the range of log entries were not sent to it.
+type WatchGap struct {
+	FromIndex            uint64   `protobuf:"varint,1,opt,name=from_index,json=fromIndex,proto3" json:"from_index,omitempty"`
+	ToIndex              uint64   `protobuf:"varint,2,opt,name=to_index,json=toIndex,proto3" json:"to_index,omitempty"`
+	Dropped              uint64   `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchGap) Reset()         { *m = WatchGap{} }
+func (m *WatchGap) String() string { return proto.CompactTextString(m) }
+func (*WatchGap) ProtoMessage()    {}
+func (*WatchGap) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{59}
+}
+
+func (m *WatchGap) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchGap.Unmarshal(m, b)
+}
+func (m *WatchGap) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchGap.Marshal(b, m, deterministic)
+}
+func (m *WatchGap) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchGap.Merge(m, src)
+}
+func (m *WatchGap) XXX_Size() int {
+	return xxx_messageInfo_WatchGap.Size(m)
+}
+func (m *WatchGap) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchGap.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchGap proto.InternalMessageInfo
+
+func (m *WatchGap) GetFromIndex() uint64 {
+	if m != nil {
+		return m.FromIndex
+	}
+	return 0
+}
+
+func (m *WatchGap) GetToIndex() uint64 {
+	if m != nil {
+		return m.ToIndex
+	}
+	return 0
+}
+
+func (m *WatchGap) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
+	}
+	return 0
+}
+
+type WatchResponse struct {
+	Event *Event `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// the index of the log entry of the event
+	Index uint64 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	// sent instead of an event when events were dropped
+	Gap *WatchGap `protobuf:"bytes,3,opt,name=gap,proto3" json:"gap,omitempty"`
+	// sent in the last response of a watch cancelled by the server
+	ResumeToken string `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// the varint framed events in the compact encoding
+	CompactEvents        []byte   `protobuf:"bytes,5,opt,name=compact_events,json=compactEvents,proto3" json:"compact_events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
+func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()    {}
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{60}
+}
+
+func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchResponse.Unmarshal(m, b)
+}
+func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchResponse.Merge(m, src)
+}
+func (m *WatchResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchResponse.Size(m)
+}
+func (m *WatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+
+func (m *WatchResponse) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *WatchResponse) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *WatchResponse) GetGap() *WatchGap {
+	if m != nil {
+		return m.Gap
+	}
+	return nil
+}
+
+func (m *WatchResponse) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+func (m *WatchResponse) GetCompactEvents() []byte {
+	if m != nil {
+		return m.CompactEvents
+	}
+	return nil
+}
+
+type MetricsResponse struct {
+	Metrics              []byte   `protobuf:"bytes,1,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MetricsResponse) Reset()         { *m = MetricsResponse{} }
+func (m *MetricsResponse) String() string { return proto.CompactTextString(m) }
+func (*MetricsResponse) ProtoMessage()    {}
+func (*MetricsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{61}
+}
+
+func (m *MetricsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetricsResponse.Unmarshal(m, b)
+}
+func (m *MetricsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetricsResponse.Marshal(b, m, deterministic)
+}
+func (m *MetricsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetricsResponse.Merge(m, src)
+}
+func (m *MetricsResponse) XXX_Size() int {
+	return xxx_messageInfo_MetricsResponse.Size(m)
+}
+func (m *MetricsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetricsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetricsResponse proto.InternalMessageInfo
+
+func (m *MetricsResponse) GetMetrics() []byte {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+type KeyValuePair struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeyValuePair) Reset()         { *m = KeyValuePair{} }
+func (m *KeyValuePair) String() string { return proto.CompactTextString(m) }
+func (*KeyValuePair) ProtoMessage()    {}
+func (*KeyValuePair) Descriptor() ([]byte, []int) {
+	return fileDescriptor_431078ad7b21f851, []int{62}
+}
+
+func (m *KeyValuePair) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyValuePair.Unmarshal(m, b)
+}
+func (m *KeyValuePair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyValuePair.Marshal(b, m, deterministic)
+}
+func (m *KeyValuePair) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyValuePair.Merge(m, src)
+}
+func (m *KeyValuePair) XXX_Size() int {
+	return xxx_messageInfo_KeyValuePair.Size(m)
+}
+func (m *KeyValuePair) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyValuePair.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyValuePair proto.InternalMessageInfo
+
+func (m *KeyValuePair) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *KeyValuePair) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("kvs.ReconcileAction_Type", ReconcileAction_Type_name, ReconcileAction_Type_value)
+	proto.RegisterEnum("kvs.DecommissionStatus_Phase", DecommissionStatus_Phase_name, DecommissionStatus_Phase_value)
+	proto.RegisterEnum("kvs.GetRequest_Consistency", GetRequest_Consistency_name, GetRequest_Consistency_value)
+	proto.RegisterEnum("kvs.Role_Mode", Role_Mode_name, Role_Mode_value)
+	proto.RegisterEnum("kvs.Event_Type", Event_Type_name, Event_Type_value)
+	proto.RegisterEnum("kvs.WatchRequest_Encoding", WatchRequest_Encoding_name, WatchRequest_Encoding_value)
+	proto.RegisterType((*LivenessCheckResponse)(nil), "kvs.LivenessCheckResponse")
+	proto.RegisterType((*ReadinessCheckResponse)(nil), "kvs.ReadinessCheckResponse")
+	proto.RegisterType((*Metadata)(nil), "kvs.Metadata")
+	proto.RegisterType((*Node)(nil), "kvs.Node")
+	proto.RegisterMapType((map[string]string)(nil), "kvs.Node.AnnotationsEntry")
+	proto.RegisterType((*Cluster)(nil), "kvs.Cluster")
+	proto.RegisterMapType((map[string]*Node)(nil), "kvs.Cluster.NodesEntry")
+	proto.RegisterType((*LeaderHint)(nil), "kvs.LeaderHint")
+	proto.RegisterType((*StageTiming)(nil), "kvs.StageTiming")
+	proto.RegisterType((*DeadlineBudget)(nil), "kvs.DeadlineBudget")
+	proto.RegisterType((*JoinRequest)(nil), "kvs.JoinRequest")
+	proto.RegisterType((*LeaveRequest)(nil), "kvs.LeaveRequest")
+	proto.RegisterType((*BatchJoinRequest)(nil), "kvs.BatchJoinRequest")
+	proto.RegisterMapType((map[string]*Node)(nil), "kvs.BatchJoinRequest.NodesEntry")
+	proto.RegisterType((*BatchJoinResponse)(nil), "kvs.BatchJoinResponse")
+	proto.RegisterType((*MembershipSpec)(nil), "kvs.MembershipSpec")
+	proto.RegisterMapType((map[string]*Node)(nil), "kvs.MembershipSpec.NodesEntry")
+	proto.RegisterType((*MembershipSpecResponse)(nil), "kvs.MembershipSpecResponse")
+	proto.RegisterType((*SetMembershipSpecRequest)(nil), "kvs.SetMembershipSpecRequest")
+	proto.RegisterType((*ReconcileAction)(nil), "kvs.ReconcileAction")
+	proto.RegisterType((*DecommissionStatus)(nil), "kvs.DecommissionStatus")
+	proto.RegisterType((*AnnotateRequest)(nil), "kvs.AnnotateRequest")
+	proto.RegisterMapType((map[string]string)(nil), "kvs.AnnotateRequest.AnnotationsEntry")
+	proto.RegisterType((*NodeStatus)(nil), "kvs.NodeStatus")
+	proto.RegisterType((*UpdateNodeStatusRequest)(nil), "kvs.UpdateNodeStatusRequest")
+	proto.RegisterType((*APIKey)(nil), "kvs.APIKey")
+	proto.RegisterType((*CreateAPIKeyRequest)(nil), "kvs.CreateAPIKeyRequest")
+	proto.RegisterType((*CreateAPIKeyResponse)(nil), "kvs.CreateAPIKeyResponse")
+	proto.RegisterType((*RevokeAPIKeyRequest)(nil), "kvs.RevokeAPIKeyRequest")
+	proto.RegisterType((*APIKeysResponse)(nil), "kvs.APIKeysResponse")
+	proto.RegisterType((*DecommissionStatusRequest)(nil), "kvs.DecommissionStatusRequest")
+	proto.RegisterType((*DecommissionStatusResponse)(nil), "kvs.DecommissionStatusResponse")
+	proto.RegisterType((*NodeResponse)(nil), "kvs.NodeResponse")
+	proto.RegisterType((*ClusterResponse)(nil), "kvs.ClusterResponse")
+	proto.RegisterType((*HashResponse)(nil), "kvs.HashResponse")
+	proto.RegisterType((*StorageLevelsRequest)(nil), "kvs.StorageLevelsRequest")
+	proto.RegisterType((*StorageTable)(nil), "kvs.StorageTable")
+	proto.RegisterType((*StorageLevel)(nil), "kvs.StorageLevel")
+	proto.RegisterType((*StorageLevelsResponse)(nil), "kvs.StorageLevelsResponse")
+	proto.RegisterType((*DumpResponse)(nil), "kvs.DumpResponse")
+	proto.RegisterType((*GetRequest)(nil), "kvs.GetRequest")
+	proto.RegisterType((*GetResponse)(nil), "kvs.GetResponse")
+	proto.RegisterType((*ScanRequest)(nil), "kvs.ScanRequest")
+	proto.RegisterType((*ScanResponse)(nil), "kvs.ScanResponse")
+	proto.RegisterType((*ExportRequest)(nil), "kvs.ExportRequest")
+	proto.RegisterType((*ExportResponse)(nil), "kvs.ExportResponse")
+	proto.RegisterType((*SetRequest)(nil), "kvs.SetRequest")
+	proto.RegisterType((*DeleteRequest)(nil), "kvs.DeleteRequest")
+	proto.RegisterType((*CopyRequest)(nil), "kvs.CopyRequest")
+	proto.RegisterType((*MoveRequest)(nil), "kvs.MoveRequest")
+	proto.RegisterType((*ExpireRequest)(nil), "kvs.ExpireRequest")
+	proto.RegisterType((*PurgeRequest)(nil), "kvs.PurgeRequest")
+	proto.RegisterType((*PurgeRequest_Key)(nil), "kvs.PurgeRequest.Key")
+	proto.RegisterType((*Role)(nil), "kvs.Role")
+	proto.RegisterType((*RoleResponse)(nil), "kvs.RoleResponse")
+	proto.RegisterType((*SetRoleRequest)(nil), "kvs.SetRoleRequest")
+	proto.RegisterType((*ReplicateRequest)(nil), "kvs.ReplicateRequest")
+	proto.RegisterType((*ClusterConfig)(nil), "kvs.ClusterConfig")
+	proto.RegisterMapType((map[string]string)(nil), "kvs.ClusterConfig.SettingsEntry")
+	proto.RegisterType((*ClusterConfigResponse)(nil), "kvs.ClusterConfigResponse")
+	proto.RegisterType((*SetClusterConfigRequest)(nil), "kvs.SetClusterConfigRequest")
+	proto.RegisterMapType((map[string]string)(nil), "kvs.SetClusterConfigRequest.SettingsEntry")
+	proto.RegisterType((*SetMetadataRequest)(nil), "kvs.SetMetadataRequest")
+	proto.RegisterType((*DeleteMetadataRequest)(nil), "kvs.DeleteMetadataRequest")
+	proto.RegisterType((*Event)(nil), "kvs.Event")
+	proto.RegisterMapType((map[string]string)(nil), "kvs.Event.MetadataEntry")
+	proto.RegisterType((*WatchRequest)(nil), "kvs.WatchRequest")
+	proto.RegisterType((*CompactEvent)(nil), "kvs.CompactEvent")
+	proto.RegisterMapType((map[string]string)(nil), "kvs.CompactEvent.MetadataEntry")
+	proto.RegisterType((*WatchGap)(nil), "kvs.WatchGap")
+	proto.RegisterType((*WatchResponse)(nil), "kvs.WatchResponse")
+	proto.RegisterType((*MetricsResponse)(nil), "kvs.MetricsResponse")
+	proto.RegisterType((*KeyValuePair)(nil), "kvs.KeyValuePair")
+}
+
+func init() {
+	proto.RegisterFile("protobuf/kvs.proto", fileDescriptor_431078ad7b21f851)
+}
+
+var fileDescriptor_431078ad7b21f851 = []byte{
+	// 3682 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x3a, 0xcb, 0x72, 0x1b, 0x49,
+	0x72, 0xd3, 0x40, 0x83, 0x00, 0x13, 0x0f, 0x36, 0x8b, 0x0f, 0x81, 0xd0, 0x83, 0x9a, 0x9e, 0x87,
+	0x24, 0xee, 0x0a, 0xf4, 0x52, 0x13, 0x8a, 0x95, 0x34, 0xbb, 0x0e, 0x0a, 0xe2, 0x68, 0x66, 0xf5,
+	0x18, 0x46, 0x43, 0xd2, 0x6c, 0xac, 0xed, 0x41, 0x34, 0xbb, 0x8b, 0x40, 0x9b, 0x8d, 0xee, 0xde,
+	0xee, 0x02, 0x45, 0xcc, 0xc4, 0x5e, 0x36, 0xc2, 0xbe, 0xf8, 0xb0, 0x07, 0xfb, 0xe6, 0x08, 0x5f,
+	0x7c, 0xf3, 0xd1, 0xe1, 0x0f, 0xb0, 0x23, 0x7c, 0xb0, 0x2f, 0x0e, 0x87, 0x7d, 0xf4, 0xc9, 0x61,
+	0xff, 0x80, 0xff, 0xc0, 0x91, 0x55, 0xd5, 0x8d, 0x6a, 0x3c, 0x44, 0x32, 0x66, 0x75, 0x42, 0x57,
+	0x56, 0x56, 0xbe, 0x2a, 0x2b, 0x33, 0x2b, 0x0b, 0x40, 0xa2, 0x38, 0x64, 0xe1, 0xd1, 0xe8, 0x78,
+	0xf7, 0xe4, 0x34, 0x69, 0xf3, 0x01, 0x29, 0x9e, 0x9c, 0x26, 0xad, 0xad, 0x7e, 0x18, 0xf6, 0x7d,
+	0xba, 0x9b, 0xcd, 0xdb, 0xc1, 0x58, 0xcc, 0xb7, 0x6e, 0x4c, 0x4f, 0xb9, 0xa3, 0xd8, 0x66, 0x5e,
+	0x18, 0xc8, 0xf9, 0xab, 0xd3, 0xf3, 0x74, 0x18, 0xb1, 0x74, 0xf1, 0xf6, 0xf4, 0x24, 0xf3, 0x86,
+	0x34, 0x61, 0xf6, 0x30, 0x5a, 0x44, 0xfd, 0x6d, 0x6c, 0x47, 0x11, 0x8d, 0xa5, 0x74, 0xad, 0x6b,
+	0x72, 0xde, 0x8e, 0xbc, 0x5d, 0x3b, 0x08, 0x42, 0xc6, 0x59, 0xa7, 0xb3, 0x3f, 0xe6, 0x3f, 0xce,
+	0xdd, 0x3e, 0x0d, 0xee, 0x26, 0x6f, 0xed, 0x7e, 0x9f, 0xc6, 0xbb, 0x61, 0xc4, 0x31, 0x66, 0xb1,
+	0xcd, 0xbb, 0xb0, 0xf1, 0xdc, 0x3b, 0xa5, 0x01, 0x4d, 0x92, 0xce, 0x80, 0x3a, 0x27, 0x16, 0x4d,
+	0xa2, 0x30, 0x48, 0x28, 0x59, 0x87, 0x92, 0xed, 0x7b, 0xa7, 0xb4, 0xa9, 0xdd, 0xd4, 0x6e, 0x57,
+	0x2c, 0x31, 0x30, 0xdb, 0xb0, 0x69, 0x51, 0xdb, 0xf5, 0xe6, 0xe2, 0xc7, 0xd4, 0x76, 0xc7, 0x29,
+	0x3e, 0x1f, 0x98, 0x7f, 0xa6, 0x41, 0xe5, 0x05, 0x65, 0xb6, 0x6b, 0x33, 0x9b, 0x7c, 0x08, 0xb5,
+	0x7e, 0x1c, 0x39, 0x3d, 0xdb, 0x75, 0x63, 0x9a, 0x24, 0x1c, 0x73, 0xd9, 0xaa, 0x22, 0x6c, 0x5f,
+	0x80, 0x10, 0x65, 0xc0, 0x58, 0x94, 0xa1, 0x14, 0x04, 0x0a, 0xc2, 0x52, 0x94, 0x7b, 0xb0, 0x89,
+	0xb4, 0x7b, 0x61, 0xe0, 0x8f, 0x7b, 0x39, 0x7a, 0x45, 0x8e, 0xbc, 0x86, 0xb3, 0x5f, 0x07, 0xfe,
+	0xf8, 0xe9, 0x84, 0xae, 0xf9, 0xb7, 0x05, 0xd0, 0x5f, 0x86, 0x2e, 0x45, 0x06, 0xb1, 0x7d, 0xcc,
+	0xa6, 0x65, 0x40, 0x58, 0xca, 0xe0, 0x0e, 0x54, 0x86, 0x52, 0x64, 0xce, 0xbf, 0xba, 0x57, 0x6f,
+	0xa3, 0x6b, 0xa4, 0x7a, 0x58, 0xd9, 0x34, 0x2a, 0x9d, 0x30, 0x9b, 0x51, 0xc9, 0x5a, 0x0c, 0xc8,
+	0x47, 0x50, 0xb7, 0xa3, 0xc8, 0xf7, 0xa8, 0xdb, 0xf3, 0x02, 0x97, 0x9e, 0x35, 0xf5, 0x9b, 0xda,
+	0x6d, 0xdd, 0xaa, 0x49, 0xe0, 0x57, 0x08, 0x23, 0x9f, 0x43, 0x55, 0xd9, 0x8d, 0x66, 0xe9, 0x66,
+	0xf1, 0x76, 0x75, 0xaf, 0xc5, 0x19, 0xa1, 0xa0, 0xed, 0xfd, 0xc9, 0xe4, 0x41, 0xc0, 0xe2, 0xb1,
+	0xa5, 0xa2, 0x4f, 0xac, 0xbd, 0xa4, 0x58, 0xbb, 0xf5, 0x73, 0x30, 0xa6, 0x97, 0x11, 0x03, 0x8a,
+	0x27, 0x74, 0x2c, 0xf5, 0xc4, 0x4f, 0x5c, 0x7b, 0x6a, 0xfb, 0x23, 0x2a, 0x8d, 0x2b, 0x06, 0x0f,
+	0x0b, 0x3f, 0xd5, 0xcc, 0x7f, 0xd4, 0xa0, 0xdc, 0xf1, 0x47, 0x09, 0xa3, 0x31, 0xb9, 0x0b, 0xa5,
+	0x20, 0x74, 0x29, 0x5a, 0x08, 0x25, 0xbb, 0xc2, 0x25, 0x93, 0x93, 0x5c, 0x42, 0x29, 0x96, 0xc0,
+	0x22, 0x9b, 0xb0, 0xe4, 0x53, 0xdb, 0xa5, 0xb1, 0xa4, 0x2a, 0x47, 0xe4, 0x0e, 0x18, 0x43, 0x3a,
+	0x3c, 0xa2, 0x71, 0x32, 0xf0, 0xa2, 0x1e, 0x8d, 0x42, 0x67, 0xc0, 0x8d, 0xa5, 0x5b, 0x2b, 0x13,
+	0xf8, 0x01, 0x82, 0x5b, 0x1d, 0x80, 0x09, 0xdd, 0x39, 0x72, 0x6f, 0xab, 0x72, 0x57, 0xf7, 0x96,
+	0x33, 0x5b, 0xa9, 0x2a, 0x3c, 0x02, 0x78, 0xce, 0x39, 0x7f, 0xe9, 0x05, 0x8c, 0x34, 0xa0, 0xe0,
+	0xb9, 0x92, 0x46, 0xc1, 0x73, 0xc9, 0x75, 0xd0, 0x51, 0xdc, 0x59, 0x0a, 0x1c, 0x6c, 0xfe, 0x12,
+	0xaa, 0x5d, 0x66, 0xf7, 0xe9, 0x2b, 0x6f, 0xe8, 0x05, 0x7d, 0xb9, 0xbb, 0x7d, 0x2a, 0x09, 0x88,
+	0x01, 0xb9, 0x07, 0x65, 0xea, 0xdb, 0x51, 0x42, 0x5d, 0x49, 0x66, 0xab, 0x2d, 0xce, 0x63, 0x3b,
+	0x3d, 0xaf, 0xed, 0x27, 0x32, 0x1a, 0x58, 0x29, 0xa6, 0xf9, 0x57, 0x1a, 0x34, 0x9e, 0x50, 0xdb,
+	0xf5, 0xbd, 0x80, 0x3e, 0x1e, 0xb9, 0x7d, 0xca, 0xc8, 0x4f, 0x60, 0xe9, 0x88, 0x7f, 0x35, 0xb5,
+	0xf3, 0xc8, 0x48, 0x44, 0xf2, 0x09, 0x34, 0xe8, 0x99, 0x43, 0xa9, 0x4b, 0xdd, 0x9e, 0x90, 0x4c,
+	0x18, 0xbb, 0x9e, 0x42, 0xb9, 0xf4, 0xe4, 0x36, 0x2c, 0xf1, 0x59, 0x3c, 0x11, 0xb8, 0x77, 0x06,
+	0xd7, 0x53, 0xd1, 0xcc, 0x92, 0xf3, 0xe6, 0x10, 0xaa, 0xbf, 0x08, 0xbd, 0xc0, 0xa2, 0xbf, 0x1e,
+	0xd1, 0xe4, 0xb2, 0xe6, 0x22, 0xbb, 0xb0, 0xee, 0xd8, 0xcc, 0x19, 0xf4, 0x46, 0x51, 0xcf, 0x4e,
+	0x7a, 0x41, 0x18, 0x9c, 0x86, 0x8c, 0xc6, 0x7c, 0x7f, 0x2b, 0xd6, 0x2a, 0x9f, 0x7b, 0x1d, 0xed,
+	0x27, 0x2f, 0xe5, 0x84, 0x79, 0x03, 0x6a, 0xcf, 0xa9, 0x7d, 0x4a, 0x17, 0xf0, 0x33, 0x7f, 0xa7,
+	0x81, 0xf1, 0x18, 0x57, 0xa9, 0x42, 0xdd, 0xcf, 0x3b, 0xe2, 0x4d, 0x2e, 0xc5, 0x34, 0xd6, 0xac,
+	0x47, 0xfe, 0x7e, 0xdc, 0xe9, 0x0f, 0x61, 0x55, 0x61, 0x25, 0x43, 0xdd, 0x26, 0x2c, 0xfd, 0x69,
+	0xe8, 0x05, 0xd4, 0xe5, 0x22, 0x2d, 0x5b, 0x72, 0x44, 0x08, 0xe8, 0x3e, 0x3d, 0x66, 0xcd, 0x02,
+	0x87, 0xf2, 0x6f, 0xf3, 0x2f, 0x34, 0x68, 0xbc, 0xc8, 0x1c, 0xbd, 0x1b, 0x51, 0x87, 0x7c, 0x96,
+	0x57, 0xe8, 0x86, 0x0c, 0x2e, 0x2a, 0xce, 0xfb, 0x52, 0x67, 0x1f, 0x36, 0xf3, 0x8c, 0x32, 0x9d,
+	0x6e, 0x81, 0x9e, 0x44, 0xd4, 0x91, 0xbe, 0xb8, 0x36, 0x47, 0x26, 0x8b, 0x23, 0x98, 0x1d, 0x68,
+	0x76, 0x29, 0x9b, 0xa6, 0x22, 0xb6, 0xea, 0xc2, 0x44, 0xfe, 0x4e, 0x83, 0x15, 0x8b, 0x3a, 0x61,
+	0xe0, 0x78, 0x3e, 0xdd, 0x77, 0xd0, 0xc9, 0xc9, 0x5d, 0xd0, 0xd9, 0x38, 0x12, 0x87, 0xad, 0xb1,
+	0xb7, 0xc5, 0x17, 0x4f, 0xe1, 0xb4, 0x5f, 0x8d, 0x23, 0x6a, 0x71, 0x34, 0xe9, 0x3b, 0x85, 0x19,
+	0x5f, 0x2d, 0xce, 0x3f, 0xda, 0x0f, 0x40, 0xc7, 0xc5, 0xa4, 0x0a, 0xe5, 0xd7, 0xc1, 0x49, 0x10,
+	0xbe, 0x0d, 0x8c, 0x0f, 0x48, 0x05, 0x74, 0xdc, 0x58, 0x43, 0x23, 0x2b, 0x50, 0x7d, 0x1d, 0xc4,
+	0xd4, 0x76, 0x06, 0xf6, 0x91, 0x4f, 0x8d, 0x02, 0x59, 0x86, 0xd2, 0xc1, 0x19, 0x8b, 0x6d, 0xa3,
+	0x68, 0xfe, 0xb6, 0x00, 0xe4, 0x09, 0x75, 0xc2, 0xe1, 0xd0, 0x4b, 0x12, 0x2f, 0x0c, 0xba, 0xcc,
+	0x66, 0xa3, 0x64, 0xe6, 0xb0, 0xdc, 0x83, 0x52, 0x34, 0xb0, 0x13, 0xb1, 0x01, 0x8d, 0xbd, 0xeb,
+	0x5c, 0x82, 0xd9, 0x75, 0xed, 0x43, 0x44, 0xb2, 0x04, 0x2e, 0xa6, 0x23, 0x27, 0x0c, 0x8e, 0xbd,
+	0xbe, 0xcc, 0x14, 0x22, 0x34, 0x56, 0x05, 0x4c, 0x24, 0x8a, 0x8f, 0xa0, 0x3e, 0x8a, 0x5c, 0x9b,
+	0x4d, 0x67, 0x13, 0x09, 0xe4, 0x48, 0x66, 0x0f, 0x4a, 0x9c, 0x6e, 0x5e, 0xbf, 0x2a, 0x94, 0xf1,
+	0xbc, 0x79, 0x41, 0xdf, 0xd0, 0xc8, 0x16, 0x6c, 0x74, 0x38, 0xd9, 0xce, 0xc0, 0x0e, 0xfa, 0xb4,
+	0x83, 0x72, 0x31, 0x46, 0x5d, 0xa3, 0x40, 0x56, 0xa1, 0xfe, 0xc4, 0x66, 0xf6, 0xcb, 0x90, 0xbd,
+	0xe4, 0x61, 0xc4, 0x28, 0x92, 0x06, 0x40, 0xd7, 0x3e, 0xa6, 0xaf, 0xc2, 0x6f, 0xbc, 0x88, 0x1a,
+	0x3a, 0xdf, 0x31, 0x99, 0x5b, 0x16, 0x1d, 0x5f, 0xf2, 0x34, 0x9f, 0xd2, 0x0a, 0xdc, 0xbd, 0x3f,
+	0xe1, 0x76, 0x98, 0x5a, 0xfa, 0xee, 0xec, 0xf6, 0x83, 0xf3, 0x98, 0x23, 0xce, 0x8a, 0xdc, 0xa8,
+	0x2c, 0x49, 0x6b, 0x6a, 0x92, 0xbe, 0x5c, 0x96, 0x17, 0xc9, 0xb6, 0xa8, 0x96, 0x36, 0x16, 0x5c,
+	0x79, 0xcd, 0xb7, 0x60, 0xc2, 0x6a, 0x91, 0x61, 0x6e, 0xf1, 0x80, 0xcc, 0x46, 0x89, 0xe4, 0xb4,
+	0x92, 0x79, 0xa7, 0x5c, 0x27, 0xa7, 0xcd, 0x7f, 0xd7, 0x60, 0x69, 0xff, 0xf0, 0xab, 0x67, 0x74,
+	0x3c, 0x43, 0x63, 0x13, 0x96, 0xa2, 0x98, 0x1e, 0x7b, 0x67, 0x69, 0x82, 0x15, 0x23, 0x14, 0xee,
+	0x6d, 0xec, 0xc9, 0x12, 0xa4, 0x62, 0x89, 0x01, 0x79, 0x00, 0xe0, 0xc4, 0x94, 0x3b, 0x8d, 0xcd,
+	0xb8, 0xc7, 0x60, 0x71, 0x31, 0x9d, 0x60, 0x5e, 0xa5, 0x85, 0xa7, 0xb5, 0x2c, 0xb1, 0xf7, 0x19,
+	0x2e, 0xa5, 0x67, 0x91, 0x17, 0xd3, 0x04, 0x97, 0x96, 0xce, 0x5f, 0x2a, 0xb1, 0xf7, 0x19, 0x06,
+	0xc0, 0x81, 0x9d, 0x0c, 0x78, 0x51, 0x52, 0xb3, 0xf8, 0xb7, 0x19, 0xc1, 0x5a, 0x87, 0xd3, 0x16,
+	0x7a, 0xa5, 0x26, 0x9a, 0xa8, 0xa3, 0xcd, 0x57, 0xa7, 0xa0, 0xaa, 0xf3, 0x23, 0x28, 0x32, 0xe6,
+	0x37, 0x8b, 0xe7, 0x25, 0x4a, 0xc4, 0x32, 0x5f, 0xc2, 0x7a, 0x9e, 0xa3, 0x0c, 0x71, 0x1f, 0x43,
+	0xd9, 0x8e, 0xbc, 0x5e, 0xea, 0x45, 0xd5, 0xbd, 0xaa, 0x70, 0x4d, 0x81, 0xb5, 0x64, 0x47, 0xde,
+	0x33, 0x9a, 0xf9, 0x59, 0x21, 0xf3, 0x33, 0xf3, 0x13, 0x58, 0xb3, 0xe8, 0x69, 0x78, 0x32, 0xa5,
+	0xc1, 0x74, 0xf2, 0x7a, 0x00, 0x2b, 0x02, 0x21, 0xc9, 0x38, 0x7e, 0x0a, 0x15, 0xc9, 0x31, 0x0d,
+	0xf6, 0x39, 0x96, 0x65, 0xc1, 0x32, 0x31, 0x7f, 0x04, 0x5b, 0xb3, 0x81, 0x62, 0x11, 0x9f, 0x17,
+	0xd0, 0x9a, 0x87, 0x2c, 0x59, 0xee, 0x66, 0xae, 0x26, 0x74, 0xbc, 0xb2, 0x20, 0x0c, 0x65, 0x2e,
+	0xf7, 0xaf, 0x1a, 0xd4, 0x78, 0x9c, 0x4c, 0x29, 0xa4, 0x81, 0x54, 0x9b, 0x9f, 0xf4, 0xdb, 0xa0,
+	0xe3, 0x7d, 0xa5, 0x59, 0x38, 0xd7, 0x31, 0x38, 0x1e, 0x69, 0x42, 0xf9, 0x94, 0xc6, 0xc8, 0x58,
+	0x16, 0xc9, 0xe9, 0x90, 0x7c, 0x0a, 0x2b, 0xae, 0x97, 0x9c, 0xf4, 0x8e, 0x63, 0x4a, 0x7b, 0x47,
+	0x63, 0x46, 0x13, 0x19, 0xda, 0xea, 0x08, 0xfe, 0x22, 0xa6, 0xf4, 0x31, 0x02, 0xc9, 0x6d, 0x30,
+	0x38, 0x1e, 0x0b, 0x99, 0xed, 0x4b, 0xc4, 0x12, 0x47, 0x6c, 0x20, 0xfc, 0x15, 0x82, 0x39, 0x26,
+	0x6e, 0x81, 0xac, 0x50, 0x95, 0x2d, 0x28, 0x3b, 0x02, 0x24, 0x15, 0xaa, 0xa9, 0x85, 0xac, 0x95,
+	0x4e, 0x9a, 0x4f, 0xa1, 0xf6, 0xa5, 0x9d, 0x0c, 0xb2, 0x75, 0x33, 0x35, 0xbc, 0x36, 0xa7, 0x86,
+	0x4f, 0xfd, 0x5d, 0x38, 0x8b, 0xf0, 0xf7, 0x37, 0xb0, 0xde, 0x65, 0x61, 0x6c, 0xf7, 0xe9, 0x73,
+	0x7a, 0x4a, 0xfd, 0x44, 0x71, 0x78, 0x86, 0xb9, 0x25, 0x91, 0x17, 0x24, 0x39, 0x42, 0x2b, 0x38,
+	0xe1, 0x28, 0x60, 0x3d, 0xbc, 0x5f, 0x09, 0x57, 0x11, 0xae, 0x5f, 0xe7, 0x60, 0xbc, 0x9c, 0x71,
+	0x1f, 0xf9, 0x73, 0x0d, 0x6a, 0x92, 0xf0, 0x2b, 0x5c, 0xa9, 0xf8, 0x85, 0xce, 0x03, 0xc4, 0x3a,
+	0x94, 0x7c, 0xe4, 0xc8, 0x97, 0x97, 0x2c, 0x31, 0xc8, 0x6a, 0x12, 0x61, 0x7b, 0xfe, 0x8d, 0x98,
+	0xb1, 0xd7, 0x1f, 0x88, 0xb8, 0xb0, 0x6c, 0x89, 0x01, 0x62, 0x72, 0xee, 0xc2, 0xb4, 0xfc, 0x1b,
+	0x61, 0x89, 0xf7, 0x1d, 0xe5, 0x07, 0xba, 0x68, 0xf1, 0x6f, 0xd3, 0x85, 0x9a, 0xaa, 0xe0, 0x84,
+	0xaf, 0xa6, 0xf2, 0x9d, 0xa8, 0x2b, 0xc4, 0x49, 0xd5, 0x4d, 0xb9, 0x14, 0xe7, 0x70, 0xd1, 0x15,
+	0x2e, 0xff, 0xa3, 0xc1, 0xc6, 0x94, 0x1d, 0xe5, 0xce, 0xdc, 0xc1, 0x9b, 0x06, 0x42, 0xe4, 0x91,
+	0x5a, 0x95, 0xd5, 0xed, 0x04, 0xd7, 0x92, 0x08, 0x88, 0x9a, 0x09, 0x31, 0x83, 0xca, 0xad, 0x98,
+	0xc9, 0xb5, 0x05, 0x15, 0x3f, 0x19, 0xf6, 0xb8, 0x1c, 0x45, 0x2e, 0x47, 0xd9, 0x4f, 0x86, 0x5d,
+	0xef, 0x3b, 0x4a, 0xae, 0xc2, 0xf2, 0xa9, 0x1f, 0xf6, 0x7b, 0x8a, 0x8c, 0x15, 0x04, 0xa4, 0x93,
+	0x93, 0x8d, 0x13, 0xa6, 0xab, 0xf8, 0x72, 0xcf, 0xc8, 0x36, 0x54, 0x13, 0x66, 0xfb, 0xb4, 0xc7,
+	0xc3, 0x13, 0xb7, 0xa2, 0x66, 0x01, 0x07, 0x59, 0x08, 0x31, 0x1f, 0x42, 0xed, 0xc9, 0x68, 0x18,
+	0x65, 0xba, 0x11, 0xd0, 0x23, 0x9b, 0x0d, 0xe4, 0x69, 0xe7, 0xdf, 0x68, 0xc9, 0xa3, 0x51, 0xe0,
+	0xfa, 0xe2, 0xc8, 0xd5, 0x2c, 0x39, 0x32, 0xff, 0x45, 0x03, 0x78, 0x4a, 0x59, 0xea, 0x5f, 0xb3,
+	0xf9, 0xf1, 0x67, 0x80, 0x75, 0x44, 0xe2, 0x25, 0x8c, 0x06, 0xce, 0x58, 0x96, 0x25, 0x57, 0xb9,
+	0x09, 0x26, 0xeb, 0xda, 0x9d, 0x09, 0x8a, 0xa5, 0xe2, 0x23, 0xdf, 0xf0, 0xf8, 0x38, 0xa1, 0x4c,
+	0xee, 0x95, 0x1c, 0x89, 0x9b, 0x5e, 0xd0, 0x67, 0x03, 0x79, 0x5a, 0xe5, 0xc8, 0x7c, 0x00, 0x55,
+	0x85, 0x16, 0x16, 0x50, 0x5d, 0x54, 0xd4, 0xf8, 0x80, 0x00, 0x2c, 0x75, 0x59, 0x1c, 0xf2, 0x2a,
+	0x64, 0x0d, 0x56, 0xc4, 0xfd, 0xec, 0x30, 0xa6, 0xc7, 0x34, 0x8e, 0xb1, 0xfe, 0x30, 0xbf, 0x85,
+	0x2a, 0x97, 0x68, 0xd2, 0x4a, 0x10, 0x89, 0x5d, 0xe3, 0x0a, 0x8b, 0x01, 0x5e, 0x7e, 0x86, 0xa1,
+	0xeb, 0x1d, 0x4f, 0x8e, 0x64, 0x41, 0x44, 0x8b, 0x14, 0x9a, 0x9d, 0xc9, 0x6c, 0x13, 0x75, 0xe9,
+	0x4c, 0xff, 0xa1, 0x41, 0xb5, 0xeb, 0xd8, 0xc1, 0x79, 0xc9, 0xe7, 0x3a, 0xc0, 0x09, 0x1d, 0xf7,
+	0x62, 0xda, 0xa7, 0x67, 0x91, 0x3c, 0xd5, 0xcb, 0x27, 0x18, 0xf2, 0x11, 0x80, 0x3e, 0x82, 0xd3,
+	0x7d, 0x3f, 0x3c, 0x4a, 0x63, 0xd9, 0x09, 0x1d, 0x3f, 0xf5, 0xc3, 0x23, 0xf2, 0x31, 0x34, 0x86,
+	0x5e, 0xd0, 0xe3, 0x92, 0x4e, 0x1c, 0x45, 0xb7, 0x6a, 0x43, 0x2f, 0x78, 0x83, 0x40, 0xee, 0x2c,
+	0x88, 0x65, 0x9f, 0xa9, 0x58, 0x25, 0x89, 0x65, 0x9f, 0x4d, 0xb0, 0x54, 0x45, 0x13, 0x2f, 0x70,
+	0xc4, 0xf1, 0x53, 0x14, 0xed, 0x22, 0xd0, 0xfc, 0x14, 0x6a, 0x42, 0xa7, 0xc9, 0xad, 0x84, 0x13,
+	0x16, 0xe7, 0xa2, 0x66, 0xc9, 0x91, 0x19, 0x42, 0xfd, 0xe0, 0x2c, 0x0a, 0xe3, 0xcc, 0x53, 0x3e,
+	0x06, 0x3d, 0x71, 0xec, 0x40, 0xc6, 0x43, 0x79, 0x39, 0x9c, 0x58, 0xc7, 0xe2, 0xb3, 0xe4, 0x26,
+	0x54, 0x5d, 0x9a, 0x30, 0x2f, 0xe0, 0x99, 0x35, 0x6d, 0xc4, 0x28, 0x20, 0x64, 0x78, 0x1c, 0xc6,
+	0x43, 0x3b, 0x0d, 0x2e, 0x72, 0x64, 0x7e, 0x0e, 0x8d, 0x94, 0xe1, 0x64, 0x43, 0x79, 0x30, 0x93,
+	0xd1, 0x4a, 0x0c, 0x10, 0x2a, 0x82, 0xb9, 0xd8, 0x47, 0x31, 0x30, 0xff, 0xba, 0x00, 0xd0, 0x7d,
+	0x97, 0x5b, 0xe7, 0xca, 0xbe, 0xcc, 0x3b, 0x2e, 0x53, 0x21, 0x4c, 0x95, 0x38, 0xfa, 0x65, 0x4a,
+	0x9c, 0x0e, 0x5e, 0xc1, 0x23, 0xea, 0x4c, 0xca, 0x71, 0x51, 0x21, 0x5d, 0x9b, 0x59, 0xfe, 0xfa,
+	0xab, 0x80, 0xdd, 0xff, 0x8c, 0x6f, 0xab, 0x55, 0x4f, 0xd7, 0x08, 0x1f, 0xfd, 0x29, 0x54, 0x1c,
+	0x6c, 0x9e, 0x25, 0xa3, 0x61, 0x73, 0xe9, 0x1d, 0xcb, 0xef, 0xed, 0x89, 0xe5, 0x19, 0xb6, 0x79,
+	0x0c, 0xf5, 0x27, 0xd4, 0xa7, 0x8c, 0x2e, 0xb6, 0xcf, 0xac, 0x84, 0x85, 0x4b, 0x4b, 0x68, 0xf6,
+	0xf0, 0x30, 0x47, 0x6a, 0xb5, 0x96, 0x84, 0xa3, 0xd8, 0x49, 0x6b, 0x68, 0x39, 0xba, 0x98, 0x93,
+	0xc8, 0xa3, 0x26, 0xea, 0x53, 0x39, 0x42, 0x06, 0x2f, 0xc2, 0x53, 0xfa, 0xfe, 0x18, 0x74, 0xb9,
+	0xdb, 0x7b, 0x71, 0xc6, 0x22, 0xcd, 0x3c, 0xe2, 0xce, 0xce, 0xbf, 0x2f, 0x5b, 0xcc, 0x98, 0xff,
+	0xa4, 0x41, 0xed, 0x70, 0x14, 0xf7, 0x55, 0xb9, 0x8f, 0x3d, 0x3f, 0xad, 0x2e, 0x96, 0x2d, 0x39,
+	0x22, 0x77, 0x24, 0x33, 0x91, 0x77, 0x36, 0xf8, 0x19, 0x53, 0x17, 0xb6, 0xb1, 0xfe, 0xcb, 0xcb,
+	0x50, 0xbc, 0x98, 0x0c, 0xad, 0x9f, 0x43, 0x51, 0xa9, 0x53, 0x95, 0x8d, 0xbf, 0x58, 0x80, 0x34,
+	0xff, 0x4d, 0x03, 0xdd, 0x0a, 0x7d, 0x4a, 0x4c, 0xd0, 0x87, 0x69, 0xa1, 0xd7, 0xd8, 0x6b, 0x88,
+	0x0b, 0x77, 0xe8, 0xd3, 0xf6, 0x0b, 0x5e, 0xed, 0xe1, 0x9c, 0xb2, 0x2f, 0x85, 0xdc, 0xbe, 0x6c,
+	0xc2, 0x52, 0x4c, 0xed, 0x24, 0x2b, 0xea, 0xe4, 0x08, 0x0f, 0xa7, 0x7a, 0x49, 0x15, 0x83, 0x4c,
+	0xc5, 0xd2, 0x05, 0xcd, 0xfc, 0x63, 0xd0, 0x51, 0x06, 0xbc, 0xbf, 0x1e, 0xc6, 0xde, 0xd0, 0x8e,
+	0xc7, 0xe2, 0x32, 0xdb, 0x65, 0x76, 0xe0, 0x1e, 0x8d, 0x0d, 0x0d, 0x53, 0xca, 0x17, 0x71, 0xf8,
+	0x1d, 0x0d, 0x8c, 0x82, 0xf9, 0x4b, 0xa8, 0xa1, 0xd8, 0x6a, 0x01, 0x1b, 0x87, 0x7e, 0xbe, 0x80,
+	0xe5, 0x08, 0x1c, 0x8c, 0x1d, 0xc9, 0x98, 0x46, 0xbe, 0xe7, 0xd8, 0x6c, 0xca, 0x50, 0x2b, 0x13,
+	0xb8, 0x30, 0xd5, 0xef, 0x34, 0x68, 0x60, 0x2c, 0x0a, 0xfd, 0x74, 0xdf, 0xde, 0x8b, 0xd1, 0xe6,
+	0x49, 0xa4, 0xcf, 0x97, 0xe8, 0x17, 0x60, 0x58, 0x29, 0x28, 0x15, 0x29, 0xb3, 0xb9, 0xa6, 0xda,
+	0xfc, 0x26, 0x94, 0xe8, 0x29, 0x0d, 0x98, 0xf4, 0x6d, 0xe0, 0x92, 0x1e, 0x20, 0xc4, 0x12, 0x13,
+	0x78, 0xd9, 0xac, 0xcb, 0x3a, 0x58, 0x34, 0x06, 0xc8, 0xe7, 0x50, 0x49, 0x28, 0x63, 0x5e, 0xd0,
+	0xcf, 0x77, 0xdb, 0x72, 0x58, 0xed, 0xae, 0x44, 0x11, 0x17, 0xf7, 0x6c, 0xc5, 0x44, 0x8e, 0xc2,
+	0xbc, 0xbd, 0xbf, 0xa8, 0x7b, 0x3f, 0x82, 0x7a, 0x8e, 0xc1, 0xa5, 0x2e, 0xfe, 0x1d, 0xd8, 0xc8,
+	0xc9, 0x9a, 0xf9, 0xc4, 0x0e, 0x2c, 0x89, 0x9e, 0x8a, 0xf4, 0x0a, 0x32, 0xab, 0x97, 0x25, 0x31,
+	0xcc, 0xbf, 0xd1, 0xe0, 0x4a, 0x97, 0xb2, 0x29, 0x42, 0xc2, 0xd6, 0x5f, 0xcc, 0x58, 0x68, 0x47,
+	0xe4, 0xcf, 0xf9, 0xf8, 0x8b, 0x6c, 0xf5, 0xc3, 0xb4, 0xfc, 0x1a, 0x08, 0x6f, 0xc1, 0xc9, 0x46,
+	0xc5, 0x82, 0xa6, 0xc3, 0xc5, 0x1b, 0x1c, 0xe6, 0x2d, 0xd8, 0x10, 0x59, 0xe5, 0x1c, 0x9a, 0xe6,
+	0x7f, 0xe9, 0x50, 0xe2, 0x3e, 0x44, 0x3e, 0xca, 0x75, 0xeb, 0x56, 0x26, 0xde, 0xa5, 0xf6, 0xe8,
+	0x6e, 0x83, 0xae, 0xb0, 0x5f, 0x9f, 0xd9, 0xfb, 0xfd, 0x60, 0x6c, 0x71, 0x0c, 0xf2, 0x99, 0x22,
+	0xac, 0x68, 0x5a, 0x37, 0x15, 0x92, 0xa9, 0x58, 0xd2, 0x8a, 0x29, 0x66, 0xe6, 0x5b, 0xfa, 0x05,
+	0xef, 0xa2, 0xf3, 0x1e, 0x23, 0x4a, 0xf3, 0x1f, 0x23, 0x1e, 0x41, 0x3d, 0xc7, 0xf5, 0x52, 0x1b,
+	0xf4, 0x0f, 0x85, 0x77, 0x77, 0x1b, 0x97, 0xa1, 0xc4, 0xfb, 0xe0, 0x46, 0x81, 0x94, 0xa1, 0xd8,
+	0xa5, 0xcc, 0x28, 0x62, 0x44, 0x13, 0x7b, 0x60, 0xe8, 0x64, 0x03, 0x56, 0x67, 0x7a, 0xac, 0x46,
+	0x89, 0x34, 0x61, 0x3d, 0xdd, 0xa6, 0xdc, 0xcc, 0x12, 0xa9, 0xc3, 0x72, 0xd6, 0x2a, 0x35, 0xca,
+	0xc4, 0x80, 0x9a, 0x7a, 0xe3, 0x37, 0x2a, 0xc8, 0x1b, 0xf3, 0xb9, 0xb1, 0x8c, 0x5f, 0x98, 0x78,
+	0x0d, 0x40, 0x8e, 0x22, 0x43, 0x1a, 0x55, 0x52, 0x83, 0x4a, 0xda, 0xa2, 0x33, 0x6a, 0x64, 0x1d,
+	0x8c, 0xe9, 0xd6, 0x96, 0x51, 0x47, 0xaa, 0x6a, 0x5f, 0xc5, 0x68, 0x20, 0x44, 0xed, 0x8c, 0x18,
+	0x2b, 0xa8, 0x19, 0x4f, 0x73, 0x86, 0xc1, 0xe3, 0xb5, 0x88, 0x9d, 0xc6, 0xaa, 0x10, 0x50, 0xc6,
+	0x2d, 0x83, 0x20, 0x83, 0xe9, 0x13, 0x63, 0xac, 0x99, 0xff, 0xac, 0x41, 0xed, 0x1b, 0xec, 0xb6,
+	0x9f, 0x57, 0xa7, 0xe3, 0x23, 0x1e, 0x4d, 0x46, 0x43, 0xda, 0x63, 0xe1, 0x09, 0xcd, 0xca, 0x02,
+	0x01, 0x7b, 0x85, 0x20, 0x72, 0x1f, 0x2a, 0x34, 0x70, 0x42, 0xd7, 0x0b, 0xfa, 0x3c, 0xf4, 0x34,
+	0xe4, 0xdb, 0x9a, 0x4a, 0xbf, 0x7d, 0x20, 0x31, 0xac, 0x0c, 0x17, 0xef, 0x73, 0x58, 0xe3, 0xbb,
+	0xd4, 0x67, 0x36, 0xf7, 0xab, 0x8a, 0x85, 0x45, 0xff, 0x13, 0x1c, 0x9b, 0x1f, 0x43, 0x25, 0x5d,
+	0x82, 0xea, 0xbd, 0xa1, 0xf1, 0x51, 0x98, 0x50, 0x91, 0x9b, 0x3a, 0xe1, 0x30, 0xb2, 0x1d, 0x66,
+	0x68, 0xe6, 0x7f, 0x17, 0xa0, 0x26, 0x47, 0x97, 0x38, 0x2b, 0xdb, 0x50, 0xe5, 0x01, 0x53, 0xb2,
+	0x16, 0x31, 0x14, 0x38, 0x88, 0x33, 0x27, 0x3b, 0xb0, 0x9a, 0x0c, 0xec, 0x98, 0xba, 0x78, 0xd7,
+	0xec, 0x29, 0x35, 0x4f, 0xdd, 0x5a, 0x11, 0x13, 0xcf, 0xe8, 0xf8, 0x50, 0x18, 0x48, 0x3a, 0xab,
+	0xce, 0x2b, 0xe4, 0xbc, 0xb3, 0x96, 0xd4, 0xaa, 0x99, 0xc8, 0x03, 0x2a, 0x1b, 0x76, 0xf8, 0x4d,
+	0x1e, 0x29, 0x47, 0xb1, 0xcc, 0x8f, 0xe2, 0xb6, 0x08, 0x96, 0x8a, 0x4a, 0x0b, 0x4f, 0xe4, 0xbc,
+	0x13, 0x56, 0x79, 0x0f, 0x27, 0xec, 0x5b, 0xa8, 0xf0, 0x9d, 0x7c, 0x6a, 0x47, 0x78, 0x6b, 0x3b,
+	0x8e, 0xc3, 0x61, 0xae, 0x4f, 0xb3, 0x8c, 0x10, 0x51, 0x6c, 0x6f, 0x41, 0x85, 0x85, 0xb9, 0x3c,
+	0x5f, 0x66, 0xa1, 0x98, 0x6a, 0x42, 0xd9, 0x8d, 0xc3, 0x28, 0xa2, 0xae, 0xbc, 0x2e, 0xa6, 0x43,
+	0xf3, 0xef, 0x35, 0xa8, 0x4b, 0x57, 0x91, 0x19, 0x24, 0xcb, 0xa7, 0xda, 0x82, 0x7c, 0xba, 0x20,
+	0xff, 0x6d, 0x43, 0xb1, 0x6f, 0x47, 0xcd, 0xa2, 0x12, 0x81, 0x53, 0xc9, 0x2d, 0x9c, 0x99, 0x71,
+	0x66, 0x7d, 0xd6, 0x99, 0x3f, 0x81, 0x86, 0x23, 0xac, 0xdf, 0xe3, 0xac, 0x12, 0xb9, 0x8b, 0x75,
+	0x47, 0xd9, 0x13, 0x6c, 0x23, 0xae, 0xbc, 0xa0, 0x2c, 0xf6, 0x9c, 0x49, 0xb3, 0xa4, 0x09, 0xe5,
+	0xa1, 0x00, 0xc9, 0xcb, 0x74, 0x3a, 0x34, 0xef, 0x43, 0xed, 0x19, 0x1d, 0xf3, 0xe2, 0xff, 0xd0,
+	0xf6, 0xe2, 0x8b, 0x5e, 0xb4, 0xf6, 0xfe, 0x6f, 0x03, 0x8a, 0xcf, 0xde, 0x74, 0x49, 0x0f, 0xea,
+	0xb9, 0x3f, 0x0e, 0x90, 0xcd, 0x99, 0xf0, 0x7b, 0x80, 0x7f, 0x7a, 0x68, 0x89, 0x73, 0x37, 0xf7,
+	0x4f, 0x06, 0x66, 0xeb, 0xb7, 0xff, 0xf9, 0xbf, 0x7f, 0x59, 0x58, 0x27, 0x64, 0xf7, 0xf4, 0x27,
+	0xbb, 0xbe, 0x44, 0xe9, 0xf1, 0xdb, 0x0e, 0x39, 0x82, 0x46, 0xfe, 0xaf, 0x06, 0x0b, 0x39, 0x5c,
+	0x95, 0x6f, 0x45, 0xf3, 0xfe, 0x97, 0x60, 0x5e, 0xe5, 0x2c, 0x36, 0xc8, 0x1a, 0xb2, 0x88, 0x53,
+	0x1c, 0xc9, 0xa3, 0x23, 0xff, 0x15, 0xb0, 0x88, 0xf2, 0xea, 0xa4, 0xfb, 0x99, 0xd2, 0x33, 0x38,
+	0x3d, 0x20, 0x15, 0xa4, 0xc7, 0x3b, 0xa2, 0x87, 0x22, 0xae, 0x13, 0x71, 0x93, 0x56, 0x1e, 0x25,
+	0x5b, 0x0b, 0xc8, 0x9a, 0x37, 0x38, 0x8d, 0x66, 0xcb, 0x40, 0x1a, 0xb2, 0x03, 0xb9, 0xfb, 0xbd,
+	0xe7, 0xfe, 0xe6, 0xa1, 0xe8, 0xb1, 0x3e, 0x9f, 0x3c, 0xc3, 0x2f, 0x92, 0x6c, 0x3d, 0xd7, 0xc6,
+	0x4c, 0x85, 0x5b, 0xe3, 0x84, 0xeb, 0xa4, 0xaa, 0x10, 0x26, 0xcf, 0x65, 0xb6, 0x21, 0x42, 0x1b,
+	0xf5, 0x05, 0x76, 0xa1, 0x84, 0x4d, 0x4e, 0x88, 0xec, 0xcc, 0x48, 0x48, 0x2c, 0x58, 0xce, 0x5e,
+	0x44, 0xc9, 0xc6, 0xdc, 0xc7, 0xd8, 0xd6, 0xe6, 0x34, 0x58, 0x8a, 0xb7, 0xc9, 0xa9, 0x1a, 0x2d,
+	0x55, 0xbc, 0x87, 0xda, 0x0e, 0xf9, 0x93, 0x99, 0x37, 0xd2, 0x77, 0x6f, 0xf5, 0xfc, 0x37, 0xcc,
+	0x94, 0x3c, 0x69, 0x20, 0xf9, 0x49, 0xb8, 0x21, 0x83, 0x39, 0xe9, 0x94, 0x5c, 0x4f, 0xeb, 0xb6,
+	0xb9, 0x4f, 0x99, 0x0b, 0x0d, 0x73, 0x8d, 0xf3, 0xd8, 0x6c, 0x4d, 0xf1, 0x78, 0xc8, 0xdf, 0x35,
+	0xc9, 0xb7, 0xf3, 0x33, 0xf4, 0x42, 0x75, 0x16, 0x71, 0x91, 0x9a, 0xec, 0x4c, 0x6b, 0x72, 0x08,
+	0x95, 0x6e, 0x60, 0x47, 0xc9, 0x20, 0x64, 0x97, 0xa6, 0xb9, 0xce, 0x69, 0x36, 0x48, 0x0d, 0x69,
+	0x26, 0x29, 0x95, 0x0e, 0xe8, 0xd8, 0xf7, 0x3e, 0xe7, 0x04, 0xa8, 0xad, 0xf1, 0xfc, 0x09, 0xc0,
+	0x9e, 0x37, 0x39, 0x82, 0x7a, 0xae, 0x57, 0x4b, 0xb6, 0x66, 0x7a, 0xb2, 0x69, 0x1f, 0xbc, 0xd5,
+	0x9a, 0x37, 0x35, 0x2f, 0x1c, 0x24, 0x02, 0x65, 0x57, 0xf6, 0x72, 0x3b, 0xa0, 0x63, 0xab, 0xf4,
+	0x1c, 0x41, 0xd5, 0x6e, 0x6a, 0x2a, 0xa8, 0xc9, 0x05, 0x75, 0x71, 0xb1, 0x3d, 0x29, 0x73, 0xc8,
+	0xfa, 0xbc, 0x87, 0xc9, 0x85, 0xd6, 0xbb, 0xc5, 0x69, 0x7d, 0xd8, 0xba, 0x36, 0x7d, 0x20, 0xd4,
+	0xff, 0x54, 0xa1, 0x2f, 0xff, 0x7a, 0xb6, 0x76, 0x22, 0xd7, 0x38, 0xab, 0x05, 0xaf, 0x85, 0xe7,
+	0xb2, 0xbc, 0x32, 0xc3, 0x52, 0x3c, 0xdd, 0x3c, 0x94, 0x4f, 0x38, 0xe4, 0x8f, 0xf3, 0x85, 0x19,
+	0x11, 0xa5, 0xf3, 0x9c, 0x57, 0xb7, 0xd6, 0xd6, 0x9c, 0x19, 0x69, 0xac, 0x2b, 0x9c, 0xdb, 0xaa,
+	0xc9, 0xdd, 0x23, 0x7d, 0xb5, 0x42, 0x85, 0xfe, 0x28, 0x5f, 0xe4, 0x49, 0xea, 0x73, 0x5e, 0xc4,
+	0x16, 0x2a, 0xb2, 0xc5, 0x49, 0xaf, 0xed, 0xac, 0xaa, 0xa4, 0x45, 0x34, 0x79, 0x01, 0x65, 0x41,
+	0x23, 0x39, 0x27, 0xd2, 0x4d, 0x3d, 0xad, 0xe5, 0xbd, 0x39, 0xa5, 0x49, 0xd8, 0xdc, 0x97, 0xfa,
+	0x1b, 0x8b, 0xde, 0xc0, 0xa4, 0xdc, 0xdb, 0x0b, 0xe7, 0x25, 0xb3, 0xeb, 0x9c, 0xd9, 0x15, 0xb2,
+	0xc1, 0x1d, 0x49, 0xc1, 0x13, 0x4a, 0x74, 0x64, 0x43, 0xe5, 0xdd, 0xae, 0xa9, 0xf6, 0x28, 0xf2,
+	0x67, 0x88, 0xb7, 0x25, 0x9e, 0x65, 0xe5, 0x32, 0x59, 0x4b, 0x43, 0x93, 0xd2, 0x78, 0x58, 0x68,
+	0x5c, 0x19, 0xf2, 0x5b, 0x19, 0x25, 0xdc, 0xb3, 0x6f, 0xa6, 0x6f, 0xf6, 0xef, 0x4e, 0xce, 0x73,
+	0xef, 0xcc, 0x26, 0xe1, 0x94, 0x6b, 0x04, 0xb8, 0xff, 0x09, 0x3a, 0xbd, 0xd9, 0xc2, 0x5d, 0x7a,
+	0xf7, 0x82, 0x1b, 0xf0, 0x42, 0xb9, 0x37, 0x38, 0xf5, 0x95, 0x96, 0x42, 0x1d, 0x25, 0xef, 0x40,
+	0xf1, 0x29, 0x65, 0x64, 0x65, 0xea, 0x99, 0xa2, 0x65, 0x4c, 0x00, 0x52, 0x3c, 0xe9, 0x55, 0x84,
+	0x7b, 0x15, 0x56, 0x91, 0xbb, 0xdf, 0x9f, 0xd0, 0xf1, 0xcf, 0x76, 0x76, 0x7e, 0x43, 0x5e, 0x83,
+	0x8e, 0x0d, 0x6d, 0x32, 0xd3, 0xdb, 0x6e, 0xad, 0x2a, 0x10, 0x49, 0xe7, 0x36, 0xa7, 0x63, 0x92,
+	0x75, 0xa4, 0x83, 0xad, 0xef, 0xdd, 0xef, 0x45, 0x71, 0x8d, 0xa4, 0x7e, 0x25, 0xb7, 0x08, 0xe1,
+	0xe4, 0x4b, 0x7e, 0x61, 0x0a, 0x63, 0x46, 0x44, 0xfb, 0x20, 0xd7, 0x56, 0x6f, 0xad, 0xe5, 0x60,
+	0x92, 0xb8, 0xd4, 0xd2, 0xe4, 0x5a, 0x52, 0x3e, 0x87, 0x5a, 0x3e, 0xe7, 0xb7, 0x3e, 0xb2, 0x92,
+	0x6d, 0xf4, 0x05, 0xb3, 0xce, 0xac, 0xae, 0x48, 0xed, 0xeb, 0xf4, 0xea, 0x28, 0xe5, 0xca, 0x75,
+	0x88, 0x2f, 0x76, 0x2a, 0xf3, 0xf6, 0x3b, 0x10, 0xb7, 0x45, 0x69, 0x3f, 0xa5, 0x11, 0x7c, 0x9e,
+	0x17, 0x8a, 0x50, 0xeb, 0x84, 0xd1, 0x18, 0xe5, 0x3a, 0x10, 0x57, 0x4d, 0x49, 0x46, 0x69, 0xf7,
+	0x5e, 0x8c, 0xcc, 0x30, 0x3c, 0xe5, 0xce, 0xbc, 0x07, 0x25, 0x5e, 0x31, 0xcb, 0xfa, 0x45, 0xbd,
+	0xc1, 0xb5, 0x88, 0x0a, 0x92, 0x36, 0xff, 0xe0, 0x0f, 0x34, 0xac, 0xa0, 0x64, 0x29, 0x7c, 0x4e,
+	0x5c, 0x99, 0x2a, 0x98, 0xf3, 0x15, 0x94, 0xac, 0x95, 0x1f, 0x7f, 0xf8, 0xab, 0xed, 0xbe, 0xc7,
+	0x06, 0xa3, 0xa3, 0xb6, 0x13, 0x0e, 0x77, 0x87, 0x61, 0x32, 0x3a, 0xb1, 0x77, 0x1d, 0xca, 0x26,
+	0x7f, 0xd1, 0x3d, 0x5a, 0xe2, 0x5f, 0xf7, 0xfe, 0x7f, 0x00, 0xc3, 0x66, 0x2c, 0x2d, 0x4e, 0x2c,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// KVSClient is the client API for KVS service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type KVSClient interface {
+	LivenessCheck(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LivenessCheckResponse, error)
+	ReadinessCheck(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ReadinessCheckResponse, error)
+	Node(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*NodeResponse, error)
+	Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Cluster(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ClusterResponse, error)
+	Leave(ctx context.Context, in *LeaveRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	BatchJoin(ctx context.Context, in *BatchJoinRequest, opts ...grpc.CallOption) (*BatchJoinResponse, error)
+	MembershipSpec(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MembershipSpecResponse, error)
+	SetMembershipSpec(ctx context.Context, in *SetMembershipSpecRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	DeleteMembershipSpec(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	Snapshot(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	Hash(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*HashResponse, error)
+	StorageLevels(ctx context.Context, in *StorageLevelsRequest, opts ...grpc.CallOption) (*StorageLevelsResponse, error)
+	Dump(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*DumpResponse, error)
+	Annotate(ctx context.Context, in *AnnotateRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	UpdateNodeStatus(ctx context.Context, in *UpdateNodeStatusRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	APIKeys(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*APIKeysResponse, error)
+	DecommissionStatus(ctx context.Context, in *DecommissionStatusRequest, opts ...grpc.CallOption) (*DecommissionStatusResponse, error)
+	Role(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*RoleResponse, error)
+	SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ClusterConfig(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ClusterConfigResponse, error)
+	SetClusterConfig(ctx context.Context, in *SetClusterConfigRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error)
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Copy(ctx context.Context, in *CopyRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (KVS_WatchClient, error)
+	Metrics(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MetricsResponse, error)
+}
+
+type kVSClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKVSClient(cc grpc.ClientConnInterface) KVSClient {
+	return &kVSClient{cc}
+}
+
+func (c *kVSClient) LivenessCheck(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*LivenessCheckResponse, error) {
+	out := new(LivenessCheckResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/LivenessCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) ReadinessCheck(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ReadinessCheckResponse, error) {
+	out := new(ReadinessCheckResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/ReadinessCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Node(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*NodeResponse, error) {
+	out := new(NodeResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Node", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Join", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Cluster(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ClusterResponse, error) {
+	out := new(ClusterResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Cluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Leave(ctx context.Context, in *LeaveRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Leave", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) BatchJoin(ctx context.Context, in *BatchJoinRequest, opts ...grpc.CallOption) (*BatchJoinResponse, error) {
+	out := new(BatchJoinResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/BatchJoin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) MembershipSpec(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MembershipSpecResponse, error) {
+	out := new(MembershipSpecResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/MembershipSpec", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) SetMembershipSpec(ctx context.Context, in *SetMembershipSpecRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/SetMembershipSpec", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) DeleteMembershipSpec(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/DeleteMembershipSpec", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Snapshot(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := c.cc.Invoke(ctx, "/kvs.KVS/Snapshot", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *kVSClient) Hash(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*HashResponse, error) {
+	out := new(HashResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Hash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) StorageLevels(ctx context.Context, in *StorageLevelsRequest, opts ...grpc.CallOption) (*StorageLevelsResponse, error) {
+	out := new(StorageLevelsResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/StorageLevels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Dump(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*DumpResponse, error) {
+	out := new(DumpResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Dump", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Annotate(ctx context.Context, in *AnnotateRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Annotate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) UpdateNodeStatus(ctx context.Context, in *UpdateNodeStatusRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/UpdateNodeStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/CreateAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/RevokeAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) APIKeys(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*APIKeysResponse, error) {
+	out := new(APIKeysResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/APIKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) DecommissionStatus(ctx context.Context, in *DecommissionStatusRequest, opts ...grpc.CallOption) (*DecommissionStatusResponse, error) {
+	out := new(DecommissionStatusResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/DecommissionStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Role(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*RoleResponse, error) {
+	out := new(RoleResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Role", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) SetRole(ctx context.Context, in *SetRoleRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/SetRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) ClusterConfig(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ClusterConfigResponse, error) {
+	out := new(ClusterConfigResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/ClusterConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) SetClusterConfig(ctx context.Context, in *SetClusterConfigRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/SetClusterConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error) {
+	out := new(ScanResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Scan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	out := new(ExportResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Export", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Set", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Copy(ctx context.Context, in *CopyRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Copy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Move", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kVSClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (KVS_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_KVS_serviceDesc.Streams[0], "/kvs.KVS/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kVSWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type KVS_WatchClient interface {
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type kVSWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *kVSWatchClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *kVSClient) Metrics(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MetricsResponse, error) {
+	out := new(MetricsResponse)
+	err := c.cc.Invoke(ctx, "/kvs.KVS/Metrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KVSServer is the server API for KVS service.
+type KVSServer interface {
+	LivenessCheck(context.Context, *empty.Empty) (*LivenessCheckResponse, error)
+	ReadinessCheck(context.Context, *empty.Empty) (*ReadinessCheckResponse, error)
+	Node(context.Context, *empty.Empty) (*NodeResponse, error)
+	Join(context.Context, *JoinRequest) (*empty.Empty, error)
+	Cluster(context.Context, *empty.Empty) (*ClusterResponse, error)
+	Leave(context.Context, *LeaveRequest) (*empty.Empty, error)
+	BatchJoin(context.Context, *BatchJoinRequest) (*BatchJoinResponse, error)
+	MembershipSpec(context.Context, *empty.Empty) (*MembershipSpecResponse, error)
+	SetMembershipSpec(context.Context, *SetMembershipSpecRequest) (*empty.Empty, error)
+	DeleteMembershipSpec(context.Context, *empty.Empty) (*empty.Empty, error)
+	Snapshot(context.Context, *empty.Empty) (*empty.Empty, error)
+	Hash(context.Context, *empty.Empty) (*HashResponse, error)
+	StorageLevels(context.Context, *StorageLevelsRequest) (*StorageLevelsResponse, error)
+	Dump(context.Context, *empty.Empty) (*DumpResponse, error)
+	Annotate(context.Context, *AnnotateRequest) (*empty.Empty, error)
+	UpdateNodeStatus(context.Context, *UpdateNodeStatusRequest) (*empty.Empty, error)
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*empty.Empty, error)
+	APIKeys(context.Context, *empty.Empty) (*APIKeysResponse, error)
+	DecommissionStatus(context.Context, *DecommissionStatusRequest) (*DecommissionStatusResponse, error)
+	Role(context.Context, *empty.Empty) (*RoleResponse, error)
+	SetRole(context.Context, *SetRoleRequest) (*empty.Empty, error)
+	ClusterConfig(context.Context, *empty.Empty) (*ClusterConfigResponse, error)
+	SetClusterConfig(context.Context, *SetClusterConfigRequest) (*empty.Empty, error)
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	Scan(context.Context, *ScanRequest) (*ScanResponse, error)
+	Export(context.Context, *ExportRequest) (*ExportResponse, error)
+	Set(context.Context, *SetRequest) (*empty.Empty, error)
+	Delete(context.Context, *DeleteRequest) (*empty.Empty, error)
+	Copy(context.Context, *CopyRequest) (*empty.Empty, error)
+	Move(context.Context, *MoveRequest) (*empty.Empty, error)
+	Watch(*WatchRequest, KVS_WatchServer) error
+	Metrics(context.Context, *empty.Empty) (*MetricsResponse, error)
+}
+
+// UnimplementedKVSServer can be embedded to have forward compatible implementations.
+type UnimplementedKVSServer struct {
+}
+
+func (*UnimplementedKVSServer) LivenessCheck(ctx context.Context, req *empty.Empty) (*LivenessCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LivenessCheck not implemented")
+}
+func (*UnimplementedKVSServer) ReadinessCheck(ctx context.Context, req *empty.Empty) (*ReadinessCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadinessCheck not implemented")
+}
+func (*UnimplementedKVSServer) Node(ctx context.Context, req *empty.Empty) (*NodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Node not implemented")
+}
+func (*UnimplementedKVSServer) Join(ctx context.Context, req *JoinRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Join not implemented")
+}
+func (*UnimplementedKVSServer) Cluster(ctx context.Context, req *empty.Empty) (*ClusterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cluster not implemented")
+}
+func (*UnimplementedKVSServer) Leave(ctx context.Context, req *LeaveRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Leave not implemented")
+}
+func (*UnimplementedKVSServer) BatchJoin(ctx context.Context, req *BatchJoinRequest) (*BatchJoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchJoin not implemented")
+}
+func (*UnimplementedKVSServer) MembershipSpec(ctx context.Context, req *empty.Empty) (*MembershipSpecResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MembershipSpec not implemented")
+}
+func (*UnimplementedKVSServer) SetMembershipSpec(ctx context.Context, req *SetMembershipSpecRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMembershipSpec not implemented")
+}
+func (*UnimplementedKVSServer) DeleteMembershipSpec(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMembershipSpec not implemented")
+}
+func (*UnimplementedKVSServer) Snapshot(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (*UnimplementedKVSServer) Hash(ctx context.Context, req *empty.Empty) (*HashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hash not implemented")
+}
+func (*UnimplementedKVSServer) StorageLevels(ctx context.Context, req *StorageLevelsRequest) (*StorageLevelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StorageLevels not implemented")
+}
+func (*UnimplementedKVSServer) Dump(ctx context.Context, req *empty.Empty) (*DumpResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Dump not implemented")
+}
+func (*UnimplementedKVSServer) Annotate(ctx context.Context, req *AnnotateRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Annotate not implemented")
+}
+func (*UnimplementedKVSServer) UpdateNodeStatus(ctx context.Context, req *UpdateNodeStatusRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNodeStatus not implemented")
+}
+func (*UnimplementedKVSServer) CreateAPIKey(ctx context.Context, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
+}
+func (*UnimplementedKVSServer) RevokeAPIKey(ctx context.Context, req *RevokeAPIKeyRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (*UnimplementedKVSServer) APIKeys(ctx context.Context, req *empty.Empty) (*APIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method APIKeys not implemented")
+}
+func (*UnimplementedKVSServer) DecommissionStatus(ctx context.Context, req *DecommissionStatusRequest) (*DecommissionStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecommissionStatus not implemented")
+}
+func (*UnimplementedKVSServer) Role(ctx context.Context, req *empty.Empty) (*RoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Role not implemented")
+}
+func (*UnimplementedKVSServer) SetRole(ctx context.Context, req *SetRoleRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRole not implemented")
+}
+func (*UnimplementedKVSServer) ClusterConfig(ctx context.Context, req *empty.Empty) (*ClusterConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClusterConfig not implemented")
+}
+func (*UnimplementedKVSServer) SetClusterConfig(ctx context.Context, req *SetClusterConfigRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetClusterConfig not implemented")
+}
+func (*UnimplementedKVSServer) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedKVSServer) Scan(ctx context.Context, req *ScanRequest) (*ScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (*UnimplementedKVSServer) Export(ctx context.Context, req *ExportRequest) (*ExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (*UnimplementedKVSServer) Set(ctx context.Context, req *SetRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (*UnimplementedKVSServer) Delete(ctx context.Context, req *DeleteRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedKVSServer) Copy(ctx context.Context, req *CopyRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Copy not implemented")
+}
+func (*UnimplementedKVSServer) Move(ctx context.Context, req *MoveRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Move not implemented")
+}
+func (*UnimplementedKVSServer) Watch(req *WatchRequest, srv KVS_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (*UnimplementedKVSServer) Metrics(ctx context.Context, req *empty.Empty) (*MetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Metrics not implemented")
+}
+
+func RegisterKVSServer(s *grpc.Server, srv KVSServer) {
+	s.RegisterService(&_KVS_serviceDesc, srv)
+}
+
+func _KVS_LivenessCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).LivenessCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/LivenessCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).LivenessCheck(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *kVSClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
-	out := new(GetResponse)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Get", in, out, opts...)
-	if err != nil {
+func _KVS_ReadinessCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(KVSServer).ReadinessCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/ReadinessCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).ReadinessCheck(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *kVSClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error) {
-	out := new(ScanResponse)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Scan", in, out, opts...)
-	if err != nil {
+func _KVS_Node_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(KVSServer).Node(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Node",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Node(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *kVSClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Set", in, out, opts...)
-	if err != nil {
+func _KVS_Join_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).Join(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Join",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Join(ctx, req.(*JoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_Cluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).Cluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Cluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Cluster(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_Leave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).Leave(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Leave",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Leave(ctx, req.(*LeaveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_BatchJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchJoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).BatchJoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/BatchJoin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).BatchJoin(ctx, req.(*BatchJoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_MembershipSpec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).MembershipSpec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/MembershipSpec",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).MembershipSpec(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_SetMembershipSpec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMembershipSpecRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).SetMembershipSpec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/SetMembershipSpec",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).SetMembershipSpec(ctx, req.(*SetMembershipSpecRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_DeleteMembershipSpec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).DeleteMembershipSpec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/DeleteMembershipSpec",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).DeleteMembershipSpec(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_Snapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).Snapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Snapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Snapshot(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_Hash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(KVSServer).Hash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Hash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Hash(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *kVSClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Delete", in, out, opts...)
-	if err != nil {
+func _KVS_StorageLevels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageLevelsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(KVSServer).StorageLevels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/StorageLevels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).StorageLevels(ctx, req.(*StorageLevelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *kVSClient) Watch(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (KVS_WatchClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_KVS_serviceDesc.Streams[0], "/kvs.KVS/Watch", opts...)
-	if err != nil {
+func _KVS_Dump_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &kVSWatchClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(KVSServer).Dump(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Dump",
 	}
-	return x, nil
-}
-
-type KVS_WatchClient interface {
-	Recv() (*WatchResponse, error)
-	grpc.ClientStream
-}
-
-type kVSWatchClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Dump(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *kVSWatchClient) Recv() (*WatchResponse, error) {
-	m := new(WatchResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _KVS_Annotate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnotateRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(KVSServer).Annotate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Annotate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Annotate(ctx, req.(*AnnotateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *kVSClient) Metrics(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*MetricsResponse, error) {
-	out := new(MetricsResponse)
-	err := c.cc.Invoke(ctx, "/kvs.KVS/Metrics", in, out, opts...)
-	if err != nil {
+func _KVS_UpdateNodeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNodeStatusRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// KVSServer is the server API for KVS service.
-type KVSServer interface {
-	LivenessCheck(context.Context, *empty.Empty) (*LivenessCheckResponse, error)
-	ReadinessCheck(context.Context, *empty.Empty) (*ReadinessCheckResponse, error)
-	Node(context.Context, *empty.Empty) (*NodeResponse, error)
-	Join(context.Context, *JoinRequest) (*empty.Empty, error)
-	Cluster(context.Context, *empty.Empty) (*ClusterResponse, error)
-	Leave(context.Context, *LeaveRequest) (*empty.Empty, error)
-	Snapshot(context.Context, *empty.Empty) (*empty.Empty, error)
-	Get(context.Context, *GetRequest) (*GetResponse, error)
-	Scan(context.Context, *ScanRequest) (*ScanResponse, error)
-	Set(context.Context, *SetRequest) (*empty.Empty, error)
-	Delete(context.Context, *DeleteRequest) (*empty.Empty, error)
-	Watch(*empty.Empty, KVS_WatchServer) error
-	Metrics(context.Context, *empty.Empty) (*MetricsResponse, error)
-}
-
-// UnimplementedKVSServer can be embedded to have forward compatible implementations.
-type UnimplementedKVSServer struct {
-}
-
-func (*UnimplementedKVSServer) LivenessCheck(ctx context.Context, req *empty.Empty) (*LivenessCheckResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method LivenessCheck not implemented")
-}
-func (*UnimplementedKVSServer) ReadinessCheck(ctx context.Context, req *empty.Empty) (*ReadinessCheckResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReadinessCheck not implemented")
-}
-func (*UnimplementedKVSServer) Node(ctx context.Context, req *empty.Empty) (*NodeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Node not implemented")
-}
-func (*UnimplementedKVSServer) Join(ctx context.Context, req *JoinRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Join not implemented")
-}
-func (*UnimplementedKVSServer) Cluster(ctx context.Context, req *empty.Empty) (*ClusterResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Cluster not implemented")
-}
-func (*UnimplementedKVSServer) Leave(ctx context.Context, req *LeaveRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Leave not implemented")
-}
-func (*UnimplementedKVSServer) Snapshot(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
-}
-func (*UnimplementedKVSServer) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
-}
-func (*UnimplementedKVSServer) Scan(ctx context.Context, req *ScanRequest) (*ScanResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
-}
-func (*UnimplementedKVSServer) Set(ctx context.Context, req *SetRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
-}
-func (*UnimplementedKVSServer) Delete(ctx context.Context, req *DeleteRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
-}
-func (*UnimplementedKVSServer) Watch(req *empty.Empty, srv KVS_WatchServer) error {
-	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
-}
-func (*UnimplementedKVSServer) Metrics(ctx context.Context, req *empty.Empty) (*MetricsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Metrics not implemented")
-}
-
-func RegisterKVSServer(s *grpc.Server, srv KVSServer) {
-	s.RegisterService(&_KVS_serviceDesc, srv)
+	if interceptor == nil {
+		return srv.(KVSServer).UpdateNodeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/UpdateNodeStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).UpdateNodeStatus(ctx, req.(*UpdateNodeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _KVS_LivenessCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _KVS_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KVSServer).LivenessCheck(ctx, in)
+		return srv.(KVSServer).CreateAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/kvs.KVS/LivenessCheck",
+		FullMethod: "/kvs.KVS/CreateAPIKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KVSServer).LivenessCheck(ctx, req.(*empty.Empty))
+		return srv.(KVSServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KVS_ReadinessCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _KVS_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KVSServer).ReadinessCheck(ctx, in)
+		return srv.(KVSServer).RevokeAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/kvs.KVS/ReadinessCheck",
+		FullMethod: "/kvs.KVS/RevokeAPIKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KVSServer).ReadinessCheck(ctx, req.(*empty.Empty))
+		return srv.(KVSServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KVS_Node_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _KVS_APIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KVSServer).Node(ctx, in)
+		return srv.(KVSServer).APIKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/kvs.KVS/Node",
+		FullMethod: "/kvs.KVS/APIKeys",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KVSServer).Node(ctx, req.(*empty.Empty))
+		return srv.(KVSServer).APIKeys(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KVS_Join_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(JoinRequest)
+func _KVS_DecommissionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KVSServer).Join(ctx, in)
+		return srv.(KVSServer).DecommissionStatus(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/kvs.KVS/Join",
+		FullMethod: "/kvs.KVS/DecommissionStatus",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KVSServer).Join(ctx, req.(*JoinRequest))
+		return srv.(KVSServer).DecommissionStatus(ctx, req.(*DecommissionStatusRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KVS_Cluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _KVS_Role_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KVSServer).Cluster(ctx, in)
+		return srv.(KVSServer).Role(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/kvs.KVS/Cluster",
+		FullMethod: "/kvs.KVS/Role",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KVSServer).Cluster(ctx, req.(*empty.Empty))
+		return srv.(KVSServer).Role(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KVS_Leave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LeaveRequest)
+func _KVS_SetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRoleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KVSServer).Leave(ctx, in)
+		return srv.(KVSServer).SetRole(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/kvs.KVS/Leave",
+		FullMethod: "/kvs.KVS/SetRole",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KVSServer).Leave(ctx, req.(*LeaveRequest))
+		return srv.(KVSServer).SetRole(ctx, req.(*SetRoleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _KVS_Snapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _KVS_ClusterConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(KVSServer).Snapshot(ctx, in)
+		return srv.(KVSServer).ClusterConfig(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/kvs.KVS/Snapshot",
+		FullMethod: "/kvs.KVS/ClusterConfig",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KVSServer).Snapshot(ctx, req.(*empty.Empty))
+		return srv.(KVSServer).ClusterConfig(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_SetClusterConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetClusterConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).SetClusterConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/SetClusterConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).SetClusterConfig(ctx, req.(*SetClusterConfigRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1453,6 +5043,24 @@ func _KVS_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KVS_Export_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).Export(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Export",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Export(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KVS_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetRequest)
 	if err := dec(in); err != nil {
@@ -1489,8 +5097,44 @@ func _KVS_Delete_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KVS_Copy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).Copy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Copy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Copy(ctx, req.(*CopyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KVS_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KVSServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kvs.KVS/Move",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KVSServer).Move(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KVS_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(empty.Empty)
+	m := new(WatchRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -1556,10 +5200,78 @@ var _KVS_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Leave",
 			Handler:    _KVS_Leave_Handler,
 		},
+		{
+			MethodName: "BatchJoin",
+			Handler:    _KVS_BatchJoin_Handler,
+		},
+		{
+			MethodName: "MembershipSpec",
+			Handler:    _KVS_MembershipSpec_Handler,
+		},
+		{
+			MethodName: "SetMembershipSpec",
+			Handler:    _KVS_SetMembershipSpec_Handler,
+		},
+		{
+			MethodName: "DeleteMembershipSpec",
+			Handler:    _KVS_DeleteMembershipSpec_Handler,
+		},
 		{
 			MethodName: "Snapshot",
 			Handler:    _KVS_Snapshot_Handler,
 		},
+		{
+			MethodName: "Hash",
+			Handler:    _KVS_Hash_Handler,
+		},
+		{
+			MethodName: "StorageLevels",
+			Handler:    _KVS_StorageLevels_Handler,
+		},
+		{
+			MethodName: "Dump",
+			Handler:    _KVS_Dump_Handler,
+		},
+		{
+			MethodName: "Annotate",
+			Handler:    _KVS_Annotate_Handler,
+		},
+		{
+			MethodName: "UpdateNodeStatus",
+			Handler:    _KVS_UpdateNodeStatus_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _KVS_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _KVS_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "APIKeys",
+			Handler:    _KVS_APIKeys_Handler,
+		},
+		{
+			MethodName: "DecommissionStatus",
+			Handler:    _KVS_DecommissionStatus_Handler,
+		},
+		{
+			MethodName: "Role",
+			Handler:    _KVS_Role_Handler,
+		},
+		{
+			MethodName: "SetRole",
+			Handler:    _KVS_SetRole_Handler,
+		},
+		{
+			MethodName: "ClusterConfig",
+			Handler:    _KVS_ClusterConfig_Handler,
+		},
+		{
+			MethodName: "SetClusterConfig",
+			Handler:    _KVS_SetClusterConfig_Handler,
+		},
 		{
 			MethodName: "Get",
 			Handler:    _KVS_Get_Handler,
@@ -1568,6 +5280,10 @@ var _KVS_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Scan",
 			Handler:    _KVS_Scan_Handler,
 		},
+		{
+			MethodName: "Export",
+			Handler:    _KVS_Export_Handler,
+		},
 		{
 			MethodName: "Set",
 			Handler:    _KVS_Set_Handler,
@@ -1576,6 +5292,14 @@ var _KVS_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _KVS_Delete_Handler,
 		},
+		{
+			MethodName: "Copy",
+			Handler:    _KVS_Copy_Handler,
+		},
+		{
+			MethodName: "Move",
+			Handler:    _KVS_Move_Handler,
+		},
 		{
 			MethodName: "Metrics",
 			Handler:    _KVS_Metrics_Handler,
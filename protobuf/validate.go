@@ -0,0 +1,344 @@
+package protobuf
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+)
+
+// Size limits of the underlying key value store.
+const (
+	MaxKeySize   = 65000
+	MaxValueSize = 1<<30 - 1
+)
+
+// ValidationError describes a malformed field of a request.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+func invalid(field string, format string, a ...interface{}) error {
+	return &ValidationError{
+		Field:  field,
+		Reason: fmt.Sprintf(format, a...),
+	}
+}
+
+func validateKey(field string, key string) error {
+	if key == "" {
+		return invalid(field, "must not be empty")
+	}
+	if len(key) > MaxKeySize {
+		return invalid(field, "must be at most %d bytes, got %d", MaxKeySize, len(key))
+	}
+
+	return nil
+}
+
+func validateID(field string, id string) error {
+	if id == "" {
+		return invalid(field, "must not be empty")
+	}
+
+	return nil
+}
+
+func validateAddress(field string, address string) error {
+	if address == "" {
+		return invalid(field, "must not be empty")
+	}
+
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return invalid(field, "%q is not in host:port format", address)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return invalid(field, "%q has an invalid port", address)
+	}
+
+	return nil
+}
+
+func validateNodes(field string, nodes map[string]*Node) error {
+	if len(nodes) == 0 {
+		return invalid(field, "must contain at least one node")
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := validateID(field+" key", id); err != nil {
+			return err
+		}
+		if err := nodes[id].validate(fmt.Sprintf("%s[%s]", field, id)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Metadata) validate(field string) error {
+	if m == nil {
+		return nil
+	}
+	if m.GrpcAddress != "" {
+		if err := validateAddress(field+".grpc_address", m.GrpcAddress); err != nil {
+			return err
+		}
+	}
+	if m.HttpAddress != "" {
+		if err := validateAddress(field+".http_address", m.HttpAddress); err != nil {
+			return err
+		}
+	}
+	if m.ReadOnlyGrpcAddress != "" {
+		if err := validateAddress(field+".read_only_grpc_address", m.ReadOnlyGrpcAddress); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Node) validate(field string) error {
+	if m == nil {
+		return invalid(field, "must not be empty")
+	}
+	if err := validateAddress(field+".raft_address", m.RaftAddress); err != nil {
+		return err
+	}
+
+	return m.Metadata.validate(field + ".metadata")
+}
+
+func (m *JoinRequest) Validate() error {
+	if err := validateID("id", m.Id); err != nil {
+		return err
+	}
+
+	return m.Node.validate("node")
+}
+
+func (m *LeaveRequest) Validate() error {
+	return validateID("id", m.Id)
+}
+
+func (m *AnnotateRequest) Validate() error {
+	if err := validateID("id", m.Id); err != nil {
+		return err
+	}
+	if len(m.Annotations) == 0 {
+		return invalid("annotations", "must contain at least one annotation")
+	}
+	for key := range m.Annotations {
+		if key == "" {
+			return invalid("annotations", "keys must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func (m *UpdateNodeStatusRequest) Validate() error {
+	if err := validateID("id", m.Id); err != nil {
+		return err
+	}
+	if m.Status == nil {
+		return invalid("status", "must not be empty")
+	}
+
+	return m.Status.Metadata.validate("status.metadata")
+}
+
+func (m *CreateAPIKeyRequest) Validate() error {
+	if len(m.Prefix) > MaxKeySize {
+		return invalid("prefix", "must be at most %d bytes, got %d", MaxKeySize, len(m.Prefix))
+	}
+	if m.Ttl == nil {
+		return invalid("ttl", "must not be empty")
+	}
+	ttl, err := ptypes.Duration(m.Ttl)
+	if err != nil {
+		return invalid("ttl", "%v", err)
+	}
+	if ttl <= 0 {
+		return invalid("ttl", "must be positive, got %s", ttl)
+	}
+
+	return nil
+}
+
+func (m *RevokeAPIKeyRequest) Validate() error {
+	return validateID("id", m.Id)
+}
+
+func (m *DecommissionStatusRequest) Validate() error {
+	return validateID("id", m.Id)
+}
+
+func (m *BatchJoinRequest) Validate() error {
+	return validateNodes("nodes", m.Nodes)
+}
+
+func (m *SetMembershipSpecRequest) Validate() error {
+	if m.Spec == nil {
+		return invalid("spec", "must not be empty")
+	}
+
+	return validateNodes("spec.nodes", m.Spec.Nodes)
+}
+
+func (m *SetRoleRequest) Validate() error {
+	if _, ok := Role_Mode_name[int32(m.Mode)]; !ok {
+		return invalid("mode", "unknown mode %d", m.Mode)
+	}
+	if m.Mode == Role_Standby {
+		return validateAddress("source", m.Source)
+	}
+	if m.Source != "" {
+		return invalid("source", "only a standby has a source")
+	}
+
+	return nil
+}
+
+func (m *SetClusterConfigRequest) Validate() error {
+	if len(m.Settings) == 0 {
+		return invalid("settings", "must contain at least one setting")
+	}
+	for key := range m.Settings {
+		if key == "" {
+			return invalid("settings", "keys must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func (m *GetRequest) Validate() error {
+	if err := validateKey("key", m.Key); err != nil {
+		return err
+	}
+	if _, ok := GetRequest_Consistency_name[int32(m.Consistency)]; !ok {
+		return invalid("consistency", "unknown consistency %d", m.Consistency)
+	}
+
+	return nil
+}
+
+func (m *ScanRequest) Validate() error {
+	return m.validate("")
+}
+
+func (m *ScanRequest) validate(field string) error {
+	if m == nil {
+		return nil
+	}
+	if len(m.Prefix) > MaxKeySize {
+		return invalid(field+"prefix", "must be at most %d bytes, got %d", MaxKeySize, len(m.Prefix))
+	}
+	if _, err := regexp.Compile(m.KeyRegexp); err != nil {
+		return invalid(field+"key_regexp", "%v", err)
+	}
+	if _, err := path.Match(m.KeyGlob, ""); err != nil {
+		return invalid(field+"key_glob", "%q is not a valid pattern", m.KeyGlob)
+	}
+	if m.MaxValueSize > 0 && m.MaxValueSize < m.MinValueSize {
+		return invalid(field+"max_value_size", "must not be less than min_value_size %d, got %d", m.MinValueSize, m.MaxValueSize)
+	}
+
+	return nil
+}
+
+func (m *ExportRequest) Validate() error {
+	if err := m.Scan.validate("scan."); err != nil {
+		return err
+	}
+	if m.Destination == "" {
+		return invalid("destination", "must not be empty")
+	}
+	u, err := url.Parse(m.Destination)
+	if err != nil {
+		return invalid("destination", "%q is not a valid URL", m.Destination)
+	}
+	switch u.Scheme {
+	case "file", "http", "https":
+	default:
+		return invalid("destination", "unsupported scheme %q, use file, http or https", u.Scheme)
+	}
+	switch m.Format {
+	case "", "jsonl":
+	default:
+		return invalid("format", "%q is not supported, use jsonl", m.Format)
+	}
+
+	return nil
+}
+
+func (m *SetRequest) Validate() error {
+	if err := validateKey("key", m.Key); err != nil {
+		return err
+	}
+	if len(m.Value) > MaxValueSize {
+		return invalid("value", "must be at most %d bytes, got %d", MaxValueSize, len(m.Value))
+	}
+	if m.Ttl != nil {
+		ttl, err := ptypes.Duration(m.Ttl)
+		if err != nil {
+			return invalid("ttl", "%v", err)
+		}
+		if ttl <= 0 {
+			return invalid("ttl", "must be positive, got %s", ttl)
+		}
+	}
+
+	return nil
+}
+
+func (m *DeleteRequest) Validate() error {
+	return validateKey("key", m.Key)
+}
+
+// validateCopy checks the source and the destination of a copy or a move. The prefixes
+// must not overlap, otherwise the copied keys would fall under the source prefix.
+func validateCopy(source string, destination string, prefix bool) error {
+	if err := validateKey("source", source); err != nil {
+		return err
+	}
+	if err := validateKey("destination", destination); err != nil {
+		return err
+	}
+	if source == destination {
+		return invalid("destination", "must differ from the source")
+	}
+	if prefix && (strings.HasPrefix(source, destination) || strings.HasPrefix(destination, source)) {
+		return invalid("destination", "%q overlaps the source prefix %q", destination, source)
+	}
+
+	return nil
+}
+
+func (m *CopyRequest) Validate() error {
+	return validateCopy(m.Source, m.Destination, m.Prefix)
+}
+
+func (m *MoveRequest) Validate() error {
+	return validateCopy(m.Source, m.Destination, m.Prefix)
+}
@@ -0,0 +1,92 @@
+package protobuf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/duration"
+)
+
+func TestValidate(t *testing.T) {
+	node := &Node{
+		RaftAddress: ":7000",
+		Metadata: &Metadata{
+			GrpcAddress: ":9000",
+			HttpAddress: ":8000",
+		},
+	}
+
+	tests := []struct {
+		name string
+		req  interface{ Validate() error }
+		err  string
+	}{
+		{"valid set", &SetRequest{Key: "a", Value: []byte("1")}, ""},
+		{"negative ttl", &SetRequest{Key: "a", Ttl: &duration.Duration{Seconds: -1}}, "invalid ttl: must be positive, got -1s"},
+		{"empty key", &SetRequest{Value: []byte("1")}, "invalid key: must not be empty"},
+		{"long key", &GetRequest{Key: strings.Repeat("a", MaxKeySize+1)}, "invalid key: must be at most 65000 bytes, got 65001"},
+		{"unknown consistency", &GetRequest{Key: "a", Consistency: 10}, "invalid consistency: unknown consistency 10"},
+		{"empty delete key", &DeleteRequest{}, "invalid key: must not be empty"},
+		{"valid copy", &CopyRequest{Source: "/a/", Destination: "/b/", Prefix: true}, ""},
+		{"empty copy destination", &CopyRequest{Source: "a"}, "invalid destination: must not be empty"},
+		{"copy to itself", &MoveRequest{Source: "a", Destination: "a"}, "invalid destination: must differ from the source"},
+		{"overlapping move", &MoveRequest{Source: "/a/", Destination: "/a/b/", Prefix: true}, `invalid destination: "/a/b/" overlaps the source prefix "/a/"`},
+		{"empty scan prefix", &ScanRequest{}, ""},
+		{"bad scan regexp", &ScanRequest{KeyRegexp: "a("}, "invalid key_regexp: error parsing regexp: missing closing ): `a(`"},
+		{"bad scan glob", &ScanRequest{KeyGlob: "a["}, `invalid key_glob: "a[" is not a valid pattern`},
+		{"valid export", &ExportRequest{Scan: &ScanRequest{Prefix: "a"}, Destination: "file:///tmp/a.jsonl"}, ""},
+		{"bad export scan", &ExportRequest{Scan: &ScanRequest{KeyGlob: "a["}, Destination: "file:///tmp/a.jsonl"}, `invalid scan.key_glob: "a[" is not a valid pattern`},
+		{"bad export destination", &ExportRequest{Destination: "s3://bucket/a.jsonl"}, `invalid destination: unsupported scheme "s3", use file, http or https`},
+		{"bad export format", &ExportRequest{Destination: "file:///tmp/a.parquet", Format: "parquet"}, `invalid format: "parquet" is not supported, use jsonl`},
+		{"bad scan value size", &ScanRequest{MinValueSize: 10, MaxValueSize: 5}, "invalid max_value_size: must not be less than min_value_size 10, got 5"},
+		{"valid join", &JoinRequest{Id: "node1", Node: node}, ""},
+		{"empty id", &JoinRequest{Node: node}, "invalid id: must not be empty"},
+		{"missing node", &JoinRequest{Id: "node1"}, "invalid node: must not be empty"},
+		{"bad raft address", &JoinRequest{Id: "node1", Node: &Node{RaftAddress: "7000"}}, `invalid node.raft_address: "7000" is not in host:port format`},
+		{"bad port", &JoinRequest{Id: "node1", Node: &Node{RaftAddress: ":70000"}}, `invalid node.raft_address: ":70000" has an invalid port`},
+		{"bad metadata", &JoinRequest{Id: "node1", Node: &Node{RaftAddress: ":7000", Metadata: &Metadata{HttpAddress: "x"}}}, `invalid node.metadata.http_address: "x" is not in host:port format`},
+		{"empty leave", &LeaveRequest{}, "invalid id: must not be empty"},
+		{"empty annotations", &AnnotateRequest{Id: "node1"}, "invalid annotations: must contain at least one annotation"},
+		{"empty annotation key", &AnnotateRequest{Id: "node1", Annotations: map[string]string{"": "a"}}, "invalid annotations: keys must not be empty"},
+		{"missing status", &UpdateNodeStatusRequest{Id: "node1"}, "invalid status: must not be empty"},
+		{"bad status metadata", &UpdateNodeStatusRequest{Id: "node1", Status: &NodeStatus{Metadata: &Metadata{GrpcAddress: "x"}}}, `invalid status.metadata.grpc_address: "x" is not in host:port format`},
+		{"api key without ttl", &CreateAPIKeyRequest{Prefix: "/jobs/"}, "invalid ttl: must not be empty"},
+		{"api key with negative ttl", &CreateAPIKeyRequest{Ttl: &duration.Duration{Seconds: -1}}, "invalid ttl: must be positive, got -1s"},
+		{"valid api key", &CreateAPIKeyRequest{Prefix: "/jobs/", Write: true, Ttl: &duration.Duration{Seconds: 3600}}, ""},
+		{"missing api key id", &RevokeAPIKeyRequest{}, "invalid id: must not be empty"},
+		{"empty batch", &BatchJoinRequest{}, "invalid nodes: must contain at least one node"},
+		{"bad batch node", &BatchJoinRequest{Nodes: map[string]*Node{"node1": node, "node2": {}}}, "invalid nodes[node2].raft_address: must not be empty"},
+		{"missing spec", &SetMembershipSpecRequest{}, "invalid spec: must not be empty"},
+		{"valid spec", &SetMembershipSpecRequest{Spec: &MembershipSpec{Nodes: map[string]*Node{"node1": node}}}, ""},
+	}
+
+	for _, test := range tests {
+		err := test.req.Validate()
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, saw %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s: expected error %q, saw %v", test.name, test.err, err)
+		}
+	}
+}
+
+func TestParseConsistency(t *testing.T) {
+	for s, expected := range map[string]GetRequest_Consistency{
+		"stale":            GetRequest_Stale,
+		"Strong":           GetRequest_Strong,
+		"leader_preferred": GetRequest_LeaderPreferred,
+		"LeaderPreferred":  GetRequest_LeaderPreferred,
+	} {
+		consistency, err := ParseConsistency(s)
+		if err != nil || consistency != expected {
+			t.Errorf("%s: expected %v, saw %v, %v", s, expected, consistency, err)
+		}
+	}
+	if _, err := ParseConsistency("linearizable"); err == nil {
+		t.Errorf("expected an error for an unknown consistency")
+	}
+}
@@ -30,6 +30,13 @@ func TypeNameByInstance(instance interface{}) string {
 	}
 }
 
+// TypeInstanceByName returns a new instance of the registered type.
+// It returns nil if no type is registered with the name.
 func TypeInstanceByName(name string) interface{} {
-	return reflect.New(TypeByName(name)).Interface()
+	typ := TypeByName(name)
+	if typ == nil {
+		return nil
+	}
+
+	return reflect.New(typ).Interface()
 }
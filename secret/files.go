@@ -0,0 +1,162 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Files writes the secrets fetched from commands and Vault to the files of a private
+// directory, for the code that reads TLS keys from files, and fetches them again
+// periodically so that rotated secrets replace the old ones.
+type Files struct {
+	dir string
+
+	mutex sync.Mutex
+	// the secret written for each reference
+	secrets map[string][]byte
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	logger *zap.Logger
+}
+
+func NewFiles(logger *zap.Logger) (*Files, error) {
+	dir, err := ioutil.TempDir("", "cete-secrets-")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Files{
+		dir:     dir,
+		secrets: make(map[string][]byte, 0),
+		logger:  logger,
+	}, nil
+}
+
+// Path returns the path of a file holding the secret. Plain paths and file:// references
+// are returned as is, so that the rotations of those files are seen by their readers.
+func (f *Files) Path(ctx context.Context, ref string) (string, error) {
+	if !IsReference(ref) {
+		return ref, nil
+	}
+	if strings.HasPrefix(ref, fileScheme) {
+		return strings.TrimPrefix(ref, fileScheme), nil
+	}
+
+	data, err := Fetch(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.write(ref, data); err != nil {
+		return "", err
+	}
+	f.secrets[ref] = data
+
+	return f.path(ref), nil
+}
+
+// path names the file after a hash of the reference, which may contain any character.
+func (f *Files) path(ref string) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%x", sha256.Sum256([]byte(ref))))
+}
+
+// write replaces the file atomically, so that it is never read half written.
+func (f *Files) write(ref string, data []byte) error {
+	tmp, err := ioutil.TempFile(f.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path(ref))
+}
+
+// Refresh fetches the secrets again and rewrites the files of the ones that changed.
+func (f *Files) Refresh(ctx context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var lastErr error
+	for ref, old := range f.secrets {
+		data, err := Fetch(ctx, ref)
+		if err != nil {
+			// keep the old secret, it may still be valid
+			f.logger.Warn("failed to refresh secret", zap.String("file", f.path(ref)), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		if bytes.Equal(data, old) {
+			continue
+		}
+		if err := f.write(ref, data); err != nil {
+			f.logger.Error("failed to write secret", zap.String("file", f.path(ref)), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		f.secrets[ref] = data
+		f.logger.Info("secret rotated", zap.String("file", f.path(ref)))
+	}
+
+	return lastErr
+}
+
+// Start refreshes the secrets at the interval until Close is called.
+func (f *Files) Start(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	f.stopCh = make(chan struct{})
+	f.doneCh = make(chan struct{})
+	go func() {
+		defer close(f.doneCh)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-f.stopCh:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				_ = f.Refresh(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// Close stops refreshing the secrets and removes their files.
+func (f *Files) Close() error {
+	if f.stopCh != nil {
+		close(f.stopCh)
+		<-f.doneCh
+	}
+
+	return os.RemoveAll(f.dir)
+}
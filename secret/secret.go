@@ -0,0 +1,75 @@
+// Package secret fetches secrets such as TLS keys from files, commands and
+// HashiCorp Vault, so that they need not be written in flags or config files.
+package secret
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+const (
+	fileScheme  = "file://"
+	execScheme  = "exec://"
+	vaultScheme = "vault://"
+)
+
+// IsReference reports whether s refers to a secret rather than being a plain path.
+func IsReference(s string) bool {
+	for _, scheme := range []string{fileScheme, execScheme, vaultScheme} {
+		if strings.HasPrefix(s, scheme) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fetch returns the secret the reference refers to:
+//
+//	file:///path/to/file       the content of the file
+//	exec://command arg...      the standard output of the command
+//	vault://mount/path#field   the field of a secret of the Vault server in VAULT_ADDR
+//
+// A reference without a scheme is a path to a file.
+func Fetch(ctx context.Context, ref string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(ref, execScheme):
+		return fetchExec(ctx, strings.TrimPrefix(ref, execScheme))
+	case strings.HasPrefix(ref, vaultScheme):
+		u, err := url.Parse(ref)
+		if err != nil {
+			return nil, err
+		}
+		v, err := newVaultFromEnvironment()
+		if err != nil {
+			return nil, err
+		}
+		return v.fetch(ctx, u)
+	default:
+		return ioutil.ReadFile(strings.TrimPrefix(ref, fileScheme))
+	}
+}
+
+// fetchExec runs the command without a shell and returns its standard output.
+func fetchExec(ctx context.Context, command string) ([]byte, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no command in %q", execScheme+command)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,119 @@
+package secret
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestFetch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cete-secret-test-")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	path := filepath.Join(dir, "secret")
+	if err := ioutil.WriteFile(path, []byte("s3cr3t"), 0600); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for _, ref := range []string{path, "file://" + path, "exec://cat " + path} {
+		data, err := Fetch(context.Background(), ref)
+		if err != nil || string(data) != "s3cr3t" {
+			t.Errorf("expected %s to be fetched, saw %q %v", ref, data, err)
+		}
+	}
+
+	if _, err := Fetch(context.Background(), "exec://false"); err == nil {
+		t.Errorf("expected a failing command to fail")
+	}
+}
+
+func TestVaultFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/cete":
+			_, _ = w.Write([]byte(`{"data":{"data":{"certificate":"cert","key":"key"},"metadata":{"version":2}}}`))
+		case "/v1/kv/cete":
+			_, _ = w.Write([]byte(`{"data":{"key":"key"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	v := &vault{address: server.URL, token: "token", client: server.Client()}
+	for _, test := range []struct {
+		ref      string
+		expected string
+		valid    bool
+	}{
+		{"vault://secret/data/cete#certificate", "cert", true},
+		{"vault://kv/cete", "key", true},
+		{"vault://secret/data/cete", "", false},
+		{"vault://secret/data/cete#password", "", false},
+		{"vault://secret/data/missing#key", "", false},
+	} {
+		u, err := url.Parse(test.ref)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		data, err := v.fetch(context.Background(), u)
+		if (err == nil) != test.valid || string(data) != test.expected {
+			t.Errorf("expected %s to be %q, saw %q %v", test.ref, test.expected, data, err)
+		}
+	}
+}
+
+func TestFilesRefresh(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cete-secret-test-")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	source := filepath.Join(dir, "source")
+	if err := ioutil.WriteFile(source, []byte("v1"), 0600); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	f, err := NewFiles(zap.NewNop())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	path, err := f.Path(context.Background(), "exec://cat "+source)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := ioutil.WriteFile(source, []byte("v2"), 0600); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := f.Refresh(context.Background()); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if data, err := ioutil.ReadFile(path); err != nil || string(data) != "v2" {
+		t.Errorf("expected the secret to be rotated, saw %q %v", data, err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the secret files to be removed, saw %v", err)
+	}
+}
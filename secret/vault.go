@@ -0,0 +1,138 @@
+package secret
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// vault reads secrets with the HTTP API of a Vault server.
+type vault struct {
+	address   string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// newVaultFromEnvironment configures the Vault client the same way as the vault CLI,
+// with VAULT_ADDR, VAULT_TOKEN (or ~/.vault-token), VAULT_NAMESPACE and VAULT_CACERT.
+func newVaultFromEnvironment() (*vault, error) {
+	address := os.Getenv("VAULT_ADDR")
+	if address == "" {
+		return nil, fmt.Errorf("VAULT_ADDR is not set")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadFile(filepath.Join(home, ".vault-token"))
+		if err != nil {
+			return nil, fmt.Errorf("VAULT_TOKEN is not set: %v", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	client := http.DefaultClient
+	if caCertificate := os.Getenv("VAULT_CACERT"); caCertificate != "" {
+		pem, err := ioutil.ReadFile(caCertificate)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate in %s", caCertificate)
+		}
+		client = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			},
+		}
+	}
+
+	return &vault{
+		address:   strings.TrimSuffix(address, "/"),
+		token:     token,
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		client:    client,
+	}, nil
+}
+
+// fetch reads vault://mount/path#field. Both versions of the KV secrets engine are
+// supported, e.g. vault://secret/data/cete#key reads the key field of the cete secret
+// of a KV version 2 engine mounted at secret/. The field may be omitted if the secret
+// has a single field.
+func (v *vault) fetch(ctx context.Context, ref *url.URL) ([]byte, error) {
+	path := strings.Trim(ref.Host+ref.Path, "/")
+	if path == "" {
+		return nil, fmt.Errorf("no secret path in %s", ref.String())
+	}
+
+	req, err := http.NewRequest(http.MethodGet, v.address+"/v1/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read %s from Vault: %s", path, resp.Status)
+	}
+
+	body := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	data := body.Data
+	// KV version 2 nests the secret with its metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	field := ref.Fragment
+	if field == "" {
+		if len(data) != 1 {
+			return nil, fmt.Errorf("the secret %s has %d fields, choose one with #field", path, len(data))
+		}
+		for name := range data {
+			field = name
+		}
+	}
+	value, ok := data[field]
+	if !ok {
+		return nil, fmt.Errorf("the secret %s has no field %q", path, field)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("the field %q of the secret %s is not a string", field, path)
+	}
+
+	return []byte(s), nil
+}
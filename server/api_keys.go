@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// operations allowed without a token, so that probes need no credentials
+	publicOperations = map[string]bool{
+		"LivenessCheck":  true,
+		"ReadinessCheck": true,
+	}
+	// operations allowed to the API keys on the keys with their prefix
+	apiKeyReadOperations = map[string]bool{
+		"Get":   true,
+		"Scan":  true,
+		"Watch": true,
+	}
+	apiKeyWriteOperations = map[string]bool{
+		"Set":    true,
+		"Delete": true,
+		"Copy":   true,
+		"Move":   true,
+	}
+)
+
+// CreateAPIKey creates an API key and returns it along with its secret, which is not
+// recorded and can not be retrieved afterwards.
+func (s *RaftServer) CreateAPIKey(req *protobuf.CreateAPIKeyRequest) (*protobuf.CreateAPIKeyResponse, error) {
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := ptypes.Duration(req.Ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	createdAt, err := ptypes.TimestampProto(now)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := ptypes.TimestampProto(now.Add(ttl))
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(secret))
+	apiKey := &protobuf.APIKey{
+		Id:        id,
+		Prefix:    req.Prefix,
+		Write:     req.Write,
+		CreatedAt: createdAt,
+		ExpiresAt: expiresAt,
+		Hash:      hash[:],
+	}
+
+	if err := s.propose(context.Background(), protobuf.Event_CreateAPIKey, apiKey); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("API key created", zap.String("id", id), zap.String("prefix", req.Prefix), zap.Bool("write", req.Write), zap.Duration("ttl", ttl))
+
+	return &protobuf.CreateAPIKeyResponse{
+		ApiKey: withoutHash(apiKey),
+		Key:    id + "." + secret,
+	}, nil
+}
+
+// RevokeAPIKey deletes the API key, the requests presenting it are refused from then on.
+func (s *RaftServer) RevokeAPIKey(req *protobuf.RevokeAPIKeyRequest) error {
+	apiKey, err := s.fsm.APIKey(req.Id)
+	if err != nil {
+		return err
+	}
+	if apiKey == nil {
+		return errors.Wrapf(errors.ErrNotFound, "API key %s", req.Id)
+	}
+
+	if err := s.propose(context.Background(), protobuf.Event_RevokeAPIKey, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("id", req.Id), zap.Error(err))
+		return err
+	}
+	s.logger.Info("API key revoked", zap.String("id", req.Id))
+
+	return nil
+}
+
+// APIKeys returns the API keys without the hashes of their secrets.
+func (s *RaftServer) APIKeys() ([]*protobuf.APIKey, error) {
+	apiKeys, err := s.fsm.APIKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, apiKey := range apiKeys {
+		apiKeys[i] = withoutHash(apiKey)
+	}
+
+	return apiKeys, nil
+}
+
+// revokeExpiredAPIKeys makes the leader revoke the expired API keys, along with the
+// expired keys of the key-value store.
+func (s *RaftServer) revokeExpiredAPIKeys() error {
+	apiKeys, err := s.fsm.APIKeys()
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now()
+	for _, apiKey := range apiKeys {
+		expiresAt, err := ptypes.Timestamp(apiKey.ExpiresAt)
+		if err != nil || now.Before(expiresAt) {
+			continue
+		}
+		if err := s.RevokeAPIKey(&protobuf.RevokeAPIKeyRequest{Id: apiKey.Id}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func withoutHash(apiKey *protobuf.APIKey) *protobuf.APIKey {
+	apiKey = proto.Clone(apiKey).(*protobuf.APIKey)
+	apiKey.Hash = nil
+
+	return apiKey
+}
+
+func randomString(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encode(b), nil
+}
+
+// bearerToken returns the token of the authorization metadata of the request, i.e. the
+// Authorization header of a RESTful API request.
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get("authorization") {
+		if strings.HasPrefix(value, "Bearer ") {
+			return strings.TrimPrefix(value, "Bearer ")
+		}
+	}
+
+	return ""
+}
+
+// tokenAuthorizer requires every request but the health checks to present the token or
+// an API key allowed to run it, then consults the next authorizer if any. Nodes present
+// the token to each other, so that the requests forwarded to the leader are allowed
+// once authorized by the node that received them.
+func tokenAuthorizer(token string, raftServer *RaftServer, next Authorizer) Authorizer {
+	return func(ctx context.Context, identity string, operation string, key string) error {
+		if !publicOperations[operation] {
+			if err := authenticate(ctx, token, raftServer, operation, key); err != nil {
+				return err
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+		return next(ctx, identity, operation, key)
+	}
+}
+
+func authenticate(ctx context.Context, token string, raftServer *RaftServer, operation string, key string) error {
+	presented := bearerToken(ctx)
+	if presented == "" {
+		return errors.Wrap(errors.ErrUnauthenticated, "missing bearer token")
+	}
+	if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1 {
+		return nil
+	}
+
+	parts := strings.SplitN(presented, ".", 2)
+	if len(parts) != 2 {
+		return errors.Wrap(errors.ErrUnauthenticated, "invalid bearer token")
+	}
+	apiKey, err := raftServer.fsm.APIKey(parts[0])
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256([]byte(parts[1]))
+	if apiKey == nil || subtle.ConstantTimeCompare(hash[:], apiKey.Hash) != 1 {
+		return errors.Wrap(errors.ErrUnauthenticated, "invalid bearer token")
+	}
+
+	return authorizeAPIKey(apiKey, raftServer.clock.Now(), operation, key)
+}
+
+// authorizeAPIKey checks that the API key has not expired and is allowed to run the
+// operation on the key.
+func authorizeAPIKey(apiKey *protobuf.APIKey, now time.Time, operation string, key string) error {
+	expiresAt, err := ptypes.Timestamp(apiKey.ExpiresAt)
+	if err != nil || !now.Before(expiresAt) {
+		return errors.Wrapf(errors.ErrUnauthenticated, "API key %s expired", apiKey.Id)
+	}
+
+	write := apiKeyWriteOperations[operation]
+	switch {
+	case !write && !apiKeyReadOperations[operation]:
+		return fmt.Errorf("API key %s may not call %s", apiKey.Id, operation)
+	case write && !apiKey.Write:
+		return fmt.Errorf("API key %s is read-only", apiKey.Id)
+	case !strings.HasPrefix(key, apiKey.Prefix):
+		return fmt.Errorf("API key %s may not access %q", apiKey.Id, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAuthorizeAPIKey(t *testing.T) {
+	now := time.Date(2020, 10, 7, 10, 0, 0, 0, time.UTC)
+	expiresAt, _ := ptypes.TimestampProto(now.Add(time.Hour))
+	readOnly := &protobuf.APIKey{Id: "r", Prefix: "/jobs/", ExpiresAt: expiresAt}
+	readWrite := &protobuf.APIKey{Id: "rw", Prefix: "/jobs/", Write: true, ExpiresAt: expiresAt}
+
+	for _, test := range []struct {
+		name      string
+		apiKey    *protobuf.APIKey
+		now       time.Time
+		operation string
+		key       string
+		allowed   bool
+	}{
+		{"read in prefix", readOnly, now, "Get", "/jobs/a", true},
+		{"scan of prefix", readOnly, now, "Scan", "/jobs/", true},
+		{"scan of all keys", readOnly, now, "Scan", "", false},
+		{"read out of prefix", readOnly, now, "Get", "/users/a", false},
+		{"write with read-only key", readOnly, now, "Set", "/jobs/a", false},
+		{"write in prefix", readWrite, now, "Delete", "/jobs/a", true},
+		{"admin operation", readWrite, now, "Leave", "", false},
+		{"expired", readWrite, now.Add(time.Hour), "Get", "/jobs/a", false},
+	} {
+		err := authorizeAPIKey(test.apiKey, test.now, test.operation, test.key)
+		if test.allowed && err != nil {
+			t.Errorf("%s: expected the request to be allowed, saw %v", test.name, err)
+		}
+		if !test.allowed && err == nil {
+			t.Errorf("%s: expected the request to be denied", test.name)
+		}
+	}
+}
+
+func TestTokenAuthorizer(t *testing.T) {
+	authorizer := tokenAuthorizer("secret", nil, nil)
+
+	if err := authorizer(context.Background(), "", "LivenessCheck", ""); err != nil {
+		t.Errorf("expected the health check to be allowed without a token, saw %v", err)
+	}
+
+	err := authorize(context.Background(), authorizer, "/kvs.KVS/Get", &protobuf.GetRequest{Key: "a"})
+	if !errors.Is(err, errors.ErrUnauthenticated) || errors.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected the request without a token to be unauthenticated, saw %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer secret"))
+	if err := authorize(ctx, authorizer, "/kvs.KVS/Leave", &protobuf.LeaveRequest{Id: "node1"}); err != nil {
+		t.Errorf("expected the request with the token to be allowed, saw %v", err)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer other"))
+	if err := authorize(ctx, authorizer, "/kvs.KVS/Get", &protobuf.GetRequest{Key: "a"}); !errors.Is(err, errors.ErrUnauthenticated) {
+		t.Errorf("expected the request with another token to be unauthenticated, saw %v", err)
+	}
+}
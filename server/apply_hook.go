@@ -0,0 +1,86 @@
+package server
+
+import (
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// applyHookBufferSize is the number of events queued for an asynchronous hook, the FSM
+// waits for the hook once they are full.
+const applyHookBufferSize = 1024
+
+// ApplyHook is called with the events applied by the FSM along with the index of their
+// log entry, so that an application embedding Cete maintains derived structures without
+// watching itself over gRPC. The event must not be modified.
+type ApplyHook func(index uint64, event *protobuf.Event)
+
+type applyHook struct {
+	prefix string
+	hook   ApplyHook
+	// the events of an asynchronous hook, nil for a synchronous hook
+	ch     chan *appliedEvent
+	doneCh chan struct{}
+}
+
+// applyHooks calls the hooks of the prefixes written by the applied events.
+type applyHooks struct {
+	hooks  []*applyHook
+	logger *zap.Logger
+}
+
+func newApplyHooks(hooks []*applyHook, logger *zap.Logger) *applyHooks {
+	h := &applyHooks{
+		hooks:  hooks,
+		logger: logger,
+	}
+	for _, hook := range hooks {
+		if hook.ch == nil {
+			continue
+		}
+		go func(hook *applyHook) {
+			defer close(hook.doneCh)
+			for applied := range hook.ch {
+				h.run(hook, applied)
+			}
+		}(hook)
+	}
+
+	return h
+}
+
+// call calls the synchronous hooks and queues the event for the asynchronous ones. It is
+// called by the FSM in the order of the log.
+func (h *applyHooks) call(applied *appliedEvent) {
+	for _, hook := range h.hooks {
+		if !watchesEvent(hook.prefix, applied.event) {
+			continue
+		}
+		if hook.ch == nil {
+			h.run(hook, applied)
+			continue
+		}
+		hook.ch <- applied
+	}
+}
+
+// run calls the hook, a panicking hook must not stop the FSM.
+func (h *applyHooks) run(hook *applyHook, applied *appliedEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.logger.Error("apply hook panicked", zap.String("prefix", hook.prefix), zap.Uint64("index", applied.index), zap.String("type", applied.event.Type.String()), zap.Any("panic", r))
+		}
+	}()
+
+	hook.hook(applied.index, applied.event)
+}
+
+// stop waits for the asynchronous hooks to handle the events queued.
+func (h *applyHooks) stop() {
+	for _, hook := range h.hooks {
+		if hook.ch == nil {
+			continue
+		}
+		close(hook.ch)
+		<-hook.doneCh
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/storage"
+	"go.uber.org/zap"
+)
+
+// bootstrapIndex and bootstrapTerm are the index and term of the snapshot of the
+// existing data, the log of the cluster starts after it.
+const (
+	bootstrapIndex = 1
+	bootstrapTerm  = 1
+)
+
+// BootstrapDataDirectory turns the Badger data of a standalone node into the data of
+// a single node cluster in place. It writes a snapshot of the key value store whose
+// configuration has the node as its only voter, so that the node elects itself when it
+// starts, and the nodes joining later receive the existing data with the snapshot. The
+// options locate the stores the same way as for the Raft server, the key value store is
+// usually given with WithStorageDirectories.
+// It fails if the node already has Raft state, or if the key value store is in use.
+func BootstrapDataDirectory(id string, raftAddress string, dataDirectory string, logger *zap.Logger, opts ...RaftServerOption) error {
+	o := defaultRaftOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	fsmPath, raftDirectory, snapshotDirectory := o.directories(dataDirectory)
+	if _, err := os.Stat(fsmPath); err != nil {
+		logger.Error("failed to find key value store", zap.String("path", fsmPath), zap.Error(err))
+		return err
+	}
+	if files, err := ioutil.ReadDir(raftDirectory); err == nil && len(files) > 0 {
+		return fmt.Errorf("raft directory %s is not empty, the node has already been started", raftDirectory)
+	}
+
+	snapshotStore, err := raft.NewFileSnapshotStore(snapshotDirectory, 2, ioutil.Discard)
+	if err != nil {
+		logger.Error("failed to create file snapshot store", zap.String("path", snapshotDirectory), zap.Error(err))
+		return err
+	}
+	snapshots, err := snapshotStore.List()
+	if err != nil {
+		logger.Error("failed to list snapshots", zap.String("path", snapshotDirectory), zap.Error(err))
+		return err
+	}
+	if len(snapshots) > 0 {
+		return fmt.Errorf("snapshot directory %s has snapshots, the node has already been started", snapshotDirectory)
+	}
+
+	if _, err := NodeIdentity(dataDirectory, id, nil, opts...); err != nil {
+		return err
+	}
+
+	// the address is the one the node advertises when it bootstraps a cluster
+	addr, err := net.ResolveTCPAddr("tcp", raftAddress)
+	if err != nil {
+		logger.Error("failed to resolve TCP address", zap.String("raft_address", raftAddress), zap.Error(err))
+		return err
+	}
+
+	// the key value store can not be opened while a node is running
+	kvs, err := storage.NewKVS(fsmPath, fsmPath, logger)
+	if err != nil {
+		return fmt.Errorf("key value store %s is in use: %w", fsmPath, err)
+	}
+	defer func() {
+		_ = kvs.Close()
+	}()
+
+	configuration := raft.Configuration{
+		Servers: []raft.Server{
+			{
+				Suffrage: raft.Voter,
+				ID:       raft.ServerID(id),
+				Address:  raft.ServerAddress(addr.String()),
+			},
+		},
+	}
+	// the transport only encodes the peers of the legacy snapshot format
+	_, transport := raft.NewInmemTransport(configuration.Servers[0].Address)
+	defer func() {
+		_ = transport.Close()
+	}()
+	sink, err := snapshotStore.Create(raft.SnapshotVersionMax, bootstrapIndex, bootstrapTerm, configuration, bootstrapIndex, transport)
+	if err != nil {
+		logger.Error("failed to create snapshot", zap.String("path", snapshotDirectory), zap.Error(err))
+		return err
+	}
+
+	snapshot := &KVSFSMSnapshot{
+		snapshot:     kvs.NewSnapshot(),
+		appliedIndex: bootstrapIndex,
+		limiter:      newRateLimiter(o.snapshotRateLimit),
+		logger:       logger,
+	}
+	defer snapshot.Release()
+	if err := snapshot.Persist(sink); err != nil {
+		// the partial snapshot must not be restored
+		_ = os.RemoveAll(filepath.Join(snapshotDirectory, "snapshots"))
+		return err
+	}
+
+	// the key value store already holds the snapshot, the node resumes from it
+	resumeIndex := make([]byte, 8)
+	binary.BigEndian.PutUint64(resumeIndex, bootstrapIndex)
+	if err := kvs.Set(resumeIndexKey, resumeIndex); err != nil {
+		return err
+	}
+
+	logger.Info("data directory bootstrapped", zap.String("id", id), zap.String("raft_address", addr.String()), zap.String("kvs_directory", fsmPath))
+
+	return nil
+}
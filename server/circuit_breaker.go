@@ -0,0 +1,85 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultPeerMinBackoff = 500 * time.Millisecond
+	defaultPeerMaxBackoff = 30 * time.Second
+)
+
+// circuitBreaker guards the calls to a single peer node.
+// After a failure the circuit opens and no calls are allowed until the backoff
+// has elapsed. The backoff doubles on every consecutive failure up to maxBackoff.
+// Once the backoff has elapsed, a single trial call is allowed (half-open) and
+// its result decides whether the circuit closes again or stays open.
+type circuitBreaker struct {
+	clock      Clock
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mutex     sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func newCircuitBreaker(clock Clock, minBackoff time.Duration, maxBackoff time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		clock:      clock,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+	}
+}
+
+func (b *circuitBreaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.failures == 0 {
+		return true
+	}
+
+	if b.trial || b.clock.Now().Before(b.openUntil) {
+		return false
+	}
+
+	// half-open
+	b.trial = true
+	return true
+}
+
+func (b *circuitBreaker) Success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.trial = false
+}
+
+func (b *circuitBreaker) Failure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.trial = false
+
+	backoff := b.minBackoff
+	for i := 1; i < b.failures && backoff < b.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > b.maxBackoff {
+		backoff = b.maxBackoff
+	}
+	b.openUntil = b.clock.Now().Add(backoff)
+}
+
+func (b *circuitBreaker) Open() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.failures > 0 && (b.trial || b.clock.Now().Before(b.openUntil))
+}
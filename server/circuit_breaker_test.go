@@ -0,0 +1,55 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	b := newCircuitBreaker(clock, 50*time.Millisecond, 100*time.Millisecond)
+
+	if !b.Allow() {
+		t.Fatalf("expected closed circuit to allow calls")
+	}
+
+	b.Failure()
+	if b.Allow() {
+		t.Fatalf("expected open circuit to reject calls")
+	}
+	if !b.Open() {
+		t.Fatalf("expected circuit to be open")
+	}
+
+	clock.Advance(60 * time.Millisecond)
+
+	// half-open allows exactly one trial call
+	if !b.Allow() {
+		t.Fatalf("expected half-open circuit to allow a trial call")
+	}
+	if b.Allow() {
+		t.Fatalf("expected half-open circuit to reject concurrent calls")
+	}
+
+	// the backoff doubles and is capped at maxBackoff
+	b.Failure()
+	b.mutex.Lock()
+	backoff := b.openUntil.Sub(clock.Now())
+	b.mutex.Unlock()
+	if backoff != 100*time.Millisecond {
+		t.Fatalf("expected a backoff of 100ms, saw %v", backoff)
+	}
+
+	clock.Advance(99 * time.Millisecond)
+	if b.Allow() {
+		t.Fatalf("expected open circuit to reject calls before the backoff elapsed")
+	}
+	clock.Advance(time.Millisecond)
+	if !b.Allow() {
+		t.Fatalf("expected half-open circuit to allow a trial call")
+	}
+	b.Success()
+	if !b.Allow() || b.Open() {
+		t.Fatalf("expected circuit to be closed after success")
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mosuka/cete/errors"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2020, 10, 7, 10, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	ticker := clock.NewTicker(time.Second)
+	timer := clock.NewTimer(1500 * time.Millisecond)
+	if n := clock.Waiters(); n != 2 {
+		t.Fatalf("expected 2 waiters, saw %d", n)
+	}
+
+	clock.Advance(999 * time.Millisecond)
+	select {
+	case <-ticker.C():
+		t.Fatalf("expected the ticker not to fire before its interval")
+	default:
+	}
+
+	clock.Advance(time.Millisecond)
+	if tick := <-ticker.C(); !tick.Equal(start.Add(time.Second)) {
+		t.Errorf("expected a tick at %v, saw %v", start.Add(time.Second), tick)
+	}
+
+	// the ticks the receiver is too slow for are dropped
+	clock.Advance(3 * time.Second)
+	if tick := <-ticker.C(); !tick.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("expected a tick at %v, saw %v", start.Add(2*time.Second), tick)
+	}
+	select {
+	case tick := <-ticker.C():
+		t.Errorf("expected the other ticks to be dropped, saw %v", tick)
+	default:
+	}
+	if tick := <-timer.C(); !tick.Equal(start.Add(1500 * time.Millisecond)) {
+		t.Errorf("expected the timer to fire at %v, saw %v", start.Add(1500*time.Millisecond), tick)
+	}
+	if timer.Stop() {
+		t.Errorf("expected the timer to have fired")
+	}
+	if now := clock.Now(); !now.Equal(start.Add(4 * time.Second)) {
+		t.Errorf("expected the time to be %v, saw %v", start.Add(4*time.Second), now)
+	}
+
+	ticker.Stop()
+	if n := clock.Waiters(); n != 0 {
+		t.Errorf("expected no waiters, saw %d", n)
+	}
+}
+
+func TestWaitForCompactionsTimeout(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	s := &RaftServer{clock: clock, writeStalled: 1}
+
+	stalled := make(chan bool)
+	go func() {
+		stalled <- s.waitForCompactions(time.Second)
+	}()
+	for clock.Waiters() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the write is held back until the timeout, however long it is in real time
+	clock.Advance(time.Second)
+	if !<-stalled {
+		t.Errorf("expected the writes to still be stalled")
+	}
+}
+
+func TestAcquireBulkSlotTimeout(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	s := &RaftServer{clock: clock, bulkSlots: make(chan struct{}, 1)}
+	s.bulkSlots <- struct{}{}
+
+	errCh := make(chan error)
+	go func() {
+		_, err := s.acquireBulkSlot(time.Second)
+		errCh <- err
+	}()
+	for clock.Waiters() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Advance(time.Second)
+	if err := <-errCh; !errors.Is(err, errors.ErrTimeout) {
+		t.Errorf("expected the bulk write to time out, saw %v", err)
+	}
+}
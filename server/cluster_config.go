@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// clusterConfigKey holds the cluster configuration.
+const clusterConfigKey = systemKeyPrefix + "cluster_config"
+
+// The settings of the cluster configuration read by cete, they override the options of
+// the nodes. The other settings, e.g. feature flags, are left to the applications.
+const (
+	// MB of log entries applied since the last snapshot above which a snapshot is taken
+	snapshotLogSizeSetting = "snapshot_log_size"
+	// maximum time between snapshots while log entries are applied, e.g. 10m
+	snapshotMaxIntervalSetting = "snapshot_max_interval"
+	// comma-separated patterns of the node IDs that may join the cluster
+	peerAllowlistIDsSetting = "peer_allowlist_ids"
+	// comma-separated networks of the Raft addresses that may join the cluster
+	peerAllowlistCIDRsSetting = "peer_allowlist_cidrs"
+)
+
+// clusterSettings are the settings of the cluster configuration read by cete, nil if
+// they are not set.
+type clusterSettings struct {
+	snapshotLogBytes *uint64
+	snapshotInterval *time.Duration
+	peerAllowlist    *PeerAllowlist
+}
+
+func splitSetting(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// parseClusterSettings parses the settings read by cete, the peer allowlist is set if
+// either of its settings is.
+func parseClusterSettings(settings map[string]string) (*clusterSettings, error) {
+	s := &clusterSettings{}
+
+	if value, ok := settings[snapshotLogSizeSetting]; ok {
+		size, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", snapshotLogSizeSetting, err)
+		}
+		logBytes := size * 1024 * 1024
+		s.snapshotLogBytes = &logBytes
+	}
+
+	if value, ok := settings[snapshotMaxIntervalSetting]; ok {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", snapshotMaxIntervalSetting, err)
+		}
+		if interval < 0 {
+			return nil, fmt.Errorf("%s: negative interval %s", snapshotMaxIntervalSetting, value)
+		}
+		s.snapshotInterval = &interval
+	}
+
+	ids, hasIDs := settings[peerAllowlistIDsSetting]
+	cidrs, hasCIDRs := settings[peerAllowlistCIDRsSetting]
+	if hasIDs || hasCIDRs {
+		allowlist, err := NewPeerAllowlist(splitSetting(ids), splitSetting(cidrs))
+		if err != nil {
+			return nil, fmt.Errorf("peer allowlist: %v", err)
+		}
+		s.peerAllowlist = allowlist
+	}
+
+	return s, nil
+}
+
+// ClusterConfig returns the cluster configuration, without settings if it was never set.
+func (f *RaftFSM) ClusterConfig() (*protobuf.ClusterConfig, error) {
+	value, err := f.kvs.Get(clusterConfigKey)
+	if errors.Is(err, errors.ErrNotFound) {
+		return &protobuf.ClusterConfig{Settings: map[string]string{}}, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get cluster configuration", zap.Error(err))
+		return nil, err
+	}
+
+	config := &protobuf.ClusterConfig{}
+	if err := proto.Unmarshal(value, config); err != nil {
+		f.logger.Error("failed to unmarshal cluster configuration", zap.Error(err))
+		return nil, err
+	}
+	if config.Settings == nil {
+		config.Settings = map[string]string{}
+	}
+
+	return config, nil
+}
+
+// mergeSettings applies the changes to the settings, an empty value removes the setting.
+func mergeSettings(settings map[string]string, changes map[string]string) map[string]string {
+	merged := make(map[string]string, len(settings)+len(changes))
+	for key, value := range settings {
+		merged[key] = value
+	}
+	for key, value := range changes {
+		if value == "" {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+
+	return merged
+}
+
+func (f *RaftFSM) applySetClusterConfig(req *protobuf.SetClusterConfigRequest, index uint64, at *timestamp.Timestamp) interface{} {
+	config, err := f.ClusterConfig()
+	if err != nil {
+		return err
+	}
+	config.Settings = mergeSettings(config.Settings, req.Settings)
+	config.Index = index
+	config.Time = at
+
+	value, err := proto.Marshal(config)
+	if err != nil {
+		f.logger.Error("failed to marshal cluster configuration", zap.Error(err))
+		return err
+	}
+	if err := f.kvs.Set(clusterConfigKey, value); err != nil {
+		f.logger.Error("failed to set cluster configuration", zap.Error(err))
+		return err
+	}
+
+	f.logger.Info("cluster configuration set", zap.Any("settings", req.Settings), zap.Uint64("index", index))
+
+	return nil
+}
+
+func (s *RaftServer) ClusterConfig() (*protobuf.ClusterConfig, error) {
+	return s.fsm.ClusterConfig()
+}
+
+// SetClusterConfig changes the settings of the cluster configuration, which every node
+// applies. The settings read by cete are refused with InvalidArgument if they are
+// malformed, or if the peer allowlist would refuse a member of the cluster.
+func (s *RaftServer) SetClusterConfig(ctx context.Context, req *protobuf.SetClusterConfigRequest) error {
+	config, err := s.fsm.ClusterConfig()
+	if err != nil {
+		return err
+	}
+	settings, err := parseClusterSettings(mergeSettings(config.Settings, req.Settings))
+	if err != nil {
+		return errors.Convert(err, codes.InvalidArgument)
+	}
+	if settings.peerAllowlist != nil {
+		nodes, err := s.Nodes()
+		if err != nil {
+			return err
+		}
+		for id, node := range nodes {
+			if err := settings.peerAllowlist.Check(id, node.RaftAddress); err != nil {
+				return errors.Convert(fmt.Errorf("the peer allowlist refuses member %s: %v", id, err), codes.InvalidArgument)
+			}
+		}
+	}
+
+	if err := s.propose(ctx, protobuf.Event_SetClusterConfig, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.Any("settings", req.Settings), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// clusterSettings returns the settings of the cluster configuration read by cete. The
+// settings are ignored if they can not be read, so that the options of the node apply.
+func (s *RaftServer) clusterSettings() *clusterSettings {
+	config, err := s.fsm.ClusterConfig()
+	if err != nil {
+		return &clusterSettings{}
+	}
+	settings, err := parseClusterSettings(config.Settings)
+	if err != nil {
+		s.logger.Warn("failed to parse cluster configuration", zap.Error(err))
+		return &clusterSettings{}
+	}
+
+	return settings
+}
+
+// snapshotSchedule returns the log size and the interval above which a snapshot is taken,
+// from the cluster configuration or else the options of the node.
+func (s *RaftServer) snapshotSchedule() (uint64, time.Duration) {
+	maxLogBytes, maxInterval := s.snapshotLogBytes, s.snapshotInterval
+
+	settings := s.clusterSettings()
+	if settings.snapshotLogBytes != nil {
+		maxLogBytes = *settings.snapshotLogBytes
+	}
+	if settings.snapshotInterval != nil {
+		maxInterval = *settings.snapshotInterval
+	}
+
+	return maxLogBytes, maxInterval
+}
+
+// effectivePeerAllowlist returns the peer allowlist of the cluster configuration or else
+// that of the node, nil if neither is set.
+func (s *RaftServer) effectivePeerAllowlist() *PeerAllowlist {
+	if settings := s.clusterSettings(); settings.peerAllowlist != nil {
+		return settings.peerAllowlist
+	}
+
+	return s.peerAllowlist
+}
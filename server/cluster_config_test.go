@@ -0,0 +1,51 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+func TestClusterConfig(t *testing.T) {
+	s := &RaftServer{fsm: newTestRaftFSM(t), logger: zap.NewNop(), snapshotLogBytes: 64 * 1024 * 1024}
+
+	config, err := s.ClusterConfig()
+	if err != nil || len(config.Settings) != 0 || config.Index != 0 {
+		t.Fatalf("expected an empty cluster configuration, saw %v, %v", config, err)
+	}
+	if logBytes, interval := s.snapshotSchedule(); logBytes != 64*1024*1024 || interval != 0 {
+		t.Errorf("expected the snapshot schedule of the node, saw %d, %s", logBytes, interval)
+	}
+
+	if err := applyTestEvent(t, s.fsm, 1, protobuf.Event_SetClusterConfig, &protobuf.SetClusterConfigRequest{Settings: map[string]string{"snapshot_log_size": "0", "snapshot_max_interval": "10m", "new_scan": "on"}}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, s.fsm, 2, protobuf.Event_SetClusterConfig, &protobuf.SetClusterConfigRequest{Settings: map[string]string{"new_scan": "", "peer_allowlist_ids": "node*"}}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	config, err = s.ClusterConfig()
+	expected := map[string]string{"snapshot_log_size": "0", "snapshot_max_interval": "10m", "peer_allowlist_ids": "node*"}
+	if err != nil || !reflect.DeepEqual(config.Settings, expected) || config.Index != 2 {
+		t.Errorf("expected the settings to be merged at index 2, saw %v, %v", config, err)
+	}
+	if logBytes, interval := s.snapshotSchedule(); logBytes != 0 || interval != 10*time.Minute {
+		t.Errorf("expected the snapshot schedule of the cluster configuration, saw %d, %s", logBytes, interval)
+	}
+	if allowlist := s.effectivePeerAllowlist(); allowlist == nil || allowlist.Check("rogue", "127.0.0.1:7000") == nil {
+		t.Errorf("expected the peer allowlist of the cluster configuration to refuse rogue")
+	}
+
+	for _, settings := range []map[string]string{
+		{"snapshot_log_size": "-1"},
+		{"snapshot_max_interval": "often"},
+		{"peer_allowlist_cidrs": "10.0.0.0"},
+	} {
+		if _, err := parseClusterSettings(settings); err == nil {
+			t.Errorf("expected %v to be refused", settings)
+		}
+	}
+}
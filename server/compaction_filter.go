@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// CompactionFilter tells whether the compaction sweeps delete a key, given its value, the
+// time it was last modified and the time of the sweep. The modification time is zero for
+// the keys last written by older versions. The filters are run by the leader, and the keys
+// dropped are deleted on every replica by a purge event, unless they were modified since.
+type CompactionFilter func(key string, value []byte, modifiedAt time.Time, now time.Time) bool
+
+type compactionFilter struct {
+	name   string
+	prefix string
+	filter CompactionFilter
+}
+
+// RetentionFilter drops the keys not modified for longer than the maximum age. The keys
+// whose modification time is not known are kept.
+func RetentionFilter(maxAge time.Duration) CompactionFilter {
+	return func(key string, value []byte, modifiedAt time.Time, now time.Time) bool {
+		return !modifiedAt.IsZero() && now.Sub(modifiedAt) > maxAge
+	}
+}
+
+// PurgeCandidates returns the keys with the prefix that the filter drops, along with the
+// index of the log entry that last modified them.
+func (f *RaftFSM) PurgeCandidates(prefix string, filter CompactionFilter, now time.Time) ([]*protobuf.PurgeRequest_Key, error) {
+	keys := make([]*protobuf.PurgeRequest_Key, 0)
+	err := f.kvs.Iterate(prefix, func(key string, value []byte) error {
+		if strings.HasPrefix(key, systemKeyPrefix) {
+			return nil
+		}
+		index, modifiedAt, err := f.modified(key)
+		if err != nil {
+			return err
+		}
+		var t time.Time
+		if modifiedAt > 0 {
+			t = time.Unix(0, modifiedAt)
+		}
+		if filter(key, value, t, now) {
+			keys = append(keys, &protobuf.PurgeRequest_Key{Key: key, ModifiedIndex: index})
+		}
+		return nil
+	})
+	if err != nil {
+		f.logger.Error("failed to find the keys to purge", zap.String("prefix", prefix), zap.Error(err))
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// sweepCompactionFilters makes the leader run the compaction filters and replicate the
+// deletion of the keys they drop.
+func (s *RaftServer) sweepCompactionFilters() error {
+	for _, f := range s.compactionFilters {
+		now := s.clock.Now()
+		keys, err := s.fsm.PurgeCandidates(f.prefix, f.filter, now)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		timestamp, err := ptypes.TimestampProto(now)
+		if err != nil {
+			return err
+		}
+		for start := 0; start < len(keys); start += expirationSweepBatchSize {
+			end := start + expirationSweepBatchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+			req := &protobuf.PurgeRequest{
+				Filter: f.name,
+				Keys:   keys[start:end],
+				Time:   timestamp,
+			}
+			if err := s.propose(context.Background(), protobuf.Event_Purge, req); err != nil {
+				return err
+			}
+		}
+		s.logger.Info("purged keys", zap.String("filter", f.name), zap.String("prefix", f.prefix), zap.Int("count", len(keys)), zap.Float64("time", float64(time.Since(now))/float64(time.Second)))
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	"github.com/mosuka/cete/version"
+	"go.uber.org/zap"
+)
+
+// dumpDiagnostics writes a diagnostic bundle to the diagnostics directory of the data
+// directory, to attach to bug reports. It returns the path and the content of the bundle.
+func (s *GRPCService) dumpDiagnostics() (string, []byte, error) {
+	buf := &bytes.Buffer{}
+	if err := s.writeDiagnostics(buf); err != nil {
+		return "", nil, err
+	}
+
+	dir := filepath.Join(s.raftServer.dataDirectory, "diagnostics")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("cete-%s-%s.txt", s.raftServer.id, time.Now().UTC().Format("20060102T150405.000Z")))
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", nil, err
+	}
+	s.logger.Info("diagnostics dumped", zap.String("path", path))
+
+	return path, buf.Bytes(), nil
+}
+
+func (s *GRPCService) writeDiagnostics(w io.Writer) error {
+	_, _ = fmt.Fprintf(w, "id: %s\n", s.raftServer.id)
+	_, _ = fmt.Fprintf(w, "time: %s\n", time.Now().UTC().Format(time.RFC3339Nano))
+	_, _ = fmt.Fprintf(w, "version: %s\n", version.Version)
+	_, _ = fmt.Fprintf(w, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	_, _ = fmt.Fprintf(w, "\n== raft ==\n")
+	if s.raftServer.raft != nil {
+		writeSortedStats(w, s.raftServer.raft.Stats())
+	}
+
+	_, _ = fmt.Fprintf(w, "\n== storage ==\n")
+	lsmSize, vlogSize := s.raftServer.fsm.Size()
+	_, _ = fmt.Fprintf(w, "kvs_lsm_size: %d\nkvs_vlog_size: %d\n", lsmSize, vlogSize)
+	for _, level := range s.raftServer.fsm.Levels() {
+		_, _ = fmt.Fprintf(w, "level %d: %d tables, %d keys, %d bytes\n", level.Level, level.Tables, level.Keys, level.Size)
+	}
+	writeSortedStats(w, s.raftServer.fsm.Stats())
+
+	_, _ = fmt.Fprintf(w, "\n== config ==\n")
+	config, err := json.MarshalIndent(s.diagnosticsConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(w, "%s\n", config)
+
+	_, _ = fmt.Fprintf(w, "\n== slow requests (over %s) ==\n", s.slowRequests.threshold)
+	for _, request := range s.slowRequests.list() {
+		_, _ = fmt.Fprintf(w, "%s %s %q %s %s\n", request.start.UTC().Format(time.RFC3339Nano), request.method, request.key, request.duration, request.code)
+	}
+
+	_, _ = fmt.Fprintf(w, "\n== goroutines ==\n")
+	return pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+func writeSortedStats(w io.Writer, stats map[string]string) {
+	keys := make([]string, 0, len(stats))
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", key, stats[key])
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package server
+
+import "syscall"
+
+// diskUsage returns the bytes available to unprivileged users and the total bytes of
+// the file system holding the directory.
+func diskUsage(dir string) (uint64, uint64, error) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	return stat.Bavail * uint64(stat.Bsize), stat.Blocks * uint64(stat.Bsize), nil
+}
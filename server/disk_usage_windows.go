@@ -0,0 +1,7 @@
+package server
+
+import "errors"
+
+func diskUsage(dir string) (uint64, uint64, error) {
+	return 0, 0, errors.New("disk usage is not supported on windows")
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+const defaultExpirationSweepInterval = time.Minute
+
+// expirationSweepBatchSize is the maximum number of keys deleted by a log entry, so that
+// a sweep of many keys does not hold up the other writes.
+const expirationSweepBatchSize = 1000
+
+// startExpirationSweep makes the leader delete the expired keys. The deletions are
+// replicated as expire events, so that every replica reclaims the same keys.
+func (s *RaftServer) startExpirationSweep(checkInterval time.Duration) {
+	s.logger.Info("start to sweep expired keys", zap.Duration("interval", checkInterval))
+
+	defer func() {
+		close(s.sweepDoneCh)
+	}()
+
+	ticker := s.clock.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.sweepStopCh:
+			s.logger.Info("received a request to stop sweeping expired keys")
+			return
+		case <-ticker.C():
+			if s.raft.State() != raft.Leader {
+				continue
+			}
+
+			if err := s.revokeExpiredAPIKeys(); err != nil {
+				s.logger.Warn("failed to revoke expired API keys", zap.Error(err))
+			}
+
+			// a standby replicates the sweeps of its source
+			if role, err := s.fsm.Role(); err != nil || role.Mode != protobuf.Role_Primary {
+				continue
+			}
+			if err := s.sweepExpiredKeys(); err != nil {
+				s.logger.Warn("failed to sweep expired keys", zap.Error(err))
+			}
+			if err := s.sweepCompactionFilters(); err != nil {
+				s.logger.Warn("failed to run the compaction filters", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *RaftServer) stopExpirationSweep() {
+	if s.sweepStopCh != nil {
+		s.logger.Info("send a request to stop sweeping expired keys")
+		close(s.sweepStopCh)
+	}
+
+	s.logger.Info("wait for the expiration sweep to stop")
+	<-s.sweepDoneCh
+	s.logger.Info("the expiration sweep has been stopped")
+}
+
+func (s *RaftServer) sweepExpiredKeys() error {
+	now := s.clock.Now()
+	keys, err := s.fsm.ExpiredKeys(now)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	timestamp, err := ptypes.TimestampProto(now)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(keys); start += expirationSweepBatchSize {
+		end := start + expirationSweepBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		req := &protobuf.ExpireRequest{
+			Keys: keys[start:end],
+			Time: timestamp,
+		}
+		if err := s.propose(context.Background(), protobuf.Event_Expire, req); err != nil {
+			return err
+		}
+	}
+	s.logger.Info("swept expired keys", zap.Int("count", len(keys)), zap.Float64("time", float64(time.Since(now))/float64(time.Second)))
+
+	return nil
+}
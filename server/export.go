@@ -0,0 +1,189 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+)
+
+// exportDestinations are the destinations a node may export to. Without them the exports
+// are refused, so that the callers of Export can neither overwrite the files of the node
+// nor make it send requests to arbitrary URLs.
+type exportDestinations struct {
+	// the directory, and its subdirectories, of the file destinations
+	directory string
+	// the prefixes of the http(s) destinations, e.g. https://bucket.s3.amazonaws.com/backups/
+	urlPrefixes []string
+}
+
+// check refuses a destination outside the export directory or the URL prefixes with
+// PermissionDenied.
+func (d *exportDestinations) check(destination string) error {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return err
+	}
+
+	switch u.Scheme {
+	case "file":
+		if d.directory == "" {
+			return errors.Wrap(errors.ErrPermissionDenied, "file exports are disabled, the node has no export directory")
+		}
+		if !filepath.IsAbs(u.Path) {
+			return errors.Wrapf(errors.ErrPermissionDenied, "file destination %s is not an absolute path", u.Path)
+		}
+		// resolve the symbolic links, which could lead out of the export directory
+		directory, err := filepath.EvalSymlinks(d.directory)
+		if err != nil {
+			return err
+		}
+		parent, err := filepath.EvalSymlinks(filepath.Dir(filepath.Clean(u.Path)))
+		if err != nil {
+			return err
+		}
+		if rel, err := filepath.Rel(directory, parent); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return errors.Wrapf(errors.ErrPermissionDenied, "file destination %s is not in the export directory", u.Path)
+		}
+	case "http", "https":
+		for _, prefix := range d.urlPrefixes {
+			p, err := url.Parse(prefix)
+			if err != nil {
+				continue
+			}
+			if p.Scheme == u.Scheme && strings.EqualFold(p.Host, u.Host) && u.User == nil && strings.HasPrefix(u.Path, p.Path) {
+				return nil
+			}
+		}
+		return errors.Wrapf(errors.ErrPermissionDenied, "destination %s does not match the export URL prefixes", redactURL(u))
+	}
+
+	return nil
+}
+
+// exportClient uploads the exports. It does not follow redirects, which would send the
+// request to a URL outside the export URL prefixes.
+var exportClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// exportItem is a line of a jsonl export. The value is base64 encoded.
+type exportItem struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// export writes the items selected by the scan to the destination and returns the
+// number of items and bytes written. The result is written to a temporary file first,
+// which is renamed to a file destination or uploaded to an HTTP destination with a PUT
+// request. Uploading a complete file works with presigned object storage URLs, which
+// do not accept chunked requests.
+func export(ctx context.Context, fsm *RaftFSM, scan *protobuf.ScanRequest, destination string) (uint64, uint64, error) {
+	if scan == nil {
+		scan = &protobuf.ScanRequest{}
+	}
+	filter, err := newScanFilter(scan)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	u, err := url.Parse(destination)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dir := os.TempDir()
+	if u.Scheme == "file" {
+		// stay on the same file system, so that the file can be renamed
+		dir = filepath.Dir(u.Path)
+	}
+	f, err := ioutil.TempFile(dir, ".cete-export-")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	count := uint64(0)
+	if err := fsm.Iterate(scan.Prefix, filter, func(key string, value []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		count++
+		return encoder.Encode(&exportItem{Key: key, Value: value})
+	}); err != nil {
+		return 0, 0, err
+	}
+	if err := w.Flush(); err != nil {
+		return 0, 0, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch u.Scheme {
+	case "file":
+		if err := f.Close(); err != nil {
+			return 0, 0, err
+		}
+		if err := os.Rename(f.Name(), u.Path); err != nil {
+			return 0, 0, err
+		}
+	case "http", "https":
+		if _, err := f.Seek(0, 0); err != nil {
+			return 0, 0, err
+		}
+		req, err := http.NewRequest(http.MethodPut, destination, f)
+		if err != nil {
+			return 0, 0, err
+		}
+		req = req.WithContext(ctx)
+		req.ContentLength = info.Size()
+		req.Header.Set("Content-Type", "application/x-ndjson")
+
+		resp, err := exportClient.Do(req)
+		if err != nil {
+			if urlErr, ok := err.(*url.Error); ok {
+				err = urlErr.Err
+			}
+			return 0, 0, fmt.Errorf("failed to upload to %s: %v", redactURL(u), err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return 0, 0, fmt.Errorf("failed to upload to %s: %s", redactURL(u), resp.Status)
+		}
+	default:
+		return 0, 0, fmt.Errorf("unsupported destination scheme %q", u.Scheme)
+	}
+
+	return count, uint64(info.Size()), nil
+}
+
+// redactURL drops the query string and the user info, which may hold credentials
+// such as the signature of a presigned URL.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	redacted.RawQuery = ""
+	redacted.Fragment = ""
+	return redacted.String()
+}
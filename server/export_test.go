@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+)
+
+func TestExport(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+	for i, key := range []string{"/users/1", "/users/2", "/groups/1"} {
+		if err := fsm.applySetValue(key, []byte("value"), 0, uint64(i+1), 0); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	path := filepath.Join(testTempDir(t), "export.jsonl")
+	count, _, err := export(context.Background(), fsm, &protobuf.ScanRequest{Prefix: "/users/"}, "file://"+path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	expected := "{\"key\":\"/users/1\",\"value\":\"dmFsdWU=\"}\n{\"key\":\"/users/2\",\"value\":\"dmFsdWU=\"}\n"
+	if count != 2 || string(data) != expected {
+		t.Errorf("expected 2 items, saw %d items %q", count, data)
+	}
+
+	var uploaded []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.ContentLength < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		uploaded, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	count, bytes, err := export(context.Background(), fsm, nil, server.URL+"/bucket/export.jsonl?signature=secret")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if count != 3 || bytes != uint64(len(uploaded)) {
+		t.Errorf("expected 3 items in %d bytes, saw %d items in %d bytes", len(uploaded), count, bytes)
+	}
+
+	server.Close()
+	_, _, err = export(context.Background(), fsm, nil, server.URL+"/bucket/export.jsonl?signature=secret")
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected an error without the signature, saw %v", err)
+	}
+}
+
+func TestExportDestinations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cete-export-")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	if err := os.Symlink(os.TempDir(), filepath.Join(dir, "out")); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := (&exportDestinations{}).check("file://" + filepath.Join(dir, "export.jsonl")); !errors.Is(err, errors.ErrPermissionDenied) {
+		t.Errorf("expected the exports to be refused without destinations, saw %v", err)
+	}
+
+	d := &exportDestinations{directory: dir, urlPrefixes: []string{"https://bucket.s3.amazonaws.com/backups/"}}
+	for _, destination := range []struct {
+		url     string
+		allowed bool
+	}{
+		{"file://" + filepath.Join(dir, "export.jsonl"), true},
+		{"file://" + filepath.Join(dir, "..", "export.jsonl"), false},
+		{"file://" + filepath.Join(dir, "out", "export.jsonl"), false},
+		{"file:///etc/passwd", false},
+		{"https://bucket.s3.amazonaws.com/backups/export.jsonl?X-Amz-Signature=secret", true},
+		{"https://bucket.s3.amazonaws.com/other/export.jsonl", false},
+		{"https://bucket.s3.amazonaws.com.example.com/backups/export.jsonl", false},
+		{"http://bucket.s3.amazonaws.com/backups/export.jsonl", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+	} {
+		err := d.check(destination.url)
+		if destination.allowed && err != nil {
+			t.Errorf("expected %s to be allowed, saw %v", destination.url, err)
+		}
+		if !destination.allowed && !errors.Is(err, errors.ErrPermissionDenied) {
+			t.Errorf("expected %s to be refused, saw %v", destination.url, err)
+		}
+	}
+}
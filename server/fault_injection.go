@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"math/rand"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/mosuka/cete/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// faultRandom is seeded, unlike the global source of Go versions before 1.20, so that
+// the nodes do not fail the same requests.
+var faultRandom = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// FaultInjection slows down or fails requests on purpose, so that applications can be
+// rehearsed against a slow or degraded cluster in staging environments. It must never
+// be enabled in production.
+type FaultInjection struct {
+	// the names of the gRPC methods to inject faults into, e.g. "Get" or "Set". All
+	// methods if empty
+	Methods []string
+	// the latency added to each request, plus a random duration up to the jitter
+	Latency time.Duration
+	Jitter  time.Duration
+	// the ratio of requests failed with Unavailable, from 0 to 1
+	ErrorRate float64
+}
+
+func (f *FaultInjection) enabled() bool {
+	return f != nil && (f.Latency > 0 || f.Jitter > 0 || f.ErrorRate > 0)
+}
+
+func (f *FaultInjection) applies(fullMethod string) bool {
+	if len(f.Methods) == 0 {
+		return true
+	}
+	method := path.Base(fullMethod)
+	for _, m := range f.Methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inject waits for the latency and returns the injected error, if any.
+func (f *FaultInjection) inject(ctx context.Context) error {
+	faultRandom.Lock()
+	jitter := faultRandom.Float64()
+	fail := faultRandom.Float64() < f.ErrorRate
+	faultRandom.Unlock()
+
+	delay := f.Latency + time.Duration(jitter*float64(f.Jitter))
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return errors.Convert(ctx.Err(), codes.DeadlineExceeded)
+		case <-timer.C:
+		}
+	}
+
+	if fail {
+		return errors.New(codes.Unavailable, "injected fault")
+	}
+
+	return nil
+}
+
+func faultInjectionUnaryServerInterceptor(f *FaultInjection) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if f.enabled() && f.applies(info.FullMethod) {
+			if err := f.inject(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func faultInjectionStreamServerInterceptor(f *FaultInjection) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if f.enabled() && f.applies(info.FullMethod) {
+			if err := f.inject(ss.Context()); err != nil {
+				return err
+			}
+		}
+
+		return handler(srv, ss)
+	}
+}
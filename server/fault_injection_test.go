@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mosuka/cete/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestFaultInjectionUnaryServerInterceptor(t *testing.T) {
+	interceptor := faultInjectionUnaryServerInterceptor(&FaultInjection{
+		Methods:   []string{"Get"},
+		Latency:   10 * time.Millisecond,
+		ErrorRate: 1,
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	start := time.Now()
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/kvs.KVS/Get"}, handler)
+	if errors.Code(err) != codes.Unavailable {
+		t.Errorf("expected an injected error, saw %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("expected an injected latency, saw %s", elapsed)
+	}
+
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/kvs.KVS/Set"}, handler); err != nil {
+		t.Errorf("expected no fault in other methods, saw %v", err)
+	}
+
+	// without fault injection every request is handled
+	interceptor = faultInjectionUnaryServerInterceptor(nil)
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/kvs.KVS/Get"}, handler); err != nil {
+		t.Errorf("expected no fault, saw %v", err)
+	}
+}
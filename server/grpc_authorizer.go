@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"path"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Authorizer decides whether a request is allowed, so that applications embedding cete
+// can enforce their own authorization model. The identity is the common name of the
+// client certificate, or empty if the client did not present one. The operation is the
+// name of the gRPC method, e.g. "Get" or "Set", and the key is the key or the prefix of
+// the request, or empty if the operation does not touch a key. Copy and Move are
+// authorized for the source and for the destination. The context carries the
+// request metadata for other schemes, e.g. the authorization header of the RESTful API.
+// Returning an error denies the request.
+//
+// Requests forwarded from a follower to the leader and requests between nodes are
+// authorized as well. Nodes do not present a client certificate, so their identity is empty.
+type Authorizer func(ctx context.Context, identity string, operation string, key string) error
+
+func requestIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return ""
+	}
+
+	return tlsInfo.State.PeerCertificates[0].Subject.CommonName
+}
+
+func requestKey(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetKey() string }:
+		return r.GetKey()
+	case interface{ GetSource() string }:
+		return r.GetSource()
+	case interface{ GetPrefix() string }:
+		return r.GetPrefix()
+	case interface{ GetScan() *protobuf.ScanRequest }:
+		return r.GetScan().GetPrefix()
+	default:
+		return ""
+	}
+}
+
+func authorize(ctx context.Context, authorizer Authorizer, fullMethod string, req interface{}) error {
+	keys := []string{requestKey(req)}
+	// copies and moves also write the destination
+	if r, ok := req.(interface {
+		GetSource() string
+		GetDestination() string
+	}); ok {
+		keys = append(keys, r.GetDestination())
+	}
+
+	for _, key := range keys {
+		if err := authorizer(ctx, requestIdentity(ctx), path.Base(fullMethod), key); err != nil {
+			if errors.Is(err, errors.ErrUnauthenticated) {
+				return errors.Convert(err, codes.Unauthenticated)
+			}
+			return errors.Wrap(errors.ErrPermissionDenied, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// authorizationUnaryServerInterceptor rejects requests denied by the authorizer with PermissionDenied.
+func authorizationUnaryServerInterceptor(authorizer Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if authorizer != nil {
+			if err := authorize(ctx, authorizer, info.FullMethod, req); err != nil {
+				return nil, err
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// authorizationStreamServerInterceptor rejects streams denied by the authorizer with
+// PermissionDenied. The streams of a single request, e.g. Watch, are authorized once the
+// request is received, so that the authorizer gets its prefix.
+func authorizationStreamServerInterceptor(authorizer Authorizer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if authorizer == nil {
+			return handler(srv, stream)
+		}
+		if info.IsClientStream {
+			if err := authorize(stream.Context(), authorizer, info.FullMethod, nil); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		}
+
+		return handler(srv, &authorizingServerStream{
+			ServerStream: stream,
+			authorize: func(req interface{}) error {
+				return authorize(stream.Context(), authorizer, info.FullMethod, req)
+			},
+		})
+	}
+}
+
+// authorizingServerStream authorizes the request of a server stream before the handler gets it.
+type authorizingServerStream struct {
+	grpc.ServerStream
+	authorize  func(req interface{}) error
+	authorized bool
+}
+
+func (s *authorizingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.authorized {
+		if err := s.authorize(m); err != nil {
+			return err
+		}
+		s.authorized = true
+	}
+
+	return nil
+}
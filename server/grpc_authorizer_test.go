@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestAuthorizationUnaryServerInterceptor(t *testing.T) {
+	authorizer := func(ctx context.Context, identity string, operation string, key string) error {
+		if operation == "Set" && key != "/public/a" {
+			return fmt.Errorf("%q may not write %s", identity, key)
+		}
+		return nil
+	}
+	interceptor := authorizationUnaryServerInterceptor(authorizer)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/kvs.KVS/Set"}
+	if _, err := interceptor(context.Background(), &protobuf.SetRequest{Key: "/public/a"}, info, handler); err != nil {
+		t.Errorf("expected the request to be allowed, saw %v", err)
+	}
+
+	_, err := interceptor(context.Background(), &protobuf.SetRequest{Key: "/private/a"}, info, handler)
+	if !errors.Is(err, errors.ErrPermissionDenied) || errors.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected the request to be denied, saw %v", err)
+	}
+
+	info = &grpc.UnaryServerInfo{FullMethod: "/kvs.KVS/Get"}
+	if _, err := interceptor(context.Background(), &protobuf.GetRequest{Key: "/private/a"}, info, handler); err != nil {
+		t.Errorf("expected the request to be allowed, saw %v", err)
+	}
+
+	// without an authorizer every request is allowed
+	interceptor = authorizationUnaryServerInterceptor(nil)
+	if _, err := interceptor(context.Background(), &protobuf.SetRequest{Key: "/private/a"}, info, handler); err != nil {
+		t.Errorf("expected the request to be allowed, saw %v", err)
+	}
+
+	// copies are authorized for the destination as well
+	info = &grpc.UnaryServerInfo{FullMethod: "/kvs.KVS/Copy"}
+	authorizer = func(ctx context.Context, identity string, operation string, key string) error {
+		if key != "/public/a" && key != "/public/b" {
+			return fmt.Errorf("%q may not write %s", identity, key)
+		}
+		return nil
+	}
+	interceptor = authorizationUnaryServerInterceptor(authorizer)
+	if _, err := interceptor(context.Background(), &protobuf.CopyRequest{Source: "/public/a", Destination: "/public/b"}, info, handler); err != nil {
+		t.Errorf("expected the request to be allowed, saw %v", err)
+	}
+	if _, err := interceptor(context.Background(), &protobuf.CopyRequest{Source: "/public/a", Destination: "/private/a"}, info, handler); !errors.Is(err, errors.ErrPermissionDenied) {
+		t.Errorf("expected the copy to the private key to be denied, saw %v", err)
+	}
+}
+
+func TestRequestKey(t *testing.T) {
+	for _, test := range []struct {
+		req interface{}
+		key string
+	}{
+		{&protobuf.GetRequest{Key: "a"}, "a"},
+		{&protobuf.ScanRequest{Prefix: "b"}, "b"},
+		{&protobuf.ExportRequest{Scan: &protobuf.ScanRequest{Prefix: "c"}}, "c"},
+		{&protobuf.ExportRequest{}, ""},
+		{&protobuf.LeaveRequest{Id: "node1"}, ""},
+	} {
+		if key := requestKey(test.req); key != test.key {
+			t.Errorf("expected the key of %T to be %q, saw %q", test.req, test.key, key)
+		}
+	}
+}
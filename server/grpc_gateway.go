@@ -5,6 +5,7 @@ import (
 	"math"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -13,8 +14,10 @@ import (
 	"github.com/mosuka/cete/protobuf"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
 func responseFilter(ctx context.Context, w http.ResponseWriter, resp proto.Message) error {
@@ -32,6 +35,51 @@ func responseFilter(ctx context.Context, w http.ResponseWriter, resp proto.Messa
 	return nil
 }
 
+// leaderRedirectHandler returns an error handler that redirects the client to
+// the leader's HTTP address when the request was rejected because this node is
+// not the leader. Other errors are handled as usual.
+func leaderRedirectHandler(secure bool) runtime.ProtoErrorHandlerFunc {
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+
+	return func(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.FailedPrecondition {
+			for _, detail := range st.Details() {
+				hint, ok := detail.(*protobuf.LeaderHint)
+				if !ok || hint.Node == nil || hint.Node.Metadata == nil || hint.Node.Metadata.HttpAddress == "" {
+					continue
+				}
+
+				host, port, err := net.SplitHostPort(hint.Node.Metadata.HttpAddress)
+				if err != nil {
+					continue
+				}
+				if host == "" {
+					// the leader listens on all interfaces, assume it is reachable by the same host name
+					host = r.Host
+					if h, _, err := net.SplitHostPort(r.Host); err == nil {
+						host = h
+					}
+				}
+
+				location := url.URL{
+					Scheme:   scheme,
+					Host:     net.JoinHostPort(host, port),
+					Path:     r.URL.Path,
+					RawPath:  r.URL.RawPath,
+					RawQuery: r.URL.RawQuery,
+				}
+				http.Redirect(w, r, location.String(), http.StatusTemporaryRedirect)
+				return
+			}
+		}
+
+		runtime.DefaultHTTPError(ctx, mux, m, w, r, err)
+	}
+}
+
 type GRPCGateway struct {
 	httpAddress string
 	grpcAddress string
@@ -40,12 +88,27 @@ type GRPCGateway struct {
 	listener net.Listener
 	mux      *runtime.ServeMux
 
-	certificateFile string
-	keyFile         string
+	// nil when TLS is disabled
+	certificates *certificateReloader
 
 	logger *zap.Logger
 }
 
+// consistencyHandler accepts the read consistency of the query string in any case,
+// e.g. ?consistency=strong or ?consistency=leader_preferred.
+func consistencyHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if value := query.Get("consistency"); value != "" {
+			if consistency, err := protobuf.ParseConsistency(value); err == nil {
+				query.Set("consistency", consistency.String())
+				r.URL.RawQuery = query.Encode()
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func NewGRPCGateway(httpAddress string, grpcAddress string, certificateFile string, keyFile string, commonName string, logger *zap.Logger) (*GRPCGateway, error) {
 	dialOpts := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(
@@ -67,6 +130,7 @@ func NewGRPCGateway(httpAddress string, grpcAddress string, certificateFile stri
 	mux := runtime.NewServeMux(
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, new(marshaler.CeteMarshaler)),
 		runtime.WithForwardResponseOption(responseFilter),
+		runtime.WithProtoErrorHandler(leaderRedirectHandler(certificateFile != "" && keyFile != "")),
 	)
 
 	if certificateFile == "" {
@@ -74,43 +138,60 @@ func NewGRPCGateway(httpAddress string, grpcAddress string, certificateFile stri
 	} else {
 		creds, err := credentials.NewClientTLSFromFile(certificateFile, commonName)
 		if err != nil {
+			cancel()
 			return nil, err
 		}
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
 	}
 
+	var certificates *certificateReloader
+	if certificateFile != "" && keyFile != "" {
+		var err error
+		certificates, err = newCertificateReloader(certificateFile, keyFile, logger)
+		if err != nil {
+			logger.Error("failed to load TLS certificate", zap.Error(err))
+			cancel()
+			return nil, err
+		}
+	}
+
 	err := protobuf.RegisterKVSHandlerFromEndpoint(ctx, mux, grpcAddress, dialOpts)
 	if err != nil {
 		logger.Error("failed to register KVS handler from endpoint", zap.Error(err))
+		cancel()
 		return nil, err
 	}
 
 	listener, err := net.Listen("tcp", httpAddress)
 	if err != nil {
 		logger.Error("failed to create key value store service", zap.Error(err))
+		cancel()
 		return nil, err
 	}
 
 	return &GRPCGateway{
-		httpAddress:     httpAddress,
-		grpcAddress:     grpcAddress,
-		listener:        listener,
-		mux:             mux,
-		cancel:          cancel,
-		certificateFile: certificateFile,
-		keyFile:         keyFile,
-		logger:          logger,
+		httpAddress:  httpAddress,
+		grpcAddress:  grpcAddress,
+		listener:     listener,
+		mux:          mux,
+		cancel:       cancel,
+		certificates: certificates,
+		logger:       logger,
 	}, nil
 }
 
 func (s *GRPCGateway) Start() error {
-	if s.certificateFile == "" && s.keyFile == "" {
+	if s.certificates == nil {
 		go func() {
-			_ = http.Serve(s.listener, s.mux)
+			_ = http.Serve(s.listener, consistencyHandler(s.mux))
 		}()
 	} else {
+		server := &http.Server{
+			Handler:   consistencyHandler(s.mux),
+			TLSConfig: s.certificates.tlsConfig(),
+		}
 		go func() {
-			_ = http.ServeTLS(s.listener, s.mux, s.certificateFile, s.keyFile)
+			_ = server.ServeTLS(s.listener, "", "")
 		}()
 	}
 
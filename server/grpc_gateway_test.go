@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"google.golang.org/grpc/status"
+)
+
+func TestLeaderRedirectHandler(t *testing.T) {
+	leader := func(httpAddress string) *protobuf.LeaderHint {
+		return &protobuf.LeaderHint{
+			Id: "node2",
+			Node: &protobuf.Node{
+				RaftAddress: "10.0.0.2:7000",
+				Metadata:    &protobuf.Metadata{GrpcAddress: "10.0.0.2:9000", HttpAddress: httpAddress},
+			},
+		}
+	}
+
+	for _, test := range []struct {
+		name     string
+		secure   bool
+		err      error
+		code     int
+		location string
+	}{
+		{"leader", false, errors.NotLeader(leader("10.0.0.2:8000")), http.StatusTemporaryRedirect, "http://10.0.0.2:8000/v1/kvs/a%2Fb?ttl=10"},
+		{"secure", true, errors.NotLeader(leader("10.0.0.2:8000")), http.StatusTemporaryRedirect, "https://10.0.0.2:8000/v1/kvs/a%2Fb?ttl=10"},
+		{"all interfaces", false, errors.NotLeader(leader(":8000")), http.StatusTemporaryRedirect, "http://node1:8000/v1/kvs/a%2Fb?ttl=10"},
+		{"unknown leader", false, errors.NotLeader(nil), http.StatusBadRequest, ""},
+		{"no http address", false, errors.NotLeader(leader("")), http.StatusBadRequest, ""},
+		{"other error", false, errors.ErrNotFound, http.StatusNotFound, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// the gateway sees the error as received from the gRPC server
+			err := status.Convert(test.err).Err()
+
+			r := httptest.NewRequest(http.MethodPut, "http://node1:8001/v1/kvs/a%2Fb?ttl=10", nil)
+			w := httptest.NewRecorder()
+			leaderRedirectHandler(test.secure)(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, r, err)
+
+			if w.Code != test.code {
+				t.Errorf("expected %d, saw %d", test.code, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != test.location {
+				t.Errorf("expected the location %q, saw %q", test.location, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"time"
+
+	"github.com/mosuka/cete/protobuf"
+)
+
+type grpcOptions struct {
+	forwarding          bool
+	authorizer          Authorizer
+	authToken           string
+	watchBufferSize     int
+	watchOverflowPolicy WatchOverflowPolicy
+
+	slowRequestThreshold time.Duration
+	diagnosticsConfig    map[string]interface{}
+	faultInjection       *FaultInjection
+	payloadLogging       *PayloadLogging
+	readOnlyAddress      string
+
+	nodeStatusInterval time.Duration
+	nodeStatusJitter   time.Duration
+	nodeMetadata       *protobuf.Metadata
+
+	warmupTimeout  time.Duration
+	warmupPrefixes []string
+
+	transformers valueTransformers
+}
+
+func defaultGRPCOptions() *grpcOptions {
+	return &grpcOptions{
+		forwarding:          true,
+		watchBufferSize:     defaultWatchBufferSize,
+		watchOverflowPolicy: WatchOverflowCancel,
+
+		slowRequestThreshold: defaultSlowRequestThreshold,
+
+		nodeStatusInterval: defaultNodeStatusInterval,
+		nodeStatusJitter:   defaultNodeStatusJitter,
+	}
+}
+
+func newGRPCOptions(opts ...GRPCServerOption) *grpcOptions {
+	o := defaultGRPCOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// GRPCServerOption configures the gRPC server and service.
+type GRPCServerOption func(*grpcOptions)
+
+// WithValueTransformer transforms the values of the keys with the prefix read by Get and
+// Scan. The values sent to the watchers and exported are not transformed. Register the
+// same transformers on every node, as the reads are served by any node.
+func WithValueTransformer(prefix string, transformer ValueTransformer) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.transformers = append(o.transformers, &valueTransformer{prefix: prefix, transform: transformer})
+	}
+}
+
+// WithValueValidator validates the values of the keys with the prefix written by Set, on
+// the node receiving the write and on the leader it is forwarded to.
+func WithValueValidator(prefix string, validator ValueValidator) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.transformers = append(o.transformers, &valueTransformer{prefix: prefix, validate: validator})
+	}
+}
+
+// WithForwarding enables or disables forwarding of write requests received by
+// a follower to the leader. If disabled, a follower rejects write requests with
+// an error carrying the leader's address.
+func WithForwarding(enabled bool) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.forwarding = enabled
+	}
+}
+
+// WithAuthorizer makes the server consult the authorizer before handling each request.
+func WithAuthorizer(authorizer Authorizer) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.authorizer = authorizer
+	}
+}
+
+// WithAuthToken requires the requests to present the token, or an API key allowed to run
+// them, as a bearer token in the authorization metadata. The node presents the token to
+// the other nodes, which must require the same token. The health checks need no token.
+func WithAuthToken(token string) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.authToken = token
+	}
+}
+
+// WithWatchBuffer sets how many events are buffered for each watch, and what happens
+// to a watch that falls behind by more events.
+func WithWatchBuffer(size int, policy WatchOverflowPolicy) GRPCServerOption {
+	return func(o *grpcOptions) {
+		if size > 0 {
+			o.watchBufferSize = size
+		}
+		o.watchOverflowPolicy = policy
+	}
+}
+
+// WithSlowRequestThreshold sets how long a request takes to be listed in the diagnostic
+// dumps as a slow request. 0 disables the list.
+func WithSlowRequestThreshold(threshold time.Duration) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.slowRequestThreshold = threshold
+	}
+}
+
+// WithDiagnosticsConfig sets the configuration written to the diagnostic dumps.
+func WithDiagnosticsConfig(config map[string]interface{}) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.diagnosticsConfig = config
+	}
+}
+
+// WithFaultInjection injects latency and errors into requests. For staging environments only.
+func WithFaultInjection(f FaultInjection) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.faultInjection = &f
+	}
+}
+
+// WithPayloadLogging logs the requests and responses of the methods, with their sensitive
+// values redacted. For debugging only.
+func WithPayloadLogging(p PayloadLogging) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.payloadLogging = &p
+	}
+}
+
+// WithReadOnlyAddress serves the reads on a second listener, which refuses the writes with
+// the leader whatever the forwarding setting, e.g. to hand out follower endpoints to
+// analytics consumers without risking accidental writes.
+func WithReadOnlyAddress(address string) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.readOnlyAddress = address
+	}
+}
+
+// WithNodeStatusPropagation makes the node replicate its state, addresses and readiness
+// at the interval plus a random jitter up to the jitter, so that the cluster state is
+// served without asking the other nodes. The metadata holds the addresses of the node,
+// the addresses it joined with are kept if it is nil. The propagation is disabled if the
+// interval is zero, the cluster state then asks the other nodes for their state.
+func WithNodeStatusPropagation(interval time.Duration, jitter time.Duration, metadata *protobuf.Metadata) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.nodeStatusInterval = interval
+		o.nodeStatusJitter = jitter
+		o.nodeMetadata = metadata
+	}
+}
+
+// WithWarmup keeps the node from reporting ready after it starts until it has caught up
+// with the leader, then has read the keys with the prefixes to warm the caches up, so
+// that load balancers do not send reads to a cold node. The node becomes ready once the
+// timeout has passed anyway. The warmup is disabled if the timeout is zero.
+func WithWarmup(timeout time.Duration, prefixes []string) GRPCServerOption {
+	return func(o *grpcOptions) {
+		o.warmupTimeout = timeout
+		o.warmupPrefixes = prefixes
+	}
+}
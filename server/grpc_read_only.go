@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"path"
+
+	"github.com/mosuka/cete/errors"
+	"google.golang.org/grpc"
+)
+
+// readMethods are the methods served by the read-only listener.
+var readMethods = map[string]bool{
+	"LivenessCheck":      true,
+	"ReadinessCheck":     true,
+	"Node":               true,
+	"Cluster":            true,
+	"MembershipSpec":     true,
+	"Hash":               true,
+	"StorageLevels":      true,
+	"DecommissionStatus": true,
+	"Role":               true,
+	"ClusterConfig":      true,
+	"Get":                true,
+	"Scan":               true,
+	"Watch":              true,
+	"Metrics":            true,
+}
+
+// dataWriteMethods are refused by the read-only listener with the leader, so that the
+// client knows where to send them.
+var dataWriteMethods = map[string]bool{
+	"Set":    true,
+	"Delete": true,
+	"Copy":   true,
+	"Move":   true,
+}
+
+// readOnlyError returns the error of the methods the read-only listener refuses.
+// Other methods, e.g. the membership changes and the exports, are refused with
+// PermissionDenied.
+func readOnlyError(fullMethod string, notLeader func() error) error {
+	method := path.Base(fullMethod)
+	switch {
+	case readMethods[method]:
+		return nil
+	case dataWriteMethods[method]:
+		return notLeader()
+	default:
+		return errors.Wrapf(errors.ErrPermissionDenied, "%s on the read-only listener", method)
+	}
+}
+
+func readOnlyUnaryServerInterceptor(notLeader func() error) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := readOnlyError(info.FullMethod, notLeader); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func readOnlyStreamServerInterceptor(notLeader func() error) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := readOnlyError(info.FullMethod, notLeader); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
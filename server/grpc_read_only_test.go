@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/mosuka/cete/errors"
+)
+
+func TestReadOnlyError(t *testing.T) {
+	notLeader := func() error {
+		return errors.NotLeader(nil)
+	}
+
+	for _, test := range []struct {
+		method   string
+		expected error
+	}{
+		{"/kvs.KVS/Get", nil},
+		{"/kvs.KVS/Watch", nil},
+		{"/kvs.KVS/Set", errors.ErrNotLeader},
+		{"/kvs.KVS/Delete", errors.ErrNotLeader},
+		{"/kvs.KVS/Join", errors.ErrPermissionDenied},
+		{"/kvs.KVS/Export", errors.ErrPermissionDenied},
+	} {
+		err := readOnlyError(test.method, notLeader)
+		if (test.expected == nil && err != nil) || (test.expected != nil && !errors.Is(err, test.expected)) {
+			t.Errorf("expected %s to fail with %v, saw %v", test.method, test.expected, err)
+		}
+	}
+}
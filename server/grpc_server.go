@@ -22,6 +22,11 @@ type GRPCServer struct {
 	server      *grpc.Server
 	listener    net.Listener
 
+	// the listener serving only reads, if enabled
+	readOnlyAddress  string
+	readOnlyServer   *grpc.Server
+	readOnlyListener net.Listener
+
 	certFile     string
 	keyFile      string
 	certHostname string
@@ -29,61 +34,103 @@ type GRPCServer struct {
 	logger *zap.Logger
 }
 
-func NewGRPCServer(grpcAddress string, raftServer *RaftServer, certificateFile string, keyFile string, commonName string, logger *zap.Logger) (*GRPCServer, error) {
+func NewGRPCServer(grpcAddress string, raftServer *RaftServer, certificateFile string, keyFile string, commonName string, logger *zap.Logger, opts ...GRPCServerOption) (*GRPCServer, error) {
 	grpcLogger := logger.Named("grpc")
+	o := newGRPCOptions(opts...)
 
-	opts := []grpc.ServerOption{
-		grpc.MaxRecvMsgSize(math.MaxInt64),
-		grpc.MaxSendMsgSize(math.MaxInt64),
-		grpc.StreamInterceptor(
-			grpcmiddleware.ChainStreamServer(
-				metric.GrpcMetrics.StreamServerInterceptor(),
-				grpczap.StreamServerInterceptor(grpcLogger),
-			),
-		),
-		grpc.UnaryInterceptor(
-			grpcmiddleware.ChainUnaryServer(
-				metric.GrpcMetrics.UnaryServerInterceptor(),
-				grpczap.UnaryServerInterceptor(grpcLogger),
-			),
-		),
-		grpc.KeepaliveParams(
-			keepalive.ServerParameters{
-				//MaxConnectionIdle:     0,
-				//MaxConnectionAge:      0,
-				//MaxConnectionAgeGrace: 0,
-				Time:    5 * time.Second,
-				Timeout: 5 * time.Second,
-			},
-		),
+	service, err := NewGRPCService(raftServer, certificateFile, commonName, logger, opts...)
+	if err != nil {
+		logger.Error("failed to create key value store service", zap.Error(err))
+		return nil, err
+	}
+
+	if o.faultInjection.enabled() {
+		logger.Warn("injecting faults into requests", zap.Strings("methods", o.faultInjection.Methods), zap.Duration("latency", o.faultInjection.Latency), zap.Duration("jitter", o.faultInjection.Jitter), zap.Float64("error_rate", o.faultInjection.ErrorRate))
+	}
+
+	payloads, err := newPayloadLogger(o.payloadLogging, grpcLogger.Named("payload"))
+	if err != nil {
+		logger.Error("failed to compile redaction patterns", zap.Error(err))
+		return nil, err
+	}
+	if payloads != nil {
+		logger.Warn("logging request and response payloads", zap.Strings("methods", o.payloadLogging.Methods), zap.Strings("redact_keys", o.payloadLogging.RedactKeys), zap.Strings("redact_values", o.payloadLogging.RedactValues))
 	}
 
+	var creds credentials.TransportCredentials
 	if certificateFile == "" && keyFile == "" {
 		logger.Info("disabling TLS")
 	} else {
 		logger.Info("enabling TLS")
-		creds, err := credentials.NewServerTLSFromFile(certificateFile, keyFile)
+		certificates, err := newCertificateReloader(certificateFile, keyFile, logger)
 		if err != nil {
 			logger.Error("failed to create credentials", zap.Error(err))
+			return nil, err
 		}
-		opts = append(opts, grpc.Creds(creds))
+		creds = credentials.NewTLS(certificates.tlsConfig())
 	}
 
-	server := grpc.NewServer(
-		opts...,
-	)
-
-	service, err := NewGRPCService(raftServer, certificateFile, commonName, logger)
-	if err != nil {
-		logger.Error("failed to create key value store service", zap.Error(err))
-		return nil, err
+	authorizer := o.authorizer
+	if o.authToken != "" {
+		logger.Info("enabling token authentication")
+		authorizer = tokenAuthorizer(o.authToken, raftServer, o.authorizer)
 	}
 
-	protobuf.RegisterKVSServer(server, service)
+	newServer := func(readOnly bool) *grpc.Server {
+		streamInterceptors := []grpc.StreamServerInterceptor{
+			metric.GrpcMetrics.StreamServerInterceptor(),
+			grpczap.StreamServerInterceptor(grpcLogger),
+			faultInjectionStreamServerInterceptor(o.faultInjection),
+			authorizationStreamServerInterceptor(authorizer),
+			payloadLoggingStreamServerInterceptor(payloads),
+		}
+		unaryInterceptors := []grpc.UnaryServerInterceptor{
+			metric.GrpcMetrics.UnaryServerInterceptor(),
+			grpczap.UnaryServerInterceptor(grpcLogger),
+			slowRequestUnaryServerInterceptor(service.slowRequests),
+			faultInjectionUnaryServerInterceptor(o.faultInjection),
+			authorizationUnaryServerInterceptor(authorizer),
+			payloadLoggingUnaryServerInterceptor(payloads),
+		}
+		if readOnly {
+			streamInterceptors = append(streamInterceptors, readOnlyStreamServerInterceptor(service.notLeaderError))
+			unaryInterceptors = append(unaryInterceptors, readOnlyUnaryServerInterceptor(service.notLeaderError))
+		}
+		unaryInterceptors = append(unaryInterceptors, validationUnaryServerInterceptor())
+
+		serverOpts := []grpc.ServerOption{
+			grpc.MaxRecvMsgSize(math.MaxInt64),
+			grpc.MaxSendMsgSize(math.MaxInt64),
+			grpc.StreamInterceptor(grpcmiddleware.ChainStreamServer(streamInterceptors...)),
+			grpc.UnaryInterceptor(grpcmiddleware.ChainUnaryServer(unaryInterceptors...)),
+			grpc.KeepaliveParams(
+				keepalive.ServerParameters{
+					//MaxConnectionIdle:     0,
+					//MaxConnectionAge:      0,
+					//MaxConnectionAgeGrace: 0,
+					Time:    5 * time.Second,
+					Timeout: 5 * time.Second,
+				},
+			),
+		}
+		if creds != nil {
+			serverOpts = append(serverOpts, grpc.Creds(creds))
+		}
+
+		server := grpc.NewServer(
+			serverOpts...,
+		)
+
+		protobuf.RegisterKVSServer(server, service)
 
-	// Initialize all metrics.
-	metric.GrpcMetrics.InitializeMetrics(server)
-	grpc_prometheus.Register(server)
+		// Initialize all metrics.
+		metric.GrpcMetrics.InitializeMetrics(server)
+		grpc_prometheus.Register(server)
+
+		return server
+	}
+
+	server := newServer(false)
 
 	listener, err := net.Listen("tcp", grpcAddress)
 	if err != nil {
@@ -91,18 +138,40 @@ func NewGRPCServer(grpcAddress string, raftServer *RaftServer, certificateFile s
 		return nil, err
 	}
 
+	var readOnlyServer *grpc.Server
+	var readOnlyListener net.Listener
+	if o.readOnlyAddress != "" {
+		readOnlyServer = newServer(true)
+		readOnlyListener, err = net.Listen("tcp", o.readOnlyAddress)
+		if err != nil {
+			logger.Error("failed to create listener", zap.String("read_only_grpc_address", o.readOnlyAddress), zap.Error(err))
+			_ = listener.Close()
+			return nil, err
+		}
+	}
+
 	return &GRPCServer{
-		grpcAddress:  grpcAddress,
-		service:      service,
-		server:       server,
-		listener:     listener,
-		certFile:     certificateFile,
-		keyFile:      keyFile,
-		certHostname: commonName,
-		logger:       logger,
+		grpcAddress:      grpcAddress,
+		service:          service,
+		server:           server,
+		listener:         listener,
+		readOnlyAddress:  o.readOnlyAddress,
+		readOnlyServer:   readOnlyServer,
+		readOnlyListener: readOnlyListener,
+		certFile:         certificateFile,
+		keyFile:          keyFile,
+		certHostname:     commonName,
+		logger:           logger,
 	}, nil
 }
 
+// Dump writes a diagnostic bundle and returns its path.
+func (s *GRPCServer) Dump() (string, error) {
+	path, _, err := s.service.dumpDiagnostics()
+
+	return path, err
+}
+
 func (s *GRPCServer) Start() error {
 	if err := s.service.Start(); err != nil {
 		s.logger.Error("failed to start service", zap.Error(err))
@@ -113,6 +182,14 @@ func (s *GRPCServer) Start() error {
 	}()
 
 	s.logger.Info("gRPC server started", zap.String("grpc_address", s.grpcAddress))
+
+	if s.readOnlyServer != nil {
+		go func() {
+			_ = s.readOnlyServer.Serve(s.readOnlyListener)
+		}()
+		s.logger.Info("read-only gRPC server started", zap.String("read_only_grpc_address", s.readOnlyAddress))
+	}
+
 	return nil
 }
 
@@ -123,6 +200,9 @@ func (s *GRPCServer) Stop() error {
 
 	//s.server.GracefulStop()
 	s.server.Stop()
+	if s.readOnlyServer != nil {
+		s.readOnlyServer.Stop()
+	}
 
 	s.logger.Info("gRPC server stopped", zap.String("grpc_address", s.grpcAddress))
 	return nil
@@ -3,49 +3,122 @@ package server
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/raft"
 	"github.com/mosuka/cete/client"
 	"github.com/mosuka/cete/errors"
 	"github.com/mosuka/cete/metric"
 	"github.com/mosuka/cete/protobuf"
+	"github.com/mosuka/cete/version"
 	"github.com/prometheus/common/expfmt"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/metadata"
 )
 
 type GRPCService struct {
 	raftServer      *RaftServer
 	certificateFile string
 	commonName      string
+	authToken       string
 	logger          *zap.Logger
 
-	watchMutex sync.RWMutex
-	watchChans map[chan protobuf.WatchResponse]struct{}
+	forwarding bool
 
-	peerClients map[string]*client.GRPCClient
+	watchers *watchRegistry
+
+	slowRequests      *slowRequestLog
+	diagnosticsConfig map[string]interface{}
+
+	peerMutex    sync.RWMutex
+	peerClients  map[string]*client.GRPCClient
+	peerBreakers map[string]*circuitBreaker
 
 	watchClusterStopCh chan struct{}
 	watchClusterDoneCh chan struct{}
+
+	trackPeersStopCh chan struct{}
+	trackPeersDoneCh chan struct{}
+
+	nodeStatusInterval time.Duration
+	nodeStatusJitter   time.Duration
+	nodeMetadata       *protobuf.Metadata
+	nodeStatusStopCh   chan struct{}
+	nodeStatusDoneCh   chan struct{}
+
+	// whether the node has warmed up, the node is not ready until then
+	warmupTimeout  time.Duration
+	warmupPrefixes []string
+	warm           int32
+	warmupStopCh   chan struct{}
+	warmupDoneCh   chan struct{}
+
+	priorityStopCh chan struct{}
+	priorityDoneCh chan struct{}
+
+	replicationStopCh chan struct{}
+	replicationDoneCh chan struct{}
+
+	transformers valueTransformers
 }
 
-func NewGRPCService(raftServer *RaftServer, certificateFile string, commonName string, logger *zap.Logger) (*GRPCService, error) {
+func NewGRPCService(raftServer *RaftServer, certificateFile string, commonName string, logger *zap.Logger, opts ...GRPCServerOption) (*GRPCService, error) {
+	o := newGRPCOptions(opts...)
+
+	warm := int32(0)
+	if o.warmupTimeout <= 0 {
+		warm = 1
+	}
+
 	return &GRPCService{
 		raftServer:      raftServer,
 		certificateFile: certificateFile,
 		commonName:      commonName,
+		authToken:       o.authToken,
 		logger:          logger,
 
-		watchChans: make(map[chan protobuf.WatchResponse]struct{}),
+		forwarding: o.forwarding,
+
+		watchers: newWatchRegistry(raftServer.id, o.watchBufferSize, o.watchOverflowPolicy, raftServer.priorityPrefixes, logger),
+
+		slowRequests:      newSlowRequestLog(o.slowRequestThreshold, slowRequestLogSize),
+		diagnosticsConfig: o.diagnosticsConfig,
 
-		peerClients: make(map[string]*client.GRPCClient, 0),
+		peerClients:  make(map[string]*client.GRPCClient, 0),
+		peerBreakers: make(map[string]*circuitBreaker, 0),
 
 		watchClusterStopCh: make(chan struct{}),
 		watchClusterDoneCh: make(chan struct{}),
+
+		trackPeersStopCh: make(chan struct{}),
+		trackPeersDoneCh: make(chan struct{}),
+
+		nodeStatusInterval: o.nodeStatusInterval,
+		nodeStatusJitter:   o.nodeStatusJitter,
+		nodeMetadata:       o.nodeMetadata,
+		nodeStatusStopCh:   make(chan struct{}),
+		nodeStatusDoneCh:   make(chan struct{}),
+
+		warmupTimeout:  o.warmupTimeout,
+		warmupPrefixes: o.warmupPrefixes,
+		warm:           warm,
+		warmupStopCh:   make(chan struct{}),
+		warmupDoneCh:   make(chan struct{}),
+
+		priorityStopCh: make(chan struct{}),
+		priorityDoneCh: make(chan struct{}),
+
+		replicationStopCh: make(chan struct{}),
+		replicationDoneCh: make(chan struct{}),
+
+		transformers: o.transformers,
 	}, nil
 }
 
@@ -53,12 +126,38 @@ func (s *GRPCService) Start() error {
 	go func() {
 		s.startWatchCluster(500 * time.Millisecond)
 	}()
+	go func() {
+		s.startTrackPeers(time.Second)
+	}()
+	go func() {
+		s.startReplication(time.Second)
+	}()
+	if len(s.raftServer.priorityPrefixes) > 0 {
+		go func() {
+			s.startDispatchPriority()
+		}()
+	}
+	if s.nodeStatusInterval > 0 {
+		go func() {
+			s.startPropagateNodeStatus(s.nodeStatusInterval, s.nodeStatusJitter)
+		}()
+	}
+	if s.warmupTimeout > 0 {
+		go func() {
+			s.startWarmup(s.warmupTimeout, s.warmupPrefixes)
+		}()
+	}
 
 	s.logger.Info("gRPC service started")
 	return nil
 }
 
 func (s *GRPCService) Stop() error {
+	s.stopReplication()
+	s.stopWarmup()
+	s.stopPropagateNodeStatus()
+	s.stopTrackPeers()
+	s.stopDispatchPriority()
 	s.stopWatchCluster()
 
 	s.logger.Info("gRPC service stopped")
@@ -72,12 +171,12 @@ func (s *GRPCService) startWatchCluster(checkInterval time.Duration) {
 		close(s.watchClusterDoneCh)
 	}()
 
-	ticker := time.NewTicker(checkInterval)
+	ticker := s.raftServer.clock.NewTicker(checkInterval)
 	defer ticker.Stop()
 
 	timeout := 60 * time.Second
 	if err := s.raftServer.WaitForDetectLeader(timeout); err != nil {
-		if err == errors.ErrTimeout {
+		if errors.Is(err, errors.ErrTimeout) {
 			s.logger.Error("leader detection timed out", zap.Duration("timeout", timeout), zap.Error(err))
 		} else {
 			s.logger.Error("failed to detect leader", zap.Error(err))
@@ -90,67 +189,146 @@ func (s *GRPCService) startWatchCluster(checkInterval time.Duration) {
 			s.logger.Info("received a request to stop updating a cluster")
 			return
 		case event := <-s.raftServer.applyCh:
-			watchResp := &protobuf.WatchResponse{
-				Event: event,
+			s.watchers.dispatch(event, false)
+		case <-ticker.C():
+			s.updatePeerClients()
+		}
+	}
+}
+
+func (s *GRPCService) updatePeerClients() {
+	s.peerMutex.Lock()
+	defer s.peerMutex.Unlock()
+
+	// open clients for peer nodes
+	nodes, err := s.raftServer.Nodes()
+	if err != nil {
+		s.logger.Warn("failed to get cluster info", zap.String("err", err.Error()))
+	}
+	for id, node := range nodes {
+		if id == s.raftServer.id {
+			continue
+		}
+
+		if node.Metadata == nil || node.Metadata.GrpcAddress == "" {
+			s.logger.Debug("gRPC address missing", zap.String("id", id))
+			continue
+		}
+		if c, ok := s.peerClients[id]; ok {
+			if c.Target() == node.Metadata.GrpcAddress {
+				continue
 			}
-			for c := range s.watchChans {
-				c <- *watchResp
+			s.logger.Debug("close client", zap.String("id", id), zap.String("grpc_address", c.Target()))
+			delete(s.peerClients, id)
+			delete(s.peerBreakers, id)
+			if err := c.Close(); err != nil {
+				s.logger.Warn("failed to close client", zap.String("id", id), zap.String("grpc_address", c.Target()), zap.Error(err))
 			}
-		case <-ticker.C:
-			s.watchMutex.Lock()
+		}
+		s.logger.Debug("create client", zap.String("id", id), zap.String("grpc_address", node.Metadata.GrpcAddress))
+		if newClient, err := client.NewGRPCClientWithOptions(node.Metadata.GrpcAddress, context.TODO(), client.WithTLS(s.certificateFile, s.commonName), client.WithAuthToken(s.authToken)); err == nil {
+			s.peerClients[id] = newClient
+			s.peerBreakers[id] = newCircuitBreaker(s.raftServer.clock, defaultPeerMinBackoff, defaultPeerMaxBackoff)
+		} else {
+			s.logger.Warn("failed to create client", zap.String("id", id), zap.String("grpc_address", node.Metadata.GrpcAddress), zap.Error(err))
+		}
+	}
 
-			// open clients for peer nodes
-			nodes, err := s.raftServer.Nodes()
-			if err != nil {
-				s.logger.Warn("failed to get cluster info", zap.String("err", err.Error()))
+	// close clients for non-existent peer nodes
+	for id, c := range s.peerClients {
+		if _, exist := nodes[id]; !exist {
+			s.logger.Debug("close client", zap.String("id", id), zap.String("grpc_address", c.Target()))
+			delete(s.peerClients, id)
+			delete(s.peerBreakers, id)
+			if err := c.Close(); err != nil {
+				s.logger.Warn("failed to close old client", zap.String("id", id), zap.String("grpc_address", c.Target()), zap.Error(err))
 			}
-			for id, node := range nodes {
-				if id == s.raftServer.id {
-					continue
-				}
+		}
+	}
+}
 
-				if node.Metadata == nil || node.Metadata.GrpcAddress == "" {
-					s.logger.Debug("gRPC address missing", zap.String("id", id))
-					continue
-				}
-				if c, ok := s.peerClients[id]; ok {
-					if c.Target() != node.Metadata.GrpcAddress {
-						s.logger.Debug("close client", zap.String("id", id), zap.String("grpc_address", c.Target()))
-						delete(s.peerClients, id)
-						if err := c.Close(); err != nil {
-							s.logger.Warn("failed to close client", zap.String("id", id), zap.String("grpc_address", c.Target()), zap.Error(err))
-						}
-						s.logger.Debug("create client", zap.String("id", id), zap.String("grpc_address", node.Metadata.GrpcAddress))
-						if newClient, err := client.NewGRPCClientWithContextTLS(node.Metadata.GrpcAddress, context.TODO(), s.certificateFile, s.commonName); err == nil {
-							s.peerClients[id] = newClient
-						} else {
-							s.logger.Warn("failed to create client", zap.String("id", id), zap.String("grpc_address", c.Target()), zap.Error(err))
-						}
-					}
-				} else {
-					s.logger.Debug("create client", zap.String("id", id), zap.String("grpc_address", node.Metadata.GrpcAddress))
-					if newClient, err := client.NewGRPCClientWithContextTLS(node.Metadata.GrpcAddress, context.TODO(), s.certificateFile, s.commonName); err == nil {
-						s.peerClients[id] = newClient
-					} else {
-						s.logger.Warn("failed to create client", zap.String("id", id), zap.String("grpc_address", c.Target()), zap.Error(err))
-					}
-				}
-			}
+// callPeer calls f with the client for the specified peer node.
+// The call is skipped while the circuit breaker of the peer is open, so that
+// an unreachable node does not add a dial timeout to every request.
+func (s *GRPCService) callPeer(id string, f func(c *client.GRPCClient) error) error {
+	s.peerMutex.RLock()
+	c, ok := s.peerClients[id]
+	breaker := s.peerBreakers[id]
+	s.peerMutex.RUnlock()
+
+	if !ok {
+		s.logger.Warn("peer client not found", zap.String("id", id))
+		return errors.ErrPeerUnavailable
+	}
 
-			// close clients for non-existent peer nodes
-			for id, c := range s.peerClients {
-				if _, exist := nodes[id]; !exist {
-					s.logger.Debug("close client", zap.String("id", id), zap.String("grpc_address", c.Target()))
-					delete(s.peerClients, id)
-					if err := c.Close(); err != nil {
-						s.logger.Warn("failed to close old client", zap.String("id", id), zap.String("grpc_address", c.Target()), zap.Error(err))
-					}
-				}
-			}
+	if !breaker.Allow() {
+		s.logger.Debug("circuit breaker is open", zap.String("id", id), zap.String("grpc_address", c.Target()))
+		return errors.ErrPeerUnavailable
+	}
 
-			s.watchMutex.Unlock()
-		}
+	err := f(c)
+	if isPeerFailure(err) {
+		breaker.Failure()
+		s.logger.Warn("peer call failed", zap.String("id", id), zap.String("grpc_address", c.Target()), zap.Error(err))
+	} else {
+		breaker.Success()
+	}
+
+	return err
+}
+
+func isPeerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch errors.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// forwardToLeader calls f with the client for the current leader node.
+func (s *GRPCService) forwardToLeader(f func(c *client.GRPCClient) error) error {
+	if !s.forwarding {
+		return s.notLeaderError()
+	}
+
+	leaderID, err := s.raftServer.LeaderID(60 * time.Second)
+	if err != nil {
+		s.logger.Error("failed to detect leader", zap.Error(err))
+		return errors.Convert(err, codes.Unavailable)
+	}
+
+	if err := s.callPeer(string(leaderID), f); err != nil {
+		s.logger.Error("failed to forward request", zap.String("id", string(leaderID)), zap.Error(err))
+		return errors.Convert(err, codes.Internal)
+	}
+
+	return nil
+}
+
+// notLeaderError returns an error telling the client that this node is not the leader.
+// The error carries the leader node if it is known.
+func (s *GRPCService) notLeaderError() error {
+	leaderID, err := s.raftServer.LeaderID(10 * time.Second)
+	if err != nil {
+		s.logger.Warn("failed to detect leader", zap.Error(err))
+		return errors.NotLeader(nil)
 	}
+
+	nodes, err := s.raftServer.Nodes()
+	if err != nil {
+		s.logger.Warn("failed to get cluster info", zap.Error(err))
+		return errors.NotLeader(nil)
+	}
+
+	return errors.NotLeader(&protobuf.LeaderHint{
+		Id:   string(leaderID),
+		Node: nodes[string(leaderID)],
+	})
 }
 
 func (s *GRPCService) stopWatchCluster() {
@@ -164,15 +342,111 @@ func (s *GRPCService) stopWatchCluster() {
 	s.logger.Info("the cluster watching has been stopped")
 
 	s.logger.Info("close all peer clients")
+	s.peerMutex.Lock()
+	defer s.peerMutex.Unlock()
 	for id, c := range s.peerClients {
 		s.logger.Debug("close client", zap.String("id", id), zap.String("grpc_address", c.Target()))
 		delete(s.peerClients, id)
+		delete(s.peerBreakers, id)
 		if err := c.Close(); err != nil {
 			s.logger.Warn("failed to close client", zap.String("id", id), zap.String("grpc_address", c.Target()), zap.Error(err))
 		}
 	}
 }
 
+// startTrackPeers follows the progress of the other nodes. It advances the decommission
+// status of the nodes that left the cluster, and promotes the non-voters that caught up.
+// Only the leader acts on the progress.
+func (s *GRPCService) startTrackPeers(checkInterval time.Duration) {
+	defer func() {
+		close(s.trackPeersDoneCh)
+	}()
+
+	ticker := s.raftServer.clock.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.trackPeersStopCh:
+			return
+		case <-ticker.C():
+			if s.raftServer.raft.State() != raft.Leader {
+				continue
+			}
+			s.trackPeers()
+		}
+	}
+}
+
+func (s *GRPCService) stopTrackPeers() {
+	close(s.trackPeersStopCh)
+	<-s.trackPeersDoneCh
+}
+
+func (s *GRPCService) trackPeers() {
+	statuses, err := s.raftServer.fsm.DecommissionStatuses()
+	if err != nil {
+		s.logger.Warn("failed to get decommission statuses", zap.Error(err))
+		return
+	}
+
+	nonvoters, err := s.raftServer.Nonvoters()
+	if err != nil {
+		s.logger.Warn("failed to get non-voters", zap.Error(err))
+		return
+	}
+
+	pending := len(nonvoters) > 0
+	for _, status := range statuses {
+		if status.Phase != protobuf.DecommissionStatus_SafeToWipe {
+			pending = true
+			break
+		}
+	}
+	if !pending {
+		return
+	}
+
+	appliedIndexes := s.appliedIndexes()
+
+	if err := s.raftServer.advanceDecommissions(appliedIndexes); err != nil {
+		s.logger.Warn("failed to advance decommissions", zap.Error(err))
+	}
+	if err := s.raftServer.promoteNonvoters(appliedIndexes); err != nil {
+		s.logger.Warn("failed to promote non-voters", zap.Error(err))
+	}
+}
+
+// appliedIndexes returns the applied index of this node and of the peers that answered.
+func (s *GRPCService) appliedIndexes() map[string]uint64 {
+	appliedIndexes := map[string]uint64{
+		s.raftServer.id: s.raftServer.fsm.AppliedIndex(),
+	}
+
+	s.peerMutex.RLock()
+	ids := make([]string, 0, len(s.peerClients))
+	for id := range s.peerClients {
+		ids = append(ids, id)
+	}
+	s.peerMutex.RUnlock()
+
+	for _, id := range ids {
+		err := s.callPeer(id, func(c *client.GRPCClient) error {
+			resp, err := c.Node()
+			if err != nil {
+				return err
+			}
+			appliedIndexes[id] = resp.Node.AppliedIndex
+			return nil
+		})
+		if err != nil {
+			s.logger.Debug("failed to get the applied index", zap.String("id", id), zap.Error(err))
+		}
+	}
+
+	return appliedIndexes
+}
+
 func (s *GRPCService) LivenessCheck(ctx context.Context, req *empty.Empty) (*protobuf.LivenessCheckResponse, error) {
 	resp := &protobuf.LivenessCheckResponse{}
 
@@ -184,16 +458,26 @@ func (s *GRPCService) LivenessCheck(ctx context.Context, req *empty.Empty) (*pro
 func (s *GRPCService) ReadinessCheck(ctx context.Context, req *empty.Empty) (*protobuf.ReadinessCheckResponse, error) {
 	resp := &protobuf.ReadinessCheckResponse{}
 
+	// a node without quorum does not become ready by waiting for a leader
+	if s.raftServer.QuorumLost() {
+		return resp, errors.ErrQuorumLost
+	}
+
+	// a node that has not caught up or warmed its caches up would serve slow reads
+	if s.warmingUp() {
+		return resp, errors.Wrap(errors.ErrNodeNotReady, "warming up")
+	}
+
 	timeout := 10 * time.Second
 	if err := s.raftServer.WaitForDetectLeader(timeout); err != nil {
 		s.logger.Error("missing leader node", zap.Error(err))
-		return resp, status.Error(codes.Internal, err.Error())
+		return resp, errors.Convert(err, codes.Internal)
 	}
 
 	if s.raftServer.State() == raft.Candidate || s.raftServer.State() == raft.Shutdown {
 		err := errors.ErrNodeNotReady
 		s.logger.Error(err.Error(), zap.Error(err))
-		return resp, status.Error(codes.Internal, err.Error())
+		return resp, err
 	}
 
 	resp.Ready = true
@@ -205,30 +489,35 @@ func (s *GRPCService) Join(ctx context.Context, req *protobuf.JoinRequest) (*emp
 	resp := &empty.Empty{}
 
 	if s.raftServer.raft.State() != raft.Leader {
-		clusterResp, err := s.Cluster(ctx, &empty.Empty{})
-		if err != nil {
-			s.logger.Error("failed to get cluster info", zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
-		}
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.Join(req)
+		})
+	}
 
-		c := s.peerClients[clusterResp.Cluster.Leader]
-		err = c.Join(req)
+	// a voter is only demoted if the other voters can make progress without it
+	catchUpAsNonvoter := req.CatchUpAsNonvoter
+	if catchUpAsNonvoter {
+		voters, err := s.raftServer.Voters()
 		if err != nil {
-			s.logger.Error("failed to forward request", zap.String("grpc_address", c.Target()), zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
+			return resp, errors.Convert(err, codes.Internal)
+		}
+		appliedIndexes := s.appliedIndexes()
+		for _, id := range voters {
+			if _, ok := appliedIndexes[id]; !ok && id != req.Id {
+				s.logger.Warn("voter unreachable, node rejoins as a voter", zap.String("id", req.Id), zap.String("unreachable", id))
+				catchUpAsNonvoter = false
+				break
+			}
 		}
-
-		return resp, nil
 	}
 
-	err := s.raftServer.Join(req.Id, req.Node)
+	err := s.raftServer.Join(req.Id, req.Node, catchUpAsNonvoter)
 	if err != nil {
-		switch err {
-		case errors.ErrNodeAlreadyExists:
+		if errors.Is(err, errors.ErrNodeAlreadyExists) {
 			s.logger.Debug("node already exists", zap.Any("req", req), zap.Error(err))
-		default:
+		} else {
 			s.logger.Error("failed to join node to the cluster", zap.String("id", req.Id), zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
+			return resp, errors.Convert(err, codes.Internal)
 		}
 	}
 
@@ -239,26 +528,180 @@ func (s *GRPCService) Leave(ctx context.Context, req *protobuf.LeaveRequest) (*e
 	resp := &empty.Empty{}
 
 	if s.raftServer.raft.State() != raft.Leader {
-		clusterResp, err := s.Cluster(ctx, &empty.Empty{})
-		if err != nil {
-			s.logger.Error("failed to get cluster info", zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
-		}
-
-		c := s.peerClients[clusterResp.Cluster.Leader]
-		err = c.Leave(req)
-		if err != nil {
-			s.logger.Error("failed to forward request", zap.String("grpc_address", c.Target()), zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
-		}
-
-		return resp, nil
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.Leave(req)
+		})
 	}
 
 	err := s.raftServer.Leave(req.Id)
 	if err != nil {
 		s.logger.Error("failed to leave node from the cluster", zap.Any("req", req), zap.Error(err))
-		return resp, status.Error(codes.Internal, err.Error())
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) BatchJoin(ctx context.Context, req *protobuf.BatchJoinRequest) (*protobuf.BatchJoinResponse, error) {
+	resp := &protobuf.BatchJoinResponse{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		err := s.forwardToLeader(func(c *client.GRPCClient) error {
+			var err error
+			resp, err = c.BatchJoin(req)
+			return err
+		})
+		return resp, err
+	}
+
+	joined, left, err := s.raftServer.BatchJoin(req.Nodes)
+	resp.Joined = joined
+	resp.Left = left
+	if err != nil {
+		s.logger.Error("failed to converge the cluster membership", zap.Any("req", req), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) MembershipSpec(ctx context.Context, req *empty.Empty) (*protobuf.MembershipSpecResponse, error) {
+	resp := &protobuf.MembershipSpecResponse{}
+
+	spec, err := s.raftServer.MembershipSpec()
+	if err != nil {
+		s.logger.Error("failed to get membership spec", zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+	if spec == nil {
+		return resp, errors.ErrNotFound
+	}
+
+	resp.Spec = spec
+
+	return resp, nil
+}
+
+func (s *GRPCService) Annotate(ctx context.Context, req *protobuf.AnnotateRequest) (*empty.Empty, error) {
+	resp := &empty.Empty{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.Annotate(req)
+		})
+	}
+
+	err := s.raftServer.Annotate(req)
+	if err != nil {
+		s.logger.Error("failed to annotate node", zap.Any("req", req), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) UpdateNodeStatus(ctx context.Context, req *protobuf.UpdateNodeStatusRequest) (*empty.Empty, error) {
+	resp := &empty.Empty{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.UpdateNodeStatus(req)
+		})
+	}
+
+	err := s.raftServer.UpdateNodeStatus(req)
+	if err != nil {
+		s.logger.Error("failed to update node status", zap.Any("req", req), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) CreateAPIKey(ctx context.Context, req *protobuf.CreateAPIKeyRequest) (*protobuf.CreateAPIKeyResponse, error) {
+	resp := &protobuf.CreateAPIKeyResponse{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		err := s.forwardToLeader(func(c *client.GRPCClient) error {
+			var err error
+			resp, err = c.CreateAPIKey(req)
+			return err
+		})
+		return resp, err
+	}
+
+	resp, err := s.raftServer.CreateAPIKey(req)
+	if err != nil {
+		s.logger.Error("failed to create API key", zap.String("prefix", req.Prefix), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) RevokeAPIKey(ctx context.Context, req *protobuf.RevokeAPIKeyRequest) (*empty.Empty, error) {
+	resp := &empty.Empty{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.RevokeAPIKey(req)
+		})
+	}
+
+	err := s.raftServer.RevokeAPIKey(req)
+	if err != nil {
+		s.logger.Error("failed to revoke API key", zap.String("id", req.Id), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) APIKeys(ctx context.Context, req *empty.Empty) (*protobuf.APIKeysResponse, error) {
+	resp := &protobuf.APIKeysResponse{}
+
+	apiKeys, err := s.raftServer.APIKeys()
+	if err != nil {
+		s.logger.Error("failed to get API keys", zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	resp.ApiKeys = apiKeys
+
+	return resp, nil
+}
+
+func (s *GRPCService) SetMembershipSpec(ctx context.Context, req *protobuf.SetMembershipSpecRequest) (*empty.Empty, error) {
+	resp := &empty.Empty{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.SetMembershipSpec(req)
+		})
+	}
+
+	err := s.raftServer.SetMembershipSpec(req.Spec)
+	if err != nil {
+		s.logger.Error("failed to set membership spec", zap.Any("req", req), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) DeleteMembershipSpec(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	resp := &empty.Empty{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.DeleteMembershipSpec()
+		})
+	}
+
+	err := s.raftServer.DeleteMembershipSpec()
+	if err != nil {
+		s.logger.Error("failed to delete membership spec", zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
 	}
 
 	return resp, nil
@@ -270,10 +713,19 @@ func (s *GRPCService) Node(ctx context.Context, req *empty.Empty) (*protobuf.Nod
 	node, err := s.raftServer.Node()
 	if err != nil {
 		s.logger.Error("failed to get node info", zap.String("err", err.Error()))
-		return resp, status.Error(codes.Internal, err.Error())
+		return resp, errors.Convert(err, codes.Internal)
 	}
 
+	node.Ready = s.ready()
 	resp.Node = node
+	resp.Time = ptypes.TimestampNow()
+	resp.Version = version.Version
+	if free, total, err := diskUsage(s.raftServer.dataDirectory); err != nil {
+		s.logger.Debug("failed to get disk usage", zap.String("data_directory", s.raftServer.dataDirectory), zap.Error(err))
+	} else {
+		resp.DiskFreeBytes = free
+		resp.DiskTotalBytes = total
+	}
 
 	return resp, nil
 }
@@ -286,20 +738,30 @@ func (s *GRPCService) Cluster(ctx context.Context, req *empty.Empty) (*protobuf.
 	nodes, err := s.raftServer.Nodes()
 	if err != nil {
 		s.logger.Error("failed to get cluster info", zap.String("err", err.Error()))
-		return resp, status.Error(codes.Internal, err.Error())
+		return resp, errors.Convert(err, codes.Internal)
 	}
 
 	for id, node := range nodes {
-		if id == s.raftServer.id {
+		switch {
+		case id == s.raftServer.id:
 			node.State = s.raftServer.StateStr()
-		} else {
-			c := s.peerClients[id]
-			nodeResp, err := c.Node()
+			node.Ready = s.ready()
+		case s.nodeStatusInterval > 0 && node.State != "":
+			// the node propagates its state
+		default:
+			err := s.callPeer(id, func(c *client.GRPCClient) error {
+				nodeResp, err := c.Node()
+				if err != nil {
+					return err
+				}
+				node.State = nodeResp.Node.State
+				node.Ready = nodeResp.Node.Ready
+				return nil
+			})
 			if err != nil {
 				node.State = raft.Shutdown.String()
-				s.logger.Error("failed to get node info", zap.String("grpc_address", node.Metadata.GrpcAddress), zap.String("err", err.Error()))
-			} else {
-				node.State = nodeResp.Node.State
+				node.Ready = false
+				s.logger.Error("failed to get node info", zap.String("id", id), zap.String("err", err.Error()))
 			}
 		}
 	}
@@ -308,10 +770,14 @@ func (s *GRPCService) Cluster(ctx context.Context, req *empty.Empty) (*protobuf.
 	serverID, err := s.raftServer.LeaderID(60 * time.Second)
 	if err != nil {
 		s.logger.Error("failed to get cluster info", zap.String("err", err.Error()))
-		return resp, status.Error(codes.Internal, err.Error())
+		return resp, errors.Convert(err, codes.Internal)
 	}
 	cluster.Leader = string(serverID)
 
+	if cluster.MembershipEpoch, err = s.raftServer.fsm.MembershipEpoch(); err != nil {
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
 	resp.Cluster = cluster
 
 	return resp, nil
@@ -323,7 +789,155 @@ func (s *GRPCService) Snapshot(ctx context.Context, req *empty.Empty) (*empty.Em
 	err := s.raftServer.Snapshot()
 	if err != nil {
 		s.logger.Error("failed to snapshot data", zap.String("err", err.Error()))
-		return resp, status.Error(codes.Internal, err.Error())
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) Hash(ctx context.Context, req *empty.Empty) (*protobuf.HashResponse, error) {
+	resp := &protobuf.HashResponse{}
+
+	appliedIndex, hash, err := s.raftServer.Hash()
+	if err != nil {
+		s.logger.Error("failed to hash data", zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	resp.AppliedIndex = appliedIndex
+	resp.Hash = hex.EncodeToString(hash)
+
+	return resp, nil
+}
+
+func (s *GRPCService) StorageLevels(ctx context.Context, req *protobuf.StorageLevelsRequest) (*protobuf.StorageLevelsResponse, error) {
+	resp := &protobuf.StorageLevelsResponse{}
+
+	tables := s.raftServer.fsm.Tables()
+	levels := make([]*protobuf.StorageLevel, 0)
+	entries := uint64(0)
+	for _, table := range tables {
+		for len(levels) <= table.Level {
+			levels = append(levels, &protobuf.StorageLevel{Level: int32(len(levels))})
+		}
+		levels[table.Level].Tables++
+		levels[table.Level].Keys += table.Keys
+		levels[table.Level].Size += table.Size
+		entries += table.Keys
+
+		if req.Tables {
+			resp.Tables = append(resp.Tables, &protobuf.StorageTable{
+				Id:    table.ID,
+				Level: int32(table.Level),
+				Left:  table.Left,
+				Right: table.Right,
+				Keys:  table.Keys,
+				Size:  table.Size,
+			})
+		}
+	}
+	resp.Levels = levels
+	resp.LsmSize, resp.VlogSize = s.raftServer.fsm.Size()
+
+	if req.CountLiveKeys {
+		liveKeys, err := s.raftServer.fsm.LiveKeys()
+		if err != nil {
+			return resp, errors.Convert(err, codes.Internal)
+		}
+		resp.LiveKeys = liveKeys
+		// the keys still in the memtables are not in the tables yet
+		if entries > liveKeys {
+			resp.StaleRatio = float64(entries-liveKeys) / float64(entries)
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) Dump(ctx context.Context, req *empty.Empty) (*protobuf.DumpResponse, error) {
+	resp := &protobuf.DumpResponse{}
+
+	path, bundle, err := s.dumpDiagnostics()
+	if err != nil {
+		s.logger.Error("failed to dump diagnostics", zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	resp.Path = path
+	resp.Bundle = bundle
+
+	return resp, nil
+}
+
+func (s *GRPCService) DecommissionStatus(ctx context.Context, req *protobuf.DecommissionStatusRequest) (*protobuf.DecommissionStatusResponse, error) {
+	resp := &protobuf.DecommissionStatusResponse{}
+
+	status, err := s.raftServer.DecommissionStatus(req.Id)
+	if err != nil {
+		s.logger.Debug("failed to get decommission status", zap.String("id", req.Id), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	resp.Status = status
+
+	return resp, nil
+}
+
+func (s *GRPCService) Role(ctx context.Context, req *empty.Empty) (*protobuf.RoleResponse, error) {
+	resp, err := s.raftServer.Role()
+	if err != nil {
+		s.logger.Error("failed to get role", zap.Error(err))
+		return &protobuf.RoleResponse{}, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) SetRole(ctx context.Context, req *protobuf.SetRoleRequest) (*empty.Empty, error) {
+	resp := &empty.Empty{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.SetRole(req)
+		})
+	}
+
+	err := s.raftServer.SetRole(ctx, req)
+	if err != nil {
+		s.logger.Error("failed to set role", zap.Any("req", req), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) ClusterConfig(ctx context.Context, req *empty.Empty) (*protobuf.ClusterConfigResponse, error) {
+	resp := &protobuf.ClusterConfigResponse{}
+
+	config, err := s.raftServer.ClusterConfig()
+	if err != nil {
+		s.logger.Error("failed to get cluster configuration", zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	resp.Config = config
+
+	return resp, nil
+}
+
+func (s *GRPCService) SetClusterConfig(ctx context.Context, req *protobuf.SetClusterConfigRequest) (*empty.Empty, error) {
+	resp := &empty.Empty{}
+
+	if s.raftServer.raft.State() != raft.Leader {
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.SetClusterConfig(req)
+		})
+	}
+
+	err := s.raftServer.SetClusterConfig(ctx, req)
+	if err != nil {
+		s.logger.Error("failed to set cluster configuration", zap.Any("req", req), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
 	}
 
 	return resp, nil
@@ -332,18 +946,65 @@ func (s *GRPCService) Snapshot(ctx context.Context, req *empty.Empty) (*empty.Em
 func (s *GRPCService) Get(ctx context.Context, req *protobuf.GetRequest) (*protobuf.GetResponse, error) {
 	resp := &protobuf.GetResponse{}
 
+	if isSystemKey(req.Key) {
+		return resp, errors.ErrReservedKey
+	}
+
 	var err error
 
-	resp, err = s.raftServer.Get(req)
+	switch req.Consistency {
+	case protobuf.GetRequest_Strong:
+		if s.raftServer.raft.State() != raft.Leader {
+			err := s.forwardToLeader(func(c *client.GRPCClient) error {
+				var err error
+				resp, err = c.Get(req)
+				return err
+			})
+			return resp, err
+		}
+		if err := s.raftServer.VerifyRead(10 * time.Second); err != nil {
+			return resp, errors.Convert(err, codes.Internal)
+		}
+	case protobuf.GetRequest_LeaderPreferred:
+		// read on the leader, or locally if the leader can not be reached
+		switch {
+		case s.raftServer.raft.Leader() == "":
+			s.logger.Debug("no leader, fall back to a stale read", zap.String("key", req.Key))
+		case s.raftServer.raft.State() != raft.Leader:
+			strongReq := &protobuf.GetRequest{
+				Key:         req.Key,
+				Consistency: protobuf.GetRequest_Strong,
+				Offset:      req.Offset,
+				Length:      req.Length,
+			}
+			err := s.forwardToLeader(func(c *client.GRPCClient) error {
+				var err error
+				resp, err = c.Get(strongReq)
+				return err
+			})
+			if err == nil || !errors.IsRetryable(err) {
+				return resp, err
+			}
+			s.logger.Debug("leader unavailable, fall back to a stale read", zap.String("key", req.Key), zap.Error(err))
+		default:
+			if err := s.raftServer.VerifyRead(10 * time.Second); err != nil {
+				s.logger.Debug("failed to verify the read, fall back to a stale read", zap.String("key", req.Key), zap.Error(err))
+			}
+		}
+	}
+
+	if s.transformers.transforms(req.Key) {
+		resp, err = s.transformedGet(ctx, req)
+	} else {
+		resp, err = s.raftServer.Get(req)
+	}
 	if err != nil {
-		switch err {
-		case errors.ErrNotFound:
+		if errors.Is(err, errors.ErrNotFound) {
 			s.logger.Debug("key not found", zap.String("key", req.Key), zap.String("err", err.Error()))
-			return resp, status.Error(codes.NotFound, err.Error())
-		default:
+		} else {
 			s.logger.Debug("failed to get data", zap.String("key", req.Key), zap.String("err", err.Error()))
-			return resp, status.Error(codes.Internal, err.Error())
 		}
+		return resp, errors.Convert(err, codes.Internal)
 	}
 
 	return resp, nil
@@ -352,44 +1013,65 @@ func (s *GRPCService) Get(ctx context.Context, req *protobuf.GetRequest) (*proto
 func (s *GRPCService) Scan(ctx context.Context, req *protobuf.ScanRequest) (*protobuf.ScanResponse, error) {
 	resp := &protobuf.ScanResponse{}
 
+	if isSystemKey(req.Prefix) {
+		return resp, errors.ErrReservedKey
+	}
+
 	var err error
 
-	resp, err = s.raftServer.Scan(req)
+	if s.transformers.transforms(req.Prefix) {
+		resp, err = s.transformedScan(ctx, req)
+	} else {
+		resp, err = s.raftServer.Scan(req)
+	}
 	if err != nil {
 		switch err {
 		default:
 			s.logger.Debug("failed to scan data", zap.String("prefix", req.Prefix), zap.String("err", err.Error()))
-			return resp, status.Error(codes.Internal, err.Error())
+			return resp, errors.Convert(err, codes.Internal)
 		}
 	}
 
 	return resp, nil
 }
 
+func (s *GRPCService) Export(ctx context.Context, req *protobuf.ExportRequest) (*protobuf.ExportResponse, error) {
+	resp := &protobuf.ExportResponse{}
+
+	var err error
+
+	resp, err = s.raftServer.Export(ctx, req)
+	if err != nil {
+		s.logger.Error("failed to export data", zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
 func (s *GRPCService) Set(ctx context.Context, req *protobuf.SetRequest) (*empty.Empty, error) {
 	resp := &empty.Empty{}
 
-	if s.raftServer.raft.State() != raft.Leader {
-		clusterResp, err := s.Cluster(ctx, &empty.Empty{})
-		if err != nil {
-			s.logger.Error("failed to get cluster info", zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
-		}
+	if isSystemKey(req.Key) {
+		return resp, errors.ErrReservedKey
+	}
 
-		c := s.peerClients[clusterResp.Cluster.Leader]
-		err = c.Set(req)
-		if err != nil {
-			s.logger.Error("failed to forward request", zap.String("grpc_address", c.Target()), zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
-		}
+	if err := s.transformers.validate(ctx, req.Key, req.Value); err != nil {
+		s.logger.Debug("invalid value", zap.String("key", req.Key), zap.Error(err))
+		return resp, err
+	}
 
-		return resp, nil
+	if s.raftServer.raft.State() != raft.Leader {
+		md := metadata.New(s.raftServer.requestMetadata(ctx))
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.Set(req, client.OutgoingMetadata(md))
+		})
 	}
 
-	err := s.raftServer.Set(req)
+	err := s.raftServer.Set(ctx, req)
 	if err != nil {
 		s.logger.Error("failed to put data", zap.Any("req", req), zap.Error(err))
-		return resp, status.Error(codes.Internal, err.Error())
+		return resp, errors.Convert(err, codes.Internal)
 	}
 
 	return resp, nil
@@ -398,54 +1080,137 @@ func (s *GRPCService) Set(ctx context.Context, req *protobuf.SetRequest) (*empty
 func (s *GRPCService) Delete(ctx context.Context, req *protobuf.DeleteRequest) (*empty.Empty, error) {
 	resp := &empty.Empty{}
 
+	if isSystemKey(req.Key) {
+		return resp, errors.ErrReservedKey
+	}
+
 	if s.raftServer.raft.State() != raft.Leader {
-		clusterResp, err := s.Cluster(ctx, &empty.Empty{})
-		if err != nil {
-			s.logger.Error("failed to get cluster info", zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
-		}
+		md := metadata.New(s.raftServer.requestMetadata(ctx))
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.Delete(req, client.OutgoingMetadata(md))
+		})
+	}
 
-		c := s.peerClients[clusterResp.Cluster.Leader]
-		err = c.Delete(req)
-		if err != nil {
-			s.logger.Error("failed to forward request", zap.String("grpc_address", c.Target()), zap.Error(err))
-			return resp, status.Error(codes.Internal, err.Error())
-		}
+	err := s.raftServer.Delete(ctx, req)
+	if err != nil {
+		s.logger.Error("failed to delete data", zap.String("key", req.Key), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) Copy(ctx context.Context, req *protobuf.CopyRequest) (*empty.Empty, error) {
+	resp := &empty.Empty{}
 
-		return resp, nil
+	if isSystemKey(req.Source) || isSystemKey(req.Destination) {
+		return resp, errors.ErrReservedKey
 	}
 
-	err := s.raftServer.Delete(req)
+	if s.raftServer.raft.State() != raft.Leader {
+		md := metadata.New(s.raftServer.requestMetadata(ctx))
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.Copy(req, client.OutgoingMetadata(md))
+		})
+	}
+
+	err := s.raftServer.Copy(ctx, req)
 	if err != nil {
-		s.logger.Error("failed to delete data", zap.String("key", req.Key), zap.Error(err))
-		return resp, status.Error(codes.Internal, err.Error())
+		s.logger.Error("failed to copy data", zap.String("source", req.Source), zap.String("destination", req.Destination), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
 	}
 
 	return resp, nil
 }
 
-func (s *GRPCService) Watch(req *empty.Empty, server protobuf.KVS_WatchServer) error {
-	chans := make(chan protobuf.WatchResponse)
+func (s *GRPCService) Move(ctx context.Context, req *protobuf.MoveRequest) (*empty.Empty, error) {
+	resp := &empty.Empty{}
+
+	if isSystemKey(req.Source) || isSystemKey(req.Destination) {
+		return resp, errors.ErrReservedKey
+	}
 
-	s.watchMutex.Lock()
-	s.watchChans[chans] = struct{}{}
-	s.watchMutex.Unlock()
+	if s.raftServer.raft.State() != raft.Leader {
+		md := metadata.New(s.raftServer.requestMetadata(ctx))
+		return resp, s.forwardToLeader(func(c *client.GRPCClient) error {
+			return c.Move(req, client.OutgoingMetadata(md))
+		})
+	}
 
-	defer func() {
-		s.watchMutex.Lock()
-		delete(s.watchChans, chans)
-		s.watchMutex.Unlock()
-		close(chans)
-	}()
+	err := s.raftServer.Move(ctx, req)
+	if err != nil {
+		s.logger.Error("failed to move data", zap.String("source", req.Source), zap.String("destination", req.Destination), zap.Error(err))
+		return resp, errors.Convert(err, codes.Internal)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCService) Watch(req *protobuf.WatchRequest, server protobuf.KVS_WatchServer) error {
+	if isSystemKey(req.Prefix) {
+		return errors.ErrReservedKey
+	}
+
+	w := s.watchers.register(req.Prefix)
+	defer s.watchers.unregister(w)
 
-	for resp := range chans {
-		if err := server.Send(&resp); err != nil {
-			s.logger.Error("failed to send watch data", zap.String("event", resp.Event.String()), zap.Error(err))
-			return status.Error(codes.Internal, err.Error())
+	sender := newWatchSender(req, server.Send)
+
+	// the events up to this index are not sent from the buffer
+	lastIndex := s.raftServer.fsm.AppliedIndex()
+
+	if req.ResumeToken != "" {
+		from, err := strconv.ParseUint(req.ResumeToken, 10, 64)
+		if err != nil {
+			return errors.Convert(fmt.Errorf("invalid resume token %q", req.ResumeToken), codes.InvalidArgument)
+		}
+		if err := s.raftServer.replayEvents(from+1, lastIndex, req.Prefix, sender.sendResponse); err != nil {
+			s.logger.Error("failed to replay watch data", zap.Uint64("from", from+1), zap.Error(err))
+			return errors.Convert(err, codes.Internal)
+		}
+		if err := sender.flush(); err != nil {
+			s.logger.Error("failed to send watch data", zap.Error(err))
+			return errors.Convert(err, codes.Internal)
+		}
+		if from > lastIndex {
+			lastIndex = from
 		}
 	}
 
-	return nil
+	for {
+		select {
+		case <-server.Context().Done():
+			return nil
+		case <-w.cancelCh:
+			s.logger.Warn("cancel the watch", zap.String("prefix", req.Prefix), zap.Uint64("index", lastIndex), zap.Error(errors.ErrWatcherTooSlow))
+			_ = sender.sendResponse(&protobuf.WatchResponse{ResumeToken: strconv.FormatUint(lastIndex, 10)})
+			return errors.ErrWatcherTooSlow
+		case resp := <-w.ch:
+			s.watchers.received()
+			if resp.Index <= lastIndex {
+				continue
+			}
+			if gap := w.takeGap(resp.Index); gap != nil {
+				if err := sender.sendResponse(&protobuf.WatchResponse{Gap: gap}); err != nil {
+					s.logger.Error("failed to send watch gap", zap.Error(err))
+					return errors.Convert(err, codes.Internal)
+				}
+			}
+			if err := sender.sendResponse(resp); err != nil {
+				s.logger.Error("failed to send watch data", zap.String("event", resp.Event.String()), zap.Error(err))
+				return errors.Convert(err, codes.Internal)
+			}
+			lastIndex = resp.Index
+
+			// the compact events are sent once the queued events are encoded
+			if len(w.ch) == 0 {
+				if err := sender.flush(); err != nil {
+					s.logger.Error("failed to send watch data", zap.Error(err))
+					return errors.Convert(err, codes.Internal)
+				}
+			}
+		}
+	}
 }
 
 func (s *GRPCService) Metrics(ctx context.Context, req *empty.Empty) (*protobuf.MetricsResponse, error) {
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+
+	"github.com/mosuka/cete/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type validator interface {
+	Validate() error
+}
+
+// validationUnaryServerInterceptor rejects malformed requests with InvalidArgument
+// before they reach the service.
+func validationUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if v, ok := req.(validator); ok {
+			if err := v.Validate(); err != nil {
+				return nil, errors.Convert(err, codes.InvalidArgument)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// identityFile is the file of the data directory holding the ID of the node the data
+// belongs to.
+const identityFile = "node_id"
+
+// IDGenerator generates the ID of a new node.
+type IDGenerator func() (string, error)
+
+// UUIDGenerator generates random (version 4) UUIDs.
+func UUIDGenerator() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// HostnameGenerator uses the host name as the ID, for hosts running a single node.
+func HostnameGenerator() (string, error) {
+	return os.Hostname()
+}
+
+// NodeIdentity returns the ID of the node the data directory belongs to, and records it
+// in the data directory the first time. If the ID is empty, the recorded ID is used, or
+// a new one is generated; a nil generator requires the ID. It fails if the ID differs
+// from the recorded one, so that a node can not start on the data of another member.
+// The options locate the stores the same way as for the Raft server.
+func NodeIdentity(dataDirectory string, id string, generate IDGenerator, opts ...RaftServerOption) (string, error) {
+	path := filepath.Join(dataDirectory, identityFile)
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil:
+		recorded := strings.TrimSpace(string(b))
+		if id != "" && id != recorded {
+			return "", fmt.Errorf("data directory %s belongs to node %s, not %s", dataDirectory, recorded, id)
+		}
+		return recorded, nil
+	case !os.IsNotExist(err):
+		return "", err
+	}
+
+	if id == "" {
+		// the data of a node started before the identity was recorded has no identity file
+		o := defaultRaftOptions()
+		for _, opt := range opts {
+			opt(o)
+		}
+		_, raftDirectory, _ := o.directories(dataDirectory)
+		if files, err := ioutil.ReadDir(raftDirectory); err == nil && len(files) > 0 {
+			return "", fmt.Errorf("data directory %s has no recorded node ID, specify the ID it was started with", dataDirectory)
+		}
+
+		if generate == nil {
+			return "", fmt.Errorf("node ID is required")
+		}
+		if id, err = generate(); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.MkdirAll(dataDirectory, 0755); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(path, []byte(id+"\n"), 0644); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
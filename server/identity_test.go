@@ -0,0 +1,42 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestNodeIdentity(t *testing.T) {
+	dir := testTempDir(t)
+
+	// a new node records the generated ID
+	id, err := NodeIdentity(dir, "", UUIDGenerator)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("expected a UUID, saw %q", id)
+	}
+	if recorded, err := NodeIdentity(dir, "", UUIDGenerator); err != nil || recorded != id {
+		t.Errorf("expected the recorded ID %s, saw %s, %v", id, recorded, err)
+	}
+	if recorded, err := NodeIdentity(dir, id, nil); err != nil || recorded != id {
+		t.Errorf("expected the configured ID %s to match, saw %s, %v", id, recorded, err)
+	}
+	if _, err := NodeIdentity(dir, "node2", UUIDGenerator); err == nil {
+		t.Errorf("expected another ID to be refused")
+	}
+
+	// the data of a node started before the identity was recorded needs the ID
+	dir = testTempDir(t)
+	if err := os.MkdirAll(filepath.Join(dir, "raft", "log"), 0755); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, err := NodeIdentity(dir, "", UUIDGenerator); err == nil {
+		t.Errorf("expected an ID to be required")
+	}
+	if recorded, err := NodeIdentity(dir, "node1", UUIDGenerator); err != nil || recorded != "node1" {
+		t.Errorf("expected node1 to be recorded, saw %s, %v", recorded, err)
+	}
+}
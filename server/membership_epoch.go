@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// membershipEpochKey holds the number of membership changes applied.
+const membershipEpochKey = systemKeyPrefix + "membership_epoch"
+
+// Keys with this prefix hold the membership epoch reached by the log entry of a membership
+// change, by index, so that the replayed events carry it too.
+const membershipChangeKeyPrefix = systemKeyPrefix + "membership_change/"
+
+func membershipChangeKey(index uint64) string {
+	return fmt.Sprintf("%s%020d", membershipChangeKeyPrefix, index)
+}
+
+func decodeEpoch(value []byte) (uint64, error) {
+	if len(value) != 8 {
+		return 0, errors.Wrapf(errors.ErrUnexpectedPayloadType, "membership epoch of %d bytes", len(value))
+	}
+
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// isMember tells whether the node joined the cluster and did not leave it.
+func (f *RaftFSM) isMember(id string) bool {
+	f.nodesMutex.RLock()
+	defer f.nodesMutex.RUnlock()
+
+	_, exists := f.metadata[id]
+
+	return exists
+}
+
+// MembershipEpoch returns the number of membership changes applied, 0 before the first
+// node joined.
+func (f *RaftFSM) MembershipEpoch() (uint64, error) {
+	value, err := f.kvs.Get(membershipEpochKey)
+	if errors.Is(err, errors.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get membership epoch", zap.Error(err))
+		return 0, err
+	}
+
+	return decodeEpoch(value)
+}
+
+// membershipEpochAt returns the membership epoch reached by the log entry at the index, or
+// 0 if the entry did not change the membership.
+func (f *RaftFSM) membershipEpochAt(index uint64) (uint64, error) {
+	value, err := f.kvs.Get(membershipChangeKey(index))
+	if errors.Is(err, errors.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeEpoch(value)
+}
+
+// applyMembershipChange increments the membership epoch for the Join or Leave event of the
+// log entry at the index, and stamps the event with it.
+func (f *RaftFSM) applyMembershipChange(event *protobuf.Event, index uint64) interface{} {
+	epoch, err := f.MembershipEpoch()
+	if err != nil {
+		return err
+	}
+	epoch++
+
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, epoch)
+	if err := f.kvs.Batch(map[string][]byte{membershipEpochKey: value, membershipChangeKey(index): value}, nil); err != nil {
+		f.logger.Error("failed to set membership epoch", zap.Uint64("epoch", epoch), zap.Error(err))
+		return err
+	}
+	event.MembershipEpoch = epoch
+
+	f.logger.Info("membership changed", zap.String("type", event.Type.String()), zap.Uint64("epoch", epoch), zap.Uint64("index", index))
+
+	return nil
+}
+
+// stampMembershipEpoch sets the membership epoch of a replayed Join or Leave event.
+func (s *RaftServer) stampMembershipEpoch(event *protobuf.Event, index uint64) {
+	if event.Type != protobuf.Event_Join && event.Type != protobuf.Event_Leave {
+		return
+	}
+	epoch, err := s.fsm.membershipEpochAt(index)
+	if err != nil {
+		s.logger.Warn("failed to get the membership epoch of a replayed event", zap.Uint64("index", index), zap.Error(err))
+		return
+	}
+	event.MembershipEpoch = epoch
+}
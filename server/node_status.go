@@ -0,0 +1,140 @@
+package server
+
+import (
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNodeStatusInterval = 10 * time.Second
+	defaultNodeStatusJitter   = 2 * time.Second
+)
+
+// startPropagateNodeStatus replicates the status of this node, i.e. its state, addresses
+// and readiness, at the interval plus a random jitter, so that the nodes of a cluster do
+// not report at once. Only the changes are written. The leader also reports the nodes it
+// can not reach as shut down, since they can not report themselves.
+func (s *GRPCService) startPropagateNodeStatus(interval time.Duration, jitter time.Duration) {
+	s.logger.Info("start to propagate node status", zap.Duration("interval", interval), zap.Duration("jitter", jitter))
+
+	defer func() {
+		close(s.nodeStatusDoneCh)
+	}()
+
+	for {
+		delay := interval
+		if jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(jitter)))
+		}
+		timer := s.raftServer.clock.NewTimer(delay)
+
+		select {
+		case <-s.nodeStatusStopCh:
+			timer.Stop()
+			s.logger.Info("received a request to stop propagating node status")
+			return
+		case <-timer.C():
+			s.propagateNodeStatus()
+		}
+	}
+}
+
+func (s *GRPCService) stopPropagateNodeStatus() {
+	if s.nodeStatusInterval <= 0 {
+		return
+	}
+
+	close(s.nodeStatusStopCh)
+	<-s.nodeStatusDoneCh
+}
+
+func (s *GRPCService) propagateNodeStatus() {
+	status := &protobuf.NodeStatus{
+		State:    s.raftServer.StateStr(),
+		Metadata: s.nodeMetadata,
+		Ready:    s.ready(),
+	}
+	s.reportNodeStatus(s.raftServer.id, status)
+
+	if s.raftServer.State() != raft.Leader {
+		return
+	}
+
+	cf := s.raftServer.raft.GetConfiguration()
+	if err := cf.Error(); err != nil {
+		s.logger.Warn("failed to get Raft configuration", zap.Error(err))
+		return
+	}
+	for _, server := range cf.Configuration().Servers {
+		id := string(server.ID)
+		if id == s.raftServer.id {
+			continue
+		}
+		recorded, err := s.raftServer.fsm.NodeStatus(id)
+		if err != nil || recorded == nil || recorded.State == raft.Shutdown.String() {
+			continue
+		}
+
+		conn, err := net.DialTimeout("tcp", string(server.Address), time.Second)
+		if err == nil {
+			_ = conn.Close()
+			continue
+		}
+		s.reportNodeStatus(id, &protobuf.NodeStatus{
+			State:    raft.Shutdown.String(),
+			Metadata: recorded.Metadata,
+		})
+	}
+}
+
+// reportNodeStatus replicates the status of the node unless it is already recorded.
+func (s *GRPCService) reportNodeStatus(id string, status *protobuf.NodeStatus) {
+	recorded, err := s.raftServer.fsm.NodeStatus(id)
+	if err != nil {
+		s.logger.Warn("failed to get node status", zap.String("id", id), zap.Error(err))
+		return
+	}
+	if recorded != nil && proto.Equal(recorded, status) {
+		return
+	}
+
+	req := &protobuf.UpdateNodeStatusRequest{
+		Id:     id,
+		Status: status,
+	}
+	if s.raftServer.State() == raft.Leader {
+		err = s.raftServer.UpdateNodeStatus(req)
+	} else {
+		// the status is sent to the leader whether forwarding is enabled or not
+		var leaderID raft.ServerID
+		if leaderID, err = s.raftServer.LeaderID(time.Second); err == nil {
+			err = s.callPeer(string(leaderID), func(c *client.GRPCClient) error {
+				return c.UpdateNodeStatus(req)
+			})
+		}
+	}
+	if err != nil {
+		// a node reports its status before it has joined the cluster
+		s.logger.Debug("failed to update node status", zap.String("id", id), zap.Error(err))
+		return
+	}
+
+	s.logger.Info("node status updated", zap.String("id", id), zap.String("state", status.State), zap.Bool("ready", status.Ready))
+}
+
+// ready tells whether the node is ready, without waiting for a leader.
+func (s *GRPCService) ready() bool {
+	if s.raftServer.QuorumLost() || s.warmingUp() || s.raftServer.raft.Leader() == "" {
+		return false
+	}
+
+	state := s.raftServer.State()
+	return state != raft.Candidate && state != raft.Shutdown
+}
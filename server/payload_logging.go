@@ -0,0 +1,282 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"path"
+	"regexp"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/mosuka/cete/marshaler"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+const redacted = "[REDACTED]"
+
+// PayloadLogging logs the requests and responses of some methods, so that the integration
+// of a client can be debugged from the logs of the node. The payloads of other clients
+// are logged as well, so redact their sensitive values, and disable it once done.
+type PayloadLogging struct {
+	// the names of the gRPC methods whose payloads are logged, e.g. "Get" or "Set"
+	Methods []string
+	// regular expressions matched against the keys, the values of the keys matching are redacted
+	RedactKeys []string
+	// regular expressions matched against the values, the values matching are redacted
+	RedactValues []string
+}
+
+type payloadLogger struct {
+	methods      map[string]bool
+	redactKeys   []*regexp.Regexp
+	redactValues []*regexp.Regexp
+	jsonpb       *jsonpb.Marshaler
+	logger       *zap.Logger
+}
+
+// newPayloadLogger returns nil if no method is logged.
+func newPayloadLogger(p *PayloadLogging, logger *zap.Logger) (*payloadLogger, error) {
+	if p == nil || len(p.Methods) == 0 {
+		return nil, nil
+	}
+
+	l := &payloadLogger{
+		methods: make(map[string]bool, len(p.Methods)),
+		jsonpb:  &jsonpb.Marshaler{OrigName: true},
+		logger:  logger,
+	}
+	for _, method := range p.Methods {
+		l.methods[method] = true
+	}
+	for _, pattern := range p.RedactKeys {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		l.redactKeys = append(l.redactKeys, re)
+	}
+	for _, pattern := range p.RedactValues {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		l.redactValues = append(l.redactValues, re)
+	}
+
+	return l, nil
+}
+
+func (l *payloadLogger) applies(fullMethod string) bool {
+	return l != nil && l.methods[path.Base(fullMethod)]
+}
+
+// log logs the payload with its values redacted. All the values are redacted if the key
+// of the request matches a redaction pattern. The decoder decodes the compact events of
+// a watch.
+func (l *payloadLogger) log(message string, fullMethod string, key string, payload interface{}, decoder *marshaler.CompactEventDecoder) {
+	tree, err := l.tree(payload, decoder)
+	if err != nil {
+		l.logger.Warn("failed to marshal payload", zap.String("grpc.method", path.Base(fullMethod)), zap.Error(err))
+		return
+	}
+
+	l.logger.Info(message, zap.String("grpc.method", path.Base(fullMethod)), zap.Any("payload", l.redact(tree, l.matchesKey(key))))
+}
+
+// tree returns the payload as decoded from JSON.
+func (l *payloadLogger) tree(payload interface{}, decoder *marshaler.CompactEventDecoder) (interface{}, error) {
+	msg, ok := payload.(proto.Message)
+	if !ok {
+		return nil, nil
+	}
+	switch m := msg.(type) {
+	case *protobuf.CreateAPIKeyResponse:
+		// the secret of an API key is only ever shown to its creator
+		m = proto.Clone(m).(*protobuf.CreateAPIKeyResponse)
+		m.Key = redacted
+		msg = m
+	case *protobuf.WatchResponse:
+		// the compact events are decoded, so that their values are redacted as well
+		if len(m.CompactEvents) > 0 && decoder != nil {
+			responses, err := decoder.Decode(m.CompactEvents)
+			if err != nil {
+				return nil, err
+			}
+			events := make([]interface{}, 0, len(responses))
+			for _, resp := range responses {
+				event, err := l.tree(resp, nil)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, event)
+			}
+			return map[string]interface{}{"compact_events": events}, nil
+		}
+		// the data of the events is not a protobuf message once encoded
+		if m.Event != nil {
+			data, err := marshaler.EventData(m.Event)
+			if err != nil {
+				return nil, err
+			}
+			dataTree, err := l.jsonTree(data)
+			if err != nil {
+				return nil, err
+			}
+			m = proto.Clone(m).(*protobuf.WatchResponse)
+			event := m.Event
+			m.Event = nil
+			tree, err := l.jsonTree(m)
+			if err != nil {
+				return nil, err
+			}
+			tree["event"] = map[string]interface{}{
+				"type":     event.Type.String(),
+				"data":     dataTree,
+				"metadata": event.Metadata,
+			}
+			return tree, nil
+		}
+	}
+
+	return l.jsonTree(msg)
+}
+
+func (l *payloadLogger) jsonTree(msg proto.Message) (map[string]interface{}, error) {
+	var buf bytes.Buffer
+	if err := l.jsonpb.Marshal(&buf, msg); err != nil {
+		return nil, err
+	}
+	tree := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// redact redacts the values of the tree in place and returns it. The keys found in the
+// tree, e.g. those of the events of a watch, redact their sibling values.
+func (l *payloadLogger) redact(tree interface{}, redactAll bool) interface{} {
+	switch node := tree.(type) {
+	case map[string]interface{}:
+		if key, ok := node["key"].(string); ok && l.matchesKey(key) {
+			redactAll = true
+		}
+		for field, child := range node {
+			switch field {
+			case "value":
+				node[field] = l.redactValue(child, redactAll)
+			case "values":
+				if values, ok := child.([]interface{}); ok {
+					for i, value := range values {
+						values[i] = l.redactValue(value, redactAll)
+					}
+				}
+			default:
+				node[field] = l.redact(child, redactAll)
+			}
+		}
+	case []interface{}:
+		for i, child := range node {
+			node[i] = l.redact(child, redactAll)
+		}
+	}
+
+	return tree
+}
+
+func (l *payloadLogger) redactValue(value interface{}, redactAll bool) interface{} {
+	if redactAll || l.matchesValue(value) {
+		return redacted
+	}
+
+	return value
+}
+
+func (l *payloadLogger) matchesKey(key string) bool {
+	for _, re := range l.redactKeys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesValue matches the value, and the bytes it holds once decoded.
+func (l *payloadLogger) matchesValue(value interface{}) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	for _, re := range l.redactValues {
+		if re.MatchString(s) || (err == nil && re.Match(decoded)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func payloadLoggingUnaryServerInterceptor(l *payloadLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !l.applies(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		key := requestKey(req)
+		l.log("request payload", info.FullMethod, key, req, nil)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			l.log("response payload", info.FullMethod, key, resp, nil)
+		}
+
+		return resp, err
+	}
+}
+
+func payloadLoggingStreamServerInterceptor(l *payloadLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !l.applies(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &payloadLoggingServerStream{
+			ServerStream: ss,
+			logger:       l,
+			fullMethod:   info.FullMethod,
+			decoder:      marshaler.NewCompactEventDecoder(),
+		})
+	}
+}
+
+// payloadLoggingServerStream logs the messages of a stream. The values of the messages
+// sent are redacted if the key of the request matches a redaction pattern.
+type payloadLoggingServerStream struct {
+	grpc.ServerStream
+	logger     *payloadLogger
+	fullMethod string
+	key        string
+	decoder    *marshaler.CompactEventDecoder
+}
+
+func (s *payloadLoggingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.key = requestKey(m)
+	s.logger.log("request payload", s.fullMethod, s.key, m, nil)
+
+	return nil
+}
+
+func (s *payloadLoggingServerStream) SendMsg(m interface{}) error {
+	s.logger.log("response payload", s.fullMethod, s.key, m, s.decoder)
+
+	return s.ServerStream.SendMsg(m)
+}
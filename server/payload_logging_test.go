@@ -0,0 +1,87 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+func TestPayloadLoggerRedact(t *testing.T) {
+	l, err := newPayloadLogger(&PayloadLogging{
+		Methods:      []string{"Get", "Scan", "Watch"},
+		RedactKeys:   []string{"^/secrets/"},
+		RedactValues: []string{"^password="},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		key      string
+		payload  interface{}
+		expected interface{}
+	}{
+		{
+			"value of a secret key",
+			"/secrets/db",
+			&protobuf.GetResponse{Value: []byte("x")},
+			map[string]interface{}{"value": redacted},
+		},
+		{
+			"value of another key",
+			"/public/a",
+			&protobuf.GetResponse{Value: []byte("x")},
+			map[string]interface{}{"value": "eA=="},
+		},
+		{
+			"value matching a pattern",
+			"/public/a",
+			&protobuf.GetResponse{Value: []byte("password=x")},
+			map[string]interface{}{"value": redacted},
+		},
+		{
+			"values of a scan of secret keys",
+			"/secrets/",
+			&protobuf.ScanResponse{Values: [][]byte{[]byte("x"), []byte("y")}},
+			map[string]interface{}{"values": []interface{}{redacted, redacted}},
+		},
+		{
+			"event of a secret key",
+			"/",
+			&protobuf.WatchResponse{Event: newTestEvent(t, protobuf.Event_Set, &protobuf.SetRequest{Key: "/secrets/db", Value: []byte("x")})},
+			map[string]interface{}{
+				"event": map[string]interface{}{
+					"type":     "Set",
+					"data":     map[string]interface{}{"key": "/secrets/db", "value": redacted},
+					"metadata": map[string]string(nil),
+				},
+			},
+		},
+	} {
+		tree, err := l.tree(test.payload, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if actual := l.redact(tree, l.matchesKey(test.key)); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: expected %v, saw %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestPayloadLoggerRedactAPIKey(t *testing.T) {
+	l, err := newPayloadLogger(&PayloadLogging{Methods: []string{"CreateAPIKey"}}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tree, err := l.tree(&protobuf.CreateAPIKeyResponse{Key: "id.secret"}, nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if key := tree.(map[string]interface{})["key"]; key != redacted {
+		t.Errorf("expected the secret to be redacted, saw %v", key)
+	}
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// PeerAllowlist restricts the nodes that may join the cluster, so that an exposed port can
+// not be used to add a replica that receives all the data.
+type PeerAllowlist struct {
+	ids      []string
+	networks []*net.IPNet
+}
+
+// NewPeerAllowlist allows the node IDs matching one of the patterns, e.g. "node*", and the
+// Raft addresses in one of the networks, e.g. "10.0.0.0/8". An empty list allows any ID or
+// any address.
+func NewPeerAllowlist(ids []string, cidrs []string) (*PeerAllowlist, error) {
+	for _, pattern := range ids {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "node ID pattern %q", pattern)
+		}
+	}
+
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+
+	return &PeerAllowlist{
+		ids:      ids,
+		networks: networks,
+	}, nil
+}
+
+func (a *PeerAllowlist) allowsID(id string) bool {
+	if len(a.ids) == 0 {
+		return true
+	}
+	for _, pattern := range a.ids {
+		if matched, _ := path.Match(pattern, id); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *PeerAllowlist) allowsIP(ip net.IP) bool {
+	if len(a.networks) == 0 {
+		return true
+	}
+	for _, network := range a.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsAddr tells whether the remote address of a connection is allowed.
+func (a *PeerAllowlist) allowsAddr(addr net.Addr) bool {
+	if len(a.networks) == 0 {
+		return true
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+
+	return ok && a.allowsIP(tcpAddr.IP)
+}
+
+// Check refuses a node unless its ID and every address its Raft address resolves to are
+// allowed. A Raft address without a host, e.g. ":7000", is a loopback address.
+func (a *PeerAllowlist) Check(id string, raftAddress string) error {
+	if !a.allowsID(id) {
+		return errors.Wrapf(errors.ErrPermissionDenied, "node ID %s is not in the peer allowlist", id)
+	}
+	if len(a.networks) == 0 {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(raftAddress)
+	if err != nil {
+		return errors.Wrapf(errors.ErrPermissionDenied, "Raft address %s of node %s: %v", raftAddress, id, err)
+	}
+	var ips []net.IP
+	switch ip := net.ParseIP(host); {
+	case host == "":
+		ips = []net.IP{net.IPv4(127, 0, 0, 1)}
+	case ip != nil:
+		ips = []net.IP{ip}
+	default:
+		ips, err = net.LookupIP(host)
+		if err != nil {
+			return errors.Wrapf(errors.ErrPermissionDenied, "Raft address %s of node %s: %v", raftAddress, id, err)
+		}
+	}
+	for _, ip := range ips {
+		if !a.allowsIP(ip) {
+			return errors.Wrapf(errors.ErrPermissionDenied, "Raft address %s of node %s resolves to %s, which is not in the peer allowlist", raftAddress, id, ip)
+		}
+	}
+
+	return nil
+}
+
+// checkPeer refuses to add a node that is not in the peer allowlist of the cluster
+// configuration, or else of the server. The node itself is always allowed.
+func (s *RaftServer) checkPeer(id string, raftAddress string) error {
+	allowlist := s.effectivePeerAllowlist()
+	if allowlist == nil || id == s.id {
+		return nil
+	}
+	if err := allowlist.Check(id, raftAddress); err != nil {
+		s.rejectedPeers.With(prometheus.Labels{"reason": "join"}).Inc()
+		s.logger.Warn("refused to add a node that is not in the peer allowlist", zap.String("id", id), zap.String("raft_address", raftAddress), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// allowlistStreamLayer is the TCP stream layer of the Raft transport, closing the
+// connections from and to the addresses that are not in the peer allowlist.
+type allowlistStreamLayer struct {
+	listener  net.Listener
+	advertise net.Addr
+	// returns the peer allowlist in effect, nil to allow any address
+	allowlist func() *PeerAllowlist
+	rejected  prometheus.Counter
+	logger    *zap.Logger
+}
+
+// newAllowlistTransport creates a Raft transport like raft.NewTCPTransport, with a stream
+// layer enforcing the peer allowlist in effect when a connection is opened.
+func newAllowlistTransport(bindAddr string, advertise net.Addr, allowlist func() *PeerAllowlist, rejected prometheus.Counter, maxPool int, timeout time.Duration, logger *zap.Logger) (*raft.NetworkTransport, error) {
+	listener, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &allowlistStreamLayer{
+		listener:  listener,
+		advertise: advertise,
+		allowlist: allowlist,
+		rejected:  rejected,
+		logger:    logger,
+	}
+	if addr, ok := stream.Addr().(*net.TCPAddr); !ok || addr.IP.IsUnspecified() {
+		_ = listener.Close()
+		return nil, fmt.Errorf("local bind address %s is not advertisable", stream.Addr())
+	}
+
+	return raft.NewNetworkTransport(stream, maxPool, timeout, ioutil.Discard), nil
+}
+
+func (l *allowlistStreamLayer) allows(addr net.Addr) bool {
+	allowlist := l.allowlist()
+
+	return allowlist == nil || allowlist.allowsAddr(addr)
+}
+
+func (l *allowlistStreamLayer) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.allows(conn.RemoteAddr()) {
+			return conn, nil
+		}
+
+		l.rejected.Inc()
+		l.logger.Warn("refused a Raft connection from an address that is not in the peer allowlist", zap.String("remote_address", conn.RemoteAddr().String()))
+		_ = conn.Close()
+	}
+}
+
+func (l *allowlistStreamLayer) Close() error {
+	return l.listener.Close()
+}
+
+func (l *allowlistStreamLayer) Addr() net.Addr {
+	if l.advertise != nil {
+		return l.advertise
+	}
+
+	return l.listener.Addr()
+}
+
+func (l *allowlistStreamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", string(address), timeout)
+	if err != nil {
+		return nil, err
+	}
+	if !l.allows(conn.RemoteAddr()) {
+		l.rejected.Inc()
+		l.logger.Warn("refused a Raft connection to an address that is not in the peer allowlist", zap.String("address", string(address)), zap.String("remote_address", conn.RemoteAddr().String()))
+		_ = conn.Close()
+		return nil, errors.Wrapf(errors.ErrPermissionDenied, "Raft address %s is not in the peer allowlist", address)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/mosuka/cete/errors"
+)
+
+func TestPeerAllowlist(t *testing.T) {
+	if _, err := NewPeerAllowlist([]string{"node["}, nil); err == nil {
+		t.Fatalf("expected a malformed pattern to be refused")
+	}
+	if _, err := NewPeerAllowlist(nil, []string{"10.0.0.0"}); err == nil {
+		t.Fatalf("expected a malformed network to be refused")
+	}
+
+	allowlist, err := NewPeerAllowlist([]string{"node*"}, []string{"10.0.0.0/8", "127.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for _, peer := range []struct {
+		id          string
+		raftAddress string
+		allowed     bool
+	}{
+		{"node1", "10.1.2.3:7000", true},
+		{"node2", ":7000", true},
+		{"node3", "127.0.0.1:7000", true},
+		{"rogue", "10.1.2.3:7000", false},
+		{"node4", "192.168.0.1:7000", false},
+		{"node5", "10.1.2.3", false},
+	} {
+		err := allowlist.Check(peer.id, peer.raftAddress)
+		if peer.allowed && err != nil {
+			t.Errorf("expected %s at %s to be allowed, saw %v", peer.id, peer.raftAddress, err)
+		}
+		if !peer.allowed && !errors.Is(err, errors.ErrPermissionDenied) {
+			t.Errorf("expected %s at %s to be refused, saw %v", peer.id, peer.raftAddress, err)
+		}
+	}
+
+	allowlist, err = NewPeerAllowlist(nil, nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := allowlist.Check("any", "192.168.0.1:7000"); err != nil {
+		t.Errorf("expected an empty allowlist to allow any node, saw %v", err)
+	}
+}
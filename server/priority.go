@@ -0,0 +1,103 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// bulkSlotsPerAppend is how many batches of bulk writes may be in flight at once when
+// priority prefixes are set. A priority write then waits behind these batches at most.
+const bulkSlotsPerAppend = 2
+
+// priorityPrefixes are the prefixes of the keys whose writes go ahead of the bulk writes,
+// e.g. the configuration or the feature flags.
+type priorityPrefixes []string
+
+func newPriorityPrefixes(prefixes []string) priorityPrefixes {
+	p := make(priorityPrefixes, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		// an empty prefix would make every write a priority write
+		if prefix != "" {
+			p = append(p, prefix)
+		}
+	}
+
+	return p
+}
+
+// event tells whether the event writes a key with a priority prefix.
+func (p priorityPrefixes) event(event *protobuf.Event) bool {
+	for _, prefix := range p {
+		if watchesEvent(prefix, event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// watcher tells whether the watcher only receives the events of priority writes.
+func (p priorityPrefixes) watcher(prefix string) bool {
+	for _, priority := range p {
+		if strings.HasPrefix(prefix, priority) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// acquireBulkSlot waits for a bulk write to be allowed in flight, and returns the function
+// that releases its slot.
+func (s *RaftServer) acquireBulkSlot(timeout time.Duration) (func(), error) {
+	release := func() {
+		<-s.bulkSlots
+	}
+	select {
+	case s.bulkSlots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	timer := s.clock.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case s.bulkSlots <- struct{}{}:
+		return release, nil
+	case <-timer.C():
+		return nil, errors.Wrapf(errors.ErrTimeout, "%d bulk writes in flight", cap(s.bulkSlots))
+	}
+}
+
+// startDispatchPriority fans the events of priority writes out to the watchers of priority
+// prefixes, so that they are not queued behind the events of bulk writes.
+func (s *GRPCService) startDispatchPriority() {
+	s.logger.Info("start to dispatch priority events", zap.Strings("prefixes", s.raftServer.priorityPrefixes))
+
+	defer func() {
+		close(s.priorityDoneCh)
+	}()
+
+	for {
+		select {
+		case <-s.priorityStopCh:
+			s.logger.Info("received a request to stop dispatching priority events")
+			return
+		case event := <-s.raftServer.fsm.priorityCh:
+			s.watchers.dispatch(event, true)
+		}
+	}
+}
+
+func (s *GRPCService) stopDispatchPriority() {
+	if len(s.raftServer.priorityPrefixes) == 0 {
+		return
+	}
+
+	close(s.priorityStopCh)
+	<-s.priorityDoneCh
+}
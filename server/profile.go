@@ -0,0 +1,86 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// Profile bundles the settings suited to the network between the nodes, since tuning
+// each of them for a geo-distributed cluster is error-prone.
+type Profile struct {
+	Name string
+
+	// Raft timing
+	HeartbeatTimeout   time.Duration
+	ElectionTimeout    time.Duration
+	LeaderLeaseTimeout time.Duration
+	CommitTimeout      time.Duration
+	// the maximum number of log entries sent at once
+	MaxAppendEntries int
+	// the number of log entries kept after a snapshot, so that followers lagging
+	// behind catch up from the log instead of receiving a snapshot
+	TrailingLogs uint64
+
+	// Raft transport
+	TransportMaxPool int
+	TransportTimeout time.Duration
+	// the bytes of a snapshot sent to a node per transport timeout, the timeout of
+	// installing a snapshot grows with its size
+	TransportTimeoutScale int
+
+	// the defaults of the --snapshot-rate-limit and --catch-up-as-nonvoter flags
+	SnapshotRateLimit int64
+	CatchUpAsNonvoter bool
+}
+
+var (
+	// LANProfile suits the nodes of a data center. These are the defaults of Raft.
+	LANProfile = Profile{
+		Name:               "lan",
+		HeartbeatTimeout:   1000 * time.Millisecond,
+		ElectionTimeout:    1000 * time.Millisecond,
+		LeaderLeaseTimeout: 500 * time.Millisecond,
+		CommitTimeout:      50 * time.Millisecond,
+		MaxAppendEntries:   64,
+		TrailingLogs:       10240,
+		TransportMaxPool:   3,
+		TransportTimeout:   10 * time.Second,
+
+		TransportTimeoutScale: raft.DefaultTimeoutScale,
+	}
+
+	// WANProfile suits nodes spread over data centers. It tolerates round trips of
+	// hundreds of milliseconds without triggering elections, sends larger batches over
+	// more connections, keeps more log entries for the followers lagging behind, limits
+	// the disk bandwidth taken by snapshots and makes new nodes catch up as non-voters,
+	// so that a distant node never slows down the commits while it catches up.
+	WANProfile = Profile{
+		Name:               "wan",
+		HeartbeatTimeout:   3000 * time.Millisecond,
+		ElectionTimeout:    3000 * time.Millisecond,
+		LeaderLeaseTimeout: 1500 * time.Millisecond,
+		CommitTimeout:      100 * time.Millisecond,
+		MaxAppendEntries:   256,
+		TrailingLogs:       51200,
+		TransportMaxPool:   8,
+		TransportTimeout:   30 * time.Second,
+		SnapshotRateLimit:  32,
+		CatchUpAsNonvoter:  true,
+
+		TransportTimeoutScale: raft.DefaultTimeoutScale / 4,
+	}
+)
+
+// ParseProfile returns the profile named "lan" or "wan".
+func ParseProfile(name string) (Profile, error) {
+	for _, p := range []Profile{LANProfile, WANProfile} {
+		if strings.EqualFold(name, p.Name) {
+			return p, nil
+		}
+	}
+
+	return LANProfile, fmt.Errorf("unknown profile %q", name)
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/metric"
+	"go.uber.org/zap"
+)
+
+const defaultQuorumLossTimeout = 30 * time.Second
+
+// peersFile is the file of the Raft directory listing the voters to recover the cluster
+// with after losing its quorum for good, in the format read by raft.ReadConfigJSON.
+const peersFile = "peers.json"
+
+// QuorumLost returns whether the node has not known a leader for longer than the quorum
+// loss timeout.
+func (s *RaftServer) QuorumLost() bool {
+	return atomic.LoadInt32(&s.quorumLost) == 1
+}
+
+// checkQuorum tracks how long the node has not known a leader, and reports the loss of
+// the quorum once it exceeds the quorum loss timeout. It runs on the cluster watching
+// goroutine.
+func (s *RaftServer) checkQuorum(now time.Time) {
+	if s.quorumLossTimeout <= 0 {
+		return
+	}
+
+	if s.raft.Leader() != "" {
+		s.noLeaderSince = time.Time{}
+		if atomic.CompareAndSwapInt32(&s.quorumLost, 1, 0) {
+			metric.RaftQuorumLostMetric.WithLabelValues(s.id).Set(0)
+			s.logger.Info("quorum regained", zap.String("leader", string(s.raft.Leader())))
+		}
+		return
+	}
+
+	if s.noLeaderSince.IsZero() {
+		s.noLeaderSince = now
+		return
+	}
+	if now.Sub(s.noLeaderSince) < s.quorumLossTimeout || !atomic.CompareAndSwapInt32(&s.quorumLost, 0, 1) {
+		return
+	}
+	metric.RaftQuorumLostMetric.WithLabelValues(s.id).Set(1)
+
+	// probing the peers takes a while, the cluster watching goroutine also relays the
+	// applied events
+	noLeaderFor := now.Sub(s.noLeaderSince)
+	go s.reportQuorumLoss(noLeaderFor)
+}
+
+// reportQuorumLoss logs which voters are unreachable and how to recover the cluster.
+func (s *RaftServer) reportQuorumLoss(noLeaderFor time.Duration) {
+	cf := s.raft.GetConfiguration()
+	if err := cf.Error(); err != nil {
+		s.logger.Error("quorum lost", zap.Duration("no_leader_for", noLeaderFor), zap.Error(err))
+		return
+	}
+
+	voters := 0
+	reachable := []string{s.id}
+	unreachable := make([]string, 0)
+	for _, server := range cf.Configuration().Servers {
+		if server.Suffrage != raft.Voter {
+			continue
+		}
+		voters++
+		if string(server.ID) == s.id {
+			continue
+		}
+
+		peer := fmt.Sprintf("%s (%s)", server.ID, server.Address)
+		conn, err := net.DialTimeout("tcp", string(server.Address), time.Second)
+		if err != nil {
+			unreachable = append(unreachable, peer)
+			continue
+		}
+		_ = conn.Close()
+		reachable = append(reachable, peer)
+	}
+
+	s.logger.Error("quorum lost",
+		zap.Duration("no_leader_for", noLeaderFor),
+		zap.Int("voters", voters),
+		zap.Int("quorum", voters/2+1),
+		zap.Strings("reachable", reachable),
+		zap.Strings("unreachable", unreachable),
+		zap.Strings("recovery", []string{
+			"if the unreachable nodes come back, the cluster elects a leader by itself",
+			fmt.Sprintf("if a majority of the voters is lost for good, stop the remaining nodes, write the remaining voters to %s on each of them, e.g. [{\"id\": \"node1\", \"address\": \":7000\"}], and start them again", filepath.Join(s.raftDirectory, peersFile)),
+			"once a leader is elected, remove the lost nodes with cete leave",
+		}),
+	)
+}
+
+// recoverCluster replaces the configuration of the cluster with the voters listed in the
+// peers file, so that the remaining nodes of a cluster that lost its quorum for good can
+// elect a leader. The log is replayed into a scratch FSM to take the snapshot holding the
+// new configuration, the FSM of the node restores it on startup.
+func (s *RaftServer) recoverCluster(config *raft.Config, path string, logs raft.LogStore, stable raft.StableStore, snaps raft.SnapshotStore) error {
+	configuration, err := raft.ReadConfigJSON(path)
+	if err != nil {
+		s.logger.Error("failed to read peers file", zap.String("path", path), zap.Error(err))
+		return err
+	}
+
+	dir, err := ioutil.TempDir(s.dataDirectory, "recovery")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	fsm, err := NewRaftFSM(dir, s.logger)
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range fsm.applyCh {
+			if event == nil {
+				return
+			}
+		}
+	}()
+	err = raft.RecoverCluster(config, fsm, logs, stable, snaps, s.transport, configuration)
+	_ = fsm.Close()
+	<-done
+	if err != nil {
+		s.logger.Error("failed to recover cluster", zap.String("path", path), zap.Error(err))
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	s.logger.Warn("cluster recovered with the voters of the peers file", zap.String("path", path), zap.Any("servers", configuration.Servers))
+
+	return nil
+}
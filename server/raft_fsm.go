@@ -1,29 +1,111 @@
 package server
 
 import (
-	"errors"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/errors"
 	"github.com/mosuka/cete/marshaler"
 	"github.com/mosuka/cete/protobuf"
 	"github.com/mosuka/cete/storage"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 )
 
+// Keys with this prefix hold the cluster state replicated along with the user data.
+// They are stored in the key value store so that they are included in snapshots,
+// but they are not visible to clients.
+const systemKeyPrefix = "\x00cete/"
+
+const membershipSpecKey = systemKeyPrefix + "membership_spec"
+
+const metadataKeyPrefix = systemKeyPrefix + "metadata/"
+
+const decommissionKeyPrefix = systemKeyPrefix + "decommission/"
+
+// Keys with this prefix hold the annotations of the nodes, see annotationKey.
+const annotationKeyPrefix = systemKeyPrefix + "annotation/"
+
+const nodeStatusKeyPrefix = systemKeyPrefix + "node_status/"
+
+// Keys with this prefix hold the API keys by ID, with the hash of their secret.
+const apiKeyKeyPrefix = systemKeyPrefix + "api_key/"
+
+// Keys with this prefix hold the index of the log entry that last modified a user key.
+const modifiedIndexKeyPrefix = systemKeyPrefix + "modified_index/"
+
+// Keys with this prefix hold the expiration time of a user key in Unix nanoseconds.
+const expiresAtKeyPrefix = systemKeyPrefix + "expires_at/"
+
+// roleKey holds the role of the cluster, the cluster is a primary without it.
+const roleKey = systemKeyPrefix + "role"
+
+// replicatedIndexKey holds the index in the log of the source cluster of the last event
+// replicated by a standby.
+const replicatedIndexKey = systemKeyPrefix + "replicated_index"
+
+// appliedIndexKey carries the applied index in snapshots. It is never written to the key value store.
+const appliedIndexKey = systemKeyPrefix + "applied_index"
+
+// resumeIndexKey holds the applied index while the node is stopped. It is written on a
+// clean shutdown and deleted on startup, so that the key value store can be resumed
+// instead of being restored from the latest snapshot.
+const resumeIndexKey = systemKeyPrefix + "resume_index"
+
+func isSystemKey(key string) bool {
+	return strings.HasPrefix(key, systemKeyPrefix)
+}
+
 type RaftFSM struct {
+	// the bytes of the log entries applied and the time of the last snapshot, to trigger
+	// snapshots based on the size of the log. They are first to be 64-bit aligned for
+	// the atomic operations
+	logBytes         uint64
+	lastSnapshotTime int64
+
 	logger *zap.Logger
 
+	// the clock telling which keys have expired
+	clock Clock
+
+	hooks *applyHooks
+
 	kvs        *storage.KVS
 	metadata   map[string]*protobuf.Metadata
 	nodesMutex sync.RWMutex
 
-	applyCh chan *protobuf.Event
+	applyCh chan *appliedEvent
+	// the events of the priority writes are also sent to their own queue
+	priorityPrefixes priorityPrefixes
+	priorityCh       chan *appliedEvent
+
+	applyMutex   sync.RWMutex
+	appliedIndex uint64
+
+	// the applied index written on the last clean shutdown, and the index up to which
+	// the log entries replayed by Raft are already in the key value store
+	shutdownIndex uint64
+	resumedIndex  uint64
+
+	snapshotLimiter *rateLimiter
+
+	expiredKeysCounter  prometheus.Counter
+	expiredBytesCounter prometheus.Counter
+	purgedKeysCounter   *prometheus.CounterVec
+	replicatedIndex     prometheus.Gauge
+	droppedPriority     prometheus.Counter
 }
 
 func NewRaftFSM(path string, logger *zap.Logger) (*RaftFSM, error) {
@@ -39,17 +121,69 @@ func NewRaftFSM(path string, logger *zap.Logger) (*RaftFSM, error) {
 		return nil, err
 	}
 
+	// the shutdown index is deleted so that a crash does not leave it behind
+	shutdownIndex := uint64(0)
+	value, err := kvs.Get(resumeIndexKey)
+	switch {
+	case err == nil && len(value) == 8:
+		shutdownIndex = binary.BigEndian.Uint64(value)
+		if err := kvs.Delete(resumeIndexKey); err != nil {
+			return nil, err
+		}
+		if err := kvs.Sync(); err != nil {
+			return nil, err
+		}
+	case err != nil && !errors.Is(err, errors.ErrNotFound):
+		logger.Error("failed to get the shutdown index", zap.Error(err))
+		return nil, err
+	}
+
 	return &RaftFSM{
-		logger:   logger,
-		kvs:      kvs,
-		metadata: make(map[string]*protobuf.Metadata, 0),
-		applyCh:  make(chan *protobuf.Event, 1024),
+		logger:           logger,
+		kvs:              kvs,
+		metadata:         make(map[string]*protobuf.Metadata, 0),
+		applyCh:          make(chan *appliedEvent, 1024),
+		lastSnapshotTime: time.Now().UnixNano(),
+		clock:            RealClock,
+		hooks:            newApplyHooks(nil, logger),
+		shutdownIndex:    shutdownIndex,
 	}, nil
 }
 
+// resume resumes the FSM from the key value store left by a clean shutdown instead of
+// restoring the snapshot at the index, and returns whether it did. Raft then replays the
+// log entries after the snapshot, those already in the key value store are skipped.
+func (f *RaftFSM) resume(snapshotIndex uint64) (bool, error) {
+	f.applyMutex.Lock()
+	defer f.applyMutex.Unlock()
+
+	if f.shutdownIndex == 0 || f.shutdownIndex < snapshotIndex {
+		return false, nil
+	}
+
+	if err := f.loadMetadata(); err != nil {
+		return false, err
+	}
+	f.appliedIndex = f.shutdownIndex
+	f.resumedIndex = f.shutdownIndex
+
+	return true, nil
+}
+
 func (f *RaftFSM) Close() error {
 	f.applyCh <- nil
 	f.logger.Info("apply channel has closed")
+	f.hooks.stop()
+
+	// no log entry is applied once the shutdown index is written
+	f.applyMutex.Lock()
+	defer f.applyMutex.Unlock()
+
+	resumeIndex := make([]byte, 8)
+	binary.BigEndian.PutUint64(resumeIndex, f.appliedIndex)
+	if err := f.kvs.Set(resumeIndexKey, resumeIndex); err != nil {
+		f.logger.Error("failed to set the shutdown index", zap.Uint64("applied_index", f.appliedIndex), zap.Error(err))
+	}
 
 	err := f.kvs.Close()
 	if err != nil {
@@ -68,19 +202,403 @@ func (f *RaftFSM) Get(key string) ([]byte, error) {
 		return nil, err
 	}
 
+	// expired keys are not visible until the expiration sweep deletes them
+	expired, err := f.expired(key, f.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, errors.ErrNotFound
+	}
+
 	return value, nil
 }
 
-func (f *RaftFSM) Scan(prefix string) ([][]byte, error) {
-	values, err := f.kvs.Scan(prefix)
+// Scan returns the values of the user keys with the prefix. If the filter is not nil,
+// only the items it selects are returned.
+func (f *RaftFSM) Scan(prefix string, filter *scanFilter) ([][]byte, error) {
+	// the keys are iterated rather than scanned by the key value store, so that the
+	// system keys and the expired keys are left out whatever the prefix
+	values := make([][]byte, 0)
+	err := f.Iterate(prefix, filter, func(key string, value []byte) error {
+		values = append(values, append([]byte{}, value...))
+		return nil
+	})
 	if err != nil {
-		f.logger.Error("failed to scan values", zap.String("prefix", prefix), zap.Error(err))
 		return nil, err
 	}
 
 	return values, nil
 }
 
+// Iterate calls fn for the user keys with the prefix that the filter selects, except the
+// expired keys. The value is only valid until fn returns.
+func (f *RaftFSM) Iterate(prefix string, filter *scanFilter, fn func(key string, value []byte) error) error {
+	return f.iterate(prefix, filter, f.clock.Now(), fn)
+}
+
+// iterate skips the keys expired at the time, unless the time is zero. The log entries
+// are applied without checking the expiration, as the clocks of the nodes differ.
+func (f *RaftFSM) iterate(prefix string, filter *scanFilter, now time.Time, fn func(key string, value []byte) error) error {
+	err := f.kvs.Iterate(prefix, func(key string, value []byte) error {
+		if isSystemKey(key) {
+			return nil
+		}
+		if !now.IsZero() {
+			expired, err := f.expired(key, now)
+			if err != nil {
+				return err
+			}
+			if expired {
+				return nil
+			}
+		}
+		if filter != nil {
+			if !filter.matchKey(key) || !filter.matchValue(len(value)) {
+				return nil
+			}
+			if filter.modifiedSince > 0 {
+				index, err := f.ModifiedIndex(key)
+				if err != nil {
+					return err
+				}
+				if !filter.matchModifiedIndex(index) {
+					return nil
+				}
+			}
+		}
+		return fn(key, value)
+	})
+	if err != nil {
+		f.logger.Error("failed to iterate values", zap.String("prefix", prefix), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetWithIndex returns the value of the key along with the index of the log entry that
+// last modified it. Both are read at the same applied index.
+func (f *RaftFSM) GetWithIndex(key string) ([]byte, uint64, error) {
+	f.applyMutex.RLock()
+	defer f.applyMutex.RUnlock()
+
+	value, err := f.Get(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	index, err := f.ModifiedIndex(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return value, index, nil
+}
+
+// checkExpectedIndex returns ErrConflict unless the key was last modified by the log
+// entry with the expected index, or does not exist if the expected index is 0.
+func (f *RaftFSM) checkExpectedIndex(key string, expected *wrappers.UInt64Value) error {
+	if expected == nil {
+		return nil
+	}
+
+	index, err := f.ModifiedIndex(key)
+	if err != nil {
+		return err
+	}
+	if index != expected.Value {
+		return errors.Wrapf(errors.ErrConflict, "%q was modified at %d, expected %d", key, index, expected.Value)
+	}
+
+	return nil
+}
+
+// checkChecksum returns ErrChecksumMismatch unless the value matches the checksum computed
+// by the client, so that a value corrupted on its way to the FSM is never written.
+func (f *RaftFSM) checkChecksum(key string, value []byte, checksum *wrappers.UInt32Value) error {
+	if checksum == nil {
+		return nil
+	}
+
+	if computed := protobuf.Checksum(value); computed != checksum.Value {
+		f.logger.Error("checksum mismatch", zap.String("key", key), zap.Uint32("checksum", checksum.Value), zap.Uint32("computed", computed), zap.Int("size", len(value)))
+		return errors.Wrapf(errors.ErrChecksumMismatch, "%q has the checksum %08x, expected %08x", key, computed, checksum.Value)
+	}
+
+	return nil
+}
+
+// ModifiedIndex returns the index of the log entry that last modified the key, or 0 if it is not known.
+func (f *RaftFSM) ModifiedIndex(key string) (uint64, error) {
+	index, _, err := f.modified(key)
+	return index, err
+}
+
+// modified returns the index of the log entry that last modified the key and the time the
+// leader proposed it in Unix nanoseconds, or 0 if they are not known. The keys written by
+// older versions only have the index.
+func (f *RaftFSM) modified(key string) (uint64, int64, error) {
+	value, err := f.kvs.Get(modifiedIndexKeyPrefix + key)
+	if errors.Is(err, errors.ErrNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get modified index", zap.String("key", key), zap.Error(err))
+		return 0, 0, err
+	}
+
+	return decodeModified(value)
+}
+
+func encodeModified(index uint64, modifiedAt int64) []byte {
+	value := make([]byte, 16)
+	binary.BigEndian.PutUint64(value, index)
+	binary.BigEndian.PutUint64(value[8:], uint64(modifiedAt))
+
+	return value
+}
+
+func decodeModified(value []byte) (uint64, int64, error) {
+	switch len(value) {
+	case 8:
+		return binary.BigEndian.Uint64(value), 0, nil
+	case 16:
+		return binary.BigEndian.Uint64(value), int64(binary.BigEndian.Uint64(value[8:])), nil
+	default:
+		return 0, 0, nil
+	}
+}
+
+// expiresAt returns the expiration time of the key in Unix nanoseconds, or 0 if it never expires.
+func (f *RaftFSM) expiresAt(key string) (int64, error) {
+	value, err := f.kvs.Get(expiresAtKeyPrefix + key)
+	if errors.Is(err, errors.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get expiration time", zap.String("key", key), zap.Error(err))
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, nil
+	}
+
+	return int64(binary.BigEndian.Uint64(value)), nil
+}
+
+func (f *RaftFSM) expired(key string, now time.Time) (bool, error) {
+	expiresAt, err := f.expiresAt(key)
+	if err != nil {
+		return false, err
+	}
+
+	return expiresAt > 0 && expiresAt <= now.UnixNano(), nil
+}
+
+// ExpiredKeys returns the keys expired at the time.
+func (f *RaftFSM) ExpiredKeys(now time.Time) ([]string, error) {
+	keys := make([]string, 0)
+	err := f.kvs.Iterate(expiresAtKeyPrefix, func(key string, value []byte) error {
+		if len(value) == 8 && int64(binary.BigEndian.Uint64(value)) <= now.UnixNano() {
+			keys = append(keys, strings.TrimPrefix(key, expiresAtKeyPrefix))
+		}
+		return nil
+	})
+	if err != nil {
+		f.logger.Error("failed to find expired keys", zap.Error(err))
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func encodeExpiresAt(expiresAt int64) []byte {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(expiresAt))
+
+	return value
+}
+
+// applySetValue sets a user key along with the index and the time of the log entry and
+// its expiration time. The key never expires if the expiration time is 0.
+func (f *RaftFSM) applySetValue(key string, value []byte, expiresAt int64, index uint64, modifiedAt int64) interface{} {
+	modifiedIndex := encodeModified(index, modifiedAt)
+
+	sets := map[string][]byte{key: value, modifiedIndexKeyPrefix + key: modifiedIndex}
+	var deletes []string
+	if expiresAt > 0 {
+		sets[expiresAtKeyPrefix+key] = encodeExpiresAt(expiresAt)
+	} else {
+		deletes = []string{expiresAtKeyPrefix + key}
+	}
+
+	err := f.kvs.Batch(sets, deletes)
+	if err != nil {
+		f.logger.Error("failed to set value", zap.String("key", key), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (f *RaftFSM) applyDeleteValue(key string) interface{} {
+	err := f.kvs.Batch(nil, []string{key, modifiedIndexKeyPrefix + key, expiresAtKeyPrefix + key})
+	if err != nil {
+		f.logger.Error("failed to delete value", zap.String("key", key), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// applyCopy copies the key, or the keys with the source prefix, to the destination in a
+// single transaction, so that readers never see a partial copy. The destination keys are
+// overwritten. With move, the source keys are deleted in the same transaction.
+func (f *RaftFSM) applyCopy(source string, destination string, prefix bool, move bool, index uint64, modifiedAt int64) interface{} {
+	modifiedIndex := encodeModified(index, modifiedAt)
+
+	sets := make(map[string][]byte, 0)
+	deletes := make([]string, 0)
+	// the copies expire along with the source keys
+	copyKey := func(key string, value []byte) error {
+		to := destination + strings.TrimPrefix(key, source)
+		sets[to] = append([]byte{}, value...)
+		sets[modifiedIndexKeyPrefix+to] = modifiedIndex
+		expiresAt, err := f.expiresAt(key)
+		if err != nil {
+			return err
+		}
+		if expiresAt > 0 {
+			sets[expiresAtKeyPrefix+to] = encodeExpiresAt(expiresAt)
+		} else {
+			deletes = append(deletes, expiresAtKeyPrefix+to)
+		}
+		if move {
+			deletes = append(deletes, key, modifiedIndexKeyPrefix+key, expiresAtKeyPrefix+key)
+		}
+		return nil
+	}
+
+	if prefix {
+		if err := f.iterate(source, nil, time.Time{}, copyKey); err != nil {
+			return err
+		}
+	} else {
+		value, err := f.kvs.Get(source)
+		if err != nil {
+			return err
+		}
+		if err := copyKey(source, value); err != nil {
+			return err
+		}
+	}
+
+	// a key both written and moved away, i.e. by a move to an overlapping destination,
+	// holds the value moved to it
+	kept := deletes[:0]
+	for _, key := range deletes {
+		if _, ok := sets[key]; !ok {
+			kept = append(kept, key)
+		}
+	}
+	deletes = kept
+
+	if err := f.kvs.Batch(sets, deletes); err != nil {
+		f.logger.Error("failed to copy values", zap.String("source", source), zap.String("destination", destination), zap.Bool("move", move), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// checkCopySize refuses the copy or the move of a prefix whose keys do not fit in a
+// single transaction of the key value store, so that it fails before being proposed
+// rather than on every replica.
+func (f *RaftFSM) checkCopySize(source string, destination string, move bool) error {
+	maxCount, maxSize := f.kvs.BatchLimits()
+
+	modifiedSize := len(encodeModified(0, 0))
+	expiresAtSize := len(encodeExpiresAt(0))
+	keys, count, size := 0, int64(0), int64(0)
+	err := f.iterate(source, nil, time.Time{}, func(key string, value []byte) error {
+		to := destination + strings.TrimPrefix(key, source)
+		keys++
+		count += 3
+		size += f.kvs.EntrySize(to, len(value)) + f.kvs.EntrySize(modifiedIndexKeyPrefix+to, modifiedSize) + f.kvs.EntrySize(expiresAtKeyPrefix+to, expiresAtSize)
+		if move {
+			count += 3
+			size += f.kvs.EntrySize(key, 0) + f.kvs.EntrySize(modifiedIndexKeyPrefix+key, 0) + f.kvs.EntrySize(expiresAtKeyPrefix+key, 0)
+		}
+		if count >= maxCount || size >= maxSize {
+			return errors.Convert(fmt.Errorf("the %d keys or more with the prefix %s do not fit in a transaction of %d entries and %d bytes, copy or move smaller prefixes", keys, source, maxCount, maxSize), codes.InvalidArgument)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// applyExpire deletes the keys that expired at the time. Keys set again with a later
+// expiration time, or without one, since the sweep found them are kept.
+func (f *RaftFSM) applyExpire(keys []string, now int64) interface{} {
+	deletes := make([]string, 0, len(keys)*3)
+	bytes := 0
+	for _, key := range keys {
+		expiresAt, err := f.expiresAt(key)
+		if err != nil {
+			return err
+		}
+		if expiresAt == 0 || expiresAt > now {
+			continue
+		}
+		value, err := f.kvs.Get(key)
+		if err != nil && !errors.Is(err, errors.ErrNotFound) {
+			return err
+		}
+		bytes += len(key) + len(value)
+		deletes = append(deletes, key, modifiedIndexKeyPrefix+key, expiresAtKeyPrefix+key)
+	}
+
+	if err := f.kvs.Batch(nil, deletes); err != nil {
+		f.logger.Error("failed to delete expired keys", zap.Int("keys", len(deletes)/3), zap.Error(err))
+		return err
+	}
+
+	if f.expiredKeysCounter != nil {
+		f.expiredKeysCounter.Add(float64(len(deletes) / 3))
+		f.expiredBytesCounter.Add(float64(bytes))
+	}
+
+	return nil
+}
+
+// applyPurge deletes the keys dropped by the compaction filter, but those modified since
+// the filter was run, so that every replica deletes the same keys.
+func (f *RaftFSM) applyPurge(filter string, keys []*protobuf.PurgeRequest_Key) interface{} {
+	deletes := make([]string, 0, len(keys)*3)
+	for _, key := range keys {
+		index, err := f.ModifiedIndex(key.Key)
+		if err != nil {
+			return err
+		}
+		if index == 0 || index != key.ModifiedIndex {
+			continue
+		}
+		deletes = append(deletes, key.Key, modifiedIndexKeyPrefix+key.Key, expiresAtKeyPrefix+key.Key)
+	}
+
+	if err := f.kvs.Batch(nil, deletes); err != nil {
+		f.logger.Error("failed to purge keys", zap.String("filter", filter), zap.Int("keys", len(deletes)/3), zap.Error(err))
+		return err
+	}
+
+	if f.purgedKeysCounter != nil {
+		f.purgedKeysCounter.WithLabelValues(filter).Add(float64(len(deletes) / 3))
+	}
+
+	return nil
+}
+
 func (f *RaftFSM) applySet(key string, value []byte) interface{} {
 	err := f.kvs.Set(key, value)
 	if err != nil {
@@ -128,117 +646,550 @@ func (f *RaftFSM) deleteMetadata(id string) {
 
 func (f *RaftFSM) applySetMetadata(id string, metadata *protobuf.Metadata) interface{} {
 	f.logger.Debug("set metadata", zap.String("id", id), zap.Any("metadata", metadata))
+
+	// the metadata is also stored in the key value store so that it is included in snapshots
+	value, err := proto.Marshal(metadata)
+	if err != nil {
+		f.logger.Error("failed to marshal metadata", zap.String("id", id), zap.Error(err))
+		return err
+	}
+	if ret := f.applySet(metadataKeyPrefix+id, value); ret != nil {
+		return ret
+	}
+
 	f.setMetadata(id, metadata)
 
 	return nil
 }
 
 func (f *RaftFSM) applyDeleteMetadata(nodeId string) interface{} {
+	if ret := f.applyDelete(metadataKeyPrefix + nodeId); ret != nil {
+		return ret
+	}
+
 	f.deleteMetadata(nodeId)
 
 	return nil
 }
 
-func (f *RaftFSM) Apply(l *raft.Log) interface{} {
-	var event protobuf.Event
-	err := proto.Unmarshal(l.Data, &event)
+// loadMetadata rebuilds the node metadata from the key value store.
+func (f *RaftFSM) loadMetadata() error {
+	metadata := make(map[string]*protobuf.Metadata, 0)
+	err := f.kvs.Iterate(metadataKeyPrefix, func(key string, value []byte) error {
+		m := &protobuf.Metadata{}
+		if err := proto.Unmarshal(value, m); err != nil {
+			return err
+		}
+		metadata[strings.TrimPrefix(key, metadataKeyPrefix)] = m
+		return nil
+	})
 	if err != nil {
-		f.logger.Error("failed to unmarshal message bytes to KVS command", zap.Error(err))
+		f.logger.Error("failed to load metadata", zap.Error(err))
 		return err
 	}
 
-	switch event.Type {
-	case protobuf.Event_Join:
-		data, err := marshaler.MarshalAny(event.Data)
-		if err != nil {
-			f.logger.Error("failed to marshal to request from KVS command request", zap.String("type", event.Type.String()), zap.Error(err))
-			return err
-		}
-		if data == nil {
-			err = errors.New("nil")
-			f.logger.Error("request is nil", zap.String("type", event.Type.String()), zap.Error(err))
+	f.nodesMutex.Lock()
+	f.metadata = metadata
+	f.nodesMutex.Unlock()
+
+	return nil
+}
+
+func (f *RaftFSM) MembershipSpec() (*protobuf.MembershipSpec, error) {
+	value, err := f.kvs.Get(membershipSpecKey)
+	if errors.Is(err, errors.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get membership spec", zap.Error(err))
+		return nil, err
+	}
+
+	spec := &protobuf.MembershipSpec{}
+	if err := proto.Unmarshal(value, spec); err != nil {
+		f.logger.Error("failed to unmarshal membership spec", zap.Error(err))
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+func (f *RaftFSM) applySetMembershipSpec(spec *protobuf.MembershipSpec) interface{} {
+	value, err := proto.Marshal(spec)
+	if err != nil {
+		f.logger.Error("failed to marshal membership spec", zap.Error(err))
+		return err
+	}
+
+	return f.applySet(membershipSpecKey, value)
+}
+
+func (f *RaftFSM) applyDeleteMembershipSpec() interface{} {
+	return f.applyDelete(membershipSpecKey)
+}
+
+func (f *RaftFSM) DecommissionStatus(id string) (*protobuf.DecommissionStatus, error) {
+	value, err := f.kvs.Get(decommissionKeyPrefix + id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &protobuf.DecommissionStatus{}
+	if err := proto.Unmarshal(value, status); err != nil {
+		f.logger.Error("failed to unmarshal decommission status", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (f *RaftFSM) DecommissionStatuses() ([]*protobuf.DecommissionStatus, error) {
+	statuses := make([]*protobuf.DecommissionStatus, 0)
+	err := f.kvs.Iterate(decommissionKeyPrefix, func(key string, value []byte) error {
+		status := &protobuf.DecommissionStatus{}
+		if err := proto.Unmarshal(value, status); err != nil {
 			return err
 		}
-		req := data.(*protobuf.SetMetadataRequest)
+		statuses = append(statuses, status)
+		return nil
+	})
+	if err != nil {
+		f.logger.Error("failed to load decommission statuses", zap.Error(err))
+		return nil, err
+	}
 
-		ret := f.applySetMetadata(req.Id, req.Metadata)
-		if ret == nil {
-			f.applyCh <- &event
+	return statuses, nil
+}
+
+func (f *RaftFSM) applySetDecommissionStatus(status *protobuf.DecommissionStatus, index uint64) interface{} {
+	status.UpdatedIndex = index
+
+	value, err := proto.Marshal(status)
+	if err != nil {
+		f.logger.Error("failed to marshal decommission status", zap.String("id", status.Id), zap.Error(err))
+		return err
+	}
+
+	return f.applySet(decommissionKeyPrefix+status.Id, value)
+}
+
+// annotationKey returns the key of an annotation of a node. The ID is terminated so that
+// the annotations of a node are not listed with those of another node sharing its prefix.
+func annotationKey(id string, key string) string {
+	return annotationKeyPrefix + id + "\x00" + key
+}
+
+// Annotations returns the annotations of the node.
+func (f *RaftFSM) Annotations(id string) (map[string]string, error) {
+	prefix := annotationKey(id, "")
+	annotations := make(map[string]string, 0)
+	err := f.kvs.Iterate(prefix, func(key string, value []byte) error {
+		annotations[strings.TrimPrefix(key, prefix)] = string(value)
+		return nil
+	})
+	if err != nil {
+		f.logger.Error("failed to get annotations", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+// applyAnnotate sets the annotations of the node, an empty value removes the annotation.
+func (f *RaftFSM) applyAnnotate(id string, annotations map[string]string) interface{} {
+	sets := make(map[string][]byte, 0)
+	deletes := make([]string, 0)
+	for key, value := range annotations {
+		if value == "" {
+			deletes = append(deletes, annotationKey(id, key))
+		} else {
+			sets[annotationKey(id, key)] = []byte(value)
 		}
+	}
+
+	if err := f.kvs.Batch(sets, deletes); err != nil {
+		f.logger.Error("failed to annotate node", zap.String("id", id), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// applyDeleteAnnotations removes the annotations of a node leaving the cluster.
+func (f *RaftFSM) applyDeleteAnnotations(id string) interface{} {
+	annotations, err := f.Annotations(id)
+	if err != nil {
+		return err
+	}
+
+	deletes := make([]string, 0, len(annotations))
+	for key := range annotations {
+		deletes = append(deletes, annotationKey(id, key))
+	}
+	if err := f.kvs.Batch(nil, deletes); err != nil {
+		f.logger.Error("failed to delete annotations", zap.String("id", id), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// NodeStatus returns the status last reported for the node, or nil if none was.
+func (f *RaftFSM) NodeStatus(id string) (*protobuf.NodeStatus, error) {
+	value, err := f.kvs.Get(nodeStatusKey(id))
+	if errors.Is(err, errors.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get node status", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
 
+	status := &protobuf.NodeStatus{}
+	if err := proto.Unmarshal(value, status); err != nil {
+		f.logger.Error("failed to unmarshal node status", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// applyUpdateNodeStatus records the status of the node, and its metadata if the node
+// reported other addresses than those it joined with.
+func (f *RaftFSM) applyUpdateNodeStatus(id string, status *protobuf.NodeStatus) interface{} {
+	value, err := proto.Marshal(status)
+	if err != nil {
+		f.logger.Error("failed to marshal node status", zap.String("id", id), zap.Error(err))
+		return err
+	}
+	if ret := f.applySet(nodeStatusKey(id), value); ret != nil {
 		return ret
-	case protobuf.Event_Leave:
-		data, err := marshaler.MarshalAny(event.Data)
-		if err != nil {
-			f.logger.Error("failed to marshal to request from KVS command request", zap.String("type", event.Type.String()), zap.Error(err))
+	}
+
+	if status.Metadata != nil && !proto.Equal(status.Metadata, f.getMetadata(id)) {
+		return f.applySetMetadata(id, status.Metadata)
+	}
+
+	return nil
+}
+
+func nodeStatusKey(id string) string {
+	return nodeStatusKeyPrefix + id
+}
+
+// APIKey returns the API key, or nil if it does not exist.
+func (f *RaftFSM) APIKey(id string) (*protobuf.APIKey, error) {
+	value, err := f.kvs.Get(apiKeyKeyPrefix + id)
+	if errors.Is(err, errors.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get API key", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	apiKey := &protobuf.APIKey{}
+	if err := proto.Unmarshal(value, apiKey); err != nil {
+		f.logger.Error("failed to unmarshal API key", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+// APIKeys returns the API keys, the expired ones included until they are revoked.
+func (f *RaftFSM) APIKeys() ([]*protobuf.APIKey, error) {
+	apiKeys := make([]*protobuf.APIKey, 0)
+	err := f.kvs.Iterate(apiKeyKeyPrefix, func(key string, value []byte) error {
+		apiKey := &protobuf.APIKey{}
+		if err := proto.Unmarshal(value, apiKey); err != nil {
 			return err
 		}
-		if data == nil {
-			err = errors.New("nil")
-			f.logger.Error("request is nil", zap.String("type", event.Type.String()), zap.Error(err))
-			return err
+		apiKeys = append(apiKeys, apiKey)
+		return nil
+	})
+	if err != nil {
+		f.logger.Error("failed to load API keys", zap.Error(err))
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+func (f *RaftFSM) applyCreateAPIKey(apiKey *protobuf.APIKey) interface{} {
+	value, err := proto.Marshal(apiKey)
+	if err != nil {
+		f.logger.Error("failed to marshal API key", zap.String("id", apiKey.Id), zap.Error(err))
+		return err
+	}
+
+	return f.applySet(apiKeyKeyPrefix+apiKey.Id, value)
+}
+
+func (f *RaftFSM) applyRevokeAPIKey(id string) interface{} {
+	return f.applyDelete(apiKeyKeyPrefix + id)
+}
+
+func (f *RaftFSM) AppliedIndex() uint64 {
+	f.applyMutex.RLock()
+	defer f.applyMutex.RUnlock()
+
+	return f.appliedIndex
+}
+
+// appliedEvent is an event applied by the FSM along with the index of its log entry.
+type appliedEvent struct {
+	index uint64
+	event *protobuf.Event
+}
+
+// applyResponse is the result of applying a log entry, along with the time the FSM took to commit it.
+type applyResponse struct {
+	err     error
+	elapsed time.Duration
+}
+
+// eventTime returns the time the leader proposed the event in Unix nanoseconds, or 0 if the
+// event was proposed by an older version.
+func eventTime(event *protobuf.Event) int64 {
+	if event.Time == nil {
+		return 0
+	}
+
+	return time.Unix(event.Time.Seconds, int64(event.Time.Nanos)).UnixNano()
+}
+
+func (f *RaftFSM) Apply(l *raft.Log) interface{} {
+	start := time.Now()
+
+	f.applyMutex.Lock()
+	if l.Index <= f.resumedIndex {
+		f.applyMutex.Unlock()
+		return &applyResponse{elapsed: time.Since(start)}
+	}
+	event, ret := f.apply(l)
+	f.appliedIndex = l.Index
+	f.applyMutex.Unlock()
+	atomic.AddUint64(&f.logBytes, uint64(len(l.Data)))
+
+	if ret == nil {
+		applied := &appliedEvent{index: l.Index, event: event}
+		f.hooks.call(applied)
+		if len(f.priorityPrefixes) > 0 && f.priorityPrefixes.event(event) {
+			// the priority events are fanned out without blocking, so the queue is only
+			// full once the fan out stopped, e.g. while the node stops
+			select {
+			case f.priorityCh <- applied:
+			default:
+				if f.droppedPriority != nil {
+					f.droppedPriority.Inc()
+				}
+			}
 		}
-		req := *data.(*protobuf.DeleteMetadataRequest)
+		f.applyCh <- applied
+	}
 
-		ret := f.applyDeleteMetadata(req.Id)
-		if ret == nil {
-			f.applyCh <- &event
+	err, _ := ret.(error)
+	return &applyResponse{
+		err:     err,
+		elapsed: time.Since(start),
+	}
+}
+
+func (f *RaftFSM) apply(l *raft.Log) (*protobuf.Event, interface{}) {
+	var event protobuf.Event
+	err := proto.Unmarshal(l.Data, &event)
+	if err != nil {
+		f.logger.Error("failed to unmarshal message bytes to KVS command", zap.Error(err))
+		return nil, err
+	}
+
+	data, err := marshaler.EventData(&event)
+	if err != nil {
+		f.logger.Error("failed to decode the event data", zap.String("type", event.Type.String()), zap.Error(err))
+		return nil, err
+	}
+
+	if err := f.checkRole(event.Type); err != nil {
+		return &event, err
+	}
+
+	// the watchers of a standby see the events of the source cluster
+	if event.Type == protobuf.Event_Replicate {
+		req := data.(*protobuf.ReplicateRequest)
+		ret := f.applyReplicate(req)
+		if req.Event != nil {
+			return req.Event, ret
 		}
+		return &event, ret
+	}
 
-		return ret
+	return &event, f.applyEvent(&event, data, l.Index)
+}
+
+// applyEvent applies the event of the log entry at the index.
+func (f *RaftFSM) applyEvent(event *protobuf.Event, data proto.Message, index uint64) interface{} {
+	var ret interface{}
+	switch event.Type {
+	case protobuf.Event_Join:
+		req := data.(*protobuf.SetMetadataRequest)
+		member := f.isMember(req.Id)
+		ret = f.applySetMetadata(req.Id, req.Metadata)
+		if ret == nil {
+			// a node joining again is no longer decommissioned
+			ret = f.applyDelete(decommissionKeyPrefix + req.Id)
+		}
+		if ret == nil && !member {
+			ret = f.applyMembershipChange(event, index)
+		}
+	case protobuf.Event_Leave:
+		req := data.(*protobuf.DeleteMetadataRequest)
+		member := f.isMember(req.Id)
+		ret = f.applyDeleteMetadata(req.Id)
+		if ret == nil {
+			ret = f.applyDeleteAnnotations(req.Id)
+		}
+		if ret == nil {
+			ret = f.applyDelete(nodeStatusKey(req.Id))
+		}
+		if ret == nil && member {
+			ret = f.applyMembershipChange(event, index)
+		}
 	case protobuf.Event_Set:
-		data, err := marshaler.MarshalAny(event.Data)
-		if err != nil {
-			f.logger.Error("failed to marshal to request from KVS command request", zap.String("type", event.Type.String()), zap.Error(err))
+		req := data.(*protobuf.SetRequest)
+		if err := f.checkExpectedIndex(req.Key, req.ExpectedIndex); err != nil {
 			return err
 		}
-		if data == nil {
-			err = errors.New("nil")
-			f.logger.Error("request is nil", zap.String("type", event.Type.String()), zap.Error(err))
+		if err := f.checkChecksum(req.Key, req.Value, req.Checksum); err != nil {
 			return err
 		}
-		req := *data.(*protobuf.SetRequest)
-
-		ret := f.applySet(req.Key, req.Value)
-		if ret == nil {
-			f.applyCh <- &event
+		var expiresAt int64
+		if req.ExpiresAt != nil {
+			expiresAt = time.Unix(req.ExpiresAt.Seconds, int64(req.ExpiresAt.Nanos)).UnixNano()
 		}
-
-		return ret
+		ret = f.applySetValue(req.Key, req.Value, expiresAt, index, eventTime(event))
 	case protobuf.Event_Delete:
-		data, err := marshaler.MarshalAny(event.Data)
-		if err != nil {
-			f.logger.Error("failed to marshal to request from KVS command request", zap.String("type", event.Type.String()), zap.Error(err))
-			return err
-		}
-		if data == nil {
-			err = errors.New("nil")
-			f.logger.Error("request is nil", zap.String("type", event.Type.String()), zap.Error(err))
+		req := data.(*protobuf.DeleteRequest)
+		if err := f.checkExpectedIndex(req.Key, req.ExpectedIndex); err != nil {
 			return err
 		}
-		req := *data.(*protobuf.DeleteRequest)
+		ret = f.applyDeleteValue(req.Key)
+	case protobuf.Event_Copy:
+		req := data.(*protobuf.CopyRequest)
+		ret = f.applyCopy(req.Source, req.Destination, req.Prefix, false, index, eventTime(event))
+	case protobuf.Event_Move:
+		req := data.(*protobuf.MoveRequest)
+		ret = f.applyCopy(req.Source, req.Destination, req.Prefix, true, index, eventTime(event))
+	case protobuf.Event_Expire:
+		req := data.(*protobuf.ExpireRequest)
+		ret = f.applyExpire(req.Keys, time.Unix(req.Time.GetSeconds(), int64(req.Time.GetNanos())).UnixNano())
+	case protobuf.Event_SetMembershipSpec:
+		req := data.(*protobuf.SetMembershipSpecRequest)
+		ret = f.applySetMembershipSpec(req.Spec)
+	case protobuf.Event_DeleteMembershipSpec:
+		ret = f.applyDeleteMembershipSpec()
+	case protobuf.Event_Decommission:
+		ret = f.applySetDecommissionStatus(data.(*protobuf.DecommissionStatus), index)
+	case protobuf.Event_Annotate:
+		req := data.(*protobuf.AnnotateRequest)
+		ret = f.applyAnnotate(req.Id, req.Annotations)
+	case protobuf.Event_UpdateNodeStatus:
+		req := data.(*protobuf.UpdateNodeStatusRequest)
+		ret = f.applyUpdateNodeStatus(req.Id, req.Status)
+	case protobuf.Event_Purge:
+		req := data.(*protobuf.PurgeRequest)
+		ret = f.applyPurge(req.Filter, req.Keys)
+	case protobuf.Event_CreateAPIKey:
+		ret = f.applyCreateAPIKey(data.(*protobuf.APIKey))
+	case protobuf.Event_RevokeAPIKey:
+		req := data.(*protobuf.RevokeAPIKeyRequest)
+		ret = f.applyRevokeAPIKey(req.Id)
+	case protobuf.Event_SetRole:
+		req := data.(*protobuf.SetRoleRequest)
+		ret = f.applySetRole(req, index, event.Time)
+	case protobuf.Event_SetClusterConfig:
+		req := data.(*protobuf.SetClusterConfigRequest)
+		ret = f.applySetClusterConfig(req, index, event.Time)
+	case protobuf.Event_Reconcile:
+		// reconcile actions do not change the state, they are only notified to watchers
+	}
 
-		ret := f.applyDelete(req.Key)
-		if ret == nil {
-			f.applyCh <- &event
-		}
+	return ret
+}
 
-		return ret
-	default:
-		err = errors.New("command type not support")
-		f.logger.Error("unsupported command", zap.String("type", event.Type.String()), zap.Error(err))
-		return err
+// Hash returns the applied index and a hash of the key value store, including the system keys, at that index.
+// Replicas that applied the same log entries return the same hash.
+func (f *RaftFSM) Hash() (uint64, []byte, error) {
+	f.applyMutex.RLock()
+	defer f.applyMutex.RUnlock()
+
+	h := sha256.New()
+	write := func(b []byte) {
+		_ = binary.Write(h, binary.BigEndian, uint64(len(b)))
+		_, _ = h.Write(b)
+	}
+
+	// the key value store iterates the keys in sorted order
+	if err := f.kvs.Iterate("", func(key string, value []byte) error {
+		write([]byte(key))
+		write(value)
+		return nil
+	}); err != nil {
+		f.logger.Error("failed to hash the key value store", zap.Error(err))
+		return 0, nil, err
 	}
+
+	return f.appliedIndex, h.Sum(nil), nil
 }
 
 func (f *RaftFSM) Stats() map[string]string {
 	return f.kvs.Stats()
 }
 
+func (f *RaftFSM) Levels() []storage.LevelInfo {
+	return f.kvs.Levels()
+}
+
+func (f *RaftFSM) Tables() []storage.TableInfo {
+	return f.kvs.Tables()
+}
+
+func (f *RaftFSM) LiveKeys() (uint64, error) {
+	return f.kvs.LiveKeys()
+}
+
+func (f *RaftFSM) CompactionBacklog() storage.CompactionBacklog {
+	return f.kvs.CompactionBacklog()
+}
+
+func (f *RaftFSM) Size() (int64, int64) {
+	return f.kvs.Size()
+}
+
+// LogSinceSnapshot returns the bytes of the log entries applied since the last snapshot,
+// and the time of the last snapshot.
+func (f *RaftFSM) LogSinceSnapshot() (uint64, time.Time) {
+	return atomic.LoadUint64(&f.logBytes), time.Unix(0, atomic.LoadInt64(&f.lastSnapshotTime))
+}
+
+func (f *RaftFSM) resetLogSinceSnapshot() {
+	atomic.StoreUint64(&f.logBytes, 0)
+	atomic.StoreInt64(&f.lastSnapshotTime, f.clock.Now().UnixNano())
+}
+
 func (f *RaftFSM) Snapshot() (raft.FSMSnapshot, error) {
+	f.applyMutex.RLock()
+	defer f.applyMutex.RUnlock()
+
+	f.resetLogSinceSnapshot()
+
+	// the view is taken while no log entry is applied, so that it holds the writes up to
+	// the applied index and none after it
 	return &KVSFSMSnapshot{
-		kvs:    f.kvs,
-		logger: f.logger,
+		snapshot:     f.kvs.NewSnapshot(),
+		appliedIndex: f.appliedIndex,
+		limiter:      f.snapshotLimiter,
+		logger:       f.logger,
 	}, nil
 }
 
@@ -260,6 +1211,11 @@ func (f *RaftFSM) Restore(rc io.ReadCloser) error {
 		return err
 	}
 
+	// the keys missing from the snapshot must not survive it
+	if err := f.kvs.DropAll(); err != nil {
+		return err
+	}
+
 	keyCount := uint64(0)
 
 	buff := proto.NewBuffer(data)
@@ -275,6 +1231,15 @@ func (f *RaftFSM) Restore(rc io.ReadCloser) error {
 			return err
 		}
 
+		if kvp.Key == appliedIndexKey {
+			if len(kvp.Value) == 8 {
+				f.applyMutex.Lock()
+				f.appliedIndex = binary.BigEndian.Uint64(kvp.Value)
+				f.applyMutex.Unlock()
+			}
+			continue
+		}
+
 		// apply item to store
 		err = f.kvs.Set(kvp.Key, kvp.Value)
 		if err != nil {
@@ -286,6 +1251,11 @@ func (f *RaftFSM) Restore(rc io.ReadCloser) error {
 		keyCount = keyCount + 1
 	}
 
+	if err := f.loadMetadata(); err != nil {
+		return err
+	}
+	f.resetLogSinceSnapshot()
+
 	f.logger.Info("finished to restore items", zap.Uint64("count", keyCount), zap.Float64("time", float64(time.Since(start))/float64(time.Second)))
 
 	return nil
@@ -294,8 +1264,10 @@ func (f *RaftFSM) Restore(rc io.ReadCloser) error {
 // ---------------------
 
 type KVSFSMSnapshot struct {
-	kvs    *storage.KVS
-	logger *zap.Logger
+	snapshot     *storage.Snapshot
+	appliedIndex uint64
+	limiter      *rateLimiter
+	logger       *zap.Logger
 }
 
 func (f *KVSFSMSnapshot) Persist(sink raft.SnapshotSink) error {
@@ -303,38 +1275,29 @@ func (f *KVSFSMSnapshot) Persist(sink raft.SnapshotSink) error {
 
 	f.logger.Info("start to persist items")
 
-	defer func() {
-		err := sink.Close()
-		if err != nil {
-			f.logger.Error("failed to close sink", zap.Error(err))
-		}
-	}()
-
-	ch := f.kvs.SnapshotItems()
-
 	kvpCount := uint64(0)
 
-	for {
-		kvp := <-ch
-		if kvp == nil {
-			f.logger.Debug("channel closed")
-			break
-		}
-
+	// a throttled snapshot is background work, it also reads the store with a lower priority
+	err := f.snapshot.Items(f.limiter != nil, func(kvp *protobuf.KeyValuePair) error {
 		kvpCount = kvpCount + 1
-
-		buff := proto.NewBuffer([]byte{})
-		err := buff.EncodeMessage(kvp)
-		if err != nil {
-			f.logger.Error("failed to encode key value pair", zap.Error(err))
-			return err
+		return f.write(sink, kvp)
+	})
+	if err == nil {
+		// the applied index is the last item of the snapshot
+		appliedIndex := make([]byte, 8)
+		binary.BigEndian.PutUint64(appliedIndex, f.appliedIndex)
+		err = f.write(sink, &protobuf.KeyValuePair{Key: appliedIndexKey, Value: appliedIndex})
+	}
+	if err != nil {
+		if err := sink.Cancel(); err != nil {
+			f.logger.Error("failed to cancel sink", zap.Error(err))
 		}
+		return err
+	}
 
-		_, err = sink.Write(buff.Bytes())
-		if err != nil {
-			f.logger.Error("failed to write key value pair", zap.Error(err))
-			return err
-		}
+	if err := sink.Close(); err != nil {
+		f.logger.Error("failed to close sink", zap.Error(err))
+		return err
 	}
 
 	f.logger.Info("finished to persist items", zap.Uint64("count", kvpCount), zap.Float64("time", float64(time.Since(start))/float64(time.Second)))
@@ -342,6 +1305,26 @@ func (f *KVSFSMSnapshot) Persist(sink raft.SnapshotSink) error {
 	return nil
 }
 
+func (f *KVSFSMSnapshot) write(sink raft.SnapshotSink, kvp *protobuf.KeyValuePair) error {
+	buff := proto.NewBuffer([]byte{})
+	err := buff.EncodeMessage(kvp)
+	if err != nil {
+		f.logger.Error("failed to encode key value pair", zap.Error(err))
+		return err
+	}
+
+	f.limiter.wait(len(buff.Bytes()))
+
+	_, err = sink.Write(buff.Bytes())
+	if err != nil {
+		f.logger.Error("failed to write key value pair", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 func (f *KVSFSMSnapshot) Release() {
+	f.snapshot.Discard()
 	f.logger.Info("release")
 }
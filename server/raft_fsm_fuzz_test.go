@@ -0,0 +1,200 @@
+//go:build go1.18
+// +build go1.18
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/marshaler"
+	"github.com/mosuka/cete/protobuf"
+)
+
+// resetTestRaftFSM removes everything the FSM holds, so that it can be reused
+// instead of opening a new key value store for every input.
+func resetTestRaftFSM(t *testing.T, fsm *RaftFSM) {
+	keys := make([]string, 0)
+	if err := fsm.kvs.Iterate("", func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, key := range keys {
+		if err := fsm.kvs.Delete(key); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	fsm.nodesMutex.Lock()
+	fsm.metadata = make(map[string]*protobuf.Metadata, 0)
+	fsm.nodesMutex.Unlock()
+}
+
+// fsmState returns everything the FSM holds, including the system keys.
+func fsmState(t *testing.T, fsm *RaftFSM) (map[string]string, map[string]*protobuf.Metadata) {
+	kvs := make(map[string]string, 0)
+	if err := fsm.kvs.Iterate("", func(key string, value []byte) error {
+		kvs[key] = string(value)
+		return nil
+	}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	fsm.nodesMutex.RLock()
+	defer fsm.nodesMutex.RUnlock()
+	metadata := make(map[string]*protobuf.Metadata, len(fsm.metadata))
+	for id, m := range fsm.metadata {
+		metadata[id] = m
+	}
+
+	return kvs, metadata
+}
+
+// fuzzOperations decodes the bytes into a sequence of log entries.
+// Each operation consumes three bytes: the operation, a key and a value.
+// Unknown operations turn the rest of the input into a raw, possibly malformed, log entry.
+func fuzzOperations(t *testing.T, ops []byte) [][]byte {
+	entries := make([][]byte, 0)
+	for len(ops) >= 3 {
+		op, k, v := ops[0], ops[1], ops[2]
+		key := fmt.Sprintf("k%d", k%4)
+		id := fmt.Sprintf("node%d", k%3)
+		value := []byte{v}
+
+		var eventType protobuf.Event_Type
+		var data proto.Message
+		switch op % 9 {
+		case 0, 1:
+			eventType, data = protobuf.Event_Set, &protobuf.SetRequest{Key: key, Value: value}
+		case 2:
+			eventType, data = protobuf.Event_Delete, &protobuf.DeleteRequest{Key: key}
+		case 3:
+			eventType, data = protobuf.Event_Join, &protobuf.SetMetadataRequest{Id: id, Metadata: &protobuf.Metadata{GrpcAddress: fmt.Sprintf(":%d", v)}}
+		case 4:
+			eventType, data = protobuf.Event_Leave, &protobuf.DeleteMetadataRequest{Id: id}
+		case 5:
+			spec := &protobuf.MembershipSpec{Nodes: map[string]*protobuf.Node{id: {RaftAddress: fmt.Sprintf(":%d", v)}}}
+			eventType, data = protobuf.Event_SetMembershipSpec, &protobuf.SetMembershipSpecRequest{Spec: spec}
+		case 6:
+			eventType, data = protobuf.Event_DeleteMembershipSpec, &empty.Empty{}
+		case 7:
+			eventType, data = protobuf.Event_Reconcile, &protobuf.ReconcileAction{Type: protobuf.ReconcileAction_Type(v % 4), Id: id}
+		default:
+			return append(entries, ops[1:])
+		}
+
+		event, err := marshaler.NewEvent(eventType, data)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		entry, err := proto.Marshal(event)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		entries = append(entries, entry)
+		ops = ops[3:]
+	}
+
+	return entries
+}
+
+func FuzzRaftFSMApply(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x08, 0x03})
+	f.Add([]byte{0x08, 0x63, 0x12, 0x00})
+
+	fsm := newTestRaftFSM(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// malformed entries must be rejected, never panic
+		_ = fsm.Apply(&raft.Log{Index: 1, Data: data})
+	})
+}
+
+func FuzzRaftFSMDeterminism(f *testing.F) {
+	f.Add([]byte{0, 1, 'a', 0, 2, 'b', 2, 1, 0})
+	f.Add([]byte{3, 0, 1, 5, 1, 2, 4, 0, 0, 6, 0, 0})
+	f.Add([]byte{0, 0, 'a', 7, 1, 2, 8, 0x08, 0x03, 0x12})
+
+	fsm1 := newTestRaftFSM(f)
+	fsm2 := newTestRaftFSM(f)
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		resetTestRaftFSM(t, fsm1)
+		resetTestRaftFSM(t, fsm2)
+
+		// the expected user data
+		model := make(map[string]string, 0)
+
+		for i, entry := range fuzzOperations(t, ops) {
+			l := &raft.Log{Index: uint64(i + 1), Data: entry}
+
+			ret1 := fsm1.Apply(l).(*applyResponse).err
+			ret2 := fsm2.Apply(l).(*applyResponse).err
+			if (ret1 == nil) != (ret2 == nil) {
+				t.Fatalf("entry %d: FSMs disagree on the result, saw %v and %v", i, ret1, ret2)
+			}
+
+			event := &protobuf.Event{}
+			if ret1 != nil || proto.Unmarshal(entry, event) != nil {
+				continue
+			}
+			switch event.Type {
+			case protobuf.Event_Set:
+				data, _ := marshaler.EventData(event)
+				req := data.(*protobuf.SetRequest)
+				model[req.Key] = string(req.Value)
+			case protobuf.Event_Delete:
+				data, _ := marshaler.EventData(event)
+				delete(model, data.(*protobuf.DeleteRequest).Key)
+			}
+		}
+
+		kvs1, metadata1 := fsmState(t, fsm1)
+		kvs2, metadata2 := fsmState(t, fsm2)
+		if !reflect.DeepEqual(kvs1, kvs2) {
+			t.Fatalf("FSMs diverged, saw %v and %v", kvs1, kvs2)
+		}
+		if len(metadata1) != len(metadata2) {
+			t.Fatalf("FSM metadata diverged, saw %v and %v", metadata1, metadata2)
+		}
+		for id, m := range metadata1 {
+			if !proto.Equal(m, metadata2[id]) {
+				t.Fatalf("FSM metadata of %s diverged, saw %v and %v", id, m, metadata2[id])
+			}
+		}
+
+		index1, hash1, err := fsm1.Hash()
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		index2, hash2, err := fsm2.Hash()
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if index1 != index2 || !bytes.Equal(hash1, hash2) {
+			t.Fatalf("FSM hashes diverged, saw %d:%x and %d:%x", index1, hash1, index2, hash2)
+		}
+
+		for key, value := range kvs1 {
+			if isSystemKey(key) {
+				continue
+			}
+			if model[key] != value {
+				t.Fatalf("expected %s to be %q, saw %q", key, model[key], value)
+			}
+		}
+		for key := range model {
+			if _, ok := kvs1[key]; !ok {
+				t.Fatalf("expected %s to exist", key)
+			}
+		}
+	})
+}
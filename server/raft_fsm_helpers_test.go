@@ -0,0 +1,74 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/marshaler"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// testTempDir creates a directory removed at the end of the test, like t.TempDir, which
+// the toolchain declared by go.mod does not have.
+func testTempDir(t testing.TB) string {
+	dir, err := ioutil.TempDir("", "cete-test-")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	return dir
+}
+
+func newTestRaftFSM(t testing.TB) *RaftFSM {
+	fsm, err := NewRaftFSM(testTempDir(t), zap.NewNop())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range fsm.applyCh {
+			if event == nil {
+				return
+			}
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = fsm.Close()
+		<-done
+	})
+
+	return fsm
+}
+
+func applyTestEvent(t *testing.T, fsm *RaftFSM, index uint64, eventType protobuf.Event_Type, data proto.Message) error {
+	return applyTestEventAt(t, fsm, index, time.Time{}, eventType, data)
+}
+
+// applyTestEventAt applies an event proposed at the time, or by an older version if the time is zero.
+func applyTestEventAt(t *testing.T, fsm *RaftFSM, index uint64, at time.Time, eventType protobuf.Event_Type, data proto.Message) error {
+	event, err := marshaler.NewEvent(eventType, data)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !at.IsZero() {
+		event.Time = &timestamp.Timestamp{Seconds: at.Unix(), Nanos: int32(at.Nanosecond())}
+	}
+	b, err := proto.Marshal(event)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return fsm.Apply(&raft.Log{Index: index, Data: b}).(*applyResponse).err
+}
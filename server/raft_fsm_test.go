@@ -0,0 +1,590 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/marshaler"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRaftFSMCopyAndMove(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	for i, key := range []string{"/a/1", "/a/2", "/b/1"} {
+		if err := applyTestEvent(t, fsm, uint64(i+1), protobuf.Event_Set, &protobuf.SetRequest{Key: key, Value: []byte(key)}); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if err := applyTestEvent(t, fsm, 4, protobuf.Event_Copy, &protobuf.CopyRequest{Source: "/b/1", Destination: "/c/1"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 5, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/a/", Destination: "/d/", Prefix: true}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for key, expected := range map[string]string{"/b/1": "/b/1", "/c/1": "/b/1", "/d/1": "/a/1", "/d/2": "/a/2"} {
+		value, err := fsm.Get(key)
+		if err != nil || string(value) != expected {
+			t.Errorf("expected %s to be %q, saw %q, %v", key, expected, value, err)
+		}
+	}
+	for _, key := range []string{"/a/1", "/a/2"} {
+		if _, err := fsm.Get(key); !errors.Is(err, errors.ErrNotFound) {
+			t.Errorf("expected %s to be moved, saw %v", key, err)
+		}
+		if index, _ := fsm.ModifiedIndex(key); index != 0 {
+			t.Errorf("expected the modified index of %s to be deleted, saw %d", key, index)
+		}
+	}
+	if index, _ := fsm.ModifiedIndex("/d/2"); index != 5 {
+		t.Errorf("expected /d/2 to be modified at 5, saw %d", index)
+	}
+
+	if err := applyTestEvent(t, fsm, 6, protobuf.Event_Copy, &protobuf.CopyRequest{Source: "/missing", Destination: "/e"}); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected copying a missing key to fail, saw %v", err)
+	}
+}
+
+func TestRaftFSMOverlappingMove(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	for i, key := range []string{"/k", "/a/1", "/a/b/1"} {
+		if err := applyTestEvent(t, fsm, uint64(i+1), protobuf.Event_Set, &protobuf.SetRequest{Key: key, Value: []byte(key)}); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if err := applyTestEvent(t, fsm, 4, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/k", Destination: "/k"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 5, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/a/", Destination: "/a/b/", Prefix: true}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for key, expected := range map[string]string{"/k": "/k", "/a/b/1": "/a/1", "/a/b/b/1": "/a/b/1"} {
+		value, err := fsm.Get(key)
+		if err != nil || string(value) != expected {
+			t.Errorf("expected %s to be %q, saw %q, %v", key, expected, value, err)
+		}
+	}
+	if _, err := fsm.Get("/a/1"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected /a/1 to be moved, saw %v", err)
+	}
+}
+
+func TestRaftFSMCheckCopySize(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	maxCount, _ := fsm.kvs.BatchLimits()
+	// a move writes 3 entries and deletes 3 entries per key
+	keys := int(maxCount/6) + 1
+	sets := make(map[string][]byte, 0)
+	for i := 0; i < keys; i++ {
+		sets[fmt.Sprintf("/big/%06d", i)] = []byte("value")
+		if len(sets) == 10000 || i == keys-1 {
+			if err := fsm.kvs.Batch(sets, nil); err != nil {
+				t.Fatalf("%v", err)
+			}
+			sets = make(map[string][]byte, 0)
+		}
+	}
+
+	if err := fsm.checkCopySize("/big/", "/moved/", false); err != nil {
+		t.Errorf("expected the copy of %d keys to fit in a transaction, saw %v", keys, err)
+	}
+	if err := fsm.checkCopySize("/big/", "/moved/", true); errors.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected the move of %d keys to be refused, saw %v", keys, err)
+	}
+}
+
+func TestRaftFSMSnapshot(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+	if err := applyTestEvent(t, fsm, 1, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("a")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	snapshot, err := fsm.Snapshot()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer snapshot.Release()
+
+	// written after the snapshot was taken, before it is persisted
+	if err := applyTestEvent(t, fsm, 2, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/a", Destination: "/b"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	store := raft.NewInmemSnapshotStore()
+	sink, err := store.Create(raft.SnapshotVersionMax, 1, 1, raft.Configuration{}, 1, nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := snapshot.Persist(sink); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	restored := newTestRaftFSM(t)
+	if err := applyTestEvent(t, restored, 1, protobuf.Event_Set, &protobuf.SetRequest{Key: "/stale", Value: []byte("stale")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	_, rc, err := store.Open(sink.ID())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := restored.Restore(rc); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if value, err := restored.Get("/a"); err != nil || string(value) != "a" {
+		t.Errorf("expected /a as of the snapshot, saw %q, %v", value, err)
+	}
+	for _, key := range []string{"/b", "/stale"} {
+		if _, err := restored.Get(key); !errors.Is(err, errors.ErrNotFound) {
+			t.Errorf("expected %s not to be restored, saw %v", key, err)
+		}
+	}
+	if index := restored.AppliedIndex(); index != 1 {
+		t.Errorf("expected the applied index of the snapshot, saw %d", index)
+	}
+
+	// the move is replayed on top of the snapshot as it was applied the first time
+	if err := applyTestEvent(t, restored, 2, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/a", Destination: "/b"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if value, err := restored.Get("/b"); err != nil || string(value) != "a" {
+		t.Errorf("expected /b to be moved, saw %q, %v", value, err)
+	}
+}
+
+func TestRaftFSMExpiration(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	past := &timestamp.Timestamp{Seconds: time.Now().Add(-time.Minute).Unix()}
+	for i, req := range []*protobuf.SetRequest{
+		{Key: "/a", Value: []byte("1"), ExpiresAt: past},
+		{Key: "/b", Value: []byte("2"), ExpiresAt: past},
+		{Key: "/c", Value: []byte("3")},
+	} {
+		if err := applyTestEvent(t, fsm, uint64(i+1), protobuf.Event_Set, req); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	// expired keys are hidden before they are deleted
+	if _, err := fsm.Get("/a"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected /a to be expired, saw %v", err)
+	}
+	if values, err := fsm.Scan("/", nil); err != nil || len(values) != 1 {
+		t.Errorf("expected to scan 1 value, saw %q, %v", values, err)
+	}
+
+	keys, err := fsm.ExpiredKeys(time.Now())
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("expected 2 expired keys, saw %v, %v", keys, err)
+	}
+
+	// /b is set again without expiration before the sweep is applied
+	if err := applyTestEvent(t, fsm, 4, protobuf.Event_Set, &protobuf.SetRequest{Key: "/b", Value: []byte("4")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 5, protobuf.Event_Expire, &protobuf.ExpireRequest{Keys: keys, Time: &timestamp.Timestamp{Seconds: time.Now().Unix()}}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if _, err := fsm.kvs.Get("/a"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected /a to be deleted, saw %v", err)
+	}
+	if value, err := fsm.Get("/b"); err != nil || string(value) != "4" {
+		t.Errorf("expected /b to be kept, saw %q, %v", value, err)
+	}
+	if keys, _ := fsm.ExpiredKeys(time.Now()); len(keys) != 0 {
+		t.Errorf("expected no expired keys, saw %v", keys)
+	}
+}
+
+func TestRaftFSMExpirationClock(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+	clock := NewFakeClock(time.Now())
+	fsm.clock = clock
+
+	expiresAt := &timestamp.Timestamp{Seconds: clock.Now().Add(time.Hour).Unix()}
+	if err := applyTestEvent(t, fsm, 1, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("1"), ExpiresAt: expiresAt}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, err := fsm.Get("/a"); err != nil {
+		t.Errorf("expected /a not to be expired yet, saw %v", err)
+	}
+
+	clock.Advance(time.Hour + time.Second)
+	if _, err := fsm.Get("/a"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected /a to be expired, saw %v", err)
+	}
+	if values, err := fsm.Scan("/", nil); err != nil || len(values) != 0 {
+		t.Errorf("expected to scan no values, saw %q, %v", values, err)
+	}
+}
+
+func TestRaftFSMApplyHooks(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	synced := make([]uint64, 0)
+	asyncCh := make(chan uint64, 10)
+	fsm.hooks = newApplyHooks([]*applyHook{
+		{prefix: "/a/", hook: func(index uint64, event *protobuf.Event) {
+			synced = append(synced, index)
+		}},
+		{prefix: "/b/", hook: func(index uint64, event *protobuf.Event) {
+			asyncCh <- index
+		}, ch: make(chan *appliedEvent, 1), doneCh: make(chan struct{})},
+		{prefix: "/", hook: func(index uint64, event *protobuf.Event) {
+			panic("the FSM must survive a panicking hook")
+		}},
+	}, zap.NewNop())
+
+	for i, key := range []string{"/a/1", "/b/1", "/c/1", "/a/2"} {
+		if err := applyTestEvent(t, fsm, uint64(i+1), protobuf.Event_Set, &protobuf.SetRequest{Key: key, Value: []byte("1")}); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	if err := applyTestEvent(t, fsm, 5, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/a/", Destination: "/b/", Prefix: true}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(synced) != 3 || synced[0] != 1 || synced[1] != 4 || synced[2] != 5 {
+		t.Errorf("expected the synchronous hook to be called for entries 1, 4 and 5, saw %v", synced)
+	}
+	fsm.hooks.stop()
+	close(asyncCh)
+	async := make([]uint64, 0)
+	for index := range asyncCh {
+		async = append(async, index)
+	}
+	if len(async) != 2 || async[0] != 2 || async[1] != 5 {
+		t.Errorf("expected the asynchronous hook to be called for entries 2 and 5, saw %v", async)
+	}
+	fsm.hooks = newApplyHooks(nil, zap.NewNop())
+}
+
+func TestRaftFSMPriorityQueueFull(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+	fsm.priorityPrefixes = newPriorityPrefixes([]string{"/config/"})
+	fsm.priorityCh = make(chan *appliedEvent, 1)
+	dropped := prometheus.NewCounter(prometheus.CounterOpts{Name: "dropped"})
+	fsm.droppedPriority = dropped
+
+	// nothing fans the priority events out, as while the node stops
+	done := make(chan error)
+	go func() {
+		for i := uint64(1); i <= 3; i++ {
+			if err := applyTestEvent(t, fsm, i, protobuf.Event_Set, &protobuf.SetRequest{Key: "/config/a", Value: []byte("a")}); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("expected the entries to be applied while the priority queue is full")
+	}
+	if n := testutil.ToFloat64(dropped); n != 2 {
+		t.Errorf("expected 2 dropped priority events, saw %v", n)
+	}
+}
+
+func TestRaftFSMExpectedIndex(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	// 0 expects the key not to exist
+	if err := applyTestEvent(t, fsm, 1, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("1"), ExpectedIndex: &wrappers.UInt64Value{Value: 0}}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 2, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("2"), ExpectedIndex: &wrappers.UInt64Value{Value: 0}}); !errors.Is(err, errors.ErrConflict) {
+		t.Errorf("expected creating /a again to conflict, saw %v", err)
+	}
+
+	if err := applyTestEvent(t, fsm, 3, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("3"), ExpectedIndex: &wrappers.UInt64Value{Value: 1}}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 4, protobuf.Event_Delete, &protobuf.DeleteRequest{Key: "/a", ExpectedIndex: &wrappers.UInt64Value{Value: 1}}); !errors.Is(err, errors.ErrConflict) {
+		t.Errorf("expected deleting a stale /a to conflict, saw %v", err)
+	}
+
+	value, index, err := fsm.GetWithIndex("/a")
+	if err != nil || string(value) != "3" || index != 3 {
+		t.Errorf("expected /a to be 3 modified at 3, saw %q at %d, %v", value, index, err)
+	}
+}
+
+func TestRaftFSMChecksum(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	checksum := &wrappers.UInt32Value{Value: protobuf.Checksum([]byte("1"))}
+	if err := applyTestEvent(t, fsm, 1, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("1"), Checksum: checksum}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	err := applyTestEvent(t, fsm, 2, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("2"), Checksum: checksum})
+	if !errors.Is(err, errors.ErrChecksumMismatch) || errors.Code(err) != codes.DataLoss {
+		t.Errorf("expected the corrupted value to be refused, saw %v", err)
+	}
+
+	value, index, err := fsm.GetWithIndex("/a")
+	if err != nil || string(value) != "1" || index != 1 {
+		t.Errorf("expected /a to be 1 modified at 1, saw %q at %d, %v", value, index, err)
+	}
+}
+
+func TestRaftFSMCompactionFilter(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+	now := time.Date(2020, 10, 7, 10, 0, 0, 0, time.UTC)
+
+	for i, test := range []struct {
+		key string
+		at  time.Time
+	}{
+		{"/logs/old", now.Add(-48 * time.Hour)},
+		{"/logs/rewritten", now.Add(-48 * time.Hour)},
+		{"/logs/new", now.Add(-time.Hour)},
+		{"/logs/unknown", time.Time{}},
+		{"/other", now.Add(-48 * time.Hour)},
+	} {
+		if err := applyTestEventAt(t, fsm, uint64(i+1), test.at, protobuf.Event_Set, &protobuf.SetRequest{Key: test.key, Value: []byte("1")}); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	keys, err := fsm.PurgeCandidates("/logs/", RetentionFilter(24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(keys) != 2 || keys[0].Key != "/logs/old" || keys[1].Key != "/logs/rewritten" {
+		t.Fatalf("expected the old keys to be dropped, saw %v", keys)
+	}
+
+	// a key modified since the filter was run is kept
+	if err := applyTestEventAt(t, fsm, 6, now, protobuf.Event_Set, &protobuf.SetRequest{Key: "/logs/rewritten", Value: []byte("2")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEventAt(t, fsm, 7, now, protobuf.Event_Purge, &protobuf.PurgeRequest{Filter: "retention", Keys: keys}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for key, expected := range map[string]bool{
+		"/logs/old":       false,
+		"/logs/rewritten": true,
+		"/logs/new":       true,
+		"/logs/unknown":   true,
+		"/other":          true,
+	} {
+		_, err := fsm.Get(key)
+		if found := err == nil; found != expected {
+			t.Errorf("expected %s to be found %v, saw %v", key, expected, err)
+		}
+	}
+}
+
+func TestRaftFSMRole(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	if err := applyTestEvent(t, fsm, 1, protobuf.Event_SetRole, &protobuf.SetRoleRequest{Mode: protobuf.Role_Standby, Source: "primary:9000"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 2, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("1")}); !errors.Is(err, errors.ErrReadOnly) {
+		t.Errorf("expected the standby to refuse the write, saw %v", err)
+	}
+
+	// the replicated keys carry the index of the source
+	event, err := marshaler.NewEvent(protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("1")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 3, protobuf.Event_Replicate, &protobuf.ReplicateRequest{Index: 100, Event: event}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if value, index, err := fsm.GetWithIndex("/a"); err != nil || string(value) != "1" || index != 100 {
+		t.Errorf("expected /a to be 1 modified at 100, saw %q at %d, %v", value, index, err)
+	}
+
+	if err := applyTestEvent(t, fsm, 4, protobuf.Event_Replicate, &protobuf.ReplicateRequest{Index: 105}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if index, err := fsm.ReplicatedIndex(); err != nil || index != 105 {
+		t.Errorf("expected the replicated index to be 105, saw %d, %v", index, err)
+	}
+
+	if err := applyTestEvent(t, fsm, 5, protobuf.Event_SetRole, &protobuf.SetRoleRequest{Mode: protobuf.Role_Primary, Reason: "failover"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 6, protobuf.Event_Replicate, &protobuf.ReplicateRequest{Index: 106, Event: event}); !errors.Is(err, errors.ErrConflict) {
+		t.Errorf("expected the primary to refuse the replicated event, saw %v", err)
+	}
+	if err := applyTestEvent(t, fsm, 7, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("2"), ExpectedIndex: &wrappers.UInt64Value{Value: 100}}); err != nil {
+		t.Errorf("expected the promoted standby to take the write, saw %v", err)
+	}
+
+	role, err := fsm.Role()
+	if err != nil || role.Mode != protobuf.Role_Primary || role.Reason != "failover" || role.Index != 5 {
+		t.Errorf("expected a primary set at 5, saw %v, %v", role, err)
+	}
+}
+
+func TestRaftFSMMembershipEpoch(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	for i, event := range []struct {
+		eventType protobuf.Event_Type
+		data      proto.Message
+	}{
+		{protobuf.Event_Join, &protobuf.SetMetadataRequest{Id: "node1", Metadata: &protobuf.Metadata{GrpcAddress: ":9000"}}},
+		{protobuf.Event_Join, &protobuf.SetMetadataRequest{Id: "node2", Metadata: &protobuf.Metadata{GrpcAddress: ":9001"}}},
+		// a node joining again after a restart does not change the membership
+		{protobuf.Event_Join, &protobuf.SetMetadataRequest{Id: "node1", Metadata: &protobuf.Metadata{GrpcAddress: ":9010"}}},
+		{protobuf.Event_Leave, &protobuf.DeleteMetadataRequest{Id: "node2"}},
+		{protobuf.Event_Leave, &protobuf.DeleteMetadataRequest{Id: "node2"}},
+	} {
+		if err := applyTestEvent(t, fsm, uint64(i+1), event.eventType, event.data); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if epoch, err := fsm.MembershipEpoch(); err != nil || epoch != 3 {
+		t.Errorf("expected the membership epoch to be 3, saw %d, %v", epoch, err)
+	}
+	for index, expected := range map[uint64]uint64{1: 1, 2: 2, 3: 0, 4: 3, 5: 0} {
+		if epoch, err := fsm.membershipEpochAt(index); err != nil || epoch != expected {
+			t.Errorf("expected the membership epoch at %d to be %d, saw %d, %v", index, expected, epoch, err)
+		}
+	}
+}
+
+func TestRaftFSMResume(t *testing.T) {
+	dir := testTempDir(t)
+
+	// the apply channel buffers the few events of the test
+	fsm, err := NewRaftFSM(dir, zap.NewNop())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	for i, key := range []string{"/a", "/b", "/c"} {
+		if err := applyTestEvent(t, fsm, uint64(i+1), protobuf.Event_Set, &protobuf.SetRequest{Key: key, Value: []byte("1")}); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	if err := fsm.Close(); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	fsm, err = NewRaftFSM(dir, zap.NewNop())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer func() {
+		_ = fsm.Close()
+	}()
+
+	// a snapshot newer than the key value store is restored
+	if resumed, err := fsm.resume(4); err != nil || resumed {
+		t.Fatalf("expected not to resume before a newer snapshot, saw %v, %v", resumed, err)
+	}
+	if resumed, err := fsm.resume(2); err != nil || !resumed {
+		t.Fatalf("expected to resume, saw %v, %v", resumed, err)
+	}
+	if index := fsm.AppliedIndex(); index != 3 {
+		t.Errorf("expected to resume at 3, saw %d", index)
+	}
+	if _, err := fsm.kvs.Get(resumeIndexKey); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected the shutdown index to be deleted, saw %v", err)
+	}
+
+	// the log entries replayed by Raft up to the shutdown index are skipped
+	if err := applyTestEvent(t, fsm, 3, protobuf.Event_Set, &protobuf.SetRequest{Key: "/c", Value: []byte("2")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := applyTestEvent(t, fsm, 4, protobuf.Event_Set, &protobuf.SetRequest{Key: "/b", Value: []byte("2")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	for key, expected := range map[string]string{"/a": "1", "/b": "2", "/c": "1"} {
+		if value, err := fsm.Get(key); err != nil || string(value) != expected {
+			t.Errorf("expected %s to be %q, saw %q, %v", key, expected, value, err)
+		}
+	}
+}
+
+func TestRaftFSMAnnotations(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	for i, req := range []*protobuf.AnnotateRequest{
+		{Id: "node1", Annotations: map[string]string{"reason": "draining", "ticket": "OPS-42"}},
+		{Id: "node10", Annotations: map[string]string{"reason": "new"}},
+		{Id: "node1", Annotations: map[string]string{"ticket": ""}},
+	} {
+		if err := applyTestEvent(t, fsm, uint64(i+1), protobuf.Event_Annotate, req); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	annotations, err := fsm.Annotations("node1")
+	if err != nil || len(annotations) != 1 || annotations["reason"] != "draining" {
+		t.Errorf("expected node1 to be annotated with its reason only, saw %v, %v", annotations, err)
+	}
+
+	// the annotations are removed when the node leaves
+	if err := applyTestEvent(t, fsm, 4, protobuf.Event_Leave, &protobuf.DeleteMetadataRequest{Id: "node1"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if annotations, _ := fsm.Annotations("node1"); len(annotations) != 0 {
+		t.Errorf("expected no annotations, saw %v", annotations)
+	}
+	if annotations, _ := fsm.Annotations("node10"); annotations["reason"] != "new" {
+		t.Errorf("expected node10 to keep its annotations, saw %v", annotations)
+	}
+}
+
+func TestRaftFSMNodeStatus(t *testing.T) {
+	fsm := newTestRaftFSM(t)
+
+	joined := &protobuf.Metadata{GrpcAddress: ":9000"}
+	if err := applyTestEvent(t, fsm, 1, protobuf.Event_Join, &protobuf.SetMetadataRequest{Id: "node1", Metadata: joined}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status, err := fsm.NodeStatus("node1"); err != nil || status != nil {
+		t.Errorf("expected no status before it is reported, saw %v, %v", status, err)
+	}
+
+	// the addresses reported by the node replace those it joined with
+	reported := &protobuf.NodeStatus{State: "Follower", Metadata: &protobuf.Metadata{GrpcAddress: ":9100"}, Ready: true}
+	if err := applyTestEvent(t, fsm, 2, protobuf.Event_UpdateNodeStatus, &protobuf.UpdateNodeStatusRequest{Id: "node1", Status: reported}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status, err := fsm.NodeStatus("node1"); err != nil || !proto.Equal(status, reported) {
+		t.Errorf("expected %v, saw %v, %v", reported, status, err)
+	}
+	if metadata := fsm.getMetadata("node1"); metadata.GrpcAddress != ":9100" {
+		t.Errorf("expected the reported gRPC address, saw %v", metadata)
+	}
+
+	// the status is removed when the node leaves
+	if err := applyTestEvent(t, fsm, 3, protobuf.Event_Leave, &protobuf.DeleteMetadataRequest{Id: "node1"}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status, err := fsm.NodeStatus("node1"); err != nil || status != nil {
+		t.Errorf("expected no status, saw %v, %v", status, err)
+	}
+}
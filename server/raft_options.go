@@ -0,0 +1,247 @@
+package server
+
+import (
+	"path/filepath"
+	"time"
+)
+
+type raftOptions struct {
+	reconcileInterval  time.Duration
+	sweepInterval      time.Duration
+	snapshotLogBytes   uint64
+	snapshotInterval   time.Duration
+	snapshotRateLimit  int64
+	kvsDirectory       string
+	raftDirectory      string
+	snapshotDirectory  string
+	propagatedMetadata []string
+	profile            Profile
+	fastRestart        bool
+	quorumLossTimeout  time.Duration
+	writeBackpressure  bool
+	clock              Clock
+	applyHooks         []*applyHook
+	writeFencing       bool
+	priorityPrefixes   []string
+	compactionFilters  []*compactionFilter
+	peerAllowlist      *PeerAllowlist
+	exportDestinations *exportDestinations
+}
+
+func defaultRaftOptions() *raftOptions {
+	return &raftOptions{
+		sweepInterval: defaultExpirationSweepInterval,
+		profile:       LANProfile,
+		fastRestart:   true,
+
+		writeBackpressure: true,
+		writeFencing:      true,
+
+		clock: RealClock,
+
+		quorumLossTimeout: defaultQuorumLossTimeout,
+
+		exportDestinations: &exportDestinations{},
+	}
+}
+
+// RaftServerOption configures the Raft server.
+type RaftServerOption func(*raftOptions)
+
+// WithMembershipReconciliation makes the leader reconcile the cluster membership
+// against the membership spec at the specified interval.
+// Reconciliation is disabled if the interval is zero.
+func WithMembershipReconciliation(interval time.Duration) RaftServerOption {
+	return func(o *raftOptions) {
+		o.reconcileInterval = interval
+	}
+}
+
+// WithExpirationSweep makes the leader delete the expired keys at the specified interval.
+// Expired keys are not visible in the meantime, but they take disk space until they are
+// deleted. The sweep is disabled if the interval is zero.
+func WithExpirationSweep(interval time.Duration) RaftServerOption {
+	return func(o *raftOptions) {
+		o.sweepInterval = interval
+	}
+}
+
+// WithAdaptiveSnapshots takes a snapshot once the log entries applied since the last
+// snapshot reach the size in bytes, or once the last snapshot is older than the interval,
+// in addition to the snapshots taken every 1024 log entries. Zero disables either trigger.
+func WithAdaptiveSnapshots(maxLogBytes uint64, maxInterval time.Duration) RaftServerOption {
+	return func(o *raftOptions) {
+		o.snapshotLogBytes = maxLogBytes
+		o.snapshotInterval = maxInterval
+	}
+}
+
+// WithSnapshotRateLimit limits how many bytes per second are written when persisting
+// a snapshot. Snapshots are not limited if the rate is zero.
+func WithSnapshotRateLimit(bytesPerSecond int64) RaftServerOption {
+	return func(o *raftOptions) {
+		o.snapshotRateLimit = bytesPerSecond
+	}
+}
+
+// WithStorageDirectories places the key value store, the Raft log and stable stores, and
+// the snapshots in separate directories, e.g. to keep the Raft log on a fast local disk
+// and the snapshots on cheaper storage. Empty directories default to the data directory.
+func WithStorageDirectories(kvsDirectory string, raftDirectory string, snapshotDirectory string) RaftServerOption {
+	return func(o *raftOptions) {
+		o.kvsDirectory = kvsDirectory
+		o.raftDirectory = raftDirectory
+		o.snapshotDirectory = snapshotDirectory
+	}
+}
+
+// WithPropagatedMetadata copies the gRPC metadata with the keys from write requests into
+// the replicated events, so that audit logs and watchers can attribute every write to its
+// source, e.g. "x-client-id". Requests forwarded to the leader keep these keys.
+func WithPropagatedMetadata(keys ...string) RaftServerOption {
+	return func(o *raftOptions) {
+		o.propagatedMetadata = keys
+	}
+}
+
+// WithPriorityPrefixes makes the writes of the keys with the prefixes go ahead of the other
+// writes, e.g. to keep the configuration or the feature flags fast during a large import.
+// On the leader, the other writes share a bounded number of slots in the Raft log, and
+// are held back while the compactions are behind. On every node, the events of the
+// priority writes are fanned out to the watchers of the prefixes by their own queue.
+func WithPriorityPrefixes(prefixes ...string) RaftServerOption {
+	return func(o *raftOptions) {
+		o.priorityPrefixes = prefixes
+	}
+}
+
+// WithProfile applies the Raft timing and transport settings of the profile. Its snapshot
+// rate limit is not applied, use WithSnapshotRateLimit.
+func WithProfile(p Profile) RaftServerOption {
+	return func(o *raftOptions) {
+		o.profile = p
+	}
+}
+
+// WithFastRestart resumes from the key value store left by a clean shutdown on startup,
+// replaying only the log entries applied after it, instead of restoring the latest
+// snapshot. A node that did not shut down cleanly always restores the snapshot.
+func WithFastRestart(enabled bool) RaftServerOption {
+	return func(o *raftOptions) {
+		o.fastRestart = enabled
+	}
+}
+
+// WithQuorumLossTimeout considers the quorum lost once the node has not known a leader
+// for the timeout. The node is then no longer ready, and it logs the unreachable voters
+// and how to recover. The detection is disabled if the timeout is zero.
+func WithQuorumLossTimeout(timeout time.Duration) RaftServerOption {
+	return func(o *raftOptions) {
+		o.quorumLossTimeout = timeout
+	}
+}
+
+// WithWriteBackpressure holds back the writes proposed by the node while the compactions
+// of its key value store are behind, until they catch up or the writes run out of their
+// queueing budget, instead of letting Badger block the writes for an unbounded time.
+func WithWriteBackpressure(enabled bool) RaftServerOption {
+	return func(o *raftOptions) {
+		o.writeBackpressure = enabled
+	}
+}
+
+// WithWriteFencing makes the leader confirm its leader lease with a quorum before taking
+// writes, at most once per leader lease timeout, and refuse to acknowledge the writes
+// applied after it lost the leadership, with a retryable error.
+func WithWriteFencing(enabled bool) RaftServerOption {
+	return func(o *raftOptions) {
+		o.writeFencing = enabled
+	}
+}
+
+// WithClock makes the background loops and timeouts of the server use the clock, so that
+// tests drive them with a FakeClock.
+func WithClock(clock Clock) RaftServerOption {
+	return func(o *raftOptions) {
+		o.clock = clock
+	}
+}
+
+// WithApplyHook calls the hook with the events applied by the FSM that write keys with
+// the prefix, or with all the events if the prefix is empty. A synchronous hook is called
+// by the FSM before the write is acknowledged, and holds up the following writes while it
+// runs. An asynchronous hook is called in the order of the log on its own goroutine.
+// The hooks are not called for the log entries restored with a snapshot or already in the
+// key value store on a fast restart, an application should initialize its structures
+// from a scan once the node has started.
+func WithApplyHook(prefix string, async bool, hook ApplyHook) RaftServerOption {
+	return func(o *raftOptions) {
+		h := &applyHook{
+			prefix: prefix,
+			hook:   hook,
+		}
+		if async {
+			h.ch = make(chan *appliedEvent, applyHookBufferSize)
+			h.doneCh = make(chan struct{})
+		}
+		o.applyHooks = append(o.applyHooks, h)
+	}
+}
+
+// WithCompactionFilter makes the leader run the filter on the keys with the prefix at
+// every expiration sweep, and delete the keys it drops, e.g. to enforce a retention policy.
+// The name tells the filters apart in the purge events, the logs and the metrics. Register
+// the same filters on every node, so that a new leader keeps running them.
+func WithCompactionFilter(name string, prefix string, filter CompactionFilter) RaftServerOption {
+	return func(o *raftOptions) {
+		o.compactionFilters = append(o.compactionFilters, &compactionFilter{
+			name:   name,
+			prefix: prefix,
+			filter: filter,
+		})
+	}
+}
+
+// WithPeerAllowlist refuses to add the nodes that are not in the allowlist to the cluster,
+// whether they join, are listed by a batch join or by the membership spec, and closes the
+// Raft connections from and to the addresses that are not in its networks. Configure the
+// same allowlist on every node, so that a new leader enforces it too.
+func WithPeerAllowlist(allowlist *PeerAllowlist) RaftServerOption {
+	return func(o *raftOptions) {
+		o.peerAllowlist = allowlist
+	}
+}
+
+// WithExportDestinations allows the exports to files in the directory or its
+// subdirectories, and to the http(s) URLs starting with one of the prefixes, e.g.
+// https://bucket.s3.amazonaws.com/backups/. The exports to other destinations are refused.
+func WithExportDestinations(directory string, urlPrefixes ...string) RaftServerOption {
+	return func(o *raftOptions) {
+		o.exportDestinations = &exportDestinations{
+			directory:   directory,
+			urlPrefixes: urlPrefixes,
+		}
+	}
+}
+
+// directories returns the directories of the key value store, of the Raft stores and
+// of the snapshot store. The snapshot store keeps the snapshots in a "snapshots"
+// directory under its directory.
+func (o *raftOptions) directories(dataDirectory string) (string, string, string) {
+	kvsDirectory := o.kvsDirectory
+	if kvsDirectory == "" {
+		kvsDirectory = filepath.Join(dataDirectory, "kvs")
+	}
+
+	raftDirectory := o.raftDirectory
+	if raftDirectory == "" {
+		raftDirectory = filepath.Join(dataDirectory, "raft")
+	}
+
+	snapshotDirectory := o.snapshotDirectory
+	if snapshotDirectory == "" {
+		snapshotDirectory = dataDirectory
+	}
+
+	return kvsDirectory, raftDirectory, snapshotDirectory
+}
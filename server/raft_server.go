@@ -1,26 +1,36 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	raftbadgerdb "github.com/bbva/raft-badger"
 	"github.com/dgraph-io/badger/v2"
 	"github.com/golang/protobuf/proto"
-	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/raft"
 	"github.com/mosuka/cete/errors"
 	"github.com/mosuka/cete/marshaler"
 	"github.com/mosuka/cete/metric"
 	"github.com/mosuka/cete/protobuf"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
 )
 
+// catchUpMaxLag is how many entries a non-voter may lag behind the leader to be promoted.
+const catchUpMaxLag = 64
+
 type RaftServer struct {
 	id            string
 	raftAddress   string
@@ -28,24 +38,103 @@ type RaftServer struct {
 	bootstrap     bool
 	logger        *zap.Logger
 
+	raftDirectory     string
+	snapshotDirectory string
+
 	fsm *RaftFSM
 
 	transport *raft.NetworkTransport
 	raft      *raft.Raft
+	logStore  raft.LogStore
 
 	watchClusterStopCh chan struct{}
 	watchClusterDoneCh chan struct{}
 
-	applyCh chan *protobuf.Event
+	reconcileInterval time.Duration
+	reconcileStopCh   chan struct{}
+	reconcileDoneCh   chan struct{}
+
+	sweepInterval time.Duration
+	sweepStopCh   chan struct{}
+	sweepDoneCh   chan struct{}
+
+	snapshotLogBytes      uint64
+	snapshotInterval      time.Duration
+	snapshotTriggerStopCh chan struct{}
+	snapshotTriggerDoneCh chan struct{}
+
+	propagatedMetadata []string
+
+	profile Profile
+
+	fastRestart bool
+
+	// the time since which no leader is known and whether the quorum is considered lost
+	quorumLossTimeout time.Duration
+	noLeaderSince     time.Time
+	quorumLost        int32
+
+	// whether the writes are held back while the compactions are behind
+	writeBackpressure bool
+	lastBlockedPuts   int64
+	writeStalled      int32
+
+	// the time the leader lease was last confirmed with a quorum
+	writeFencing   bool
+	leaseConfirmed int64
+
+	clock Clock
+
+	applyCh chan *appliedEvent
+
+	// the applied index each non-voter has to reach to be promoted
+	catchUpMutex   sync.Mutex
+	catchUpIndexes map[string]uint64
+
+	// the writes of the keys with these prefixes do not wait for the slots of bulk writes
+	priorityPrefixes priorityPrefixes
+	bulkSlots        chan struct{}
+
+	// run by the leader at every expiration sweep
+	compactionFilters []*compactionFilter
+
+	// the nodes that may be added to the cluster and connect to the Raft transport
+	peerAllowlist *PeerAllowlist
+	rejectedPeers *prometheus.CounterVec
+
+	// the destinations the node may export to
+	exportDestinations *exportDestinations
 }
 
-func NewRaftServer(id string, raftAddress string, dataDirectory string, bootstrap bool, logger *zap.Logger) (*RaftServer, error) {
-	fsmPath := filepath.Join(dataDirectory, "kvs")
+func NewRaftServer(id string, raftAddress string, dataDirectory string, bootstrap bool, logger *zap.Logger, opts ...RaftServerOption) (*RaftServer, error) {
+	o := defaultRaftOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	fsmPath, raftDirectory, snapshotDirectory := o.directories(dataDirectory)
 	fsm, err := NewRaftFSM(fsmPath, logger)
 	if err != nil {
 		logger.Error("failed to create FSM", zap.String("path", fsmPath), zap.Error(err))
 		return nil, err
 	}
+	fsm.snapshotLimiter = newRateLimiter(o.snapshotRateLimit)
+	fsm.expiredKeysCounter = metric.KvsExpiredKeysMetric.WithLabelValues(id)
+	fsm.expiredBytesCounter = metric.KvsExpiredBytesMetric.WithLabelValues(id)
+	fsm.purgedKeysCounter = metric.KvsPurgedKeysMetric.MustCurryWith(prometheus.Labels{"id": id})
+	fsm.replicatedIndex = metric.ReplicationReplicatedIndexMetric.WithLabelValues(id)
+	fsm.droppedPriority = metric.WatchDroppedPriorityEventsMetric.WithLabelValues(id)
+	fsm.clock = o.clock
+	fsm.hooks = newApplyHooks(o.applyHooks, logger)
+	fsm.lastSnapshotTime = o.clock.Now().UnixNano()
+
+	priority := newPriorityPrefixes(o.priorityPrefixes)
+	var bulkSlots chan struct{}
+	if len(priority) > 0 {
+		fsm.priorityPrefixes = priority
+		fsm.priorityCh = make(chan *appliedEvent, 1024)
+		bulkSlots = make(chan struct{}, bulkSlotsPerAppend*o.profile.MaxAppendEntries)
+	}
 
 	return &RaftServer{
 		id:            id,
@@ -55,10 +144,51 @@ func NewRaftServer(id string, raftAddress string, dataDirectory string, bootstra
 		fsm:           fsm,
 		logger:        logger,
 
+		raftDirectory:     raftDirectory,
+		snapshotDirectory: snapshotDirectory,
+
 		watchClusterStopCh: make(chan struct{}),
 		watchClusterDoneCh: make(chan struct{}),
 
-		applyCh: make(chan *protobuf.Event, 1024),
+		reconcileInterval: o.reconcileInterval,
+		reconcileStopCh:   make(chan struct{}),
+		reconcileDoneCh:   make(chan struct{}),
+
+		sweepInterval: o.sweepInterval,
+		sweepStopCh:   make(chan struct{}),
+		sweepDoneCh:   make(chan struct{}),
+
+		snapshotLogBytes:      o.snapshotLogBytes,
+		snapshotInterval:      o.snapshotInterval,
+		snapshotTriggerStopCh: make(chan struct{}),
+		snapshotTriggerDoneCh: make(chan struct{}),
+
+		propagatedMetadata: o.propagatedMetadata,
+
+		profile: o.profile,
+
+		fastRestart: o.fastRestart,
+
+		quorumLossTimeout: o.quorumLossTimeout,
+
+		writeBackpressure: o.writeBackpressure,
+		writeFencing:      o.writeFencing,
+
+		clock: o.clock,
+
+		applyCh: make(chan *appliedEvent, 1024),
+
+		catchUpIndexes: make(map[string]uint64, 0),
+
+		priorityPrefixes: priority,
+		bulkSlots:        bulkSlots,
+
+		compactionFilters: o.compactionFilters,
+
+		peerAllowlist: o.peerAllowlist,
+		rejectedPeers: metric.RaftRejectedPeersMetric.MustCurryWith(prometheus.Labels{"id": id}),
+
+		exportDestinations: o.exportDestinations,
 	}, nil
 }
 
@@ -67,6 +197,13 @@ func (s *RaftServer) Start() error {
 	config.LocalID = raft.ServerID(s.id)
 	config.SnapshotThreshold = 1024
 	config.LogOutput = ioutil.Discard
+	config.HeartbeatTimeout = s.profile.HeartbeatTimeout
+	config.ElectionTimeout = s.profile.ElectionTimeout
+	config.LeaderLeaseTimeout = s.profile.LeaderLeaseTimeout
+	config.CommitTimeout = s.profile.CommitTimeout
+	config.MaxAppendEntries = s.profile.MaxAppendEntries
+	config.TrailingLogs = s.profile.TrailingLogs
+	s.logger.Info("Raft profile", zap.String("profile", s.profile.Name))
 
 	addr, err := net.ResolveTCPAddr("tcp", s.raftAddress)
 	if err != nil {
@@ -74,20 +211,25 @@ func (s *RaftServer) Start() error {
 		return err
 	}
 
-	s.transport, err = raft.NewTCPTransport(s.raftAddress, addr, 3, 10*time.Second, ioutil.Discard)
+	// the transport enforces the peer allowlist of the node, or that of the cluster configuration
+	s.transport, err = newAllowlistTransport(s.raftAddress, addr, s.effectivePeerAllowlist, s.rejectedPeers.With(prometheus.Labels{"reason": "transport"}), s.profile.TransportMaxPool, s.profile.TransportTimeout, s.logger)
 	if err != nil {
 		s.logger.Error("failed to create TCP transport", zap.String("raft_address", s.raftAddress), zap.Error(err))
 		return err
 	}
+	if s.profile.TransportTimeoutScale > 0 {
+		s.transport.TimeoutScale = s.profile.TransportTimeoutScale
+	}
+	s.logger.Info("Raft transport", zap.Int("max_pool", s.profile.TransportMaxPool), zap.Duration("timeout", s.profile.TransportTimeout), zap.Int("timeout_scale", s.transport.TimeoutScale))
 
 	// create snapshot store
-	snapshotStore, err := raft.NewFileSnapshotStore(s.dataDirectory, 2, ioutil.Discard)
+	snapshotStore, err := raft.NewFileSnapshotStore(s.snapshotDirectory, 2, ioutil.Discard)
 	if err != nil {
-		s.logger.Error("failed to create file snapshot store", zap.String("path", s.dataDirectory), zap.Error(err))
+		s.logger.Error("failed to create file snapshot store", zap.String("path", s.snapshotDirectory), zap.Error(err))
 		return err
 	}
 
-	logStorePath := filepath.Join(s.dataDirectory, "raft", "log")
+	logStorePath := filepath.Join(s.raftDirectory, "log")
 	err = os.MkdirAll(logStorePath, 0755)
 	if err != nil {
 		s.logger.Fatal(err.Error())
@@ -107,7 +249,7 @@ func (s *RaftServer) Start() error {
 		return err
 	}
 
-	stableStorePath := filepath.Join(s.dataDirectory, "raft", "stable")
+	stableStorePath := filepath.Join(s.raftDirectory, "stable")
 	err = os.MkdirAll(stableStorePath, 0755)
 	if err != nil {
 		s.logger.Fatal(err.Error())
@@ -127,6 +269,38 @@ func (s *RaftServer) Start() error {
 		return err
 	}
 
+	s.logStore = raftLogStore
+
+	// the remaining nodes of a cluster that lost its quorum for good start with new voters
+	peersPath := filepath.Join(s.raftDirectory, peersFile)
+	if _, err := os.Stat(peersPath); err == nil {
+		if err := s.recoverCluster(config, peersPath, raftLogStore, raftStableStore, snapshotStore); err != nil {
+			return err
+		}
+	}
+
+	if s.fastRestart {
+		snapshotIndex := uint64(0)
+		snapshots, err := snapshotStore.List()
+		if err != nil {
+			s.logger.Error("failed to list snapshots", zap.String("path", s.snapshotDirectory), zap.Error(err))
+			return err
+		}
+		if len(snapshots) > 0 {
+			snapshotIndex = snapshots[0].Index
+		}
+
+		resumed, err := s.fsm.resume(snapshotIndex)
+		if err != nil {
+			s.logger.Error("failed to resume FSM", zap.Error(err))
+			return err
+		}
+		if resumed {
+			config.NoSnapshotRestoreOnStart = true
+			s.logger.Info("resume FSM from the key value store", zap.Uint64("applied_index", s.fsm.AppliedIndex()), zap.Uint64("snapshot_index", snapshotIndex))
+		}
+	}
+
 	// create raft
 	s.raft, err = raft.NewRaft(config, s.fsm, raftLogStore, raftStableStore, snapshotStore, s.transport)
 	if err != nil {
@@ -150,6 +324,27 @@ func (s *RaftServer) Start() error {
 		s.startWatchCluster(500 * time.Millisecond)
 	}()
 
+	if s.reconcileInterval > 0 {
+		go func() {
+			s.startReconcileMembership(s.reconcileInterval)
+		}()
+	} else {
+		close(s.reconcileDoneCh)
+	}
+
+	if s.sweepInterval > 0 {
+		go func() {
+			s.startExpirationSweep(s.sweepInterval)
+		}()
+	} else {
+		close(s.sweepDoneCh)
+	}
+
+	// the cluster configuration may schedule snapshots even if the node does not
+	go func() {
+		s.startSnapshotTrigger()
+	}()
+
 	s.logger.Info("Raft server started", zap.String("raft_address", s.raftAddress))
 	return nil
 }
@@ -159,17 +354,22 @@ func (s *RaftServer) Stop() error {
 	s.logger.Info("apply channel has closed")
 
 	s.stopWatchCluster()
+	s.stopReconcileMembership()
+	s.stopExpirationSweep()
+	s.stopSnapshotTrigger()
+
+	// Raft no longer applies log entries once it has shut down, so the applied index
+	// recorded by the FSM is the last one
+	if err := s.raft.Shutdown().Error(); err != nil {
+		s.logger.Info("failed to shutdown Raft", zap.Error(err))
+	}
+	s.logger.Info("Raft has shutdown", zap.String("raft_address", s.raftAddress))
 
 	if err := s.fsm.Close(); err != nil {
 		s.logger.Error("failed to close FSM", zap.Error(err))
 	}
 	s.logger.Info("Raft FSM Closed")
 
-	if future := s.raft.Shutdown(); future.Error() != nil {
-		s.logger.Info("failed to shutdown Raft", zap.Error(future.Error()))
-	}
-	s.logger.Info("Raft has shutdown", zap.String("raft_address", s.raftAddress))
-
 	return nil
 }
 
@@ -180,12 +380,12 @@ func (s *RaftServer) startWatchCluster(checkInterval time.Duration) {
 		close(s.watchClusterDoneCh)
 	}()
 
-	ticker := time.NewTicker(checkInterval)
+	ticker := s.clock.NewTicker(checkInterval)
 	defer ticker.Stop()
 
 	timeout := 60 * time.Second
 	if err := s.WaitForDetectLeader(timeout); err != nil {
-		if err == errors.ErrTimeout {
+		if errors.Is(err, errors.ErrTimeout) {
 			s.logger.Error("leader detection timed out", zap.Duration("timeout", timeout), zap.Error(err))
 		} else {
 			s.logger.Error("failed to detect leader", zap.Error(err))
@@ -201,7 +401,10 @@ func (s *RaftServer) startWatchCluster(checkInterval time.Duration) {
 			s.logger.Info("became a leader", zap.String("leaderAddr", string(s.raft.Leader())))
 		case event := <-s.fsm.applyCh:
 			s.applyCh <- event
-		case <-ticker.C:
+		case now := <-ticker.C():
+			s.checkQuorum(now)
+			s.checkWriteStall()
+
 			raftStats := s.raft.Stats()
 
 			switch raftStats["state"] {
@@ -346,22 +549,172 @@ func (s *RaftServer) stopWatchCluster() {
 	s.logger.Info("the cluster update has been stopped")
 }
 
+// startReconcileMembership makes the leader converge the cluster membership to the membership spec.
+// Missing nodes that are reachable are added to the cluster, unreachable and extra nodes are only flagged.
+// Each action is replicated as a reconcile event so that watchers are notified.
+func (s *RaftServer) startReconcileMembership(checkInterval time.Duration) {
+	s.logger.Info("start to reconcile membership", zap.Duration("interval", checkInterval))
+
+	defer func() {
+		close(s.reconcileDoneCh)
+	}()
+
+	ticker := s.clock.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	// the last action reported for each node, to avoid flagging the same node on every tick
+	reported := make(map[string]protobuf.ReconcileAction_Type, 0)
+
+	for {
+		select {
+		case <-s.reconcileStopCh:
+			s.logger.Info("received a request to stop reconciling membership")
+			return
+		case <-ticker.C():
+			if s.raft.State() != raft.Leader {
+				reported = make(map[string]protobuf.ReconcileAction_Type, 0)
+				continue
+			}
+
+			if err := s.reconcileMembership(reported); err != nil {
+				s.logger.Warn("failed to reconcile membership", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *RaftServer) stopReconcileMembership() {
+	if s.reconcileStopCh != nil {
+		s.logger.Info("send a request to stop reconciling membership")
+		close(s.reconcileStopCh)
+	}
+
+	s.logger.Info("wait for the membership reconciliation to stop")
+	<-s.reconcileDoneCh
+	s.logger.Info("the membership reconciliation has been stopped")
+}
+
+func (s *RaftServer) reconcileMembership(reported map[string]protobuf.ReconcileAction_Type) error {
+	spec, err := s.fsm.MembershipSpec()
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		return nil
+	}
+
+	cf := s.raft.GetConfiguration()
+	if err := cf.Error(); err != nil {
+		return err
+	}
+
+	current := make(map[string]raft.ServerAddress, 0)
+	for _, server := range cf.Configuration().Servers {
+		current[string(server.ID)] = server.Address
+	}
+
+	ids := make([]string, 0, len(spec.Nodes))
+	for id := range spec.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		node := spec.Nodes[id]
+		if address, exists := current[id]; exists && address == raft.ServerAddress(node.RaftAddress) {
+			delete(reported, id)
+			continue
+		}
+
+		conn, err := net.DialTimeout("tcp", node.RaftAddress, time.Second)
+		if err != nil {
+			s.logger.Debug("node is unreachable", zap.String("id", id), zap.String("raft_address", node.RaftAddress), zap.Error(err))
+			if reported[id] != protobuf.ReconcileAction_Unreachable {
+				s.logger.Warn("node in the membership spec is unreachable", zap.String("id", id), zap.String("raft_address", node.RaftAddress))
+				if err := s.reconcile(protobuf.ReconcileAction_Unreachable, id, node); err != nil {
+					return err
+				}
+				reported[id] = protobuf.ReconcileAction_Unreachable
+			}
+			continue
+		}
+		_ = conn.Close()
+
+		if err := s.checkPeer(id, node.RaftAddress); err != nil {
+			continue
+		}
+		if future := s.raft.AddVoter(raft.ServerID(id), raft.ServerAddress(node.RaftAddress), 0, 0); future.Error() != nil {
+			s.logger.Error("failed to add voter", zap.String("id", id), zap.String("raft_address", node.RaftAddress), zap.Error(future.Error()))
+			return future.Error()
+		}
+		if err := s.join(id, node.Metadata); err != nil {
+			s.logger.Error("failed to set node metadata", zap.String("id", id), zap.Any("metadata", node.Metadata), zap.Error(err))
+			return err
+		}
+		s.logger.Info("node in the membership spec has joined", zap.String("id", id), zap.String("raft_address", node.RaftAddress))
+		if err := s.reconcile(protobuf.ReconcileAction_Join, id, node); err != nil {
+			return err
+		}
+		delete(reported, id)
+	}
+
+	extras := make([]string, 0)
+	for id := range current {
+		if _, desired := spec.Nodes[id]; !desired {
+			extras = append(extras, id)
+		}
+	}
+	sort.Strings(extras)
+
+	for _, id := range extras {
+		if reported[id] == protobuf.ReconcileAction_Extra {
+			continue
+		}
+		s.logger.Warn("node is not in the membership spec", zap.String("id", id), zap.String("raft_address", string(current[id])))
+		if err := s.reconcile(protobuf.ReconcileAction_Extra, id, &protobuf.Node{RaftAddress: string(current[id])}); err != nil {
+			return err
+		}
+		reported[id] = protobuf.ReconcileAction_Extra
+	}
+
+	// forget nodes that are neither in the spec nor in the cluster anymore
+	for id, action := range reported {
+		_, desired := spec.Nodes[id]
+		_, exists := current[id]
+		if (action == protobuf.ReconcileAction_Extra && !exists) || (action == protobuf.ReconcileAction_Unreachable && !desired) {
+			delete(reported, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *RaftServer) reconcile(actionType protobuf.ReconcileAction_Type, id string, node *protobuf.Node) error {
+	action := &protobuf.ReconcileAction{
+		Type: actionType,
+		Id:   id,
+		Node: node,
+	}
+
+	return s.propose(context.Background(), protobuf.Event_Reconcile, action)
+}
+
 func (s *RaftServer) LeaderAddress(timeout time.Duration) (raft.ServerAddress, error) {
-	ticker := time.NewTicker(100 * time.Millisecond)
+	ticker := s.clock.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
-	timer := time.NewTimer(timeout)
+	timer := s.clock.NewTimer(timeout)
 	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
 			leaderAddr := s.raft.Leader()
 			if leaderAddr != "" {
 				s.logger.Debug("detected a leader address", zap.String("raft_address", string(leaderAddr)))
 				return leaderAddr, nil
 			}
-		case <-timer.C:
-			err := errors.ErrTimeout
+		case <-timer.C():
+			err := errors.Wrapf(errors.ErrTimeout, "no leader detected within %v", timeout)
 			s.logger.Error("failed to detect leader address", zap.Error(err))
 			return "", err
 		}
@@ -388,7 +741,7 @@ func (s *RaftServer) LeaderID(timeout time.Duration) (raft.ServerID, error) {
 		}
 	}
 
-	err = errors.ErrNotFoundLeader
+	err = errors.Wrapf(errors.ErrNotFoundLeader, "leader %s is not in the configuration", leaderAddr)
 	s.logger.Error("failed to detect leader ID", zap.Error(err))
 	return "", err
 }
@@ -437,26 +790,7 @@ func (s *RaftServer) join(id string, metadata *protobuf.Metadata) error {
 		Metadata: metadata,
 	}
 
-	dataAny := &any.Any{}
-	err := marshaler.UnmarshalAny(data, dataAny)
-	if err != nil {
-		s.logger.Error("failed to unmarshal request to the command data", zap.String("id", id), zap.Any("metadata", metadata), zap.Error(err))
-		return err
-	}
-
-	c := &protobuf.Event{
-		Type: protobuf.Event_Join,
-		Data: dataAny,
-	}
-
-	msg, err := proto.Marshal(c)
-	if err != nil {
-		s.logger.Error("failed to marshal the command into the bytes as message", zap.String("id", id), zap.Any("metadata", metadata), zap.Error(err))
-		return err
-	}
-
-	f := s.raft.Apply(msg, 10*time.Second)
-	if err = f.Error(); err != nil {
+	if err := s.propose(context.Background(), protobuf.Event_Join, data); err != nil {
 		s.logger.Error("failed to apply message", zap.String("id", id), zap.Any("metadata", metadata), zap.Error(err))
 		return err
 	}
@@ -464,15 +798,37 @@ func (s *RaftServer) join(id string, metadata *protobuf.Metadata) error {
 	return nil
 }
 
-func (s *RaftServer) Join(id string, node *protobuf.Node) error {
+// Join adds the node to the cluster as a voter. If catchUpAsNonvoter is true the node
+// joins as a non-voter instead, and a node that is already a voter is demoted, so that
+// it does not count towards the quorum until it has caught up with the leader.
+func (s *RaftServer) Join(id string, node *protobuf.Node, catchUpAsNonvoter bool) error {
+	if err := s.checkPeer(id, node.RaftAddress); err != nil {
+		return err
+	}
+
 	nodeExists, err := s.Exist(id)
 	if err != nil {
 		return err
 	}
 
-	if nodeExists {
+	switch {
+	case nodeExists && catchUpAsNonvoter && id != s.id:
+		if future := s.raft.DemoteVoter(raft.ServerID(id), 0, 0); future.Error() != nil {
+			s.logger.Error("failed to demote voter", zap.String("id", id), zap.Error(future.Error()))
+			return future.Error()
+		}
+		s.setCatchUpIndex(id)
+		s.logger.Info("node rejoins as a non-voter until it catches up", zap.String("id", id), zap.String("raft_address", node.RaftAddress))
+	case nodeExists:
 		s.logger.Debug("node already exists", zap.String("id", id), zap.String("raft_address", node.RaftAddress))
-	} else {
+	case catchUpAsNonvoter:
+		if future := s.raft.AddNonvoter(raft.ServerID(id), raft.ServerAddress(node.RaftAddress), 0, 0); future.Error() != nil {
+			s.logger.Error("failed to add non-voter", zap.String("id", id), zap.String("raft_address", node.RaftAddress), zap.Error(future.Error()))
+			return future.Error()
+		}
+		s.setCatchUpIndex(id)
+		s.logger.Info("node has successfully joined as a non-voter", zap.String("id", id), zap.String("raft_address", node.RaftAddress))
+	default:
 		if future := s.raft.AddVoter(raft.ServerID(id), raft.ServerAddress(node.RaftAddress), 0, 0); future.Error() != nil {
 			s.logger.Error("failed to add voter", zap.String("id", id), zap.String("raft_address", node.RaftAddress), zap.Error(future.Error()))
 			return future.Error()
@@ -498,26 +854,7 @@ func (s *RaftServer) leave(id string) error {
 		Id: id,
 	}
 
-	dataAny := &any.Any{}
-	err := marshaler.UnmarshalAny(data, dataAny)
-	if err != nil {
-		s.logger.Error("failed to unmarshal request to the command data", zap.String("id", id), zap.Error(err))
-		return err
-	}
-
-	c := &protobuf.Event{
-		Type: protobuf.Event_Leave,
-		Data: dataAny,
-	}
-
-	msg, err := proto.Marshal(c)
-	if err != nil {
-		s.logger.Error("failed to marshal the command into the bytes as the message", zap.String("id", id), zap.Error(err))
-		return err
-	}
-
-	f := s.raft.Apply(msg, 10*time.Second)
-	if err = f.Error(); err != nil {
+	if err := s.propose(context.Background(), protobuf.Event_Leave, data); err != nil {
 		s.logger.Error("failed to apply the message", zap.String("id", id), zap.Error(err))
 		return err
 	}
@@ -531,11 +868,18 @@ func (s *RaftServer) Leave(id string) error {
 		return err
 	}
 
+	var configIndex uint64
 	if nodeExists {
-		if future := s.raft.RemoveServer(raft.ServerID(id), 0, 0); future.Error() != nil {
+		if err := s.setDecommissionStatus(id, protobuf.DecommissionStatus_Leaving, 0); err != nil {
+			return err
+		}
+
+		future := s.raft.RemoveServer(raft.ServerID(id), 0, 0)
+		if future.Error() != nil {
 			s.logger.Error("failed to remove server", zap.String("id", id), zap.Error(future.Error()))
 			return future.Error()
 		}
+		configIndex = future.Index()
 		s.logger.Info("node has successfully left", zap.String("id", id))
 	} else {
 		s.logger.Debug("node does not exists", zap.String("id", id))
@@ -546,9 +890,249 @@ func (s *RaftServer) Leave(id string) error {
 		return err
 	}
 
+	if nodeExists {
+		if err := s.setDecommissionStatus(id, protobuf.DecommissionStatus_ConfigChangeCommitted, configIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *RaftServer) setDecommissionStatus(id string, phase protobuf.DecommissionStatus_Phase, configIndex uint64) error {
+	status := &protobuf.DecommissionStatus{
+		Id:          id,
+		Phase:       phase,
+		ConfigIndex: configIndex,
+	}
+
+	if err := s.propose(context.Background(), protobuf.Event_Decommission, status); err != nil {
+		s.logger.Error("failed to record the decommission status", zap.String("id", id), zap.String("phase", phase.String()), zap.Error(err))
+		return err
+	}
+	s.logger.Info("decommission status changed", zap.String("id", id), zap.String("phase", phase.String()))
+
+	return nil
+}
+
+func (s *RaftServer) DecommissionStatus(id string) (*protobuf.DecommissionStatus, error) {
+	status, err := s.fsm.DecommissionStatus(id)
+	if errors.Is(err, errors.ErrNotFound) {
+		return nil, errors.Wrapf(errors.ErrNotFound, "decommission status of %s", id)
+	}
+
+	return status, err
+}
+
+// promoteNonvoters makes the non-voters that caught up with this node voters.
+// appliedIndexes holds the applied index of the nodes that could be asked.
+func (s *RaftServer) promoteNonvoters(appliedIndexes map[string]uint64) error {
+	cf := s.raft.GetConfiguration()
+	if err := cf.Error(); err != nil {
+		s.logger.Error("failed to get Raft configuration", zap.Error(err))
+		return err
+	}
+
+	appliedIndex := s.fsm.AppliedIndex()
+	for _, server := range cf.Configuration().Servers {
+		if server.Suffrage != raft.Nonvoter {
+			continue
+		}
+
+		// the non-voter may have been added by a previous leader
+		s.catchUpMutex.Lock()
+		target, ok := s.catchUpIndexes[string(server.ID)]
+		if !ok {
+			target = appliedIndex
+			s.catchUpIndexes[string(server.ID)] = target
+		}
+		s.catchUpMutex.Unlock()
+
+		index, ok := appliedIndexes[string(server.ID)]
+		if !ok || index < target || index+catchUpMaxLag < appliedIndex {
+			continue
+		}
+
+		if future := s.raft.AddVoter(server.ID, server.Address, 0, 0); future.Error() != nil {
+			s.logger.Error("failed to promote non-voter", zap.String("id", string(server.ID)), zap.Error(future.Error()))
+			return future.Error()
+		}
+		s.logger.Info("node caught up and has been promoted to a voter", zap.String("id", string(server.ID)), zap.Uint64("applied_index", index))
+
+		s.catchUpMutex.Lock()
+		delete(s.catchUpIndexes, string(server.ID))
+		s.catchUpMutex.Unlock()
+	}
+
+	return nil
+}
+
+// setCatchUpIndex requires the non-voter to apply everything this node applied before it is promoted.
+func (s *RaftServer) setCatchUpIndex(id string) {
+	s.catchUpMutex.Lock()
+	defer s.catchUpMutex.Unlock()
+
+	s.catchUpIndexes[id] = s.fsm.AppliedIndex()
+}
+
+func (s *RaftServer) Voters() ([]string, error) {
+	return s.servers(raft.Voter)
+}
+
+func (s *RaftServer) Nonvoters() ([]string, error) {
+	return s.servers(raft.Nonvoter)
+}
+
+func (s *RaftServer) servers(suffrage raft.ServerSuffrage) ([]string, error) {
+	cf := s.raft.GetConfiguration()
+	if err := cf.Error(); err != nil {
+		s.logger.Error("failed to get Raft configuration", zap.Error(err))
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+	for _, server := range cf.Configuration().Servers {
+		if server.Suffrage == suffrage {
+			ids = append(ids, string(server.ID))
+		}
+	}
+
+	return ids, nil
+}
+
+// advanceDecommissions moves the decommissioned nodes forward once the remaining
+// voters have caught up with their removal. appliedIndexes holds the applied index
+// of the voters that could be asked.
+func (s *RaftServer) advanceDecommissions(appliedIndexes map[string]uint64) error {
+	statuses, err := s.fsm.DecommissionStatuses()
+	if err != nil {
+		return err
+	}
+
+	cf := s.raft.GetConfiguration()
+	if err := cf.Error(); err != nil {
+		s.logger.Error("failed to get Raft configuration", zap.Error(err))
+		return err
+	}
+	members := make(map[string]struct{}, 0)
+	voters := make(map[string]struct{}, 0)
+	for _, server := range cf.Configuration().Servers {
+		members[string(server.ID)] = struct{}{}
+		if server.Suffrage == raft.Voter {
+			voters[string(server.ID)] = struct{}{}
+		}
+	}
+
+	for _, status := range statuses {
+		_, isMember := members[status.Id]
+
+		switch status.Phase {
+		case protobuf.DecommissionStatus_Leaving:
+			// the removal was committed, but the node that requested it could not record it
+			if !isMember {
+				if err := s.setDecommissionStatus(status.Id, protobuf.DecommissionStatus_ConfigChangeCommitted, cf.Index()); err != nil {
+					return err
+				}
+			}
+		case protobuf.DecommissionStatus_ConfigChangeCommitted, protobuf.DecommissionStatus_DataNotNeeded:
+			caughtUp := 0
+			for id := range voters {
+				if index, ok := appliedIndexes[id]; ok && index >= status.ConfigIndex {
+					caughtUp++
+				}
+			}
+
+			phase := status.Phase
+			switch {
+			case caughtUp == len(voters):
+				phase = protobuf.DecommissionStatus_SafeToWipe
+			case caughtUp > len(voters)/2:
+				phase = protobuf.DecommissionStatus_DataNotNeeded
+			}
+			if phase != status.Phase {
+				if err := s.setDecommissionStatus(status.Id, phase, status.ConfigIndex); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
+// BatchJoin converges the cluster membership to the specified nodes.
+// Nodes missing from the cluster are added as voters, nodes that are not in the
+// specified list are removed. This node is removed last, if requested.
+func (s *RaftServer) BatchJoin(nodes map[string]*protobuf.Node) ([]string, []string, error) {
+	// the nodes missing from the batch are removed, an empty batch would remove them all
+	if len(nodes) == 0 {
+		return nil, nil, errors.ErrEmptyMembership
+	}
+
+	cf := s.raft.GetConfiguration()
+	if err := cf.Error(); err != nil {
+		s.logger.Error("failed to get Raft configuration", zap.Error(err))
+		return nil, nil, err
+	}
+
+	current := make(map[string]raft.ServerAddress, 0)
+	for _, server := range cf.Configuration().Servers {
+		current[string(server.ID)] = server.Address
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := s.checkPeer(id, nodes[id].RaftAddress); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	joined := make([]string, 0)
+	for _, id := range ids {
+		node := nodes[id]
+		if address, exists := current[id]; !exists || address != raft.ServerAddress(node.RaftAddress) {
+			if future := s.raft.AddVoter(raft.ServerID(id), raft.ServerAddress(node.RaftAddress), 0, 0); future.Error() != nil {
+				s.logger.Error("failed to add voter", zap.String("id", id), zap.String("raft_address", node.RaftAddress), zap.Error(future.Error()))
+				return joined, nil, future.Error()
+			}
+			s.logger.Info("node has successfully joined", zap.String("id", id), zap.String("raft_address", node.RaftAddress))
+			joined = append(joined, id)
+		}
+
+		if err := s.join(id, node.Metadata); err != nil {
+			s.logger.Error("failed to set node metadata", zap.String("id", id), zap.Any("metadata", node.Metadata), zap.Error(err))
+			return joined, nil, err
+		}
+	}
+
+	extras := make([]string, 0)
+	for id := range current {
+		if _, desired := nodes[id]; !desired && id != s.id {
+			extras = append(extras, id)
+		}
+	}
+	sort.Strings(extras)
+	if _, desired := nodes[s.id]; !desired {
+		extras = append(extras, s.id)
+	}
+
+	left := make([]string, 0)
+	for _, id := range extras {
+		if err := s.Leave(id); err != nil {
+			s.logger.Error("failed to remove node", zap.String("id", id), zap.Error(err))
+			return joined, left, err
+		}
+		left = append(left, id)
+	}
+
+	return joined, left, nil
+}
+
 func (s *RaftServer) Node() (*protobuf.Node, error) {
 	nodes, err := s.Nodes()
 	if err != nil {
@@ -557,10 +1141,11 @@ func (s *RaftServer) Node() (*protobuf.Node, error) {
 
 	node, ok := nodes[s.id]
 	if !ok {
-		return nil, errors.ErrNotFound
+		return nil, errors.Wrapf(errors.ErrNotFound, "node %s", s.id)
 	}
 
 	node.State = s.StateStr()
+	node.AppliedIndex = s.fsm.AppliedIndex()
 
 	return node, nil
 }
@@ -574,10 +1159,25 @@ func (s *RaftServer) Nodes() (map[string]*protobuf.Node, error) {
 
 	nodes := make(map[string]*protobuf.Node, 0)
 	for _, server := range cf.Configuration().Servers {
-		nodes[string(server.ID)] = &protobuf.Node{
+		annotations, err := s.fsm.Annotations(string(server.ID))
+		if err != nil {
+			return nil, err
+		}
+		node := &protobuf.Node{
 			RaftAddress: string(server.Address),
 			Metadata:    s.fsm.getMetadata(string(server.ID)),
+			Annotations: annotations,
+		}
+
+		status, err := s.fsm.NodeStatus(string(server.ID))
+		if err != nil {
+			return nil, err
 		}
+		if status != nil {
+			node.State = status.State
+			node.Ready = status.Ready
+		}
+		nodes[string(server.ID)] = node
 	}
 
 	return nodes, nil
@@ -592,22 +1192,125 @@ func (s *RaftServer) Snapshot() error {
 	return nil
 }
 
+func (s *RaftServer) Hash() (uint64, []byte, error) {
+	return s.fsm.Hash()
+}
+
+// VerifyRead makes sure that reads served by this node see every write acknowledged
+// before the call. It confirms that this node is still the leader, then waits for
+// the FSM to apply everything committed so far.
+func (s *RaftServer) VerifyRead(timeout time.Duration) error {
+	if err := s.raft.VerifyLeader().Error(); err != nil {
+		s.logger.Debug("failed to verify leadership", zap.Error(err))
+		switch err {
+		case raft.ErrNotLeader, raft.ErrLeadershipLost, raft.ErrLeadershipTransferInProgress:
+			return errors.Wrap(errors.ErrNotLeader, err.Error())
+		default:
+			return err
+		}
+	}
+	commitIndex, err := strconv.ParseUint(s.raft.Stats()["commit_index"], 10, 64)
+	if err != nil {
+		return err
+	}
+	if s.raft.AppliedIndex() >= commitIndex {
+		return nil
+	}
+
+	ticker := s.clock.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	timer := s.clock.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			if s.raft.AppliedIndex() >= commitIndex {
+				return nil
+			}
+		case <-timer.C():
+			return errors.Wrapf(errors.ErrTimeout, "index %d not applied within %v", commitIndex, timeout)
+		}
+	}
+}
+
+// replayEvents calls f with the events watched by the prefix in the log entries from
+// the index to the end index. Entries that are no longer in the log are reported with a gap.
+func (s *RaftServer) replayEvents(from uint64, to uint64, prefix string, f func(resp *protobuf.WatchResponse) error) error {
+	var gap *protobuf.WatchGap
+	for index := from; index <= to; index++ {
+		l := &raft.Log{}
+		if err := s.logStore.GetLog(index, l); err == raft.ErrLogNotFound {
+			// compacted into a snapshot
+			if gap == nil {
+				gap = &protobuf.WatchGap{FromIndex: index}
+			}
+			gap.ToIndex = index
+			gap.Dropped++
+			continue
+		} else if err != nil {
+			s.logger.Error("failed to get log", zap.Uint64("index", index), zap.Error(err))
+			return err
+		}
+		if gap != nil {
+			if err := f(&protobuf.WatchResponse{Gap: gap}); err != nil {
+				return err
+			}
+			gap = nil
+		}
+
+		if l.Type != raft.LogCommand {
+			continue
+		}
+		event := &protobuf.Event{}
+		if err := proto.Unmarshal(l.Data, event); err != nil {
+			continue
+		}
+		event = replicatedEvent(event)
+		if !watchesEvent(prefix, event) {
+			continue
+		}
+		s.stampMembershipEpoch(event, index)
+		if err := f(&protobuf.WatchResponse{Event: event, Index: index}); err != nil {
+			return err
+		}
+	}
+	if gap != nil {
+		return f(&protobuf.WatchResponse{Gap: gap})
+	}
+
+	return nil
+}
+
 func (s *RaftServer) Get(req *protobuf.GetRequest) (*protobuf.GetResponse, error) {
-	value, err := s.fsm.Get(req.Key)
+	value, index, err := s.fsm.GetWithIndex(req.Key)
 	if err != nil {
 		s.logger.Error("failed to get", zap.Any("key", req.Key), zap.Error(err))
 		return nil, err
 	}
 
+	// only the range is sent, the whole value is still read from the key value store
+	r, err := valueRange(value, req.Offset, req.Length)
+	if err != nil {
+		return nil, err
+	}
+
 	resp := &protobuf.GetResponse{
-		Value: value,
+		Value:         r,
+		ModifiedIndex: index,
+		Size:          uint64(len(value)),
 	}
 
 	return resp, nil
 }
 
 func (s *RaftServer) Scan(req *protobuf.ScanRequest) (*protobuf.ScanResponse, error) {
-	values, err := s.fsm.Scan(req.Prefix)
+	filter, err := newScanFilter(req)
+	if err != nil {
+		s.logger.Error("failed to parse scan filter", zap.Any("prefix", req.Prefix), zap.Error(err))
+		return nil, err
+	}
+
+	values, err := s.fsm.Scan(req.Prefix, filter)
 	if err != nil {
 		s.logger.Error("failed to scan", zap.Any("prefix", req.Prefix), zap.Error(err))
 		return nil, err
@@ -620,54 +1323,283 @@ func (s *RaftServer) Scan(req *protobuf.ScanRequest) (*protobuf.ScanResponse, er
 	return resp, nil
 }
 
-func (s *RaftServer) Set(req *protobuf.SetRequest) error {
-	kvpAny := &any.Any{}
-	if err := marshaler.UnmarshalAny(req, kvpAny); err != nil {
-		s.logger.Error("failed to unmarshal request to the command data", zap.String("key", req.Key), zap.Error(err))
+// Export writes the items selected by the scan on this node to the destination.
+func (s *RaftServer) Export(ctx context.Context, req *protobuf.ExportRequest) (*protobuf.ExportResponse, error) {
+	start := time.Now()
+
+	if err := s.exportDestinations.check(req.Destination); err != nil {
+		s.logger.Warn("refused to export", zap.Error(err))
+		return nil, err
+	}
+
+	count, bytes, err := export(ctx, s.fsm, req.Scan, req.Destination)
+	if err != nil {
+		s.logger.Error("failed to export", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("exported", zap.Uint64("count", count), zap.Uint64("bytes", bytes), zap.Float64("time", float64(time.Since(start))/float64(time.Second)))
+	return &protobuf.ExportResponse{
+		Count: count,
+		Bytes: bytes,
+	}, nil
+}
+
+func (s *RaftServer) Set(ctx context.Context, req *protobuf.SetRequest) error {
+	if req.Ttl != nil {
+		// the expiration time is fixed by the leader, so that the replicas agree on it
+		ttl, err := ptypes.Duration(req.Ttl)
+		if err != nil {
+			return err
+		}
+		if req.ExpiresAt, err = ptypes.TimestampProto(s.clock.Now().Add(ttl)); err != nil {
+			return err
+		}
+	}
+
+	if err := s.propose(ctx, protobuf.Event_Set, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("key", req.Key), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *RaftServer) Delete(ctx context.Context, req *protobuf.DeleteRequest) error {
+	if err := s.propose(ctx, protobuf.Event_Delete, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("key", req.Key), zap.Error(err))
 		return err
 	}
 
-	c := &protobuf.Event{
-		Type: protobuf.Event_Set,
-		Data: kvpAny,
+	return nil
+}
+
+func (s *RaftServer) Copy(ctx context.Context, req *protobuf.CopyRequest) error {
+	if req.Prefix {
+		if err := s.fsm.checkCopySize(req.Source, req.Destination, false); err != nil {
+			s.logger.Warn("refused to copy the prefix", zap.String("source", req.Source), zap.String("destination", req.Destination), zap.Error(err))
+			return err
+		}
 	}
 
-	msg, err := proto.Marshal(c)
+	if err := s.propose(ctx, protobuf.Event_Copy, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("source", req.Source), zap.String("destination", req.Destination), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *RaftServer) Move(ctx context.Context, req *protobuf.MoveRequest) error {
+	if req.Prefix {
+		if err := s.fsm.checkCopySize(req.Source, req.Destination, true); err != nil {
+			s.logger.Warn("refused to move the prefix", zap.String("source", req.Source), zap.String("destination", req.Destination), zap.Error(err))
+			return err
+		}
+	}
+
+	if err := s.propose(ctx, protobuf.Event_Move, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("source", req.Source), zap.String("destination", req.Destination), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Annotate sets the annotations of a member of the cluster, an empty value removes the annotation.
+func (s *RaftServer) Annotate(req *protobuf.AnnotateRequest) error {
+	nodes, err := s.Nodes()
 	if err != nil {
-		s.logger.Error("failed to marshal the command into the bytes as the message", zap.String("key", req.Key), zap.Error(err))
 		return err
 	}
+	if _, ok := nodes[req.Id]; !ok {
+		return errors.Wrapf(errors.ErrNotFound, "node %s", req.Id)
+	}
 
-	if future := s.raft.Apply(msg, 10*time.Second); future.Error() != nil {
-		s.logger.Error("failed to apply the message", zap.Error(future.Error()))
-		return future.Error()
+	if err := s.propose(context.Background(), protobuf.Event_Annotate, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("id", req.Id), zap.Any("annotations", req.Annotations), zap.Error(err))
+		return err
 	}
 
 	return nil
 }
 
-func (s *RaftServer) Delete(req *protobuf.DeleteRequest) error {
-	kvpAny := &any.Any{}
-	if err := marshaler.UnmarshalAny(req, kvpAny); err != nil {
-		s.logger.Error("failed to unmarshal request to the command data", zap.String("key", req.Key), zap.Error(err))
+// UpdateNodeStatus records the status of a member of the cluster.
+func (s *RaftServer) UpdateNodeStatus(req *protobuf.UpdateNodeStatusRequest) error {
+	nodes, err := s.Nodes()
+	if err != nil {
 		return err
 	}
+	if _, ok := nodes[req.Id]; !ok {
+		return errors.Wrapf(errors.ErrNotFound, "node %s", req.Id)
+	}
 
-	c := &protobuf.Event{
-		Type: protobuf.Event_Delete,
-		Data: kvpAny,
+	if err := s.propose(context.Background(), protobuf.Event_UpdateNodeStatus, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("id", req.Id), zap.Any("status", req.Status), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *RaftServer) MembershipSpec() (*protobuf.MembershipSpec, error) {
+	return s.fsm.MembershipSpec()
+}
+
+func (s *RaftServer) SetMembershipSpec(spec *protobuf.MembershipSpec) error {
+	if len(spec.GetNodes()) == 0 {
+		return errors.ErrEmptyMembership
+	}
+
+	req := &protobuf.SetMembershipSpecRequest{
+		Spec: spec,
+	}
+
+	if err := s.propose(context.Background(), protobuf.Event_SetMembershipSpec, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.Any("spec", spec), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *RaftServer) DeleteMembershipSpec() error {
+	if err := s.propose(context.Background(), protobuf.Event_DeleteMembershipSpec, &empty.Empty{}); err != nil {
+		s.logger.Error("failed to apply the message", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// propose replicates the event through Raft.
+// It returns an error if the event could not be committed or applying it to the FSM failed.
+func (s *RaftServer) propose(ctx context.Context, eventType protobuf.Event_Type, data proto.Message) error {
+	// the FSM checks the role again, the writes refused here are not written to the log
+	if err := s.fsm.checkRole(eventType); err != nil {
+		return err
+	}
+
+	c, err := marshaler.NewEvent(eventType, data)
+	if err != nil {
+		s.logger.Error("failed to create the event", zap.String("type", eventType.String()), zap.Error(err))
+		return err
+	}
+	c.Metadata = s.requestMetadata(ctx)
+	if c.Time, err = ptypes.TimestampProto(s.clock.Now()); err != nil {
+		return err
 	}
 
 	msg, err := proto.Marshal(c)
 	if err != nil {
-		s.logger.Error("failed to marshal the command into the bytes as the message", zap.String("key", req.Key), zap.Error(err))
+		s.logger.Error("failed to marshal the command into the bytes as the message", zap.String("type", eventType.String()), zap.Error(err))
 		return err
 	}
 
-	if future := s.raft.Apply(msg, 10*time.Second); future.Error() != nil {
-		s.logger.Error("failed to unmarshal request to the command data", zap.String("key", req.Key), zap.Error(future.Error()))
-		return future.Error()
+	budget := newWriteBudget(ctx, s.clock)
+
+	if s.writeFencing {
+		if err := s.fenceWrite(); err != nil {
+			s.logger.Warn("failed to apply the message", zap.String("type", eventType.String()), zap.Error(err))
+			return err
+		}
+	}
+
+	// the priority writes neither wait for the compactions nor for the bulk writes
+	priority := s.priorityPrefixes.event(c)
+	start := time.Now()
+	if s.bulkSlots != nil && !priority {
+		release, err := s.acquireBulkSlot(budget.queueingTimeout())
+		if err != nil {
+			s.recordWriteStage(budget, stageQueueing, time.Since(start))
+			s.logger.Warn("failed to apply the message", zap.String("type", eventType.String()), zap.Error(err))
+			return err
+		}
+		defer release()
+	}
+	if s.writeBackpressure && !priority && s.WritesStalled() {
+		stalled := s.waitForCompactions(budget.queueingTimeout())
+		s.recordWriteStage(budget, stageBackpressure, time.Since(start))
+		if stalled {
+			err := budget.exceeded(stageBackpressure)
+			s.logger.Warn("failed to apply the message, the writes are stalled", zap.String("type", eventType.String()), zap.Error(err))
+			return err
+		}
+		start = time.Now()
+	}
+	f := s.raft.Apply(msg, budget.queueingTimeout())
+	s.recordWriteStage(budget, stageQueueing, time.Since(start))
+
+	// the future can not be abandoned, so wait for it in the background
+	start = time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- f.Error()
+	}()
+
+	timer := s.clock.NewTimer(budget.remaining())
+	defer timer.Stop()
+
+	select {
+	case err = <-errCh:
+	case <-timer.C():
+		s.recordWriteStage(budget, stageRaftApply, time.Since(start))
+		err := budget.exceeded(stageRaftApply)
+		s.logger.Error("failed to apply the message", zap.String("type", eventType.String()), zap.Error(err))
+		return err
+	}
+	if err != nil {
+		s.logger.Error("failed to apply the message", zap.String("type", eventType.String()), zap.Error(err))
+		switch err {
+		case raft.ErrNotLeader, raft.ErrLeadershipTransferInProgress:
+			// the entry was refused before it was appended to the log, it can be retried
+			return errors.Wrap(errors.ErrNotLeader, err.Error())
+		case raft.ErrLeadershipLost:
+			// the entry may still be committed by the new leader, retrying it could apply it twice
+			return errors.Wrap(errors.ErrOutcomeUnknown, err.Error())
+		case raft.ErrEnqueueTimeout:
+			return budget.exceeded(stageQueueing)
+		default:
+			return err
+		}
+	}
+
+	resp := f.Response().(*applyResponse)
+	s.recordWriteStage(budget, stageRaftApply, time.Since(start)-resp.elapsed)
+	s.recordWriteStage(budget, stageFSMCommit, resp.elapsed)
+	if resp.err != nil {
+		s.logger.Error("failed to apply the message to the FSM", zap.String("type", eventType.String()), zap.Error(resp.err))
+		return resp.err
+	}
+
+	if s.writeFencing {
+		s.noteDeposed(eventType)
 	}
 
 	return nil
 }
+
+// requestMetadata returns the propagated metadata of the request. Only the first value of each key is kept.
+func (s *RaftServer) requestMetadata(ctx context.Context) map[string]string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var propagated map[string]string
+	for _, key := range s.propagatedMetadata {
+		if values := md.Get(key); len(values) > 0 {
+			if propagated == nil {
+				propagated = make(map[string]string, len(s.propagatedMetadata))
+			}
+			propagated[strings.ToLower(key)] = values[0]
+		}
+	}
+
+	return propagated
+}
+
+func (s *RaftServer) recordWriteStage(budget *writeBudget, stage string, elapsed time.Duration) {
+	budget.record(stage, elapsed)
+	metric.RaftWriteStageDurationMetric.WithLabelValues(s.id, stage).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestMetadata(t *testing.T) {
+	s := &RaftServer{propagatedMetadata: []string{"x-client-id", "X-Origin-Service"}}
+
+	if md := s.requestMetadata(context.Background()); md != nil {
+		t.Errorf("expected no metadata, saw %v", md)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-client-id", "loader",
+		"x-client-id", "ignored",
+		"x-origin-service", "etl",
+		"authorization", "secret",
+	))
+	expected := map[string]string{"x-client-id": "loader", "x-origin-service": "etl"}
+	if md := s.requestMetadata(ctx); !reflect.DeepEqual(md, expected) {
+		t.Errorf("expected %v, saw %v", expected, md)
+	}
+}
+
+func TestGetRange(t *testing.T) {
+	s := &RaftServer{fsm: newTestRaftFSM(t), logger: zap.NewNop()}
+	if err := applyTestEvent(t, s.fsm, 1, protobuf.Event_Set, &protobuf.SetRequest{Key: "/blob", Value: []byte("0123456789")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for _, r := range []struct {
+		offset   uint64
+		length   uint64
+		expected string
+	}{
+		{0, 0, "0123456789"},
+		{3, 4, "3456"},
+		{8, 10, "89"},
+		{10, 0, ""},
+	} {
+		resp, err := s.Get(&protobuf.GetRequest{Key: "/blob", Offset: r.offset, Length: r.length})
+		if err != nil || string(resp.Value) != r.expected || resp.Size != 10 || resp.ModifiedIndex != 1 {
+			t.Errorf("expected %q of 10 bytes at offset %d, length %d, saw %v, %v", r.expected, r.offset, r.length, resp, err)
+		}
+	}
+
+	if _, err := s.Get(&protobuf.GetRequest{Key: "/blob", Offset: 11}); !errors.Is(err, errors.ErrOutOfRange) {
+		t.Errorf("expected an offset past the end to be out of range, saw %v", err)
+	}
+}
+
+func TestScanSystemKeys(t *testing.T) {
+	s := &GRPCService{
+		raftServer: &RaftServer{fsm: newTestRaftFSM(t), logger: zap.NewNop()},
+		logger:     zap.NewNop(),
+	}
+	if err := applyTestEvent(t, s.raftServer.fsm, 1, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("a")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := s.raftServer.fsm.kvs.Set(apiKeyKeyPrefix+"key", []byte("secret")); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for _, prefix := range []string{systemKeyPrefix, apiKeyKeyPrefix} {
+		if _, err := s.Scan(context.Background(), &protobuf.ScanRequest{Prefix: prefix}); !errors.Is(err, errors.ErrReservedKey) {
+			t.Errorf("expected the scan of %q to be refused, saw %v", prefix, err)
+		}
+		if err := s.Watch(&protobuf.WatchRequest{Prefix: prefix}, nil); !errors.Is(err, errors.ErrReservedKey) {
+			t.Errorf("expected the watch of %q to be refused, saw %v", prefix, err)
+		}
+	}
+
+	// the prefixes of the system prefix are accepted, without the system keys
+	for _, prefix := range []string{"", "\x00", "\x00cete"} {
+		values, err := s.raftServer.fsm.Scan(prefix, nil)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		for _, value := range values {
+			if string(value) == "secret" {
+				t.Errorf("expected the scan of %q to leave out the system keys", prefix)
+			}
+		}
+	}
+	if values, err := s.raftServer.fsm.Scan(systemKeyPrefix, nil); err != nil || len(values) != 0 {
+		t.Errorf("expected no value with the system prefix, saw %q, %v", values, err)
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(100 * 1024)
+
+	// one second worth of bytes is available right away
+	start := time.Now()
+	limiter.wait(100 * 1024)
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected the burst not to wait, waited %v", elapsed)
+	}
+
+	start = time.Now()
+	limiter.wait(20 * 1024)
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected to wait about 200ms, waited %v", elapsed)
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	limiter := newRateLimiter(0)
+	if limiter != nil {
+		t.Fatalf("expected no limiter")
+	}
+
+	// a nil limiter never waits
+	limiter.wait(1 << 30)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// startReplication makes the leader of a standby watch its source cluster and replicate
+// the writes, resuming after the replicated index. The replication is asynchronous, the
+// source cluster acknowledges the writes before the standby applies them.
+func (s *GRPCService) startReplication(checkInterval time.Duration) {
+	s.logger.Info("start to replicate the source cluster of a standby", zap.Duration("interval", checkInterval))
+
+	defer func() {
+		close(s.replicationDoneCh)
+	}()
+
+	ticker := s.raftServer.clock.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.replicationStopCh:
+			s.logger.Info("received a request to stop replicating the source cluster")
+			return
+		case <-ticker.C():
+			if s.raftServer.State() != raft.Leader {
+				continue
+			}
+			role, err := s.raftServer.fsm.Role()
+			if err != nil || role.Mode != protobuf.Role_Standby {
+				continue
+			}
+
+			if err := s.replicate(role, checkInterval); err != nil {
+				s.logger.Warn("failed to replicate the source cluster", zap.String("source", role.Source), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *GRPCService) stopReplication() {
+	close(s.replicationStopCh)
+	<-s.replicationDoneCh
+}
+
+// replicate replicates the writes of the source cluster until the node is no longer the
+// leader, or the role of the cluster changes.
+func (s *GRPCService) replicate(role *protobuf.Role, checkInterval time.Duration) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		ticker := s.raftServer.clock.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.replicationStopCh:
+				cancel()
+				return
+			case <-ticker.C():
+				current, err := s.raftServer.fsm.Role()
+				if s.raftServer.State() != raft.Leader || err != nil || current.Index != role.Index {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	from, err := s.raftServer.fsm.ReplicatedIndex()
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewGRPCClientWithOptions(role.Source, ctx, client.WithTLS(s.certificateFile, s.commonName), client.WithAuthToken(s.authToken))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	stream, err := c.Watch(&protobuf.WatchRequest{ResumeToken: strconv.FormatUint(from, 10)})
+	if err != nil {
+		return err
+	}
+	s.logger.Info("replicating the source cluster", zap.String("source", role.Source), zap.Uint64("replicated_index", from))
+
+	for {
+		resp, err := stream.Recv()
+		if ctx.Err() != nil {
+			s.logger.Info("stopped replicating the source cluster", zap.String("source", role.Source), zap.Uint64("replicated_index", from))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case resp.Gap != nil:
+			// the events dropped from the buffer of the watch are replayed from the log of
+			// the source by the next watch, those compacted into a snapshot are lost
+			return errors.New(codes.DataLoss, fmt.Sprintf("events %d to %d of the source cluster were dropped, they are replicated again unless they were compacted into a snapshot", resp.Gap.FromIndex, resp.Gap.ToIndex))
+		case resp.Event == nil || resp.Index <= from:
+			continue
+		}
+
+		req := &protobuf.ReplicateRequest{Index: resp.Index}
+		switch {
+		case replicatedEventTypes[resp.Event.Type]:
+			req.Event = resp.Event
+		case resp.Event.Type == protobuf.Event_SetRole:
+			// only moves the replicated index, so that a failover sees that the standby
+			// caught up with the freeze of the source
+		default:
+			continue
+		}
+
+		if err := s.raftServer.propose(ctx, protobuf.Event_Replicate, req); err != nil {
+			if errors.IsRetryable(err) || ctx.Err() != nil {
+				return err
+			}
+			// the data of the standby diverged from the source, e.g. it was written while
+			// the cluster was a primary. The event is skipped
+			s.logger.Warn("failed to apply a replicated event", zap.Uint64("index", resp.Index), zap.String("type", resp.Event.Type.String()), zap.Error(err))
+		}
+		from = resp.Index
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mosuka/cete/storage"
+	"go.uber.org/zap"
+)
+
+// ResetDataDirectory removes the key value store, the Raft logs and the snapshots
+// of a node, so that it can join a cluster again as a new node. The options locate
+// the stores the same way as for the Raft server.
+// It fails if the data directory is in use by a running node.
+func ResetDataDirectory(dataDirectory string, logger *zap.Logger, opts ...RaftServerOption) error {
+	o := defaultRaftOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	fsmPath, raftDirectory, snapshotDirectory := o.directories(dataDirectory)
+	if _, err := os.Stat(fsmPath); err == nil {
+		// the key value store can not be opened while the node is running
+		kvs, err := storage.NewKVS(fsmPath, fsmPath, logger)
+		if err != nil {
+			return fmt.Errorf("data directory %s is in use: %w", dataDirectory, err)
+		}
+		if err := kvs.Close(); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range []string{
+		filepath.Join(dataDirectory, identityFile),
+		fsmPath,
+		raftDirectory,
+		filepath.Join(snapshotDirectory, "snapshots"),
+	} {
+		if err := os.RemoveAll(path); err != nil {
+			logger.Error("failed to remove data", zap.String("path", path), zap.Error(err))
+			return err
+		}
+		logger.Info("data removed", zap.String("path", path))
+	}
+
+	return nil
+}
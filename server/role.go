@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/marshaler"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// replicatedEventTypes are the events a standby replicates from its source, and refuses
+// from its clients.
+var replicatedEventTypes = map[protobuf.Event_Type]bool{
+	protobuf.Event_Set:    true,
+	protobuf.Event_Delete: true,
+	protobuf.Event_Copy:   true,
+	protobuf.Event_Move:   true,
+	protobuf.Event_Expire: true,
+	protobuf.Event_Purge:  true,
+}
+
+// replicatedEvent returns the event of the source cluster replicated by the event, or the
+// event itself.
+func replicatedEvent(event *protobuf.Event) *protobuf.Event {
+	if event.Type != protobuf.Event_Replicate {
+		return event
+	}
+	data, err := marshaler.EventData(event)
+	if err != nil {
+		return event
+	}
+	if req := data.(*protobuf.ReplicateRequest); req.Event != nil {
+		return req.Event
+	}
+
+	return event
+}
+
+// Role returns the role of the cluster.
+func (f *RaftFSM) Role() (*protobuf.Role, error) {
+	value, err := f.kvs.Get(roleKey)
+	if errors.Is(err, errors.ErrNotFound) {
+		return &protobuf.Role{Mode: protobuf.Role_Primary}, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get role", zap.Error(err))
+		return nil, err
+	}
+
+	role := &protobuf.Role{}
+	if err := proto.Unmarshal(value, role); err != nil {
+		f.logger.Error("failed to unmarshal role", zap.Error(err))
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// ReplicatedIndex returns the index in the log of the source cluster of the last event
+// replicated by a standby.
+func (f *RaftFSM) ReplicatedIndex() (uint64, error) {
+	value, err := f.kvs.Get(replicatedIndexKey)
+	if errors.Is(err, errors.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		f.logger.Error("failed to get replicated index", zap.Error(err))
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, errors.Wrapf(errors.ErrUnexpectedPayloadType, "replicated index of %d bytes", len(value))
+	}
+
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// checkRole refuses the writes of the clients unless the cluster is a primary, and the
+// replicated events unless it is a standby.
+func (f *RaftFSM) checkRole(eventType protobuf.Event_Type) error {
+	if !replicatedEventTypes[eventType] && eventType != protobuf.Event_Replicate {
+		return nil
+	}
+
+	role, err := f.Role()
+	if err != nil {
+		return err
+	}
+	switch {
+	case eventType == protobuf.Event_Replicate && role.Mode != protobuf.Role_Standby:
+		return errors.Wrap(errors.ErrConflict, "the cluster is no longer a standby")
+	case eventType != protobuf.Event_Replicate && role.Mode == protobuf.Role_Standby:
+		return errors.Wrapf(errors.ErrReadOnly, "the cluster is a standby of %s", role.Source)
+	case eventType != protobuf.Event_Replicate && role.Mode == protobuf.Role_Frozen:
+		return errors.Wrap(errors.ErrReadOnly, "the cluster is frozen")
+	}
+
+	return nil
+}
+
+func encodeReplicatedIndex(index uint64) []byte {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, index)
+
+	return value
+}
+
+// applySetRole sets the role of the cluster. A cluster becoming a standby replicates the
+// events of its source after the replicated index, a standby keeps its replicated index
+// unless another one is given.
+func (f *RaftFSM) applySetRole(req *protobuf.SetRoleRequest, index uint64, time *timestamp.Timestamp) interface{} {
+	previous, err := f.Role()
+	if err != nil {
+		return err
+	}
+
+	role := &protobuf.Role{
+		Mode:   req.Mode,
+		Source: req.Source,
+		Reason: req.Reason,
+		Index:  index,
+		Time:   time,
+	}
+	value, err := proto.Marshal(role)
+	if err != nil {
+		f.logger.Error("failed to marshal role", zap.Error(err))
+		return err
+	}
+
+	sets := map[string][]byte{roleKey: value}
+	if req.Mode == protobuf.Role_Standby && (req.ReplicatedIndex > 0 || previous.Mode != protobuf.Role_Standby) {
+		sets[replicatedIndexKey] = encodeReplicatedIndex(req.ReplicatedIndex)
+	}
+	if err := f.kvs.Batch(sets, nil); err != nil {
+		f.logger.Error("failed to set role", zap.String("mode", req.Mode.String()), zap.Error(err))
+		return err
+	}
+
+	f.logger.Info("role set", zap.String("mode", req.Mode.String()), zap.String("source", req.Source), zap.String("reason", req.Reason), zap.Uint64("index", index))
+
+	return nil
+}
+
+// applyReplicate applies an event of the source cluster. The keys are written with the
+// index of the event in the log of the source cluster, so that the expected indexes and
+// the purges of the source hold on the standby. The replicated index moves on even if the
+// event fails, as it failed on every replica.
+func (f *RaftFSM) applyReplicate(req *protobuf.ReplicateRequest) interface{} {
+	var ret interface{}
+	if req.Event != nil {
+		if !replicatedEventTypes[req.Event.Type] {
+			return errors.Wrapf(errors.ErrUnexpectedPayloadType, "%s events are not replicated", req.Event.Type.String())
+		}
+		data, err := marshaler.EventData(req.Event)
+		if err != nil {
+			f.logger.Error("failed to decode the replicated event data", zap.String("type", req.Event.Type.String()), zap.Error(err))
+			return err
+		}
+		ret = f.applyEvent(req.Event, data, req.Index)
+	}
+
+	if err := f.kvs.Set(replicatedIndexKey, encodeReplicatedIndex(req.Index)); err != nil {
+		f.logger.Error("failed to set replicated index", zap.Uint64("index", req.Index), zap.Error(err))
+		return err
+	}
+	if f.replicatedIndex != nil {
+		f.replicatedIndex.Set(float64(req.Index))
+	}
+
+	return ret
+}
+
+func (s *RaftServer) Role() (*protobuf.RoleResponse, error) {
+	role, err := s.fsm.Role()
+	if err != nil {
+		return nil, err
+	}
+	replicatedIndex, err := s.fsm.ReplicatedIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.RoleResponse{Role: role, ReplicatedIndex: replicatedIndex}, nil
+}
+
+// SetRole sets the role of the cluster. The change is notified to the watchers.
+func (s *RaftServer) SetRole(ctx context.Context, req *protobuf.SetRoleRequest) error {
+	if err := s.propose(ctx, protobuf.Event_SetRole, req); err != nil {
+		s.logger.Error("failed to apply the message", zap.String("mode", req.Mode.String()), zap.String("source", req.Source), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"path"
+	"regexp"
+
+	"github.com/mosuka/cete/protobuf"
+)
+
+// scanFilter selects the items of a scan on the server, so that clients do not have
+// to download a whole prefix to pick a few items out of it.
+type scanFilter struct {
+	keyRegexp     *regexp.Regexp
+	keyGlob       string
+	minValueSize  uint64
+	maxValueSize  uint64
+	modifiedSince uint64
+}
+
+// newScanFilter returns nil if the request has no filters.
+func newScanFilter(req *protobuf.ScanRequest) (*scanFilter, error) {
+	if req.KeyRegexp == "" && req.KeyGlob == "" && req.MinValueSize == 0 && req.MaxValueSize == 0 && req.ModifiedSince == 0 {
+		return nil, nil
+	}
+
+	filter := &scanFilter{
+		keyGlob:       req.KeyGlob,
+		minValueSize:  req.MinValueSize,
+		maxValueSize:  req.MaxValueSize,
+		modifiedSince: req.ModifiedSince,
+	}
+	if req.KeyRegexp != "" {
+		var err error
+		if filter.keyRegexp, err = regexp.Compile(req.KeyRegexp); err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+func (f *scanFilter) matchKey(key string) bool {
+	if f.keyRegexp != nil && !f.keyRegexp.MatchString(key) {
+		return false
+	}
+	if f.keyGlob != "" {
+		if ok, err := path.Match(f.keyGlob, key); err != nil || !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *scanFilter) matchValue(size int) bool {
+	if uint64(size) < f.minValueSize {
+		return false
+	}
+	if f.maxValueSize > 0 && uint64(size) > f.maxValueSize {
+		return false
+	}
+
+	return true
+}
+
+// matchModifiedIndex reports whether an item last modified at the index is selected.
+// Items written before the modified index was recorded have the index 0.
+func (f *scanFilter) matchModifiedIndex(index uint64) bool {
+	return index >= f.modifiedSince
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/mosuka/cete/protobuf"
+)
+
+func TestScanFilter(t *testing.T) {
+	filter, err := newScanFilter(&protobuf.ScanRequest{Prefix: "/users/"})
+	if err != nil || filter != nil {
+		t.Fatalf("expected no filter, saw %v, %v", filter, err)
+	}
+
+	filter, err = newScanFilter(&protobuf.ScanRequest{
+		KeyRegexp:     "[0-9]$",
+		KeyGlob:       "/users/*",
+		MinValueSize:  2,
+		MaxValueSize:  4,
+		ModifiedSince: 10,
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for key, expected := range map[string]bool{
+		"/users/1":       true,
+		"/users/a":       false,
+		"/users/1/posts": false,
+		"/groups/1":      false,
+	} {
+		if filter.matchKey(key) != expected {
+			t.Errorf("expected the key %s to match: %v", key, expected)
+		}
+	}
+	for size, expected := range map[int]bool{1: false, 2: true, 4: true, 5: false} {
+		if filter.matchValue(size) != expected {
+			t.Errorf("expected the value size %d to match: %v", size, expected)
+		}
+	}
+	if filter.matchModifiedIndex(9) || !filter.matchModifiedIndex(10) {
+		t.Errorf("expected only the items modified at or after 10 to match")
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/mosuka/cete/errors"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultSlowRequestThreshold = time.Second
+	slowRequestLogSize          = 100
+)
+
+type slowRequest struct {
+	start    time.Time
+	method   string
+	key      string
+	duration time.Duration
+	code     string
+}
+
+// slowRequestLog keeps the most recent requests that took longer than the threshold,
+// for the diagnostic dumps.
+type slowRequestLog struct {
+	threshold time.Duration
+
+	mutex    sync.Mutex
+	requests []slowRequest
+	next     int
+}
+
+func newSlowRequestLog(threshold time.Duration, size int) *slowRequestLog {
+	return &slowRequestLog{
+		threshold: threshold,
+		requests:  make([]slowRequest, 0, size),
+	}
+}
+
+func (l *slowRequestLog) record(request slowRequest) {
+	if l.threshold <= 0 || request.duration < l.threshold {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.requests) < cap(l.requests) {
+		l.requests = append(l.requests, request)
+		return
+	}
+	l.requests[l.next] = request
+	l.next = (l.next + 1) % len(l.requests)
+}
+
+// list returns the requests from the oldest to the most recent.
+func (l *slowRequestLog) list() []slowRequest {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	requests := make([]slowRequest, 0, len(l.requests))
+	requests = append(requests, l.requests[l.next:]...)
+	requests = append(requests, l.requests[:l.next]...)
+
+	return requests
+}
+
+func slowRequestUnaryServerInterceptor(l *slowRequestLog) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		l.record(slowRequest{
+			start:    start,
+			method:   path.Base(info.FullMethod),
+			key:      requestKey(req),
+			duration: time.Since(start),
+			code:     errors.Code(err).String(),
+		})
+
+		return resp, err
+	}
+}
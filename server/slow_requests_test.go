@@ -0,0 +1,20 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlowRequestLog(t *testing.T) {
+	l := newSlowRequestLog(time.Second, 2)
+
+	l.record(slowRequest{method: "Get", duration: time.Millisecond})
+	for _, method := range []string{"Set", "Delete", "Scan"} {
+		l.record(slowRequest{method: method, duration: 2 * time.Second})
+	}
+
+	requests := l.list()
+	if len(requests) != 2 || requests[0].method != "Delete" || requests[1].method != "Scan" {
+		t.Errorf("expected the 2 most recent slow requests, saw %v", requests)
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"time"
+
+	"github.com/hashicorp/raft"
+	"go.uber.org/zap"
+)
+
+const snapshotTriggerCheckInterval = time.Second
+
+// startSnapshotTrigger takes a snapshot once the log entries applied since the last
+// snapshot reach the size, or once the last snapshot is older than the interval, in
+// addition to the snapshots Raft takes every 1024 log entries. Nodes storing large values
+// thus do not keep enormous logs between snapshots. The schedule is read on every check,
+// so that a change of the cluster configuration applies right away.
+func (s *RaftServer) startSnapshotTrigger() {
+	maxLogBytes, maxInterval := s.snapshotSchedule()
+	s.logger.Info("start to trigger snapshots", zap.Uint64("max_log_bytes", maxLogBytes), zap.Duration("max_interval", maxInterval))
+
+	defer func() {
+		close(s.snapshotTriggerDoneCh)
+	}()
+
+	ticker := s.clock.NewTicker(snapshotTriggerCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.snapshotTriggerStopCh:
+			s.logger.Info("received a request to stop triggering snapshots")
+			return
+		case <-ticker.C():
+			if logBytes, interval := s.snapshotSchedule(); logBytes != maxLogBytes || interval != maxInterval {
+				maxLogBytes, maxInterval = logBytes, interval
+				s.logger.Info("snapshot schedule changed", zap.Uint64("max_log_bytes", maxLogBytes), zap.Duration("max_interval", maxInterval))
+			}
+
+			logBytes, lastSnapshot := s.fsm.LogSinceSnapshot()
+			var reason string
+			switch {
+			case logBytes == 0:
+				continue
+			case maxLogBytes > 0 && logBytes >= maxLogBytes:
+				reason = "log size"
+			case maxInterval > 0 && s.clock.Since(lastSnapshot) >= maxInterval:
+				reason = "interval"
+			default:
+				continue
+			}
+
+			s.logger.Info("trigger a snapshot", zap.String("reason", reason), zap.Uint64("log_bytes", logBytes), zap.Time("last_snapshot", lastSnapshot))
+			if err := s.raft.Snapshot().Error(); err != nil && err != raft.ErrNothingNewToSnapshot {
+				s.logger.Warn("failed to snapshot", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *RaftServer) stopSnapshotTrigger() {
+	if s.snapshotTriggerStopCh != nil {
+		s.logger.Info("send a request to stop triggering snapshots")
+		close(s.snapshotTriggerStopCh)
+	}
+
+	s.logger.Info("wait for the snapshot trigger to stop")
+	<-s.snapshotTriggerDoneCh
+	s.logger.Info("the snapshot trigger has been stopped")
+}
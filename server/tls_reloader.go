@@ -0,0 +1,93 @@
+package server
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// certificateReloader loads the TLS key pair again when its files change, so that
+// rotated certificates are served without a restart.
+type certificateReloader struct {
+	certificateFile string
+	keyFile         string
+
+	mutex       sync.Mutex
+	certificate *tls.Certificate
+	modTime     time.Time
+
+	logger *zap.Logger
+}
+
+func newCertificateReloader(certificateFile string, keyFile string, logger *zap.Logger) (*certificateReloader, error) {
+	r := &certificateReloader{
+		certificateFile: certificateFile,
+		keyFile:         keyFile,
+		logger:          logger,
+	}
+
+	modTime, err := r.lastModified()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *certificateReloader) lastModified() (time.Time, error) {
+	var modTime time.Time
+	for _, file := range []string{r.certificateFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	return modTime, nil
+}
+
+func (r *certificateReloader) load(modTime time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(r.certificateFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.certificate = &certificate
+	r.modTime = modTime
+
+	return nil
+}
+
+// GetCertificate is called for every TLS handshake. It keeps the current certificate
+// if the files cannot be loaded, e.g. while they are replaced one after the other.
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	modTime, err := r.lastModified()
+	if err == nil && !modTime.Equal(r.modTime) {
+		if err := r.load(modTime); err != nil {
+			// try again once the files change again
+			r.modTime = modTime
+			r.logger.Warn("failed to reload TLS certificate", zap.String("certificate_file", r.certificateFile), zap.Error(err))
+		} else {
+			r.logger.Info("TLS certificate reloaded", zap.String("certificate_file", r.certificateFile))
+		}
+	}
+
+	return r.certificate, nil
+}
+
+func (r *certificateReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: r.GetCertificate,
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// ValueTransformer transforms the value of a key read by a client, e.g. to render a
+// template, so that applications embedding cete can serve derived values without a proxy
+// in front of the cluster. The stored value is left as is. Returning an error fails the read.
+type ValueTransformer func(ctx context.Context, key string, value []byte) ([]byte, error)
+
+// ValueValidator validates the value of a key written by a client, e.g. against a schema.
+// Returning an error refuses the write, with INVALID_ARGUMENT unless the error carries
+// another code.
+type ValueValidator func(ctx context.Context, key string, value []byte) error
+
+type valueTransformer struct {
+	prefix    string
+	transform ValueTransformer
+	validate  ValueValidator
+}
+
+// valueTransformers are run in the order they were registered.
+type valueTransformers []*valueTransformer
+
+// transforms tells whether a transformer reads the keys with the prefix.
+func (t valueTransformers) transforms(prefix string) bool {
+	for _, vt := range t {
+		if vt.transform != nil && (strings.HasPrefix(prefix, vt.prefix) || strings.HasPrefix(vt.prefix, prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t valueTransformers) transform(ctx context.Context, key string, value []byte) ([]byte, error) {
+	for _, vt := range t {
+		if vt.transform == nil || !strings.HasPrefix(key, vt.prefix) {
+			continue
+		}
+		var err error
+		if value, err = vt.transform(ctx, key, value); err != nil {
+			return nil, errors.Convert(err, codes.Internal)
+		}
+	}
+
+	return value, nil
+}
+
+func (t valueTransformers) validate(ctx context.Context, key string, value []byte) error {
+	for _, vt := range t {
+		if vt.validate == nil || !strings.HasPrefix(key, vt.prefix) {
+			continue
+		}
+		if err := vt.validate(ctx, key, value); err != nil {
+			return errors.Convert(err, codes.InvalidArgument)
+		}
+	}
+
+	return nil
+}
+
+// valueRange returns the range of the value from the offset, of at most length bytes, or
+// up to the end of the value if the length is 0.
+func valueRange(value []byte, offset uint64, length uint64) ([]byte, error) {
+	size := uint64(len(value))
+	if offset > size {
+		return nil, errors.Wrapf(errors.ErrOutOfRange, "offset %d of a value of %d bytes", offset, size)
+	}
+	end := size
+	if length > 0 && length < size-offset {
+		end = offset + length
+	}
+
+	return value[offset:end], nil
+}
+
+// transformedGet reads the whole value of the key, transforms it and returns the range of
+// the transformed value.
+func (s *GRPCService) transformedGet(ctx context.Context, req *protobuf.GetRequest) (*protobuf.GetResponse, error) {
+	resp, err := s.raftServer.Get(&protobuf.GetRequest{Key: req.Key})
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.transformers.transform(ctx, req.Key, resp.Value)
+	if err != nil {
+		s.logger.Warn("failed to transform value", zap.String("key", req.Key), zap.Error(err))
+		return nil, err
+	}
+	if resp.Value, err = valueRange(value, req.Offset, req.Length); err != nil {
+		return nil, err
+	}
+	resp.Size = uint64(len(value))
+
+	return resp, nil
+}
+
+// transformedScan scans the values with their keys, so that the transformers are given
+// the key of each value.
+func (s *GRPCService) transformedScan(ctx context.Context, req *protobuf.ScanRequest) (*protobuf.ScanResponse, error) {
+	filter, err := newScanFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]byte, 0)
+	err = s.raftServer.fsm.Iterate(req.Prefix, filter, func(key string, value []byte) error {
+		transformed, err := s.transformers.transform(ctx, key, append([]byte{}, value...))
+		if err != nil {
+			s.logger.Warn("failed to transform value", zap.String("key", key), zap.Error(err))
+			return err
+		}
+		values = append(values, transformed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.ScanResponse{Values: values}, nil
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+func TestValueTransformers(t *testing.T) {
+	o := newGRPCOptions(
+		WithValueTransformer("/config/", func(ctx context.Context, key string, value []byte) ([]byte, error) {
+			return bytes.ReplaceAll(value, []byte("{{env}}"), []byte("prod")), nil
+		}),
+		WithValueValidator("/config/", func(ctx context.Context, key string, value []byte) error {
+			if len(value) == 0 {
+				return fmt.Errorf("empty configuration")
+			}
+			return nil
+		}),
+	)
+	s := &GRPCService{
+		raftServer:   &RaftServer{fsm: newTestRaftFSM(t), logger: zap.NewNop()},
+		logger:       zap.NewNop(),
+		transformers: o.transformers,
+	}
+	for i, key := range []string{"/config/a", "/config/b", "/data/c"} {
+		if err := applyTestEvent(t, s.raftServer.fsm, uint64(i+1), protobuf.Event_Set, &protobuf.SetRequest{Key: key, Value: []byte("env={{env}}")}); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if !s.transformers.transforms("/config/a") || !s.transformers.transforms("/") || s.transformers.transforms("/data/") {
+		t.Errorf("expected the transformers to read /config/ and / only")
+	}
+
+	resp, err := s.transformedGet(context.Background(), &protobuf.GetRequest{Key: "/config/a", Offset: 4})
+	if err != nil || string(resp.Value) != "prod" || resp.Size != 8 {
+		t.Errorf("expected the range of the transformed value, saw %v, %v", resp, err)
+	}
+
+	scanResp, err := s.transformedScan(context.Background(), &protobuf.ScanRequest{Prefix: "/"})
+	expected := [][]byte{[]byte("env=prod"), []byte("env=prod"), []byte("env={{env}}")}
+	if err != nil || !reflect.DeepEqual(scanResp.Values, expected) {
+		t.Errorf("expected the values of /config/ to be transformed, saw %q, %v", scanResp.GetValues(), err)
+	}
+
+	if err := s.transformers.validate(context.Background(), "/config/a", nil); errors.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected an empty configuration to be refused, saw %v", err)
+	}
+	if err := s.transformers.validate(context.Background(), "/data/c", nil); err != nil {
+		t.Errorf("expected the values of the other keys to be accepted, saw %v", err)
+	}
+}
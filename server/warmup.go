@@ -0,0 +1,141 @@
+package server
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/client"
+	"github.com/mosuka/cete/errors"
+	"go.uber.org/zap"
+)
+
+const warmupPollInterval = 500 * time.Millisecond
+
+// startWarmup keeps the node from reporting ready until it has applied the log entries
+// the leader had applied when the node started, then has read the keys with the hot
+// prefixes, so that the first reads of these keys are served from the caches of Badger
+// and of the OS. The node becomes ready once the timeout has passed anyway.
+func (s *GRPCService) startWarmup(timeout time.Duration, prefixes []string) {
+	s.logger.Info("start to warm up", zap.Duration("timeout", timeout), zap.Strings("prefixes", prefixes))
+
+	defer func() {
+		atomic.StoreInt32(&s.warm, 1)
+		close(s.warmupDoneCh)
+	}()
+
+	start := s.raftServer.clock.Now()
+	deadline := start.Add(timeout)
+
+	index, err := s.catchUp(deadline)
+	if err != nil {
+		s.logger.Warn("failed to catch up with the leader, the node is ready anyway", zap.Duration("timeout", timeout), zap.Error(err))
+		return
+	}
+	s.logger.Info("caught up with the leader", zap.Uint64("index", index), zap.Duration("duration", s.raftServer.clock.Since(start)))
+
+	for _, prefix := range prefixes {
+		keys, bytes, err := s.prewarm(prefix, deadline)
+		if err != nil {
+			s.logger.Warn("failed to warm up the prefix, the node is ready anyway", zap.String("prefix", prefix), zap.Int("keys", keys), zap.Int("bytes", bytes), zap.Error(err))
+			return
+		}
+		s.logger.Info("warmed up the prefix", zap.String("prefix", prefix), zap.Int("keys", keys), zap.Int("bytes", bytes))
+	}
+
+	s.logger.Info("warmed up", zap.Duration("duration", s.raftServer.clock.Since(start)))
+}
+
+func (s *GRPCService) stopWarmup() {
+	if s.warmupTimeout <= 0 {
+		return
+	}
+
+	close(s.warmupStopCh)
+	<-s.warmupDoneCh
+}
+
+// warmingUp tells whether the node has not warmed up yet.
+func (s *GRPCService) warmingUp() bool {
+	return atomic.LoadInt32(&s.warm) == 0
+}
+
+// catchUp waits for the FSM to apply the index the leader had applied when first asked,
+// and returns it. The index keeps moving while the leader is written to, so it is not
+// asked again.
+func (s *GRPCService) catchUp(deadline time.Time) (uint64, error) {
+	known := false
+	var index uint64
+	for {
+		if !known {
+			var err error
+			if index, err = s.leaderAppliedIndex(); err == nil {
+				known = true
+			} else {
+				s.logger.Debug("failed to get the applied index of the leader", zap.Error(err))
+			}
+		}
+		if known && s.raftServer.fsm.AppliedIndex() >= index {
+			return index, nil
+		}
+
+		if s.raftServer.clock.Now().After(deadline) {
+			return index, errors.Wrapf(errors.ErrTimeout, "applied index %d, leader applied index %d", s.raftServer.fsm.AppliedIndex(), index)
+		}
+		timer := s.raftServer.clock.NewTimer(warmupPollInterval)
+		select {
+		case <-s.warmupStopCh:
+			timer.Stop()
+			return index, errors.Wrap(errors.ErrNodeNotReady, "warmup stopped")
+		case <-timer.C():
+		}
+	}
+}
+
+// leaderAppliedIndex returns the applied index of the leader, or the commit index if
+// this node is the leader.
+func (s *GRPCService) leaderAppliedIndex() (uint64, error) {
+	if s.raftServer.State() == raft.Leader {
+		return strconv.ParseUint(s.raftServer.raft.Stats()["commit_index"], 10, 64)
+	}
+
+	leaderID, err := s.raftServer.LeaderID(time.Second)
+	if err != nil {
+		return 0, err
+	}
+	var index uint64
+	err = s.callPeer(string(leaderID), func(c *client.GRPCClient) error {
+		resp, err := c.Node()
+		if err != nil {
+			return err
+		}
+		index = resp.Node.AppliedIndex
+		return nil
+	})
+
+	return index, err
+}
+
+// prewarm reads the keys with the prefix along with their values, and returns how many
+// keys and bytes were read.
+func (s *GRPCService) prewarm(prefix string, deadline time.Time) (int, int, error) {
+	keys := 0
+	bytes := 0
+	err := s.raftServer.fsm.kvs.Iterate(prefix, func(key string, value []byte) error {
+		select {
+		case <-s.warmupStopCh:
+			return errors.Wrap(errors.ErrNodeNotReady, "warmup stopped")
+		default:
+		}
+		if s.raftServer.clock.Now().After(deadline) {
+			return errors.Wrapf(errors.ErrTimeout, "stopped at %q", key)
+		}
+
+		keys++
+		bytes += len(value)
+		return nil
+	})
+
+	return keys, bytes, err
+}
@@ -0,0 +1,415 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mosuka/cete/marshaler"
+	"github.com/mosuka/cete/metric"
+	"github.com/mosuka/cete/protobuf"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// watchRegistryShards is the number of shards of the watcher registry. Watchers are
+// sharded by the first byte of their prefix after the leading slash most keys start with,
+// so that watches starting and stopping only contend with the events of the keys starting
+// with the same byte.
+const watchRegistryShards = 64
+
+const defaultWatchBufferSize = 1024
+
+// WatchOverflowPolicy tells what happens to a watch that falls behind by more events than its buffer holds.
+type WatchOverflowPolicy int
+
+const (
+	// WatchOverflowCancel cancels the watch. The last response tells the watcher how to resume it.
+	WatchOverflowCancel WatchOverflowPolicy = iota
+	// WatchOverflowDropOldest drops the oldest buffered events and sends a gap in their place.
+	WatchOverflowDropOldest
+)
+
+func (p WatchOverflowPolicy) String() string {
+	switch p {
+	case WatchOverflowCancel:
+		return "cancel"
+	case WatchOverflowDropOldest:
+		return "drop_oldest"
+	default:
+		return fmt.Sprintf("WatchOverflowPolicy(%d)", int(p))
+	}
+}
+
+// ParseWatchOverflowPolicy parses "cancel" or "drop_oldest".
+func ParseWatchOverflowPolicy(s string) (WatchOverflowPolicy, error) {
+	for _, p := range []WatchOverflowPolicy{WatchOverflowCancel, WatchOverflowDropOldest} {
+		if strings.EqualFold(s, p.String()) {
+			return p, nil
+		}
+	}
+
+	return WatchOverflowCancel, fmt.Errorf("unknown watch overflow policy %q", s)
+}
+
+type watcher struct {
+	prefix string
+	ch     chan *protobuf.WatchResponse
+	// whether the watcher receives the events of the priority queue
+	priority bool
+
+	// the events dropped since the last gap was sent
+	gapMutex sync.Mutex
+	gap      *protobuf.WatchGap
+
+	cancelOnce sync.Once
+	cancelCh   chan struct{}
+}
+
+func (w *watcher) addGap(index uint64) {
+	w.gapMutex.Lock()
+	defer w.gapMutex.Unlock()
+
+	if w.gap == nil {
+		w.gap = &protobuf.WatchGap{FromIndex: index}
+	}
+	w.gap.ToIndex = index
+	w.gap.Dropped++
+}
+
+// takeGap returns the events dropped before the index, if any.
+func (w *watcher) takeGap(index uint64) *protobuf.WatchGap {
+	w.gapMutex.Lock()
+	defer w.gapMutex.Unlock()
+
+	if w.gap == nil || w.gap.ToIndex >= index {
+		return nil
+	}
+	gap := w.gap
+	w.gap = nil
+
+	return gap
+}
+
+// cancel tells the watch stream to stop, e.g. because the watcher fell behind.
+func (w *watcher) cancel() {
+	w.cancelOnce.Do(func() {
+		close(w.cancelCh)
+	})
+}
+
+// watchTrieNode is a node of a trie of the watched prefixes. The watchers of a node
+// watch the prefix spelled by the path from the root.
+type watchTrieNode struct {
+	children map[byte]*watchTrieNode
+	watchers map[*watcher]struct{}
+}
+
+func newWatchTrieNode() *watchTrieNode {
+	return &watchTrieNode{
+		children: make(map[byte]*watchTrieNode, 0),
+		watchers: make(map[*watcher]struct{}, 0),
+	}
+}
+
+type watchShard struct {
+	mutex sync.RWMutex
+	root  *watchTrieNode
+}
+
+func (s *watchShard) add(w *watcher) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	node := s.root
+	for i := 0; i < len(w.prefix); i++ {
+		child, ok := node.children[w.prefix[i]]
+		if !ok {
+			child = newWatchTrieNode()
+			node.children[w.prefix[i]] = child
+		}
+		node = child
+	}
+	node.watchers[w] = struct{}{}
+}
+
+func (s *watchShard) remove(w *watcher) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := make([]*watchTrieNode, 0, len(w.prefix)+1)
+	node := s.root
+	path = append(path, node)
+	for i := 0; i < len(w.prefix); i++ {
+		child, ok := node.children[w.prefix[i]]
+		if !ok {
+			return
+		}
+		node = child
+		path = append(path, node)
+	}
+	delete(node.watchers, w)
+
+	// prune the nodes nobody watches through anymore
+	for i := len(path) - 1; i > 0; i-- {
+		if len(path[i].watchers) > 0 || len(path[i].children) > 0 {
+			break
+		}
+		delete(path[i-1].children, w.prefix[i-1])
+	}
+}
+
+// match calls f for every watcher whose prefix is a prefix of the key. If the key is a
+// prefix itself, f is also called for the watchers of the longer prefixes under it.
+func (s *watchShard) match(key string, prefix bool, f func(w *watcher)) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	node := s.root
+	for i := 0; ; i++ {
+		if i == len(key) && prefix {
+			node.walk(f)
+			return
+		}
+		for w := range node.watchers {
+			f(w)
+		}
+		if i == len(key) {
+			return
+		}
+		child, ok := node.children[key[i]]
+		if !ok {
+			return
+		}
+		node = child
+	}
+}
+
+// walk calls f for the watchers of the node and of its descendants.
+func (n *watchTrieNode) walk(f func(w *watcher)) {
+	for w := range n.watchers {
+		f(w)
+	}
+	for _, child := range n.children {
+		child.walk(f)
+	}
+}
+
+// watchRegistry fans the applied events out to the watchers of the keys. Events are
+// queued to the watchers without blocking, so that slow watchers never hold up the FSM.
+type watchRegistry struct {
+	bufferSize int
+	policy     WatchOverflowPolicy
+	priority   priorityPrefixes
+
+	// the watchers of every event
+	all watchShard
+	// the watchers of a prefix too short to be sharded, i.e. "/"
+	short  watchShard
+	shards [watchRegistryShards]watchShard
+
+	watchersGauge    prometheus.Gauge
+	queuedGauge      prometheus.Gauge
+	droppedCounter   prometheus.Counter
+	cancelledCounter prometheus.Counter
+
+	logger *zap.Logger
+}
+
+func newWatchRegistry(id string, bufferSize int, policy WatchOverflowPolicy, priority priorityPrefixes, logger *zap.Logger) *watchRegistry {
+	r := &watchRegistry{
+		bufferSize:       bufferSize,
+		policy:           policy,
+		priority:         priority,
+		all:              watchShard{root: newWatchTrieNode()},
+		short:            watchShard{root: newWatchTrieNode()},
+		watchersGauge:    metric.WatchWatchersMetric.WithLabelValues(id),
+		queuedGauge:      metric.WatchQueuedEventsMetric.WithLabelValues(id),
+		droppedCounter:   metric.WatchDroppedEventsMetric.WithLabelValues(id),
+		cancelledCounter: metric.WatchCancelledMetric.WithLabelValues(id),
+		logger:           logger,
+	}
+	for i := range r.shards {
+		r.shards[i].root = newWatchTrieNode()
+	}
+
+	return r
+}
+
+// shardIndex returns the shard of the watchers of the prefix, or false if the prefix is
+// "/" or empty.
+func shardIndex(prefix string) (int, bool) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if prefix == "" {
+		return 0, false
+	}
+
+	return int(prefix[0]) % watchRegistryShards, true
+}
+
+func (r *watchRegistry) shard(prefix string) *watchShard {
+	if prefix == "" {
+		return &r.all
+	}
+	i, ok := shardIndex(prefix)
+	if !ok {
+		return &r.short
+	}
+
+	return &r.shards[i]
+}
+
+func (r *watchRegistry) register(prefix string) *watcher {
+	w := &watcher{
+		prefix:   prefix,
+		ch:       make(chan *protobuf.WatchResponse, r.bufferSize),
+		priority: r.priority.watcher(prefix),
+		cancelCh: make(chan struct{}),
+	}
+	r.shard(prefix).add(w)
+	r.watchersGauge.Inc()
+
+	return w
+}
+
+func (r *watchRegistry) unregister(w *watcher) {
+	r.shard(w.prefix).remove(w)
+	r.watchersGauge.Dec()
+
+	// no more events are queued once the watcher is removed
+	r.queuedGauge.Sub(float64(len(w.ch)))
+}
+
+// received tells the registry that a watcher took an event off its queue.
+func (r *watchRegistry) received() {
+	r.queuedGauge.Dec()
+}
+
+func (r *watchRegistry) dispatch(applied *appliedEvent, priority bool) {
+	resp := &protobuf.WatchResponse{
+		Event: applied.event,
+		Index: applied.index,
+	}
+	enqueue := func(w *watcher) {
+		// the watchers of priority prefixes only receive the events of the priority queue
+		if w.priority != priority {
+			return
+		}
+		select {
+		case <-w.cancelCh:
+			// the stream is about to stop
+			return
+		default:
+		}
+		select {
+		case w.ch <- resp:
+			r.queuedGauge.Inc()
+			return
+		default:
+		}
+
+		switch r.policy {
+		case WatchOverflowDropOldest:
+			// events are only queued here, so there is room once the oldest one is dropped
+			for {
+				select {
+				case dropped := <-w.ch:
+					r.queuedGauge.Dec()
+					r.droppedCounter.Inc()
+					w.addGap(dropped.Index)
+				default:
+				}
+				select {
+				case w.ch <- resp:
+					r.queuedGauge.Inc()
+					return
+				default:
+				}
+			}
+		default:
+			r.logger.Warn("watcher fell behind", zap.String("prefix", w.prefix), zap.Int("buffer_size", r.bufferSize))
+			r.cancelledCounter.Inc()
+			w.cancel()
+		}
+	}
+
+	r.all.match("", false, enqueue)
+	keys, prefix := eventKeys(applied.event)
+	if len(keys) > 1 {
+		// a watcher matching several keys receives the event once
+		matched := make(map[*watcher]bool, 0)
+		once := enqueue
+		enqueue = func(w *watcher) {
+			if !matched[w] {
+				matched[w] = true
+				once(w)
+			}
+		}
+	}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		r.short.match(key, prefix, enqueue)
+		if i, ok := shardIndex(key); ok {
+			r.shards[i].match(key, prefix, enqueue)
+			continue
+		}
+		// the watchers under a prefix too short to be sharded are in every shard
+		if prefix {
+			for i := range r.shards {
+				r.shards[i].match(key, prefix, enqueue)
+			}
+		}
+	}
+}
+
+// watchesEvent reports whether a watcher of the prefix receives the event.
+func watchesEvent(prefix string, event *protobuf.Event) bool {
+	if prefix == "" {
+		return true
+	}
+	keys, keyPrefix := eventKeys(event)
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) || (keyPrefix && strings.HasPrefix(prefix, key)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eventKeys returns the keys written by the event, or the prefixes of the keys if prefix
+// is true, e.g. the source and the destination of a move. Cluster events have no key.
+func eventKeys(event *protobuf.Event) ([]string, bool) {
+	switch event.Type {
+	case protobuf.Event_Set, protobuf.Event_Delete, protobuf.Event_Copy, protobuf.Event_Move, protobuf.Event_Expire, protobuf.Event_Purge:
+	default:
+		return nil, false
+	}
+
+	data, err := marshaler.EventData(event)
+	if err != nil {
+		return nil, false
+	}
+	switch req := data.(type) {
+	case *protobuf.SetRequest:
+		return []string{req.Key}, false
+	case *protobuf.DeleteRequest:
+		return []string{req.Key}, false
+	case *protobuf.CopyRequest:
+		return []string{req.Destination}, req.Prefix
+	case *protobuf.MoveRequest:
+		return []string{req.Source, req.Destination}, req.Prefix
+	case *protobuf.ExpireRequest:
+		return req.Keys, false
+	case *protobuf.PurgeRequest:
+		keys := make([]string, 0, len(req.Keys))
+		for _, key := range req.Keys {
+			keys = append(keys, key.Key)
+		}
+		return keys, false
+	}
+
+	return nil, false
+}
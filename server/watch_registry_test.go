@@ -0,0 +1,216 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mosuka/cete/marshaler"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+func newTestEvent(t *testing.T, eventType protobuf.Event_Type, data proto.Message) *protobuf.Event {
+	event, err := marshaler.NewEvent(eventType, data)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return event
+}
+
+func TestWatchRegistry(t *testing.T) {
+	r := newWatchRegistry("test", 2, WatchOverflowCancel, nil, zap.NewNop())
+
+	all := r.register("")
+	a := r.register("/a")
+	ab := r.register("/a/b")
+	b := r.register("/b")
+
+	r.dispatch(&appliedEvent{index: 1, event: newTestEvent(t, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a/b/c", Value: []byte("1")})}, false)
+	r.dispatch(&appliedEvent{index: 2, event: newTestEvent(t, protobuf.Event_Join, &protobuf.SetMetadataRequest{Id: "node1"})}, false)
+
+	for _, test := range []struct {
+		name     string
+		watcher  *watcher
+		expected int
+	}{
+		{"all", all, 2},
+		{"/a", a, 1},
+		{"/a/b", ab, 1},
+		{"/b", b, 0},
+	} {
+		if len(test.watcher.ch) != test.expected {
+			t.Errorf("expected %s to receive %d events, saw %d", test.name, test.expected, len(test.watcher.ch))
+		}
+	}
+
+	// a watcher that fell behind is cancelled instead of blocking the others
+	r.dispatch(&appliedEvent{index: 3, event: newTestEvent(t, protobuf.Event_Delete, &protobuf.DeleteRequest{Key: "/b"})}, false)
+	select {
+	case <-all.cancelCh:
+	default:
+		t.Errorf("expected the full watcher to be cancelled")
+	}
+	if len(b.ch) != 1 {
+		t.Errorf("expected /b to receive 1 event, saw %d", len(b.ch))
+	}
+
+	// the trie is pruned as the watchers go away
+	for _, w := range []*watcher{all, a, ab, b} {
+		r.unregister(w)
+	}
+	for i := range r.shards {
+		if len(r.shards[i].root.children) > 0 {
+			t.Errorf("expected shard %d to be empty", i)
+		}
+	}
+}
+
+func TestWatchRegistryShards(t *testing.T) {
+	r := newWatchRegistry("test", 4, WatchOverflowCancel, nil, zap.NewNop())
+
+	root := r.register("/")
+	users := r.register("/users/")
+	groups := r.register("/groups/")
+	if r.shard("/users/") == r.shard("/groups/") || r.shard("/") != &r.short {
+		t.Fatalf("expected the watchers to be sharded by the byte after the leading slash")
+	}
+
+	r.dispatch(&appliedEvent{index: 1, event: newTestEvent(t, protobuf.Event_Set, &protobuf.SetRequest{Key: "/users/1", Value: []byte("1")})}, false)
+	r.dispatch(&appliedEvent{index: 2, event: newTestEvent(t, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/", Destination: "/archive/", Prefix: true})}, false)
+
+	for _, test := range []struct {
+		name     string
+		watcher  *watcher
+		expected int
+	}{
+		{"/", root, 2},
+		{"/users/", users, 2},
+		{"/groups/", groups, 1},
+	} {
+		if len(test.watcher.ch) != test.expected {
+			t.Errorf("expected %s to receive %d events, saw %d", test.name, test.expected, len(test.watcher.ch))
+		}
+	}
+}
+
+func TestWatchRegistryPriority(t *testing.T) {
+	priority := newPriorityPrefixes([]string{"/config/", ""})
+	r := newWatchRegistry("test", 4, WatchOverflowCancel, priority, zap.NewNop())
+
+	all := r.register("")
+	config := r.register("/config/")
+	flags := r.register("/config/flags/")
+	data := r.register("/data/")
+
+	setConfig := newTestEvent(t, protobuf.Event_Set, &protobuf.SetRequest{Key: "/config/flags/a", Value: []byte("1")})
+	setData := newTestEvent(t, protobuf.Event_Set, &protobuf.SetRequest{Key: "/data/a", Value: []byte("1")})
+	moveAll := newTestEvent(t, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/", Destination: "/backup/", Prefix: true})
+	for _, test := range []struct {
+		name     string
+		event    *protobuf.Event
+		expected bool
+	}{
+		{"set of a priority key", setConfig, true},
+		{"set of a bulk key", setData, false},
+		{"move of a prefix holding priority keys", moveAll, true},
+	} {
+		if priority.event(test.event) != test.expected {
+			t.Errorf("%s: expected priority %v", test.name, test.expected)
+		}
+	}
+
+	// the FSM queues the priority events to both queues
+	r.dispatch(&appliedEvent{index: 1, event: setConfig}, true)
+	r.dispatch(&appliedEvent{index: 1, event: setConfig}, false)
+	r.dispatch(&appliedEvent{index: 2, event: setData}, false)
+
+	for _, test := range []struct {
+		name     string
+		watcher  *watcher
+		expected int
+	}{
+		{"all", all, 2},
+		{"/config/", config, 1},
+		{"/config/flags/", flags, 1},
+		{"/data/", data, 1},
+	} {
+		if len(test.watcher.ch) != test.expected {
+			t.Errorf("expected %s to receive %d events, saw %d", test.name, test.expected, len(test.watcher.ch))
+		}
+	}
+}
+
+func TestWatchRegistryMove(t *testing.T) {
+	r := newWatchRegistry("test", 2, WatchOverflowCancel, nil, zap.NewNop())
+
+	a := r.register("/a/")
+	bc := r.register("/b/c/")
+	b := r.register("/b")
+	d := r.register("/d/")
+
+	// the watchers of the source, of the destination and of the prefixes under the destination receive a move once
+	r.dispatch(&appliedEvent{index: 1, event: newTestEvent(t, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/a/", Destination: "/b/", Prefix: true})}, false)
+
+	for _, test := range []struct {
+		name     string
+		watcher  *watcher
+		expected int
+	}{
+		{"/a/", a, 1},
+		{"/b/c/", bc, 1},
+		{"/b", b, 1},
+		{"/d/", d, 0},
+	} {
+		if len(test.watcher.ch) != test.expected {
+			t.Errorf("expected %s to receive %d events, saw %d", test.name, test.expected, len(test.watcher.ch))
+		}
+		if watchesEvent(test.watcher.prefix, newTestEvent(t, protobuf.Event_Move, &protobuf.MoveRequest{Source: "/a/", Destination: "/b/", Prefix: true})) != (test.expected > 0) {
+			t.Errorf("expected the replay of %s to match the dispatch", test.name)
+		}
+	}
+}
+
+func TestWatchRegistryDropOldest(t *testing.T) {
+	r := newWatchRegistry("test", 2, WatchOverflowDropOldest, nil, zap.NewNop())
+
+	w := r.register("/a")
+	for i := uint64(1); i <= 5; i++ {
+		r.dispatch(&appliedEvent{index: i, event: newTestEvent(t, protobuf.Event_Set, &protobuf.SetRequest{Key: "/a"})}, false)
+	}
+
+	select {
+	case <-w.cancelCh:
+		t.Fatalf("expected the watcher not to be cancelled")
+	default:
+	}
+
+	resp := <-w.ch
+	if resp.Index != 4 {
+		t.Errorf("expected the oldest event left to be 4, saw %d", resp.Index)
+	}
+	gap := w.takeGap(resp.Index)
+	if gap == nil || gap.FromIndex != 1 || gap.ToIndex != 3 || gap.Dropped != 3 {
+		t.Errorf("expected a gap of 3 events from 1 to 3, saw %v", gap)
+	}
+	if gap := w.takeGap(5); gap != nil {
+		t.Errorf("expected the gap to be sent once, saw %v", gap)
+	}
+}
+
+func TestParseWatchOverflowPolicy(t *testing.T) {
+	for _, test := range []struct {
+		s        string
+		expected WatchOverflowPolicy
+		valid    bool
+	}{
+		{"cancel", WatchOverflowCancel, true},
+		{"DROP_OLDEST", WatchOverflowDropOldest, true},
+		{"block", WatchOverflowCancel, false},
+	} {
+		policy, err := ParseWatchOverflowPolicy(test.s)
+		if (err == nil) != test.valid || policy != test.expected {
+			t.Errorf("expected %q to parse as %v, saw %v %v", test.s, test.expected, policy, err)
+		}
+	}
+}
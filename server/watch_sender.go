@@ -0,0 +1,64 @@
+package server
+
+import (
+	"github.com/mosuka/cete/marshaler"
+	"github.com/mosuka/cete/protobuf"
+)
+
+// maxCompactFrameSize is the size above which the compact events are sent without
+// waiting for more events.
+const maxCompactFrameSize = 64 * 1024
+
+// watchSender sends the responses of a watch in the encoding requested by the watcher.
+// With the compact encoding, the events are buffered until flush is called, so that the
+// events queued at once are sent in a single response.
+type watchSender struct {
+	send    func(*protobuf.WatchResponse) error
+	encoder *marshaler.CompactEventEncoder
+	frame   []byte
+}
+
+func newWatchSender(req *protobuf.WatchRequest, send func(*protobuf.WatchResponse) error) *watchSender {
+	s := &watchSender{
+		send: send,
+	}
+	if req.Encoding == protobuf.WatchRequest_Compact {
+		s.encoder = marshaler.NewCompactEventEncoder(req.KeyDelta)
+	}
+
+	return s
+}
+
+// sendResponse sends an event, or any other response after the buffered events.
+func (s *watchSender) sendResponse(resp *protobuf.WatchResponse) error {
+	if s.encoder == nil {
+		return s.send(resp)
+	}
+	if resp.Event == nil {
+		if err := s.flush(); err != nil {
+			return err
+		}
+		return s.send(resp)
+	}
+
+	var err error
+	if s.frame, err = s.encoder.Append(s.frame, resp.Index, resp.Event); err != nil {
+		return err
+	}
+	if len(s.frame) >= maxCompactFrameSize {
+		return s.flush()
+	}
+
+	return nil
+}
+
+// flush sends the buffered events.
+func (s *watchSender) flush() error {
+	if len(s.frame) == 0 {
+		return nil
+	}
+	frame := s.frame
+	s.frame = nil
+
+	return s.send(&protobuf.WatchResponse{CompactEvents: frame})
+}
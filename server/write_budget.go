@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+)
+
+const (
+	stageBackpressure = "backpressure"
+	stageQueueing     = "queueing"
+	stageRaftApply    = "raft_apply"
+	stageFSMCommit    = "fsm_commit"
+
+	// defaultWriteBudget is used for writes whose context has no deadline.
+	defaultWriteBudget = 10 * time.Second
+
+	// responseReserve is the largest share of the budget kept to send the response,
+	// so that the client gets a timeout error before its own deadline expires.
+	responseReserve    = 0.1
+	maxResponseReserve = 100 * time.Millisecond
+
+	// queueingBudgetShare is the largest share of the budget a write may spend
+	// waiting to be enqueued, the rest is left to replicate and commit it.
+	queueingBudgetShare = 0.25
+)
+
+// writeBudget splits the deadline of a write across the stages it goes through,
+// and records the time spent in each of them.
+type writeBudget struct {
+	clock    Clock
+	budget   time.Duration
+	deadline time.Time
+	stages   []*protobuf.StageTiming
+}
+
+func newWriteBudget(ctx context.Context, clock Clock) *writeBudget {
+	budget := defaultWriteBudget
+	if deadline, ok := ctx.Deadline(); ok {
+		// the deadline of the context is in real time, the budget is measured by the clock
+		timeout := time.Until(deadline)
+		reserve := time.Duration(float64(timeout) * responseReserve)
+		if reserve > maxResponseReserve {
+			reserve = maxResponseReserve
+		}
+		budget = timeout - reserve
+	}
+
+	return &writeBudget{
+		clock:    clock,
+		budget:   budget,
+		deadline: clock.Now().Add(budget),
+		stages:   make([]*protobuf.StageTiming, 0),
+	}
+}
+
+func (b *writeBudget) remaining() time.Duration {
+	return b.deadline.Sub(b.clock.Now())
+}
+
+// queueingTimeout returns how long the write may wait to be enqueued.
+func (b *writeBudget) queueingTimeout() time.Duration {
+	timeout := time.Duration(float64(b.budget) * queueingBudgetShare)
+	if remaining := b.remaining(); remaining < timeout {
+		timeout = remaining
+	}
+
+	return timeout
+}
+
+func (b *writeBudget) record(stage string, elapsed time.Duration) {
+	b.stages = append(b.stages, &protobuf.StageTiming{
+		Stage:   stage,
+		Elapsed: ptypes.DurationProto(elapsed),
+	})
+}
+
+// exceeded returns a timeout error telling where the time went.
+// The stage that ran out of time must have been recorded.
+func (b *writeBudget) exceeded(stage string) error {
+	timings := make([]string, 0, len(b.stages))
+	for _, timing := range b.stages {
+		elapsed, _ := ptypes.Duration(timing.Elapsed)
+		timings = append(timings, fmt.Sprintf("%s %v", timing.Stage, elapsed.Round(time.Microsecond)))
+	}
+
+	return &errors.Error{
+		Code:      errors.ErrTimeout.Code,
+		Message:   fmt.Sprintf("write budget of %v exceeded in %s (%s)", b.budget.Round(time.Millisecond), stage, strings.Join(timings, ", ")),
+		Retryable: true,
+		Budget: &protobuf.DeadlineBudget{
+			Budget:        ptypes.DurationProto(b.budget),
+			ExceededStage: stage,
+			Stages:        b.stages,
+		},
+		Err: errors.ErrTimeout,
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mosuka/cete/errors"
+	"google.golang.org/grpc/status"
+)
+
+func TestWriteBudget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	budget := newWriteBudget(ctx, RealClock)
+	if budget.budget > 900*time.Millisecond || budget.budget < 800*time.Millisecond {
+		t.Errorf("expected the budget to keep 100ms for the response, saw %v", budget.budget)
+	}
+	if timeout := budget.queueingTimeout(); timeout > budget.budget/4 {
+		t.Errorf("expected queueing to get at most a quarter of the budget, saw %v", timeout)
+	}
+
+	budget.record(stageQueueing, time.Millisecond)
+	budget.record(stageRaftApply, 850*time.Millisecond)
+	err := budget.exceeded(stageRaftApply)
+
+	if !errors.Is(err, errors.ErrTimeout) {
+		t.Errorf("expected %v to be a timeout", err)
+	}
+	if !strings.Contains(err.Error(), "exceeded in raft_apply (queueing 1ms, raft_apply 850ms)") {
+		t.Errorf("expected the message to tell where the time went, saw %q", err.Error())
+	}
+
+	// the timings reach the client
+	st, _ := status.FromError(err)
+	received := errors.FromStatus(st)
+	if !errors.Is(received, errors.ErrTimeout) {
+		t.Errorf("expected %v to be a timeout", received)
+	}
+	details := errors.DeadlineBudget(received)
+	if details == nil || details.ExceededStage != stageRaftApply || len(details.Stages) != 2 {
+		t.Fatalf("expected the deadline budget in the error, saw %v", details)
+	}
+}
+
+func TestWriteBudgetWithoutDeadline(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	budget := newWriteBudget(context.Background(), clock)
+	if budget.budget != defaultWriteBudget {
+		t.Errorf("expected the default budget, saw %v", budget.budget)
+	}
+
+	// the budget runs out with the clock, however long it is in real time
+	clock.Advance(defaultWriteBudget - time.Second)
+	if remaining := budget.remaining(); remaining != time.Second {
+		t.Errorf("expected 1s of the budget to remain, saw %v", remaining)
+	}
+	if timeout := budget.queueingTimeout(); timeout != time.Second {
+		t.Errorf("expected the queueing timeout to be capped by the remaining budget, saw %v", timeout)
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"sync/atomic"
+
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/metric"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// fenceWrite refuses a write unless this node is the leader and holds the leader lease.
+// The lease is confirmed with a quorum, and lasts for the leader lease timeout from the
+// time the confirmation was asked for, which is shorter than the election timeout the
+// other nodes wait for before electing another leader. A write arriving after the lease
+// expired confirms it again, so a deposed leader stops taking writes even before it
+// notices that it lost the leadership.
+func (s *RaftServer) fenceWrite() error {
+	if s.raft.State() != raft.Leader {
+		return s.fenced("not_leader", "not the leader")
+	}
+
+	if s.clock.Now().UnixNano()-atomic.LoadInt64(&s.leaseConfirmed) < int64(s.profile.LeaderLeaseTimeout) {
+		return nil
+	}
+
+	start := s.clock.Now()
+	if err := s.raft.VerifyLeader().Error(); err != nil {
+		s.logger.Warn("failed to confirm the leader lease", zap.Error(err))
+		return s.fenced("lease", "the leader lease could not be confirmed with a quorum")
+	}
+	atomic.StoreInt64(&s.leaseConfirmed, start.UnixNano())
+
+	return nil
+}
+
+// noteDeposed forgets the leader lease if the leadership was lost while a write was
+// applied. The write was committed, so it is acknowledged, but the next write confirms
+// the lease again.
+func (s *RaftServer) noteDeposed(eventType protobuf.Event_Type) {
+	if s.raft.State() == raft.Leader {
+		return
+	}
+
+	atomic.StoreInt64(&s.leaseConfirmed, 0)
+	s.logger.Warn("the leadership was lost after the message was applied", zap.String("type", eventType.String()))
+}
+
+func (s *RaftServer) fenced(reason string, message string) error {
+	metric.RaftFencedWritesMetric.WithLabelValues(s.id, reason).Inc()
+
+	return errors.Wrap(errors.ErrNotLeader, message)
+}
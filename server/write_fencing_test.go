@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/mosuka/cete/errors"
+	"github.com/mosuka/cete/protobuf"
+	"go.uber.org/zap"
+)
+
+// newTestRaftCluster starts a cluster of nodes connected by in-memory transports, and
+// returns the nodes and their transports once a leader is elected.
+func newTestRaftCluster(t *testing.T, n int) ([]*RaftServer, []*raft.InmemTransport) {
+	profile := LANProfile
+	profile.HeartbeatTimeout = 50 * time.Millisecond
+	profile.ElectionTimeout = 50 * time.Millisecond
+	profile.LeaderLeaseTimeout = 50 * time.Millisecond
+	profile.CommitTimeout = 5 * time.Millisecond
+
+	configuration := raft.Configuration{}
+	transports := make([]*raft.InmemTransport, n)
+	for i := range transports {
+		var addr raft.ServerAddress
+		// a node answering an RPC the sender gave up on blocks, the timeout must not expire
+		addr, transports[i] = raft.NewInmemTransportWithTimeout("", 10*time.Second)
+		configuration.Servers = append(configuration.Servers, raft.Server{Suffrage: raft.Voter, ID: raft.ServerID(fmt.Sprintf("node%d", i+1)), Address: addr})
+	}
+	for _, t1 := range transports {
+		for _, t2 := range transports {
+			t1.Connect(t2.LocalAddr(), t2)
+		}
+	}
+
+	servers := make([]*RaftServer, n)
+	for i := range servers {
+		s := &RaftServer{
+			id:           string(configuration.Servers[i].ID),
+			fsm:          newTestRaftFSM(t),
+			logger:       zap.NewNop(),
+			clock:        RealClock,
+			profile:      profile,
+			writeFencing: true,
+		}
+
+		config := raft.DefaultConfig()
+		config.LocalID = configuration.Servers[i].ID
+		config.HeartbeatTimeout = profile.HeartbeatTimeout
+		config.ElectionTimeout = profile.ElectionTimeout
+		config.LeaderLeaseTimeout = profile.LeaderLeaseTimeout
+		config.CommitTimeout = profile.CommitTimeout
+		config.LogOutput = ioutil.Discard
+		logStore := raft.NewInmemStore()
+		snapshotStore := raft.NewInmemSnapshotStore()
+		if err := raft.BootstrapCluster(config, logStore, logStore, snapshotStore, transports[i], configuration); err != nil {
+			t.Fatalf("%v", err)
+		}
+		r, err := raft.NewRaft(config, s.fsm, logStore, logStore, snapshotStore, transports[i])
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		s.raft = r
+		// Raft shuts down before the FSM is closed
+		t.Cleanup(func() {
+			_ = r.Shutdown().Error()
+		})
+		servers[i] = s
+	}
+
+	if leader := testLeader(t, servers); leader == nil {
+		t.Fatalf("expected a leader to be elected")
+	}
+
+	return servers, transports
+}
+
+// testLeader waits for one of the nodes to be the leader.
+func testLeader(t *testing.T, servers []*RaftServer) *RaftServer {
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		for _, s := range servers {
+			if s.raft.State() == raft.Leader {
+				return s
+			}
+		}
+	}
+
+	return nil
+}
+
+// partitionTestLeader cuts the leader off from the other nodes.
+func partitionTestLeader(servers []*RaftServer, transports []*raft.InmemTransport, leader *RaftServer) {
+	for i, s := range servers {
+		if s == leader {
+			transports[i].DisconnectAll()
+			for _, tr := range transports {
+				tr.Disconnect(transports[i].LocalAddr())
+			}
+		}
+	}
+}
+
+func TestFencedWriteCommittedWhileDeposed(t *testing.T) {
+	servers, transports := newTestRaftCluster(t, 3)
+	leader := testLeader(t, servers)
+
+	// the leader is cut off from the other nodes while the write is applied, after it
+	// was committed, and steps down once its lease expires
+	leader.fsm.hooks = newApplyHooks([]*applyHook{{prefix: "/depose", hook: func(index uint64, event *protobuf.Event) {
+		partitionTestLeader(servers, transports, leader)
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline) && leader.raft.State() == raft.Leader; {
+			time.Sleep(time.Millisecond)
+		}
+	}}}, zap.NewNop())
+
+	err := leader.propose(context.Background(), protobuf.Event_Set, &protobuf.SetRequest{Key: "/depose", Value: []byte("a")})
+	if err != nil {
+		t.Fatalf("expected the committed write to be acknowledged, saw %v", err)
+	}
+	if state := leader.raft.State(); state == raft.Leader {
+		t.Fatalf("expected the leader to be deposed, saw %v", state)
+	}
+	if atomic.LoadInt64(&leader.leaseConfirmed) != 0 {
+		t.Errorf("expected the leader lease to be forgotten")
+	}
+	if value, err := leader.fsm.Get("/depose"); err != nil || string(value) != "a" {
+		t.Errorf("expected the write to be applied, saw %q, %v", value, err)
+	}
+
+	// the deposed leader refuses the next write before proposing it
+	err = leader.propose(context.Background(), protobuf.Event_Set, &protobuf.SetRequest{Key: "/b", Value: []byte("b")})
+	if !errors.Is(err, errors.ErrNotLeader) || !errors.IsRetryable(err) {
+		t.Errorf("expected a retryable not leader error, saw %v", err)
+	}
+}
+
+func TestFencedWritePartitionedLeader(t *testing.T) {
+	servers, transports := newTestRaftCluster(t, 3)
+	leader := testLeader(t, servers)
+
+	if err := leader.propose(context.Background(), protobuf.Event_Set, &protobuf.SetRequest{Key: "/a", Value: []byte("a")}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the leader lease expires while the leader is cut off from the other nodes
+	partitionTestLeader(servers, transports, leader)
+	time.Sleep(2 * leader.profile.LeaderLeaseTimeout)
+
+	err := leader.propose(context.Background(), protobuf.Event_Set, &protobuf.SetRequest{Key: "/b", Value: []byte("b")})
+	if !errors.Is(err, errors.ErrNotLeader) || !errors.IsRetryable(err) {
+		t.Errorf("expected the write to be refused with a retryable not leader error, saw %v", err)
+	}
+	for _, s := range servers {
+		if _, err := s.fsm.Get("/b"); !errors.Is(err, errors.ErrNotFound) {
+			t.Errorf("expected the refused write not to be applied on %s, saw %v", s.id, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/mosuka/cete/metric"
+	"github.com/mosuka/cete/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// writeStallShare and writeResumeShare are the shares of the level 0 tables at which
+	// Badger stalls the writes from which the writes are held back, and below which they
+	// resume, so that the compactions catch up before Badger blocks the writes for good.
+	writeStallShare  = 0.8
+	writeResumeShare = 0.5
+
+	// writeStallPollInterval is how often a held back write checks whether the writes
+	// resumed.
+	writeStallPollInterval = 10 * time.Millisecond
+)
+
+// WritesStalled returns whether the compactions are behind the writes.
+func (s *RaftServer) WritesStalled() bool {
+	return atomic.LoadInt32(&s.writeStalled) == 1
+}
+
+// checkWriteStall tracks the compaction backlog of the key value store, and stalls the
+// writes while the compactions are behind. It runs on the cluster watching goroutine.
+func (s *RaftServer) checkWriteStall() {
+	backlog := s.fsm.CompactionBacklog()
+	metric.KvsLevel0TablesMetric.WithLabelValues(s.id).Set(float64(backlog.Level0Tables))
+
+	blockedPuts := backlog.BlockedPuts - s.lastBlockedPuts
+	s.lastBlockedPuts = backlog.BlockedPuts
+
+	stalled := s.WritesStalled()
+	switch {
+	case !stalled && writeStalled(backlog, blockedPuts, stalled):
+		atomic.StoreInt32(&s.writeStalled, 1)
+		metric.KvsWriteStallsMetric.WithLabelValues(s.id).Inc()
+		s.logger.Warn("writes stalled, the compactions are behind", zap.Int("level0_tables", backlog.Level0Tables), zap.Int("level0_tables_stall", backlog.Level0TablesStall), zap.Int64("blocked_puts", blockedPuts), zap.Bool("backpressure", s.writeBackpressure))
+	case stalled && !writeStalled(backlog, blockedPuts, stalled):
+		atomic.StoreInt32(&s.writeStalled, 0)
+		s.logger.Info("writes resumed, the compactions caught up", zap.Int("level0_tables", backlog.Level0Tables))
+	}
+	metric.KvsWriteStalledMetric.WithLabelValues(s.id).Set(float64(atomic.LoadInt32(&s.writeStalled)))
+}
+
+// writeStalled returns whether the writes are stalled given the compaction backlog and
+// the writes blocked since the last check.
+func writeStalled(backlog storage.CompactionBacklog, blockedPuts int64, stalled bool) bool {
+	if blockedPuts > 0 {
+		return true
+	}
+
+	share := writeStallShare
+	if stalled {
+		share = writeResumeShare
+	}
+	threshold := int(float64(backlog.Level0TablesStall) * share)
+	if threshold < 1 {
+		threshold = 1
+	}
+	if stalled {
+		return backlog.Level0Tables > threshold
+	}
+
+	return backlog.Level0Tables >= threshold
+}
+
+// waitForCompactions holds back a write while the writes are stalled, for at most the
+// timeout. It returns whether the writes are still stalled.
+func (s *RaftServer) waitForCompactions(timeout time.Duration) bool {
+	if !s.WritesStalled() {
+		return false
+	}
+
+	ticker := s.clock.NewTicker(writeStallPollInterval)
+	defer ticker.Stop()
+	timer := s.clock.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			if !s.WritesStalled() {
+				return false
+			}
+		case <-timer.C():
+			return s.WritesStalled()
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/mosuka/cete/storage"
+)
+
+func TestWriteStalled(t *testing.T) {
+	tests := []struct {
+		name         string
+		level0Tables int
+		blockedPuts  int64
+		stalled      bool
+		expected     bool
+	}{
+		{name: "empty level 0", level0Tables: 0, expected: false},
+		{name: "below the stall share", level0Tables: 7, expected: false},
+		{name: "at the stall share", level0Tables: 8, expected: true},
+		{name: "blocked puts", level0Tables: 0, blockedPuts: 1, expected: true},
+		{name: "above the resume share", level0Tables: 6, stalled: true, expected: true},
+		{name: "at the resume share", level0Tables: 5, stalled: true, expected: false},
+		{name: "blocked puts while stalled", level0Tables: 0, blockedPuts: 3, stalled: true, expected: true},
+	}
+
+	for _, test := range tests {
+		backlog := storage.CompactionBacklog{Level0Tables: test.level0Tables, Level0TablesStall: 10}
+		if stalled := writeStalled(backlog, test.blockedPuts, test.stalled); stalled != test.expected {
+			t.Errorf("%s: expected %v, saw %v", test.name, test.expected, stalled)
+		}
+	}
+}
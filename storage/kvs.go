@@ -1,9 +1,11 @@
 package storage
 
 import (
+	"os"
 	"time"
 
 	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/table"
 	"github.com/dgraph-io/badger/v2/y"
 	"github.com/mosuka/cete/errors"
 	"github.com/mosuka/cete/protobuf"
@@ -11,10 +13,12 @@ import (
 )
 
 type KVS struct {
-	dir      string
-	valueDir string
-	db       *badger.DB
-	logger   *zap.Logger
+	dir               string
+	valueDir          string
+	db                *badger.DB
+	level0TablesStall int
+	valueThreshold    int
+	logger            *zap.Logger
 }
 
 func NewKVS(dir string, valueDir string, logger *zap.Logger) (*KVS, error) {
@@ -30,10 +34,12 @@ func NewKVS(dir string, valueDir string, logger *zap.Logger) (*KVS, error) {
 	}
 
 	return &KVS{
-		dir:      dir,
-		valueDir: valueDir,
-		db:       db,
-		logger:   logger,
+		dir:               dir,
+		valueDir:          valueDir,
+		db:                db,
+		level0TablesStall: opts.NumLevelZeroTablesStall,
+		valueThreshold:    opts.ValueThreshold,
+		logger:            logger,
 	}, nil
 }
 
@@ -46,12 +52,25 @@ func (k *KVS) Close() error {
 	return nil
 }
 
+// Sync flushes the writes to disk.
+func (k *KVS) Sync() error {
+	if err := k.db.Sync(); err != nil {
+		k.logger.Error("failed to sync database", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 func (k *KVS) Get(key string) ([]byte, error) {
 	start := time.Now()
 
 	var value []byte
 	if err := k.db.View(func(txn *badger.Txn) error {
 		item, err := txn.Get([]byte(key))
+		if err == badger.ErrKeyNotFound {
+			return err
+		}
 		if err != nil {
 			k.logger.Error("failed to get item", zap.String("key", key), zap.Error(err))
 			return err
@@ -107,6 +126,53 @@ func (k *KVS) Scan(prefix string) ([][]byte, error) {
 	return value, nil
 }
 
+func (k *KVS) Iterate(prefix string, f func(key string, value []byte) error) error {
+	start := time.Now()
+
+	if err := k.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		prefixBytes := []byte(prefix)
+		for it.Seek(prefixBytes); it.ValidForPrefix(prefixBytes); it.Next() {
+			item := it.Item()
+			key := string(item.Key())
+			err := item.Value(func(val []byte) error {
+				return f(key, val)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		k.logger.Error("failed to iterate items", zap.String("prefix", prefix), zap.Error(err))
+		return err
+	}
+
+	k.logger.Debug("iterate", zap.String("prefix", prefix), zap.Float64("time", float64(time.Since(start))/float64(time.Second)))
+	return nil
+}
+
+// HasPrefix reports whether a key with the prefix exists.
+func (k *KVS) HasPrefix(prefix string) (bool, error) {
+	found := false
+	if err := k.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = []byte(prefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		it.Rewind()
+		found = it.Valid()
+		return nil
+	}); err != nil {
+		k.logger.Error("failed to look up prefix", zap.String("prefix", prefix), zap.Error(err))
+		return false, err
+	}
+
+	return found, nil
+}
+
 func (k *KVS) Set(key string, value []byte) error {
 	start := time.Now()
 
@@ -145,6 +211,49 @@ func (k *KVS) Delete(key string) error {
 	return nil
 }
 
+// Batch sets and deletes several keys in a single transaction.
+func (k *KVS) Batch(sets map[string][]byte, deletes []string) error {
+	start := time.Now()
+
+	if err := k.db.Update(func(txn *badger.Txn) error {
+		for key, value := range sets {
+			if err := txn.Set([]byte(key), value); err != nil {
+				k.logger.Error("failed to set item", zap.String("key", key), zap.Error(err))
+				return err
+			}
+		}
+		for _, key := range deletes {
+			if err := txn.Delete([]byte(key)); err != nil {
+				k.logger.Error("failed to delete item", zap.String("key", key), zap.Error(err))
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		k.logger.Error("failed to write batch", zap.Int("sets", len(sets)), zap.Int("deletes", len(deletes)), zap.Error(err))
+		return err
+	}
+
+	k.logger.Debug("batch", zap.Int("sets", len(sets)), zap.Int("deletes", len(deletes)), zap.Float64("time", float64(time.Since(start))/float64(time.Second)))
+	return nil
+}
+
+// BatchLimits returns the number of entries and the size of the entries a batch must stay
+// below to be written in a single transaction.
+func (k *KVS) BatchLimits() (int64, int64) {
+	return k.db.MaxBatchCount(), k.db.MaxBatchSize()
+}
+
+// EntrySize returns the size an entry takes in a transaction, as Badger estimates it. The
+// values stored in the value log only take a pointer. A deletion is an entry without value.
+func (k *KVS) EntrySize(key string, valueSize int) int64 {
+	if valueSize < k.valueThreshold {
+		return int64(len(key) + valueSize + 2)
+	}
+
+	return int64(len(key) + 12 + 2)
+}
+
 func (k *KVS) Stats() map[string]string {
 	stats := map[string]string{}
 
@@ -165,51 +274,178 @@ func (k *KVS) Stats() map[string]string {
 	return stats
 }
 
-func (k *KVS) SnapshotItems() <-chan *protobuf.KeyValuePair {
-	ch := make(chan *protobuf.KeyValuePair, 1024)
+// LevelInfo describes a level of the LSM tree.
+type LevelInfo struct {
+	Level  int
+	Tables int
+	Keys   uint64
+	Size   int64
+}
 
-	go func() {
-		start := time.Now()
+// TableInfo describes a table of the LSM tree. The keys include all the versions and
+// deletions of the keys held by the table.
+type TableInfo struct {
+	ID    uint64
+	Level int
+	Left  string
+	Right string
+	Keys  uint64
+	Size  int64
+}
 
-		k.logger.Info("start to snapshot items")
+// Tables returns the tables of the LSM tree.
+func (k *KVS) Tables() []TableInfo {
+	tables := make([]TableInfo, 0)
+	for _, t := range k.db.Tables(true) {
+		info := TableInfo{
+			ID:    t.ID,
+			Level: t.Level,
+			Left:  string(y.ParseKey(t.Left)),
+			Right: string(y.ParseKey(t.Right)),
+			Keys:  t.KeyCount,
+		}
+		// the table may have been compacted away meanwhile
+		if stat, err := os.Stat(table.NewFilename(t.ID, k.dir)); err == nil {
+			info.Size = stat.Size()
+		}
+		tables = append(tables, info)
+	}
 
-		keyCount := uint64(0)
+	return tables
+}
 
-		if err := k.db.View(func(txn *badger.Txn) error {
-			opts := badger.DefaultIteratorOptions
-			opts.PrefetchSize = 10
-			it := txn.NewIterator(opts)
-			defer it.Close()
+// Levels returns the levels of the LSM tree holding tables.
+func (k *KVS) Levels() []LevelInfo {
+	levels := make([]LevelInfo, 0)
+	for _, t := range k.Tables() {
+		for len(levels) <= t.Level {
+			levels = append(levels, LevelInfo{Level: len(levels)})
+		}
+		levels[t.Level].Tables++
+		levels[t.Level].Keys += t.Keys
+		levels[t.Level].Size += t.Size
+	}
 
-			for it.Rewind(); it.Valid(); it.Next() {
-				item := it.Item()
-				key := string(item.Key())
+	return levels
+}
 
-				var value []byte
-				if err := item.Value(func(val []byte) error {
-					value = append([]byte{}, val...)
-					return nil
-				}); err != nil {
-					k.logger.Error("failed to get item value", zap.String("key", key), zap.Error(err))
-					return err
-				}
+// LiveKeys counts the keys neither deleted nor expired, including the system keys. It
+// iterates over all the keys without reading the values.
+func (k *KVS) LiveKeys() (uint64, error) {
+	count := uint64(0)
+	if err := k.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			count++
+		}
+		return nil
+	}); err != nil {
+		k.logger.Error("failed to count live keys", zap.Error(err))
+		return 0, err
+	}
 
-				ch <- &protobuf.KeyValuePair{
-					Key:   key,
-					Value: append([]byte{}, value...),
-				}
+	return count, nil
+}
 
-				keyCount = keyCount + 1
-			}
-			ch <- nil
-			return nil
-		}); err != nil {
-			k.logger.Error("failed to snapshot items", zap.Error(err))
-			return
+// CompactionBacklog describes how far the compactions are behind the writes.
+type CompactionBacklog struct {
+	// Level0Tables is the number of tables in level 0 waiting to be compacted.
+	Level0Tables int
+	// Level0TablesStall is the number of tables in level 0 at which Badger stalls the writes.
+	Level0TablesStall int
+	// BlockedPuts is the number of writes blocked so far because the memtables could not be flushed.
+	BlockedPuts int64
+}
+
+// CompactionBacklog returns the compaction backlog of the LSM tree.
+func (k *KVS) CompactionBacklog() CompactionBacklog {
+	backlog := CompactionBacklog{
+		Level0TablesStall: k.level0TablesStall,
+		BlockedPuts:       y.NumBlockedPuts.Value(),
+	}
+	for _, t := range k.db.Tables(false) {
+		if t.Level == 0 {
+			backlog.Level0Tables++
+		}
+	}
+
+	return backlog
+}
+
+// Size returns the size of the LSM tree and of the value log in bytes.
+func (k *KVS) Size() (int64, int64) {
+	return k.db.Size()
+}
+
+// Snapshot is a consistent view of the key value store, taken when it was created. It
+// must be discarded once read.
+type Snapshot struct {
+	txn    *badger.Txn
+	logger *zap.Logger
+}
+
+// NewSnapshot returns a view of the key value store as it is now. The writes made
+// afterwards are not seen through the view.
+func (k *KVS) NewSnapshot() *Snapshot {
+	return &Snapshot{
+		txn:    k.db.NewTransaction(false),
+		logger: k.logger,
+	}
+}
+
+// Items calls f with all the items of the view. In the background mode the values are
+// read one at a time instead of being prefetched, so that the iteration has less impact
+// on concurrent reads and writes.
+func (s *Snapshot) Items(background bool, f func(kvp *protobuf.KeyValuePair) error) error {
+	start := time.Now()
+
+	s.logger.Info("start to snapshot items")
+
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchSize = 10
+	if background {
+		opts.PrefetchValues = false
+	}
+	it := s.txn.NewIterator(opts)
+	defer it.Close()
+
+	keyCount := uint64(0)
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		key := string(item.Key())
+
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			s.logger.Error("failed to get item value", zap.String("key", key), zap.Error(err))
+			return err
 		}
 
-		k.logger.Info("finished to snapshot items", zap.Uint64("count", keyCount), zap.Float64("time", float64(time.Since(start))/float64(time.Second)))
-	}()
+		if err := f(&protobuf.KeyValuePair{Key: key, Value: value}); err != nil {
+			return err
+		}
+
+		keyCount = keyCount + 1
+	}
+
+	s.logger.Info("finished to snapshot items", zap.Uint64("count", keyCount), zap.Float64("time", float64(time.Since(start))/float64(time.Second)))
+
+	return nil
+}
+
+// Discard releases the view.
+func (s *Snapshot) Discard() {
+	s.txn.Discard()
+}
 
-	return ch
+// DropAll deletes all the items.
+func (k *KVS) DropAll() error {
+	if err := k.db.DropAll(); err != nil {
+		k.logger.Error("failed to drop all items", zap.Error(err))
+		return err
+	}
+
+	return nil
 }